@@ -5,8 +5,12 @@ package agentkit
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	multiagentspec "github.com/agentplexus/multi-agent-spec/sdk/go"
@@ -36,23 +40,56 @@ func (a *Adapter) DefaultDir() string {
 	return "plugins/agentkit"
 }
 
+// LossyFields returns the canonical fields that do not survive an agentkit
+// round trip: tools collapse onto a few agentkit tools (e.g., WebFetch to
+// shell), models become provider IDs, and the rest has no config key.
+func (a *Adapter) LossyFields() []string {
+	return []string{"Tools", "AllowedTools", "Skills", "Dependencies", "Requires", "Arguments", "Triggers"}
+}
+
 // Parse converts agentkit config bytes to canonical Agent.
 func (a *Adapter) Parse(data []byte) (*core.Agent, error) {
 	var cfg AgentConfig
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return nil, &core.ParseError{Format: "agentkit", Err: err}
 	}
-	return configToAgent(&cfg), nil
+	return a.ToCore(&cfg), nil
 }
 
 // Marshal converts canonical Agent to agentkit config bytes.
 func (a *Adapter) Marshal(agent *core.Agent) ([]byte, error) {
-	cfg := agentToConfig(agent)
+	data, _, err := a.MarshalWithWarnings(agent)
+	return data, err
+}
+
+// MarshalWithWarnings converts canonical Agent to agentkit config bytes,
+// noting tools with no agentkit mapping and models that are not aliases or
+// known model IDs, both of which are passed through unchanged.
+func (a *Adapter) MarshalWithWarnings(agent *core.Agent) ([]byte, []core.Warning, error) {
+	cfg := a.FromCore(agent)
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, &core.MarshalError{Format: "agentkit", Err: err}
+	}
 	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
-		return nil, &core.MarshalError{Format: "agentkit", Err: err}
+		return nil, nil, &core.MarshalError{Format: "agentkit", Err: err}
 	}
-	return append(data, '\n'), nil
+	return append(data, '\n'), conversionWarnings(agent), nil
+}
+
+// SupportedTools returns the canonical tools with an agentkit mapping.
+func (a *Adapter) SupportedTools() []string {
+	tools := make([]string, 0, len(multiagentspec.AgentKitTools))
+	for tool := range multiagentspec.AgentKitTools {
+		tools = append(tools, string(tool))
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+// SupportsModelFallback reports that agentkit configs carry fallback chains.
+func (a *Adapter) SupportsModelFallback() bool {
+	return true
 }
 
 // ReadFile reads from path and returns canonical Agent.
@@ -71,16 +108,7 @@ func (a *Adapter) WriteFile(agent *core.Agent, path string) error {
 		return err
 	}
 
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, core.DefaultDirMode); err != nil {
-		return &core.WriteError{Path: path, Err: err}
-	}
-
-	if err := os.WriteFile(path, data, core.DefaultFileMode); err != nil {
-		return &core.WriteError{Path: path, Err: err}
-	}
-
-	return nil
+	return core.WriteOutputFile(path, data)
 }
 
 // AgentConfig matches agentkit/platforms/local AgentConfig structure.
@@ -91,6 +119,79 @@ type AgentConfig struct {
 	Tools        []string `json:"tools"`
 	Model        string   `json:"model,omitempty"`
 	MaxTokens    int      `json:"max_tokens,omitempty"`
+
+	// Version is the version of the spec the agent was generated from.
+	Version string `json:"version,omitempty"`
+
+	// ModelFallback lists models to try, in order, when Model is unavailable.
+	ModelFallback []string `json:"model_fallback,omitempty"`
+
+	// MaxTurns caps the agent loop's iterations. Nil uses the runtime default.
+	MaxTurns *int `json:"max_turns,omitempty"`
+
+	// Timeouts are this agent's effective timeouts: the global defaults
+	// with the agent's overrides applied. Nil uses Config.Timeouts.
+	Timeouts *TimeoutConfig `json:"timeouts,omitempty"`
+
+	// Retry is the agent's retry policy. Nil disables retries.
+	Retry *RetryConfig `json:"retry,omitempty"`
+
+	// Workspace overrides Config.Workspace for this agent. It must be a
+	// relative path within the global workspace.
+	Workspace string `json:"workspace,omitempty"`
+
+	// ResourceAttributes are OpenTelemetry resource attributes for this
+	// agent's telemetry. Nil when observability is disabled.
+	ResourceAttributes map[string]string `json:"resource_attributes,omitempty"`
+
+	// InstructionSections are Instructions split at their level-two
+	// headings, for runtimes that assemble prompts from labeled parts.
+	// Omitted when the instructions have no sections.
+	InstructionSections []InstructionSection `json:"instruction_sections,omitempty"`
+
+	// Metadata carries the agent's passthrough metadata (e.g., owner)
+	// unchanged. The runtime does not interpret it.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Validate checks that the agent's workspace, if set, is a relative path
+// that stays within the global workspace root, that MaxTurns is not
+// negative, and that timeout overrides and the retry policy are valid.
+func (c *AgentConfig) Validate() error {
+	if c.Workspace != "" && !filepath.IsLocal(c.Workspace) {
+		return fmt.Errorf("agent %s: workspace %q must be a relative path within the workspace root", c.Name, c.Workspace)
+	}
+	if c.MaxTurns != nil && *c.MaxTurns < 0 {
+		return fmt.Errorf("agent %s: max_turns must be non-negative, got %d", c.Name, *c.MaxTurns)
+	}
+	if c.Timeouts != nil {
+		if err := c.Timeouts.Validate(); err != nil {
+			return fmt.Errorf("agent %s: %w", c.Name, err)
+		}
+	}
+	if c.Retry != nil {
+		if err := c.Retry.canonical().Validate(); err != nil {
+			return fmt.Errorf("agent %s: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+// InstructionSection is one labeled part of an agent's instructions.
+type InstructionSection struct {
+	Heading string `json:"heading,omitempty"`
+	Body    string `json:"body"`
+}
+
+// RetryConfig defines an agent's retry policy.
+type RetryConfig struct {
+	MaxAttempts int    `json:"max_attempts"`
+	Backoff     string `json:"backoff,omitempty"`
+}
+
+// canonical returns r as a canonical retry policy.
+func (r *RetryConfig) canonical() *core.RetryConfig {
+	return &core.RetryConfig{MaxAttempts: r.MaxAttempts, Backoff: r.Backoff}
 }
 
 // Config is the full agentkit local configuration.
@@ -101,8 +202,41 @@ type Config struct {
 	MCP       MCPConfig     `json:"mcp"`
 	LLM       LLMConfig     `json:"llm"`
 	Timeouts  TimeoutConfig `json:"timeouts"`
+
+	// Analytics enables tool-call logging scaffolding. Nil leaves it disabled.
+	Analytics *AnalyticsConfig `json:"analytics,omitempty"`
+}
+
+// Tool analytics formats understood by the agentkit runtime.
+const (
+	// AnalyticsJSON logs one structured JSON line per tool call.
+	AnalyticsJSON = "json"
+
+	// AnalyticsOTEL records one OpenTelemetry span per tool call.
+	AnalyticsOTEL = "otel"
+)
+
+// AnalyticsConfig configures per-tool-call logging in the agentkit runtime.
+type AnalyticsConfig struct {
+	// Format is the log format ("json" or "otel").
+	Format string `json:"format"`
+
+	// ToolCalls emits one log entry per tool invocation.
+	ToolCalls bool `json:"tool_calls"`
 }
 
+// MCP server transports understood by the agentkit runtime.
+const (
+	// TransportStdio serves MCP over the process's stdin and stdout.
+	TransportStdio = "stdio"
+
+	// TransportSSE serves MCP over HTTP with server-sent events.
+	TransportSSE = "sse"
+
+	// TransportHTTP serves MCP over streamable HTTP.
+	TransportHTTP = "http"
+)
+
 // MCPConfig configures the MCP server.
 type MCPConfig struct {
 	Enabled       bool   `json:"enabled"`
@@ -110,6 +244,45 @@ type MCPConfig struct {
 	Port          int    `json:"port,omitempty"`
 	ServerName    string `json:"server_name,omitempty"`
 	ServerVersion string `json:"server_version,omitempty"`
+
+	// AuthToken is the bearer token network clients must present. It may
+	// be an environment reference such as ${MCP_AUTH_TOKEN}.
+	AuthToken string `json:"auth_token,omitempty"`
+
+	// TLS serves a network transport over HTTPS. Nil serves plain HTTP.
+	TLS *TLSConfig `json:"tls,omitempty"`
+}
+
+// TLSConfig locates the certificate and key an MCP server serves HTTPS
+// with.
+type TLSConfig struct {
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+}
+
+// Validate checks that the transport is known and that network transports
+// (sse and http) have a port. AuthToken and TLS apply only to network
+// transports.
+func (m *MCPConfig) Validate() error {
+	if m.Port < 0 || m.Port > 65535 {
+		return fmt.Errorf("mcp: port %d out of range", m.Port)
+	}
+	switch m.Transport {
+	case TransportStdio:
+		if m.AuthToken != "" || m.TLS != nil {
+			return fmt.Errorf("mcp: auth_token and tls require the %s or %s transport", TransportSSE, TransportHTTP)
+		}
+	case TransportSSE, TransportHTTP:
+		if m.Port == 0 {
+			return fmt.Errorf("mcp: %s transport requires a port", m.Transport)
+		}
+		if m.TLS != nil && (m.TLS.CertFile == "" || m.TLS.KeyFile == "") {
+			return fmt.Errorf("mcp: tls requires cert_file and key_file")
+		}
+	default:
+		return fmt.Errorf("mcp: unknown transport %q (available: %s, %s, %s)", m.Transport, TransportStdio, TransportSSE, TransportHTTP)
+	}
+	return nil
 }
 
 // LLMConfig configures the language model.
@@ -129,12 +302,132 @@ type TimeoutConfig struct {
 	ParallelTotal string `json:"parallel_total"`
 }
 
-// agentKitModelMapping maps canonical models to agentkit local model strings.
-// AgentKit uses full model strings rather than Bedrock ARNs.
-var agentKitModelMapping = map[string]string{
-	"haiku":  "claude-3-haiku-20240307",
-	"sonnet": "claude-3-5-sonnet-20241022",
-	"opus":   "claude-3-opus-20240229",
+// Validate checks that every set timeout is a valid positive duration.
+func (t *TimeoutConfig) Validate() error {
+	return t.canonical().Validate()
+}
+
+// WithOverrides returns a copy of t with the set fields of overrides
+// applied.
+func (t TimeoutConfig) WithOverrides(overrides *core.Timeouts) TimeoutConfig {
+	if overrides == nil {
+		return t
+	}
+	if overrides.AgentInvoke != "" {
+		t.AgentInvoke = overrides.AgentInvoke
+	}
+	if overrides.ShellCommand != "" {
+		t.ShellCommand = overrides.ShellCommand
+	}
+	if overrides.FileRead != "" {
+		t.FileRead = overrides.FileRead
+	}
+	if overrides.ParallelTotal != "" {
+		t.ParallelTotal = overrides.ParallelTotal
+	}
+	return t
+}
+
+// canonical returns t as canonical timeouts.
+func (t *TimeoutConfig) canonical() *core.Timeouts {
+	return &core.Timeouts{
+		AgentInvoke:   t.AgentInvoke,
+		ShellCommand:  t.ShellCommand,
+		FileRead:      t.FileRead,
+		ParallelTotal: t.ParallelTotal,
+	}
+}
+
+// diffTimeouts returns the fields of t that differ from base, or nil if
+// none do.
+func diffTimeouts(t, base TimeoutConfig) *core.Timeouts {
+	var diff core.Timeouts
+	if t.AgentInvoke != base.AgentInvoke {
+		diff.AgentInvoke = t.AgentInvoke
+	}
+	if t.ShellCommand != base.ShellCommand {
+		diff.ShellCommand = t.ShellCommand
+	}
+	if t.FileRead != base.FileRead {
+		diff.FileRead = t.FileRead
+	}
+	if t.ParallelTotal != base.ParallelTotal {
+		diff.ParallelTotal = t.ParallelTotal
+	}
+	if diff == (core.Timeouts{}) {
+		return nil
+	}
+	return &diff
+}
+
+// ToolMapping maps canonical tool names to agentkit tool names.
+type ToolMapping map[string]string
+
+// DefaultToolMapping returns a copy of the default canonical → agentkit tool
+// mapping from multi-agent-spec.
+func DefaultToolMapping() ToolMapping {
+	m := make(ToolMapping, len(multiagentspec.AgentKitTools))
+	for tool, mapped := range multiagentspec.AgentKitTools {
+		m[string(tool)] = mapped
+	}
+	return m
+}
+
+// WithOverrides returns a copy of the mapping with overrides applied.
+// Override keys must be known canonical tools; the receiver is not modified.
+func (m ToolMapping) WithOverrides(overrides map[string]string) (ToolMapping, error) {
+	known := DefaultToolMapping()
+	result := make(ToolMapping, len(m)+len(overrides))
+	for tool, mapped := range m {
+		result[tool] = mapped
+	}
+
+	var unknown []string
+	for tool, mapped := range overrides {
+		if _, ok := known[tool]; !ok {
+			unknown = append(unknown, tool)
+			continue
+		}
+		result[tool] = mapped
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		names := make([]string, 0, len(known))
+		for tool := range known {
+			names = append(names, tool)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown canonical tools in tool mapping overrides: %s (known: %s)",
+			strings.Join(unknown, ", "), strings.Join(names, ", "))
+	}
+
+	return result, nil
+}
+
+// conversionWarnings returns the warnings for converting agent: its tools
+// with no agentkit tool and its models with no known model ID.
+func conversionWarnings(agent *core.Agent) []core.Warning {
+	var warnings []core.Warning
+	for _, tool := range agent.Tools {
+		if _, ok := multiagentspec.AgentKitTools[multiagentspec.Tool(tool)]; !ok {
+			warnings = append(warnings, core.Warning{
+				Agent:   agent.Name,
+				Field:   "tools",
+				Message: fmt.Sprintf("no agentkit tool for %s; passed through unchanged", tool),
+			})
+		}
+	}
+	for _, model := range agent.ModelChain() {
+		if _, ok := core.ResolveModel(string(model)); !ok {
+			warnings = append(warnings, core.Warning{
+				Agent:   agent.Name,
+				Field:   "model",
+				Message: fmt.Sprintf("unknown model %q; passed through unchanged", model),
+			})
+		}
+	}
+	return warnings
 }
 
 // mapToolToAgentKit converts a canonical tool string to AgentKit tool using multi-agent-spec.
@@ -143,43 +436,88 @@ func mapToolToAgentKit(tool string) string {
 }
 
 // mapModelToAgentKit converts a canonical model to AgentKit model string.
+// AgentKit uses Anthropic model IDs rather than Bedrock ARNs.
 func mapModelToAgentKit(model core.Model) string {
-	if mapped, ok := agentKitModelMapping[string(model)]; ok {
-		return mapped
-	}
-	return string(model)
+	id, _ := core.ResolveModel(string(model))
+	return id
+}
+
+// FromCore converts canonical Agent to the agentkit config that Marshal
+// encodes, so callers can inspect or adjust it before writing it. Timeout
+// overrides are merged over the DefaultConfig timeouts.
+func (a *Adapter) FromCore(agent *core.Agent) *AgentConfig {
+	return agentToConfigWithMapping(agent, nil, DefaultConfig().Timeouts)
 }
 
-func agentToConfig(agent *core.Agent) *AgentConfig {
+// agentToConfigWithMapping converts an agent, consulting mapping before the
+// default multi-agent-spec tool mapping. A nil mapping uses the defaults.
+// The agent's timeout overrides are merged over global.
+func agentToConfigWithMapping(agent *core.Agent, mapping ToolMapping, global TimeoutConfig) *AgentConfig {
 	cfg := &AgentConfig{
 		Name:         agent.Name,
-		Description:  agent.Description,
+		Description:  core.FormatDescription("agentkit", agent.Description),
 		Instructions: agent.Instructions,
+		Version:      agent.Version,
+		Workspace:    agent.Workspace,
+		MaxTurns:     agent.MaxTurns,
+		Metadata:     cloneMetadata(agent.Metadata),
+	}
+	if agent.Timeouts != nil {
+		timeouts := global.WithOverrides(agent.Timeouts)
+		cfg.Timeouts = &timeouts
+	}
+	if agent.Retry != nil {
+		cfg.Retry = &RetryConfig{MaxAttempts: agent.Retry.MaxAttempts, Backoff: agent.Retry.Backoff}
+	}
+	// Agents built in code may not have their sections derived yet
+	sections := agent.InstructionSections
+	if sections == nil {
+		sections = core.ParseInstructionSections(agent.Instructions)
+	}
+	for _, section := range sections {
+		cfg.InstructionSections = append(cfg.InstructionSections, InstructionSection(section))
 	}
 
-	// Map tools using multi-agent-spec mappings
+	// Map tools, keeping first-seen order and dropping duplicates
 	toolSet := make(map[string]bool)
 	for _, tool := range agent.Tools {
-		mapped := mapToolToAgentKit(tool)
-		if mapped != "" {
-			toolSet[mapped] = true
-		} else {
+		mapped, ok := mapping[tool]
+		if !ok {
+			mapped = mapToolToAgentKit(tool)
+		}
+		if mapped == "" {
 			// Keep unknown tools as-is (lowercase)
-			toolSet[strings.ToLower(tool)] = true
+			mapped = strings.ToLower(tool)
+		}
+		if !toolSet[mapped] {
+			toolSet[mapped] = true
+			cfg.Tools = append(cfg.Tools, mapped)
 		}
-	}
-	for tool := range toolSet {
-		cfg.Tools = append(cfg.Tools, tool)
 	}
 
-	// Map model
-	if agent.Model != "" {
-		cfg.Model = mapModelToAgentKit(agent.Model)
+	// Map model chain: the first entry is the model, the rest fallbacks
+	if chain := agent.ModelChain(); len(chain) > 0 {
+		cfg.Model = mapModelToAgentKit(chain[0])
+		for _, model := range chain[1:] {
+			cfg.ModelFallback = append(cfg.ModelFallback, mapModelToAgentKit(model))
+		}
 	}
 
 	return cfg
 }
 
+// cloneMetadata returns a copy of metadata, or nil if it is empty.
+func cloneMetadata(metadata map[string]string) map[string]string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	clone := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		clone[key] = value
+	}
+	return clone
+}
+
 // reverseAgentKitToolMapping provides reverse mapping from AgentKit tools to canonical.
 // Note: Some AgentKit tools map to multiple canonical tools, so we pick a representative.
 var reverseAgentKitToolMapping = map[string]string{
@@ -190,12 +528,36 @@ var reverseAgentKitToolMapping = map[string]string{
 	"grep":  "Grep",
 }
 
-func configToAgent(cfg *AgentConfig) *core.Agent {
+// ToCore converts an agentkit config to canonical Agent.
+func (a *Adapter) ToCore(cfg *AgentConfig) *core.Agent {
 	agent := &core.Agent{
-		Name:         cfg.Name,
-		Description:  cfg.Description,
-		Instructions: cfg.Instructions,
-		Model:        core.Model(cfg.Model),
+		Spec: core.Spec{
+			Name:         cfg.Name,
+			Description:  cfg.Description,
+			Instructions: cfg.Instructions,
+			Model:        core.Model(core.ModelAlias(cfg.Model)),
+		},
+		Version:   cfg.Version,
+		Workspace: cfg.Workspace,
+		MaxTurns:  cfg.MaxTurns,
+		Metadata:  cloneMetadata(cfg.Metadata),
+	}
+	if cfg.Timeouts != nil {
+		agent.Timeouts = diffTimeouts(*cfg.Timeouts, DefaultConfig().Timeouts)
+	}
+	if cfg.Retry != nil {
+		agent.Retry = cfg.Retry.canonical()
+	}
+	for _, section := range cfg.InstructionSections {
+		agent.InstructionSections = append(agent.InstructionSections, core.InstructionSection(section))
+	}
+	if agent.Instructions == "" && len(agent.InstructionSections) > 0 {
+		agent.Instructions = core.JoinInstructionSections(agent.InstructionSections)
+	}
+	if len(cfg.ModelFallback) > 0 {
+		for _, model := range append([]string{cfg.Model}, cfg.ModelFallback...) {
+			agent.ModelFallback = append(agent.ModelFallback, core.ModelAlias(model))
+		}
 	}
 
 	// Reverse map tools
@@ -218,13 +580,13 @@ func DefaultConfig() *Config {
 		Agents:    []AgentConfig{},
 		MCP: MCPConfig{
 			Enabled:       true,
-			Transport:     "stdio",
+			Transport:     TransportStdio,
 			ServerName:    "agentkit-local",
 			ServerVersion: "1.0.0",
 		},
 		LLM: LLMConfig{
 			Provider:    "anthropic",
-			Model:       "claude-3-5-sonnet-20241022",
+			Model:       mapModelToAgentKit(core.ModelSonnet),
 			APIKey:      "${ANTHROPIC_API_KEY}",
 			Temperature: 0.7,
 		},
@@ -239,30 +601,122 @@ func DefaultConfig() *Config {
 
 // GenerateFullConfig creates a complete agentkit config from multiple agents.
 func GenerateFullConfig(agents []*core.Agent) *Config {
+	return GenerateFullConfigWithMapping(agents, nil)
+}
+
+// GenerateFullConfigWithMapping creates a complete agentkit config, mapping
+// tools through the given mapping (see DefaultToolMapping and WithOverrides).
+func GenerateFullConfigWithMapping(agents []*core.Agent, mapping ToolMapping) *Config {
 	cfg := DefaultConfig()
 	for _, agent := range agents {
-		cfg.Agents = append(cfg.Agents, *agentToConfig(agent))
+		cfg.Agents = append(cfg.Agents, *agentToConfigWithMapping(agent, mapping, cfg.Timeouts))
 	}
 	return cfg
 }
 
+// ApplyResourceAttributes sets OpenTelemetry resource attributes on each
+// agent in cfg, derived from the matching canonical agent (by name) and team.
+// An empty keys list leaves observability disabled and changes nothing.
+func ApplyResourceAttributes(cfg *Config, agents []*core.Agent, team string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	byName := make(map[string]*core.Agent, len(agents))
+	for _, agent := range agents {
+		byName[agent.Name] = agent
+	}
+
+	for i := range cfg.Agents {
+		agent, ok := byName[cfg.Agents[i].Name]
+		if !ok {
+			continue
+		}
+		attrs, err := core.ResourceAttributes(agent, team, keys)
+		if err != nil {
+			return err
+		}
+		cfg.Agents[i].ResourceAttributes = attrs
+	}
+	return nil
+}
+
 // WriteFullConfig writes a complete agentkit configuration file.
+// Agent names must be unique, since the runtime addresses agents by name.
 func WriteFullConfig(agents []*core.Agent, path string) error {
-	cfg := GenerateFullConfig(agents)
+	if err := core.CheckUniqueOutputNames(agents); err != nil {
+		return err
+	}
+	return WriteConfig(GenerateFullConfig(agents), path)
+}
 
-	data, err := json.MarshalIndent(cfg, "", "  ")
+// MergeFullConfig writes the agents into the agentkit configuration at
+// path, replacing only its Agents array so manual edits to the other
+// sections (MCP, LLM, Timeouts, ...) survive regeneration. If path does not
+// exist, a default config is written as by WriteFullConfig.
+func MergeFullConfig(agents []*core.Agent, path string) error {
+	if err := core.CheckUniqueOutputNames(agents); err != nil {
+		return err
+	}
+	existing, err := ReadConfig(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return WriteConfig(GenerateFullConfig(agents), path)
+	}
 	if err != nil {
-		return &core.MarshalError{Format: "agentkit", Err: err}
+		return err
 	}
+	return WriteConfig(MergeAgents(existing, GenerateFullConfig(agents)), path)
+}
 
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, core.DefaultDirMode); err != nil {
-		return &core.WriteError{Path: path, Err: err}
-	}
+// MergeAgents returns a copy of existing whose Agents array is replaced by
+// the one in generated. Every other section of existing is kept. Keys that
+// Config does not model are not preserved.
+func MergeAgents(existing, generated *Config) *Config {
+	merged := *existing
+	merged.Agents = append([]AgentConfig{}, generated.Agents...)
+	return &merged
+}
 
-	if err := os.WriteFile(path, append(data, '\n'), core.DefaultFileMode); err != nil {
-		return &core.WriteError{Path: path, Err: err}
+// ReadConfig reads an agentkit configuration file.
+func ReadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &core.ReadError{Path: path, Err: err}
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, &core.ParseError{Format: "agentkit", Path: path, Err: err}
 	}
+	return &cfg, nil
+}
 
+// Validate checks the MCP server, the global timeouts and every agent's
+// configuration.
+func (c *Config) Validate() error {
+	if err := c.MCP.Validate(); err != nil {
+		return err
+	}
+	if err := c.Timeouts.Validate(); err != nil {
+		return err
+	}
+	for i := range c.Agents {
+		if err := c.Agents[i].Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// WriteConfig validates and writes an agentkit configuration file.
+func WriteConfig(cfg *Config, path string) error {
+	if err := cfg.Validate(); err != nil {
+		return &core.MarshalError{Format: "agentkit", Err: err}
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return &core.MarshalError{Format: "agentkit", Err: err}
+	}
+
+	return core.WriteOutputFile(path, append(data, '\n'))
+}
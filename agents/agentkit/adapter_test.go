@@ -0,0 +1,415 @@
+package agentkit
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestWriteFullConfigDuplicateNames(t *testing.T) {
+	first := core.NewAgent("reviewer", "Reviews code")
+	first.SourcePath = "specs/reviewer.md"
+	second := core.NewAgent("reviewer", "Reviews docs")
+	second.SourcePath = "specs/docs/reviewer.md"
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := WriteFullConfig([]*core.Agent{first, second}, path)
+
+	var dupErr *core.DuplicateNameError
+	if !errors.As(err, &dupErr) {
+		t.Fatalf("expected DuplicateNameError, got %v", err)
+	}
+	for _, want := range []string{"specs/reviewer.md", "specs/docs/reviewer.md"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q should mention %s", err.Error(), want)
+		}
+	}
+	if _, statErr := os.Stat(path); !os.IsNotExist(statErr) {
+		t.Error("config should not be written when names clash")
+	}
+}
+
+func TestWriteFullConfigUniqueNames(t *testing.T) {
+	agents := []*core.Agent{
+		core.NewAgent("reviewer", "Reviews code"),
+		core.NewAgent("planner", "Plans work"),
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := WriteFullConfig(agents, path); err != nil {
+		t.Fatalf("WriteFullConfig() error = %v", err)
+	}
+}
+
+func TestToolMappingOverrides(t *testing.T) {
+	agent := core.NewAgent("researcher", "Researches topics").WithTools("WebSearch", "Read", "Bash")
+
+	defaults := DefaultToolMapping()
+	overridden, err := defaults.WithOverrides(map[string]string{"WebSearch": "web_search"})
+	if err != nil {
+		t.Fatalf("WithOverrides() error = %v", err)
+	}
+
+	got := GenerateFullConfigWithMapping([]*core.Agent{agent}, overridden).Agents[0].Tools
+	want := []string{"web_search", "read", "shell"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("overridden tools = %v, want %v", got, want)
+	}
+
+	// The default mapping must be unaffected by the override.
+	if defaults["WebSearch"] != "shell" {
+		t.Errorf("defaults[WebSearch] = %q, want shell", defaults["WebSearch"])
+	}
+	got = GenerateFullConfig([]*core.Agent{agent}).Agents[0].Tools
+	want = []string{"shell", "read"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("default tools = %v, want %v", got, want)
+	}
+}
+
+func TestToolMappingOverridesUnknownTool(t *testing.T) {
+	_, err := DefaultToolMapping().WithOverrides(map[string]string{"Browse": "browser"})
+	if err == nil {
+		t.Fatal("expected error for unknown canonical tool")
+	}
+	if !strings.Contains(err.Error(), "Browse") {
+		t.Errorf("error %q should name the unknown tool", err.Error())
+	}
+}
+
+func TestAgentWorkspace(t *testing.T) {
+	tests := []struct {
+		workspace string
+		wantErr   bool
+	}{
+		{"", false},
+		{"services/api", false},
+		{"./tools", false},
+		{"/abs/path", true},
+		{"../outside", true},
+		{"services/../../outside", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.workspace, func(t *testing.T) {
+			agent := core.NewAgent("api", "API agent")
+			agent.Workspace = tt.workspace
+
+			cfg := GenerateFullConfig([]*core.Agent{agent})
+			if got := cfg.Agents[0].Workspace; got != tt.workspace {
+				t.Errorf("Workspace = %q, want %q", got, tt.workspace)
+			}
+
+			err := WriteConfig(cfg, filepath.Join(t.TempDir(), "config.json"))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("WriteConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyResourceAttributes(t *testing.T) {
+	agent := core.NewAgent("api", "API agent")
+	agent.Group = "backend"
+
+	cfg := GenerateFullConfig([]*core.Agent{agent})
+	if err := ApplyResourceAttributes(cfg, []*core.Agent{agent}, "platform", nil); err != nil {
+		t.Fatalf("ApplyResourceAttributes(nil) error = %v", err)
+	}
+	if cfg.Agents[0].ResourceAttributes != nil {
+		t.Error("no attributes should be emitted when disabled")
+	}
+
+	keys := []string{core.AttrServiceName, core.AttrTeam, core.AttrAgentGroup}
+	if err := ApplyResourceAttributes(cfg, []*core.Agent{agent}, "platform", keys); err != nil {
+		t.Fatalf("ApplyResourceAttributes() error = %v", err)
+	}
+	got := cfg.Agents[0].ResourceAttributes
+	if got["service.name"] != "api" || got["team"] != "platform" || got["agent.group"] != "backend" {
+		t.Errorf("ResourceAttributes = %v", got)
+	}
+}
+
+func TestModelFallback(t *testing.T) {
+	agent := core.NewAgent("planner", "Plans")
+	agent.ModelFallback = []string{"opus", "sonnet", "haiku"}
+
+	cfg := (&Adapter{}).FromCore(agent)
+	if cfg.Model != mapModelToAgentKit(core.ModelOpus) {
+		t.Errorf("Model = %q, want the opus mapping", cfg.Model)
+	}
+	want := []string{mapModelToAgentKit(core.ModelSonnet), mapModelToAgentKit(core.ModelHaiku)}
+	if strings.Join(cfg.ModelFallback, ",") != strings.Join(want, ",") {
+		t.Errorf("ModelFallback = %v, want %v", cfg.ModelFallback, want)
+	}
+
+	back := (&Adapter{}).ToCore(cfg)
+	if strings.Join(back.ModelFallback, ",") != "opus,sonnet,haiku" || back.Model != core.ModelOpus {
+		t.Errorf("round trip Model = %q, ModelFallback = %v, want aliases", back.Model, back.ModelFallback)
+	}
+}
+
+func TestMaxTurns(t *testing.T) {
+	adapter := &Adapter{}
+	agent := core.NewAgent("looper", "Loops")
+	maxTurns := 8
+	agent.MaxTurns = &maxTurns
+
+	data, err := adapter.Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"max_turns": 8`) {
+		t.Errorf("output missing max_turns:\n%s", data)
+	}
+
+	back, err := adapter.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if back.MaxTurns == nil || *back.MaxTurns != 8 {
+		t.Errorf("round trip MaxTurns = %v, want 8", back.MaxTurns)
+	}
+
+	negative := -1
+	agent.MaxTurns = &negative
+	if _, err := adapter.Marshal(agent); err == nil {
+		t.Error("Marshal() should reject negative max_turns")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	adapter := &Adapter{}
+	agent := core.NewAgent("fetcher", "Fetches")
+	agent.Retry = &core.RetryConfig{MaxAttempts: 4, Backoff: "1s"}
+
+	data, err := adapter.Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"max_attempts": 4`) {
+		t.Errorf("output missing max_attempts:\n%s", data)
+	}
+
+	back, err := adapter.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if back.Retry == nil || *back.Retry != *agent.Retry {
+		t.Errorf("round trip Retry = %+v, want %+v", back.Retry, agent.Retry)
+	}
+
+	agent.Retry = &core.RetryConfig{MaxAttempts: 0}
+	if _, err := adapter.Marshal(agent); err == nil {
+		t.Error("Marshal() should reject a non-positive max_attempts")
+	}
+}
+
+func TestMergeFullConfigKeepsTimeouts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := MergeFullConfig([]*core.Agent{core.NewAgent("reviewer", "Reviews code")}, path); err != nil {
+		t.Fatalf("MergeFullConfig() without existing config error = %v", err)
+	}
+
+	// Hand-edit the generated config
+	cfg, err := ReadConfig(path)
+	if err != nil {
+		t.Fatalf("ReadConfig() error = %v", err)
+	}
+	cfg.Timeouts.AgentInvoke = "15m"
+	cfg.LLM.Temperature = 0.2
+	if err := WriteConfig(cfg, path); err != nil {
+		t.Fatal(err)
+	}
+
+	agents := []*core.Agent{
+		core.NewAgent("reviewer", "Reviews code and docs"),
+		core.NewAgent("planner", "Plans work"),
+	}
+	if err := MergeFullConfig(agents, path); err != nil {
+		t.Fatalf("MergeFullConfig() error = %v", err)
+	}
+
+	merged, err := ReadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.Timeouts.AgentInvoke != "15m" || merged.LLM.Temperature != 0.2 {
+		t.Errorf("custom settings lost: timeouts %+v, llm %+v", merged.Timeouts, merged.LLM)
+	}
+	if len(merged.Agents) != 2 || merged.Agents[0].Description != "Reviews code and docs" {
+		t.Errorf("Agents = %+v, want regenerated agents", merged.Agents)
+	}
+
+	// Without merge the defaults come back
+	if err := WriteFullConfig(agents, path); err != nil {
+		t.Fatal(err)
+	}
+	if regenerated, _ := ReadConfig(path); regenerated.Timeouts.AgentInvoke != DefaultConfig().Timeouts.AgentInvoke {
+		t.Errorf("WriteFullConfig() kept timeout %q", regenerated.Timeouts.AgentInvoke)
+	}
+}
+
+func TestAgentTimeouts(t *testing.T) {
+	slow := core.NewAgent("indexer", "Indexes the repo")
+	slow.Timeouts = &core.Timeouts{AgentInvoke: "30m"}
+	fast := core.NewAgent("reviewer", "Reviews code")
+
+	cfg := GenerateFullConfig([]*core.Agent{slow, fast})
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	got := cfg.Agents[0].Timeouts
+	if got == nil || got.AgentInvoke != "30m" || got.ShellCommand != cfg.Timeouts.ShellCommand {
+		t.Errorf("indexer timeouts = %+v, want agent_invoke 30m over the global defaults", got)
+	}
+	if cfg.Agents[1].Timeouts != nil {
+		t.Errorf("reviewer timeouts = %+v, want global defaults", cfg.Agents[1].Timeouts)
+	}
+
+	back := (&Adapter{}).ToCore(&cfg.Agents[0])
+	if back.Timeouts == nil || *back.Timeouts != *slow.Timeouts {
+		t.Errorf("round trip Timeouts = %+v, want %+v", back.Timeouts, slow.Timeouts)
+	}
+
+	slow.Timeouts.ShellCommand = "ten minutes"
+	if _, err := (&Adapter{}).Marshal(slow); err == nil {
+		t.Error("Marshal() should reject an invalid duration")
+	}
+}
+
+func TestMCPConfigValidate(t *testing.T) {
+	tls := &TLSConfig{CertFile: "server.crt", KeyFile: "server.key"}
+	tests := []struct {
+		name    string
+		mcp     MCPConfig
+		wantErr string
+	}{
+		{"stdio", MCPConfig{Transport: TransportStdio}, ""},
+		{"stdio with port", MCPConfig{Transport: TransportStdio, Port: 8080}, ""},
+		{"stdio with auth", MCPConfig{Transport: TransportStdio, AuthToken: "secret"}, "require the sse or http transport"},
+		{"stdio with tls", MCPConfig{Transport: TransportStdio, TLS: tls}, "require the sse or http transport"},
+		{"sse", MCPConfig{Transport: TransportSSE, Port: 8080, AuthToken: "${MCP_AUTH_TOKEN}"}, ""},
+		{"sse without port", MCPConfig{Transport: TransportSSE}, "sse transport requires a port"},
+		{"http", MCPConfig{Transport: TransportHTTP, Port: 8443, AuthToken: "secret", TLS: tls}, ""},
+		{"http without port", MCPConfig{Transport: TransportHTTP, TLS: tls}, "http transport requires a port"},
+		{"http with partial tls", MCPConfig{Transport: TransportHTTP, Port: 8443, TLS: &TLSConfig{CertFile: "server.crt"}}, "tls requires cert_file and key_file"},
+		{"port out of range", MCPConfig{Transport: TransportHTTP, Port: 70000}, "port 70000 out of range"},
+		{"unknown transport", MCPConfig{Transport: "websocket", Port: 8080}, `unknown transport "websocket"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.mcp.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := DefaultConfig().MCP.Validate(); err != nil {
+		t.Errorf("DefaultConfig().MCP.Validate() error = %v", err)
+	}
+}
+
+func TestMergeFullConfigRejectsHTTPWithoutPort(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := DefaultConfig()
+	cfg.MCP.Transport = TransportHTTP
+	cfg.MCP.Port = 8080
+	if err := WriteConfig(cfg, path); err != nil {
+		t.Fatalf("WriteConfig() error = %v", err)
+	}
+
+	// A hand edit that drops the port is rejected on regeneration
+	cfg.MCP.Port = 0
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	err = MergeFullConfig([]*core.Agent{core.NewAgent("reviewer", "Reviews code")}, path)
+	if err == nil || !strings.Contains(err.Error(), "http transport requires a port") {
+		t.Errorf("MergeFullConfig() error = %v, want a missing port error", err)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	adapter := &Adapter{}
+	agent := core.NewAgent("reviewer", "Reviews code")
+	agent.Metadata = map[string]string{"owner": "alice", "cost-center": "4711"}
+
+	data, err := adapter.Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"owner": "alice"`) {
+		t.Errorf("output missing metadata:\n%s", data)
+	}
+
+	back, err := adapter.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(back.Metadata) != 2 || back.Metadata["cost-center"] != "4711" {
+		t.Errorf("round trip Metadata = %v, want %v", back.Metadata, agent.Metadata)
+	}
+}
+
+func TestInstructionSections(t *testing.T) {
+	adapter := &Adapter{}
+	agent := core.NewAgent("reviewer", "Reviews code").
+		WithInstructions("## Role\n\nYou review code.\n\n## Guidelines\n\nBe brief.")
+
+	cfg := adapter.FromCore(agent)
+	want := []InstructionSection{{Heading: "Role", Body: "You review code."}, {Heading: "Guidelines", Body: "Be brief."}}
+	if !reflect.DeepEqual(cfg.InstructionSections, want) {
+		t.Errorf("InstructionSections = %+v, want %+v", cfg.InstructionSections, want)
+	}
+	if cfg.Instructions != agent.Instructions {
+		t.Errorf("Instructions = %q, want the flattened instructions", cfg.Instructions)
+	}
+
+	// A config holding only sections parses back to flattened instructions
+	back := adapter.ToCore(&AgentConfig{Name: "reviewer", InstructionSections: want})
+	if back.Instructions != agent.Instructions {
+		t.Errorf("ToCore() Instructions = %q, want %q", back.Instructions, agent.Instructions)
+	}
+}
+
+func TestMarshalWithWarnings(t *testing.T) {
+	agent := core.NewAgent("scout", "Explores").
+		WithModel("gpt-9").
+		WithTools("Read", "Teleport")
+
+	_, warnings, err := (&Adapter{}).MarshalWithWarnings(agent)
+	if err != nil {
+		t.Fatalf("MarshalWithWarnings() error = %v", err)
+	}
+	want := []core.Warning{
+		{Agent: "scout", Field: "tools", Message: "no agentkit tool for Teleport; passed through unchanged"},
+		{Agent: "scout", Field: "model", Message: `unknown model "gpt-9"; passed through unchanged`},
+	}
+	if !reflect.DeepEqual(warnings, want) {
+		t.Errorf("warnings = %v, want %v", warnings, want)
+	}
+
+	_, warnings, err = core.MarshalWithWarnings(&Adapter{}, core.NewAgent("plain", "Plain").WithTools("Read"))
+	if err != nil || len(warnings) != 0 {
+		t.Errorf("MarshalWithWarnings() = %v, %v, want no warnings", warnings, err)
+	}
+}
@@ -3,6 +3,13 @@
 // Supported tools:
 //   - Claude Code: agents/<name>.md (Markdown with YAML frontmatter)
 //   - AWS Kiro CLI: ~/.kiro/agents/<name>.json (JSON format)
+//   - OpenCode: opencode.json agent section (JSON format)
+//   - Cursor: .cursor/rules/<name>.mdc (MDC rules)
+//   - Windsurf: .windsurf/rules/<name>.md (Markdown rules)
+//   - Claude Skills: skills/<name>/SKILL.md plus supporting files
+//   - OpenAI Assistants: assistants/<name>.json (Assistants API create payload)
+//   - Vertex AI Gemini: vertex/<name>.json (system instruction and function declarations)
+//   - Continue: .continue/assistants/<name>.yaml (config.yaml with one chat model)
 //
 // Example usage:
 //
@@ -38,15 +45,48 @@ import (
 	_ "github.com/agentplexus/assistantkit/agents/awsagentcore"
 	_ "github.com/agentplexus/assistantkit/agents/claude"
 	_ "github.com/agentplexus/assistantkit/agents/codex"
+	_ "github.com/agentplexus/assistantkit/agents/continue"
+	_ "github.com/agentplexus/assistantkit/agents/cursor"
 	_ "github.com/agentplexus/assistantkit/agents/gemini"
 	_ "github.com/agentplexus/assistantkit/agents/kiro"
+	_ "github.com/agentplexus/assistantkit/agents/openai"
+	_ "github.com/agentplexus/assistantkit/agents/opencode"
+	_ "github.com/agentplexus/assistantkit/agents/skill"
+	_ "github.com/agentplexus/assistantkit/agents/windsurf"
 )
 
 // Re-export core types for convenience
 type (
-	Agent   = core.Agent
-	Adapter = core.Adapter
-	Model   = core.Model
+	Agent    = core.Agent
+	Spec     = core.Spec
+	Adapter  = core.Adapter
+	Model    = core.Model
+	Registry = core.Registry
+
+	LineEnding       = core.LineEnding
+	ToolSupporter    = core.ToolSupporter
+	WarningMarshaler = core.WarningMarshaler
+	Warning          = core.Warning
+	Trigger          = core.Trigger
+	DescriptionStyle = core.DescriptionStyle
+	LintWarning      = core.LintWarning
+	Catalog          = core.Catalog
+	CatalogEntry     = core.CatalogEntry
+
+	FallbackSupporter = core.FallbackSupporter
+	PathResolver      = core.PathResolver
+	DirectoryAdapter  = core.DirectoryAdapter
+	Argument          = core.Argument
+	MCPPrompt         = core.MCPPrompt
+	Lockfile          = core.Lockfile
+	Timeouts          = core.Timeouts
+	RetryConfig       = core.RetryConfig
+	LossyAdapter      = core.LossyAdapter
+
+	InstructionSection = core.InstructionSection
+
+	SecretResolver    = core.SecretResolver
+	EnvSecretResolver = core.EnvSecretResolver
 )
 
 // Re-export model constants
@@ -58,22 +98,107 @@ const (
 
 // Re-export core functions
 var (
-	NewAgent             = core.NewAgent
-	GetAdapter           = core.GetAdapter
-	AdapterNames         = core.AdapterNames
-	ReadCanonicalFile    = core.ReadCanonicalFile
-	WriteCanonicalFile   = core.WriteCanonicalFile
-	WriteCanonicalJSON   = core.WriteCanonicalJSON
-	ReadCanonicalDir     = core.ReadCanonicalDir
-	WriteAgentsToDir     = core.WriteAgentsToDir
-	ParseMarkdownAgent   = core.ParseMarkdownAgent
-	MarshalMarkdownAgent = core.MarshalMarkdownAgent
+	NewAgent            = core.NewAgent
+	NewRegistry         = core.NewRegistry
+	GetAdapter          = core.GetAdapter
+	AdapterNames        = core.AdapterNames
+	ReadCanonicalFile   = core.ReadCanonicalFile
+	WriteCanonicalFile  = core.WriteCanonicalFile
+	WriteCanonicalJSON  = core.WriteCanonicalJSON
+	ReadCanonicalDir    = core.ReadCanonicalDir
+	WalkCanonical       = core.WalkCanonical
+	ReadCanonicalTree   = core.ReadCanonicalTree
+	ReadCanonicalBundle = core.ReadCanonicalBundle
+	ReadCanonicalSpecs  = core.ReadCanonicalSpecs
+	ResolveCanonical    = core.ResolveCanonical
+	ResolveInheritance  = core.ResolveInheritance
+	Diff                = core.Diff
+	ApplyModelOverrides = core.ApplyModelOverrides
+	Interpolate         = core.Interpolate
+	InterpolatePartial  = core.InterpolatePartial
+
+	ParseInstructionSections = core.ParseInstructionSections
+	JoinInstructionSections  = core.JoinInstructionSections
+	WriteAgentsToDir         = core.WriteAgentsToDir
+	GenerateFiles            = core.GenerateFiles
+	NormalizeSpec            = core.NormalizeSpec
+	ParseMarkdownAgent       = core.ParseMarkdownAgent
+	MarshalMarkdownAgent     = core.MarshalMarkdownAgent
+	CheckUniqueNames         = core.CheckUniqueNames
+	CheckUniqueOutputNames   = core.CheckUniqueOutputNames
+	MergeAgent               = core.MergeAgent
+	MergeAgents              = core.MergeAgents
+	MergeAgentsStrict        = core.MergeAgentsStrict
+	CheckCategories          = core.CheckCategories
+	Lint                     = core.Lint
+	FixToolCasing            = core.FixToolCasing
+	CanonicalToolName        = core.CanonicalToolName
+	BuildCatalog             = core.BuildCatalog
+	FilterDeprecated         = core.FilterDeprecated
+	CheckScopes              = core.CheckScopes
+	CheckArguments           = core.CheckArguments
+	CheckLocales             = core.CheckLocales
+	CheckTimeouts            = core.CheckTimeouts
+	CheckRetries             = core.CheckRetries
+	ValidateLocale           = core.ValidateLocale
+	Localize                 = core.Localize
+	SPDXHeader               = core.SPDXHeader
+	AddLicenseHeader         = core.AddLicenseHeader
+	SetHashAlgorithm         = core.SetHashAlgorithm
+	ContentHash              = core.ContentHash
+	NewLockfile              = core.NewLockfile
+	ReadLockfile             = core.ReadLockfile
+	WriteLockfile            = core.WriteLockfile
+	AgentPath                = core.AgentPath
+	WithDeprecationNotice    = core.WithDeprecationNotice
+	CheckModelFallbacks      = core.CheckModelFallbacks
+	ResolveModel             = core.ResolveModel
+	ModelAlias               = core.ModelAlias
+	CheckMaxTurns            = core.CheckMaxTurns
+	SupportsModelFallback    = core.SupportsModelFallback
+	ResolveSpecDir           = core.ResolveSpecDir
+	ParseGitSource           = core.ParseGitSource
+	FilterGroups             = core.FilterGroups
+	ParseLineEnding          = core.ParseLineEnding
+	SetLineEnding            = core.SetLineEnding
+	WriteOutputFile          = core.WriteOutputFile
+	CheckTools               = core.CheckTools
+	CanonicalTools           = core.CanonicalTools
+	KnownTools               = core.KnownTools
+	NormalizeTools           = core.NormalizeTools
+	FilterTools              = core.FilterTools
+	SupportsTool             = core.SupportsTool
+	AdaptersSupporting       = core.AdaptersSupporting
+	MarshalWithWarnings      = core.MarshalWithWarnings
+	ResolveSecrets           = core.ResolveSecrets
+	ResourceAttributes       = core.ResourceAttributes
+	SetDescriptionStyle      = core.SetDescriptionStyle
+	TruncateText             = core.TruncateText
+	RoundTrip                = core.RoundTrip
+	RoundTripDiff            = core.RoundTripDiff
+	LossyFields              = core.LossyFields
 )
 
+// ErrNotSupported is returned for operations an adapter does not implement.
+var ErrNotSupported = core.ErrNotSupported
+
 // Re-export error types
 type (
 	ParseError   = core.ParseError
 	MarshalError = core.MarshalError
 	ReadError    = core.ReadError
 	WriteError   = core.WriteError
+
+	DuplicateNameError = core.DuplicateNameError
+	UnknownToolError   = core.UnknownToolError
+	SecretError        = core.SecretError
+	UnknownHashError   = core.UnknownHashError
+	ValidationError    = core.ValidationError
+	FieldError         = core.FieldError
 )
+
+// ToConfig converts agent to the typed config of adapter (see
+// core.ConfigAdapter). It reports false if adapter is not backed by a C.
+func ToConfig[C any](adapter Adapter, agent *Agent) (C, bool) {
+	return core.ToConfig[C](adapter, agent)
+}
@@ -1,8 +1,17 @@
 package agents
 
 import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/agentkit"
+	"github.com/agentplexus/assistantkit/agents/core"
+	"github.com/agentplexus/assistantkit/agents/kiro"
+	"github.com/agentplexus/assistantkit/agents/opencode"
 )
 
 func TestAdapterRegistry(t *testing.T) {
@@ -114,3 +123,169 @@ func TestClaudeAdapterMinimal(t *testing.T) {
 		t.Error("should not have skills when empty")
 	}
 }
+
+func TestAdaptersRespectLineEndings(t *testing.T) {
+	defer SetLineEnding(core.OutputLineEnding())
+	SetLineEnding(core.LineEndingCRLF)
+
+	agent := NewAgent("reviewer", "Reviews code").
+		WithTools("Read", "Grep").
+		WithInstructions("Line one\nLine two")
+
+	for _, name := range AdapterNames() {
+		t.Run(name, func(t *testing.T) {
+			adapter, _ := GetAdapter(name)
+			path := filepath.Join(t.TempDir(), agent.Name+adapter.FileExtension())
+			if err := adapter.WriteFile(agent, path); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.HasSuffix(data, []byte("\r\n")) {
+				t.Error("output should end with CRLF")
+			}
+			if n := bytes.Count(data, []byte("\n")); n != bytes.Count(data, []byte("\r\n")) {
+				t.Errorf("output has bare LF line endings: %q", data)
+			}
+		})
+	}
+}
+
+func TestDescriptionStyle(t *testing.T) {
+	defer SetDescriptionStyle("kiro", core.DescriptionStyleFor("kiro"))
+	SetDescriptionStyle("kiro", DescriptionStyle{MaxLength: 8, Mode: core.DescriptionTruncate})
+
+	agent := NewAgent("reviewer", "コードをレビューするエージェント")
+	kiro, _ := GetAdapter("kiro")
+	data, err := kiro.Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"description": "コードをレビュ…"`) {
+		t.Errorf("kiro description not truncated:\n%s", data)
+	}
+	if agent.Description != "コードをレビューするエージェント" {
+		t.Errorf("canonical Description modified: %q", agent.Description)
+	}
+
+	claude, _ := GetAdapter("claude")
+	data, err = claude.Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), "description: "+agent.Description+"\n") {
+		t.Errorf("claude description should be unaffected:\n%s", data)
+	}
+}
+
+func TestToConfig(t *testing.T) {
+	agent := NewAgent("reviewer", "Reviews code").WithTools("Read", "Bash")
+
+	kiroAdapter, _ := GetAdapter("kiro")
+	kiroCfg, ok := ToConfig[*kiro.AgentConfig](kiroAdapter, agent)
+	if !ok || kiroCfg.Name != "reviewer" {
+		t.Errorf("ToConfig[*kiro.AgentConfig]() = %+v, %v", kiroCfg, ok)
+	}
+
+	agentkitAdapter, _ := GetAdapter("agentkit")
+	akCfg, ok := ToConfig[*agentkit.AgentConfig](agentkitAdapter, agent)
+	if !ok || len(akCfg.Tools) != 2 {
+		t.Fatalf("ToConfig[*agentkit.AgentConfig]() = %+v, %v", akCfg, ok)
+	}
+
+	// The typed config can be adjusted before encoding it
+	akCfg.MaxTokens = 4096
+	if back := agentkitAdapter.(*agentkit.Adapter).ToCore(akCfg); back.Name != agent.Name {
+		t.Errorf("ToCore() Name = %q", back.Name)
+	}
+
+	opencodeAdapter, _ := GetAdapter("opencode")
+	if _, ok := ToConfig[*opencode.AgentConfig](opencodeAdapter, agent); !ok {
+		t.Error("ToConfig[*opencode.AgentConfig]() not supported")
+	}
+
+	// Mismatched config types and adapters without typed configs report false
+	if _, ok := ToConfig[*kiro.AgentConfig](agentkitAdapter, agent); ok {
+		t.Error("ToConfig should reject a mismatched config type")
+	}
+	claudeAdapter, _ := GetAdapter("claude")
+	if _, ok := ToConfig[*kiro.AgentConfig](claudeAdapter, agent); ok {
+		t.Error("ToConfig should report false for adapters without a typed config")
+	}
+}
+
+func TestLicenseHeaderRoundTrip(t *testing.T) {
+	header, err := core.SPDXHeader("MIT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	agent := NewAgent("reviewer", "Reviews code").WithTools("Read").WithInstructions("Review the diff.")
+
+	for _, name := range AdapterNames() {
+		t.Run(name, func(t *testing.T) {
+			adapter, _ := GetAdapter(name)
+			data, err := adapter.Marshal(agent)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			licensed := core.AddLicenseHeader(adapter.FileExtension(), data, header)
+			if !bytes.Contains(licensed, []byte("SPDX-License-Identifier: MIT")) {
+				t.Fatalf("no license header in %s output:\n%s", name, licensed)
+			}
+
+			parsed, err := adapter.Parse(licensed)
+			if errors.Is(err, core.ErrNotSupported) {
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() of licensed output error = %v", err)
+			}
+			if parsed.Name != agent.Name {
+				t.Errorf("Name = %q, want %q", parsed.Name, agent.Name)
+			}
+		})
+	}
+}
+
+// conformanceAgent populates every canonical field, so that any loss an
+// adapter does not declare through LossyFields shows up as a failure.
+func conformanceAgent() *core.Agent {
+	agent := core.NewAgent("data-analyst", "Analyzes data: trends, outliers and \"quotes\"").
+		WithModel(core.ModelOpus).
+		WithTools("Read", "Grep", "Bash").
+		WithInstructions("You analyze data.\n\n## Steps\n\n1. Load the data\n2. Report findings").
+		WithNamespace("analytics")
+	agent.Icon = "lucide:chart"
+	agent.AllowedTools = []string{"Read"}
+	agent.Skills = []string{"statistics"}
+	agent.Dependencies = []string{"data-loader"}
+	agent.Requires = []string{"python"}
+	agent.Descriptions = map[string]string{"de": "Analysiert Daten"}
+	agent.Workspace = "analysis"
+	agent.Group = "research"
+	agent.ModelFallback = []string{"opus", "sonnet"}
+	maxTurns := 20
+	agent.MaxTurns = &maxTurns
+	agent.Timeouts = &core.Timeouts{ShellCommand: "5m"}
+	agent.Retry = &core.RetryConfig{MaxAttempts: 3, Backoff: "2s"}
+	agent.Version = "1.4.0"
+	agent.Category = "productivity"
+	agent.Tags = []string{"data"}
+	agent.Arguments = []core.Argument{{Name: "dataset", Description: "Dataset to analyze", Required: true}}
+	agent.Triggers = []core.Trigger{{Event: core.TriggerAfterTool, Pattern: "Write", Action: "gofmt -w ."}}
+	return agent
+}
+
+func TestRoundTripConformance(t *testing.T) {
+	for _, name := range AdapterNames() {
+		adapter, _ := GetAdapter(name)
+		t.Run(name, func(t *testing.T) {
+			if _, err := core.RoundTrip(adapter, conformanceAgent()); errors.Is(err, core.ErrNotSupported) {
+				t.Skip("marshal only")
+			}
+			core.AssertRoundTrip(t, adapter, conformanceAgent())
+		})
+	}
+}
@@ -1,5 +1,6 @@
 // Package awsagentcore provides an adapter for generating AWS Bedrock AgentCore CDK deployments.
 // This generates Infrastructure-as-Code for deploying multi-agent systems to AWS.
+// The "bedrock-agents" adapter targets classic Bedrock Agents instead.
 package awsagentcore
 
 import (
@@ -8,8 +9,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
-	"text/template"
 
 	multiagentspec "github.com/agentplexus/multi-agent-spec/sdk/go"
 
@@ -40,17 +41,37 @@ func (a *Adapter) DefaultDir() string {
 
 // Parse is not typically used for CDK output (it's a generator, not a reader).
 func (a *Adapter) Parse(data []byte) (*core.Agent, error) {
-	return nil, &core.ParseError{Format: "aws-agentcore", Err: fmt.Errorf("parsing CDK output not supported")}
+	return nil, &core.ParseError{Format: "aws-agentcore", Err: fmt.Errorf("parsing CDK output: %w", core.ErrNotSupported)}
 }
 
 // Marshal converts canonical Agent to CDK construct bytes.
 func (a *Adapter) Marshal(agent *core.Agent) ([]byte, error) {
-	return generateAgentConstruct(agent)
+	data, err := generateAgentConstruct(agent)
+	if err != nil {
+		return nil, err
+	}
+	return core.AddVersionComment(a.FileExtension(), data, agent.Version), nil
+}
+
+// SupportedTools returns the canonical tools with a Lambda action mapping.
+func (a *Adapter) SupportedTools() []string {
+	tools := make([]string, 0, len(toolToAction))
+	for tool := range toolToAction {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+// SupportsModelFallback reports that generated constructs expose the
+// fallback chain as foundationModelFallback.
+func (a *Adapter) SupportsModelFallback() bool {
+	return true
 }
 
 // ReadFile is not typically used for CDK output.
 func (a *Adapter) ReadFile(path string) (*core.Agent, error) {
-	return nil, &core.ReadError{Path: path, Err: fmt.Errorf("reading CDK files not supported")}
+	return nil, &core.ReadError{Path: path, Err: fmt.Errorf("reading CDK files: %w", core.ErrNotSupported)}
 }
 
 // WriteFile writes canonical Agent as CDK construct to path.
@@ -60,16 +81,7 @@ func (a *Adapter) WriteFile(agent *core.Agent, path string) error {
 		return err
 	}
 
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, core.DefaultDirMode); err != nil {
-		return &core.WriteError{Path: path, Err: err}
-	}
-
-	if err := os.WriteFile(path, data, core.DefaultFileMode); err != nil {
-		return &core.WriteError{Path: path, Err: err}
-	}
-
-	return nil
+	return core.WriteOutputFile(path, data)
 }
 
 // AgentCoreConfig holds configuration for AgentCore deployment.
@@ -78,6 +90,27 @@ type AgentCoreConfig struct {
 	FoundationModel string `json:"foundation_model"`
 	LambdaRuntime   string `json:"lambda_runtime"`
 	StackName       string `json:"stack_name"`
+
+	// ToolAnalytics selects the tool-call analytics scaffolding emitted into
+	// each agent construct (e.g., "json", "otel"). Empty disables it.
+	ToolAnalytics string `json:"tool_analytics,omitempty"`
+
+	// ResourceAttributes lists the OpenTelemetry resource attribute keys
+	// (see core.ResourceAttributeKeys) to emit for each agent. Empty
+	// disables them.
+	ResourceAttributes []string `json:"resource_attributes,omitempty"`
+
+	// TemplateDir, if set, holds template files (e.g., agent.ts.tmpl) that
+	// replace the built-in templates of the same name.
+	TemplateDir string `json:"template_dir,omitempty"`
+
+	// GuardrailID and GuardrailVersion associate a Bedrock guardrail with
+	// every agent. GuardrailVersion is required whenever GuardrailID is set.
+	GuardrailID      string `json:"guardrail_id,omitempty"`
+	GuardrailVersion string `json:"guardrail_version,omitempty"`
+
+	// Memory configures agent memory across sessions. Nil disables it.
+	Memory *MemoryConfig `json:"memory,omitempty"`
 }
 
 // DefaultAgentCoreConfig returns default configuration.
@@ -104,19 +137,48 @@ var toolToAction = map[string]string{
 }
 
 func generateAgentConstruct(agent *core.Agent) ([]byte, error) {
-	tmpl, err := template.New("agent").Parse(agentConstructTemplate)
+	return generateAgentConstructWithConfig(agent, "", nil)
+}
+
+func generateAgentConstructWithConfig(agent *core.Agent, teamName string, config *AgentCoreConfig) ([]byte, error) {
+	tmpl, err := parseTemplate(config, "aws-agentcore", AgentTemplateFile, agentConstructTemplate)
 	if err != nil {
-		return nil, &core.MarshalError{Format: "aws-agentcore", Err: err}
+		return nil, err
+	}
+
+	var analytics *AnalyticsFormat
+	if config != nil && config.ToolAnalytics != "" {
+		f, ok := GetAnalyticsFormat(config.ToolAnalytics)
+		if !ok {
+			return nil, &core.MarshalError{Format: "aws-agentcore", Err: fmt.Errorf("unknown tool analytics format %q", config.ToolAnalytics)}
+		}
+		analytics = &f
+	}
+
+	var resourceAttrs map[string]string
+	if config != nil && len(config.ResourceAttributes) > 0 {
+		resourceAttrs, err = core.ResourceAttributes(agent, teamName, config.ResourceAttributes)
+		if err != nil {
+			return nil, &core.MarshalError{Format: "aws-agentcore", Err: err}
+		}
 	}
 
 	// Prepare data for template
 	data := map[string]interface{}{
 		"Name":            agent.Name,
 		"NamePascal":      toPascalCase(agent.Name),
-		"Description":     escapeString(agent.Description),
+		"Description":     escapeString(core.FormatDescriptionLine("aws-agentcore", agent.Description)),
 		"Instructions":    escapeString(agent.Instructions),
-		"FoundationModel": getFoundationModel(agent.Model),
+		"FoundationModel": getFoundationModel(agent.PrimaryModel()),
+		"ModelFallback":   getFallbackModels(agent),
+		"MaxTurns":        agent.MaxTurns,
+		"Retry":           agent.Retry,
 		"Actions":         getActions(agent.Tools),
+		"ResourceAttrs":   sortedResourceAttributes(resourceAttrs),
+	}
+	addAgentOptions(data, config)
+	if len(resourceAttrs) > 0 {
+		data["OTELResourceAttributes"] = escapeSingleQuoted(core.FormatResourceAttributes(resourceAttrs))
 	}
 
 	var buf bytes.Buffer
@@ -124,9 +186,29 @@ func generateAgentConstruct(agent *core.Agent) ([]byte, error) {
 		return nil, &core.MarshalError{Format: "aws-agentcore", Err: err}
 	}
 
+	if analytics != nil {
+		if err := renderAnalytics(&buf, analytics, data); err != nil {
+			return nil, err
+		}
+	}
+
 	return buf.Bytes(), nil
 }
 
+// getFallbackModels returns the Bedrock model IDs that follow the primary
+// model in the agent's chain.
+func getFallbackModels(agent *core.Agent) []string {
+	chain := agent.ModelChain()
+	if len(chain) < 2 {
+		return nil
+	}
+	models := make([]string, 0, len(chain)-1)
+	for _, model := range chain[1:] {
+		models = append(models, getFoundationModel(model))
+	}
+	return models
+}
+
 func toPascalCase(s string) string {
 	parts := strings.Split(s, "-")
 	var result strings.Builder
@@ -172,6 +254,32 @@ func getFoundationModel(model core.Model) string {
 	return mapped
 }
 
+// resourceAttribute is a single OpenTelemetry resource attribute.
+type resourceAttribute struct {
+	Key   string
+	Value string
+}
+
+// sortedResourceAttributes returns attrs sorted by key for stable output,
+// or nil if there are none.
+func sortedResourceAttributes(attrs map[string]string) []resourceAttribute {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make([]resourceAttribute, 0, len(attrs))
+	for key, value := range attrs {
+		out = append(out, resourceAttribute{Key: key, Value: escapeSingleQuoted(value)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// escapeSingleQuoted escapes a value for a single-quoted TypeScript string.
+func escapeSingleQuoted(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, "'", `\'`)
+}
+
 func getActions(tools []string) []string {
 	actions := make([]string, 0, len(tools))
 	for _, tool := range tools {
@@ -195,6 +303,30 @@ export interface {{.NamePascal}}AgentProps {
 export class {{.NamePascal}}Agent extends Construct {
   public readonly agent: bedrock.CfnAgent;
   public readonly agentAlias: bedrock.CfnAgentAlias;
+{{- if .ModelFallback}}
+
+  /** Models to switch foundationModel to, in order, if it is unavailable. */
+  public readonly foundationModelFallback: readonly string[] = [
+{{- range .ModelFallback}}
+    '{{.}}',
+{{- end}}
+  ];
+{{- end}}
+{{- if .MaxTurns}}
+
+  /** Maximum turns per invocation, for the runtime's agent loop. */
+  public readonly maxTurns = {{.MaxTurns}};
+{{- end}}
+{{- with .Retry}}
+
+  /** Retry policy for failed tool and model calls; backoff doubles per attempt. */
+  public readonly retry = { maxAttempts: {{.MaxAttempts}}{{with .BackoffDuration}}, backoffMs: {{.Milliseconds}}{{end}} };
+{{- end}}
+{{- if .ResourceAttrs}}
+
+  /** OpenTelemetry resource attributes, in OTEL_RESOURCE_ATTRIBUTES format. */
+  public readonly otelResourceAttributes = '{{.OTELResourceAttributes}}';
+{{- end}}
 
   constructor(scope: Construct, id: string, props?: {{.NamePascal}}AgentProps) {
     super(scope, id);
@@ -221,6 +353,25 @@ export class {{.NamePascal}}Agent extends Construct {
       agentResourceRoleArn: agentRole.roleArn,
       idleSessionTtlInSeconds: 600,
       autoPrepare: true,
+{{- with .Guardrail}}
+      guardrailConfiguration: {
+        guardrailIdentifier: '{{.ID}}',
+        guardrailVersion: '{{.Version}}',
+      },
+{{- end}}
+{{- with .MemoryStorageDays}}
+      memoryConfiguration: {
+        enabledMemoryTypes: ['SESSION_SUMMARY'],
+        storageDays: {{.}},
+      },
+{{- end}}
+{{- if .ResourceAttrs}}
+      tags: {
+{{- range .ResourceAttrs}}
+        '{{.Key}}': '{{.Value}}',
+{{- end}}
+      },
+{{- end}}
     });
 
     // Create agent alias for invocation
@@ -239,14 +390,19 @@ export class {{.NamePascal}}Agent extends Construct {
 `
 
 // GenerateStack creates a full CDK stack with all agents.
+// Agent names must be unique, since each becomes a construct and file name.
 func GenerateStack(teamName string, agents []*core.Agent, config *AgentCoreConfig) ([]byte, error) {
 	if config == nil {
 		config = DefaultAgentCoreConfig()
 	}
 
-	tmpl, err := template.New("stack").Parse(stackTemplate)
+	if err := core.CheckUniqueOutputNames(agents); err != nil {
+		return nil, err
+	}
+
+	tmpl, err := parseTemplate(config, "aws-agentcore", StackTemplateFile, stackTemplate)
 	if err != nil {
-		return nil, &core.MarshalError{Format: "aws-agentcore", Err: err}
+		return nil, err
 	}
 
 	// Prepare agent data
@@ -316,9 +472,9 @@ func GenerateCDKApp(teamName string, config *AgentCoreConfig) ([]byte, error) {
 		config = DefaultAgentCoreConfig()
 	}
 
-	tmpl, err := template.New("app").Parse(appTemplate)
+	tmpl, err := parseTemplate(config, "aws-agentcore", AppTemplateFile, appTemplate)
 	if err != nil {
-		return nil, &core.MarshalError{Format: "aws-agentcore", Err: err}
+		return nil, err
 	}
 
 	data := map[string]interface{}{
@@ -363,6 +519,22 @@ func GenerateCDKJSON(teamName string) ([]byte, error) {
 
 // GeneratePackageJSON creates package.json for the CDK project.
 func GeneratePackageJSON(teamName string) ([]byte, error) {
+	return generatePackageJSON(teamName, nil)
+}
+
+func generatePackageJSON(teamName string, config *AgentCoreConfig) ([]byte, error) {
+	dependencies := map[string]string{
+		"aws-cdk-lib": "^2.170.0",
+		"constructs":  "^10.0.0",
+	}
+	if config != nil && config.ToolAnalytics != "" {
+		if f, ok := GetAnalyticsFormat(config.ToolAnalytics); ok {
+			for name, version := range f.Dependencies {
+				dependencies[name] = version
+			}
+		}
+	}
+
 	pkg := map[string]interface{}{
 		"name":    teamName + "-cdk",
 		"version": "1.0.0",
@@ -381,19 +553,35 @@ func GeneratePackageJSON(teamName string) ([]byte, error) {
 			"typescript":         "^5.0.0",
 			"source-map-support": "^0.5.21",
 		},
-		"dependencies": map[string]string{
-			"aws-cdk-lib": "^2.170.0",
-			"constructs":  "^10.0.0",
-		},
+		"dependencies": dependencies,
 	}
 	return json.MarshalIndent(pkg, "", "  ")
 }
 
+// ConstructGenerator renders the CDK construct of one agent in a project.
+// The construct must be exported as <NamePascal>Agent with an optional
+// foundationModel prop, as the generated stack expects.
+type ConstructGenerator func(agent *core.Agent, teamName string, config *AgentCoreConfig) ([]byte, error)
+
 // WriteCDKProject writes a complete CDK project structure.
 func WriteCDKProject(teamName string, agents []*core.Agent, outputDir string, config *AgentCoreConfig) error {
+	return WriteCDKProjectWith(teamName, agents, outputDir, config, generateAgentConstructWithConfig)
+}
+
+// WriteCDKProjectWith writes a complete CDK project structure whose agent
+// constructs are rendered by construct.
+func WriteCDKProjectWith(teamName string, agents []*core.Agent, outputDir string, config *AgentCoreConfig, construct ConstructGenerator) error {
 	if config == nil {
 		config = DefaultAgentCoreConfig()
 	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	// Check names before writing anything so a clash leaves no partial project
+	if err := core.CheckUniqueOutputNames(agents); err != nil {
+		return err
+	}
 
 	// Create directories
 	dirs := []string{
@@ -412,17 +600,17 @@ func WriteCDKProject(teamName string, agents []*core.Agent, outputDir string, co
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(outputDir, "cdk.json"), cdkJSON, core.DefaultFileMode); err != nil {
-		return &core.WriteError{Path: "cdk.json", Err: err}
+	if err := core.WriteOutputFile(filepath.Join(outputDir, "cdk.json"), cdkJSON); err != nil {
+		return err
 	}
 
 	// Write package.json
-	pkgJSON, err := GeneratePackageJSON(teamName)
+	pkgJSON, err := generatePackageJSON(teamName, config)
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(outputDir, "package.json"), pkgJSON, core.DefaultFileMode); err != nil {
-		return &core.WriteError{Path: "package.json", Err: err}
+	if err := core.WriteOutputFile(filepath.Join(outputDir, "package.json"), pkgJSON); err != nil {
+		return err
 	}
 
 	// Write app entry point
@@ -430,8 +618,8 @@ func WriteCDKProject(teamName string, agents []*core.Agent, outputDir string, co
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(outputDir, "bin", teamName+".ts"), appTS, core.DefaultFileMode); err != nil {
-		return &core.WriteError{Path: "bin/app.ts", Err: err}
+	if err := core.WriteOutputFile(filepath.Join(outputDir, "bin", teamName+".ts"), appTS); err != nil {
+		return err
 	}
 
 	// Write stack
@@ -439,19 +627,19 @@ func WriteCDKProject(teamName string, agents []*core.Agent, outputDir string, co
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(outputDir, "lib", teamName+"-stack.ts"), stackTS, core.DefaultFileMode); err != nil {
-		return &core.WriteError{Path: "lib/stack.ts", Err: err}
+	if err := core.WriteOutputFile(filepath.Join(outputDir, "lib", teamName+"-stack.ts"), stackTS); err != nil {
+		return err
 	}
 
 	// Write individual agent constructs
 	for _, agent := range agents {
-		agentTS, err := generateAgentConstruct(agent)
+		agentTS, err := construct(agent, teamName, config)
 		if err != nil {
 			return err
 		}
 		agentPath := filepath.Join(outputDir, "lib", "agents", agent.Name+".ts")
-		if err := os.WriteFile(agentPath, agentTS, core.DefaultFileMode); err != nil {
-			return &core.WriteError{Path: agentPath, Err: err}
+		if err := core.WriteOutputFile(agentPath, agentTS); err != nil {
+			return err
 		}
 	}
 
@@ -481,8 +669,8 @@ func WriteCDKProject(teamName string, agents []*core.Agent, outputDir string, co
   "exclude": ["node_modules", "cdk.out"]
 }
 `
-	if err := os.WriteFile(filepath.Join(outputDir, "tsconfig.json"), []byte(tsconfig), core.DefaultFileMode); err != nil {
-		return &core.WriteError{Path: "tsconfig.json", Err: err}
+	if err := core.WriteOutputFile(filepath.Join(outputDir, "tsconfig.json"), []byte(tsconfig)); err != nil {
+		return err
 	}
 
 	return nil
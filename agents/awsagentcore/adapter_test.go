@@ -0,0 +1,174 @@
+package awsagentcore
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func testAgent() *core.Agent {
+	return core.NewAgent("data-analyst", "Analyzes data").
+		WithTools("Read", "Bash").
+		WithInstructions("Analyze the data.")
+}
+
+func TestToolAnalyticsDisabled(t *testing.T) {
+	agent := testAgent()
+
+	plain, err := generateAgentConstruct(agent)
+	if err != nil {
+		t.Fatalf("generateAgentConstruct() error = %v", err)
+	}
+	withConfig, err := generateAgentConstructWithConfig(agent, "", DefaultAgentCoreConfig())
+	if err != nil {
+		t.Fatalf("generateAgentConstructWithConfig() error = %v", err)
+	}
+	if !bytes.Equal(plain, withConfig) {
+		t.Error("output should be unchanged when tool analytics are disabled")
+	}
+}
+
+func TestToolAnalyticsFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{AnalyticsJSON, []string{"withDataAnalystToolLogging", "event: 'tool_call'", "'read_file', 'execute_command'"}},
+		{AnalyticsOTEL, []string{"withDataAnalystToolLogging", "@opentelemetry/api", "startActiveSpan"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			config := DefaultAgentCoreConfig()
+			config.ToolAnalytics = tt.format
+
+			data, err := generateAgentConstructWithConfig(testAgent(), "", config)
+			if err != nil {
+				t.Fatalf("generateAgentConstructWithConfig() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("output missing %q", want)
+				}
+			}
+		})
+	}
+}
+
+func TestToolAnalyticsUnknownFormat(t *testing.T) {
+	config := DefaultAgentCoreConfig()
+	config.ToolAnalytics = "syslog"
+
+	if _, err := generateAgentConstructWithConfig(testAgent(), "", config); err == nil {
+		t.Error("expected error for unknown analytics format")
+	}
+}
+
+func TestResourceAttributes(t *testing.T) {
+	config := DefaultAgentCoreConfig()
+	config.ResourceAttributes = core.DefaultResourceAttributes
+
+	agent := testAgent().WithNamespace("analytics")
+	data, err := generateAgentConstructWithConfig(agent, "stats-team", config)
+	if err != nil {
+		t.Fatalf("generateAgentConstructWithConfig() error = %v", err)
+	}
+
+	out := string(data)
+	for _, want := range []string{
+		"otelResourceAttributes = 'agent.id=analytics/data-analyst,service.name=data-analyst,team=stats-team'",
+		"tags: {",
+		"'service.name': 'data-analyst',",
+		"'team': 'stats-team',",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+
+	config.ResourceAttributes = []string{"host.name"}
+	if _, err := generateAgentConstructWithConfig(agent, "stats-team", config); err == nil {
+		t.Error("expected error for unknown resource attribute")
+	}
+}
+
+func TestModelFallback(t *testing.T) {
+	agent := testAgent()
+	agent.ModelFallback = []string{"opus", "haiku"}
+
+	data, err := generateAgentConstruct(agent)
+	if err != nil {
+		t.Fatalf("generateAgentConstruct() error = %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		"props?.foundationModel ?? '" + getFoundationModel(core.ModelOpus) + "'",
+		"public readonly foundationModelFallback: readonly string[] = [",
+		"'" + getFoundationModel(core.ModelHaiku) + "',",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q", want)
+		}
+	}
+
+	plain, err := generateAgentConstruct(testAgent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(plain), "foundationModelFallback") {
+		t.Error("fallback property should only be emitted with a fallback chain")
+	}
+}
+
+func TestMaxTurns(t *testing.T) {
+	agent := testAgent()
+	maxTurns := 20
+	agent.MaxTurns = &maxTurns
+
+	data, err := generateAgentConstruct(agent)
+	if err != nil {
+		t.Fatalf("generateAgentConstruct() error = %v", err)
+	}
+	if !strings.Contains(string(data), "public readonly maxTurns = 20;") {
+		t.Errorf("output missing maxTurns:\n%s", data)
+	}
+
+	plain, err := generateAgentConstruct(testAgent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(plain), "maxTurns") {
+		t.Error("maxTurns should only be emitted when set")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		retry *core.RetryConfig
+		want  string
+	}{
+		{&core.RetryConfig{MaxAttempts: 3, Backoff: "1500ms"}, "public readonly retry = { maxAttempts: 3, backoffMs: 1500 };"},
+		{&core.RetryConfig{MaxAttempts: 2}, "public readonly retry = { maxAttempts: 2 };"},
+	}
+	for _, tt := range tests {
+		agent := testAgent()
+		agent.Retry = tt.retry
+		data, err := generateAgentConstruct(agent)
+		if err != nil {
+			t.Fatalf("generateAgentConstruct() error = %v", err)
+		}
+		if !strings.Contains(string(data), tt.want) {
+			t.Errorf("output missing %q:\n%s", tt.want, data)
+		}
+	}
+
+	plain, err := generateAgentConstruct(testAgent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(plain), "retry") {
+		t.Error("retry should only be emitted when set")
+	}
+}
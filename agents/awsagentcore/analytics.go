@@ -0,0 +1,127 @@
+package awsagentcore
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"text/template"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// Built-in tool analytics formats.
+const (
+	// AnalyticsJSON emits one structured JSON log line per tool call.
+	AnalyticsJSON = "json"
+
+	// AnalyticsOTEL emits one OpenTelemetry span per tool call.
+	AnalyticsOTEL = "otel"
+)
+
+// AnalyticsFormat describes the TypeScript scaffolding appended to each agent
+// construct when tool analytics are enabled. Templates receive the same data
+// as the agent construct template (.Name, .NamePascal, .Actions, ...).
+type AnalyticsFormat struct {
+	// Helper is a text/template rendering the logging wrapper.
+	Helper string
+
+	// Dependencies are npm packages added to package.json.
+	Dependencies map[string]string
+}
+
+var (
+	analyticsMu      sync.RWMutex
+	analyticsFormats = map[string]AnalyticsFormat{
+		AnalyticsJSON: {Helper: jsonAnalyticsTemplate},
+		AnalyticsOTEL: {
+			Helper:       otelAnalyticsTemplate,
+			Dependencies: map[string]string{"@opentelemetry/api": "^1.9.0"},
+		},
+	}
+)
+
+// RegisterAnalyticsFormat adds or replaces a tool analytics format.
+func RegisterAnalyticsFormat(name string, format AnalyticsFormat) {
+	analyticsMu.Lock()
+	defer analyticsMu.Unlock()
+	analyticsFormats[name] = format
+}
+
+// GetAnalyticsFormat returns a tool analytics format by name.
+func GetAnalyticsFormat(name string) (AnalyticsFormat, bool) {
+	analyticsMu.RLock()
+	defer analyticsMu.RUnlock()
+	f, ok := analyticsFormats[name]
+	return f, ok
+}
+
+// AnalyticsFormatNames returns all registered analytics format names sorted alphabetically.
+func AnalyticsFormatNames() []string {
+	analyticsMu.RLock()
+	defer analyticsMu.RUnlock()
+	names := make([]string, 0, len(analyticsFormats))
+	for name := range analyticsFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func renderAnalytics(buf *bytes.Buffer, format *AnalyticsFormat, data map[string]interface{}) error {
+	tmpl, err := template.New("analytics").Parse(format.Helper)
+	if err != nil {
+		return &core.MarshalError{Format: "aws-agentcore", Err: err}
+	}
+	buf.WriteString("\n")
+	if err := tmpl.Execute(buf, data); err != nil {
+		return &core.MarshalError{Format: "aws-agentcore", Err: err}
+	}
+	return nil
+}
+
+const jsonAnalyticsTemplate = `// Tool call analytics: one JSON log line per invocation.
+export const {{.NamePascal}}ToolActions = [{{range $i, $a := .Actions}}{{if $i}}, {{end}}'{{$a}}'{{end}}];
+
+export async function with{{.NamePascal}}ToolLogging<T>(tool: string, invoke: () => Promise<T>): Promise<T> {
+  const start = Date.now();
+  let status = 'ok';
+  try {
+    return await invoke();
+  } catch (err) {
+    status = 'error';
+    throw err;
+  } finally {
+    console.log(JSON.stringify({
+      event: 'tool_call',
+      agent: '{{.Name}}',
+      tool,
+      status,
+      durationMs: Date.now() - start,
+      timestamp: new Date().toISOString(),
+    }));
+  }
+}
+`
+
+const otelAnalyticsTemplate = `// Tool call analytics: one OpenTelemetry span per invocation.
+import { trace, SpanStatusCode } from '@opentelemetry/api';
+
+export const {{.NamePascal}}ToolActions = [{{range $i, $a := .Actions}}{{if $i}}, {{end}}'{{$a}}'{{end}}];
+
+const {{.NamePascal}}Tracer = trace.getTracer('{{.Name}}');
+
+export async function with{{.NamePascal}}ToolLogging<T>(tool: string, invoke: () => Promise<T>): Promise<T> {
+  return {{.NamePascal}}Tracer.startActiveSpan('tool_call ' + tool, async (span) => {
+    span.setAttribute('agent.name', '{{.Name}}');
+    span.setAttribute('tool.name', tool);
+    try {
+      return await invoke();
+    } catch (err) {
+      span.setStatus({ code: SpanStatusCode.ERROR });
+      throw err;
+    } finally {
+      span.end();
+    }
+  });
+}
+`
@@ -0,0 +1,279 @@
+package awsagentcore
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func init() {
+	core.Register(&BedrockAgentAdapter{})
+}
+
+// BedrockAgentAdapter converts canonical Agent definitions to CDK constructs
+// for classic Bedrock Agents: an instruction, a foundation model, and one
+// action group per mapped tool. Action groups are stubs that return control
+// to the invoking application, which fulfils the tool call.
+type BedrockAgentAdapter struct{}
+
+// Name returns the adapter identifier.
+func (a *BedrockAgentAdapter) Name() string {
+	return "bedrock-agents"
+}
+
+// FileExtension returns the file extension for CDK files.
+func (a *BedrockAgentAdapter) FileExtension() string {
+	return ".ts"
+}
+
+// DefaultDir returns the default directory name for CDK output.
+func (a *BedrockAgentAdapter) DefaultDir() string {
+	return "cdk"
+}
+
+// Parse is not supported; CDK output is generated, not read.
+func (a *BedrockAgentAdapter) Parse(data []byte) (*core.Agent, error) {
+	return nil, &core.ParseError{Format: "bedrock-agents", Err: fmt.Errorf("parsing CDK output: %w", core.ErrNotSupported)}
+}
+
+// Marshal converts canonical Agent to Bedrock Agent CDK construct bytes.
+func (a *BedrockAgentAdapter) Marshal(agent *core.Agent) ([]byte, error) {
+	data, err := generateBedrockAgentConstruct(agent, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	return core.AddVersionComment(a.FileExtension(), data, agent.Version), nil
+}
+
+// SupportedTools returns the canonical tools with an action group mapping.
+func (a *BedrockAgentAdapter) SupportedTools() []string {
+	return (&Adapter{}).SupportedTools()
+}
+
+// ReadFile is not supported; CDK output is generated, not read.
+func (a *BedrockAgentAdapter) ReadFile(path string) (*core.Agent, error) {
+	return nil, &core.ReadError{Path: path, Err: fmt.Errorf("reading CDK files: %w", core.ErrNotSupported)}
+}
+
+// WriteFile writes canonical Agent as Bedrock Agent CDK construct to path.
+func (a *BedrockAgentAdapter) WriteFile(agent *core.Agent, path string) error {
+	data, err := a.Marshal(agent)
+	if err != nil {
+		return err
+	}
+
+	return core.WriteOutputFile(path, data)
+}
+
+// WriteBedrockAgentProject writes a complete CDK project deploying agents as
+// classic Bedrock Agents. Tool analytics in config are not supported.
+func WriteBedrockAgentProject(teamName string, agents []*core.Agent, outputDir string, config *AgentCoreConfig) error {
+	if config != nil && config.ToolAnalytics != "" {
+		return &core.MarshalError{Format: "bedrock-agents", Err: fmt.Errorf("tool analytics are not supported for Bedrock Agents")}
+	}
+	return WriteCDKProjectWith(teamName, agents, outputDir, config, generateBedrockAgentConstruct)
+}
+
+// actionParameter is a parameter of an action group function.
+type actionParameter struct {
+	Name        string
+	Type        string
+	Description string
+	Required    bool
+}
+
+// actionFunction describes the function schema of an action group stub.
+type actionFunction struct {
+	Description string
+	Parameters  []actionParameter
+}
+
+// actionFunctions describes each action in toolToAction.
+var actionFunctions = map[string]actionFunction{
+	"web_search": {"Search the web", []actionParameter{
+		{"query", "string", "Search query", true},
+	}},
+	"web_fetch": {"Fetch the content of a URL", []actionParameter{
+		{"url", "string", "URL to fetch", true},
+	}},
+	"read_file": {"Read a file", []actionParameter{
+		{"path", "string", "Path of the file", true},
+	}},
+	"write_file": {"Write a file", []actionParameter{
+		{"path", "string", "Path of the file", true},
+		{"content", "string", "Content to write", true},
+	}},
+	"glob_files": {"List files matching a glob pattern", []actionParameter{
+		{"pattern", "string", "Glob pattern", true},
+	}},
+	"grep_content": {"Search file contents with a regular expression", []actionParameter{
+		{"pattern", "string", "Regular expression", true},
+		{"path", "string", "Directory or file to search", false},
+	}},
+	"execute_command": {"Run a shell command", []actionParameter{
+		{"command", "string", "Command to run", true},
+	}},
+}
+
+// actionGroup is an action group stub in the generated construct.
+type actionGroup struct {
+	Name string
+	actionFunction
+}
+
+// getActionGroups returns the action groups for the agent's mapped tools,
+// sorted by name. Unmapped tools are skipped.
+func getActionGroups(tools []string) []actionGroup {
+	seen := make(map[string]bool)
+	var groups []actionGroup
+	for _, action := range getActions(tools) {
+		if seen[action] {
+			continue
+		}
+		seen[action] = true
+		groups = append(groups, actionGroup{Name: action, actionFunction: actionFunctions[action]})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+func generateBedrockAgentConstruct(agent *core.Agent, teamName string, config *AgentCoreConfig) ([]byte, error) {
+	tmpl, err := parseTemplate(config, "bedrock-agents", BedrockAgentTemplateFile, bedrockAgentTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var resourceAttrs map[string]string
+	if config != nil && len(config.ResourceAttributes) > 0 {
+		resourceAttrs, err = core.ResourceAttributes(agent, teamName, config.ResourceAttributes)
+		if err != nil {
+			return nil, &core.MarshalError{Format: "bedrock-agents", Err: err}
+		}
+	}
+
+	data := map[string]interface{}{
+		"Name":            agent.Name,
+		"NamePascal":      toPascalCase(agent.Name),
+		"Description":     escapeSingleQuoted(core.FormatDescriptionLine("bedrock-agents", agent.Description)),
+		"Instructions":    escapeString(agent.Instructions),
+		"FoundationModel": getFoundationModel(agent.PrimaryModel()),
+		"ActionGroups":    getActionGroups(agent.Tools),
+		"ResourceAttrs":   sortedResourceAttributes(resourceAttrs),
+	}
+	addAgentOptions(data, config)
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, &core.MarshalError{Format: "bedrock-agents", Err: err}
+	}
+	return buf.Bytes(), nil
+}
+
+const bedrockAgentTemplate = `import * as cdk from 'aws-cdk-lib';
+import * as bedrock from 'aws-cdk-lib/aws-bedrock';
+import * as iam from 'aws-cdk-lib/aws-iam';
+import { Construct } from 'constructs';
+
+export interface {{.NamePascal}}AgentProps {
+  readonly foundationModel?: string;
+}
+
+export class {{.NamePascal}}Agent extends Construct {
+  public readonly agent: bedrock.CfnAgent;
+  public readonly agentAlias: bedrock.CfnAgentAlias;
+
+  constructor(scope: Construct, id: string, props?: {{.NamePascal}}AgentProps) {
+    super(scope, id);
+
+    const foundationModel = props?.foundationModel ?? '{{.FoundationModel}}';
+
+    // IAM role for the agent
+    const agentRole = new iam.Role(this, 'AgentRole', {
+      assumedBy: new iam.ServicePrincipal('bedrock.amazonaws.com'),
+    });
+    agentRole.addToPolicy(new iam.PolicyStatement({
+      actions: ['bedrock:InvokeModel'],
+      resources: [` + "`" + `arn:aws:bedrock:${cdk.Stack.of(this).region}::foundation-model/${foundationModel}` + "`" + `],
+    }));
+{{- with .Guardrail}}
+    agentRole.addToPolicy(new iam.PolicyStatement({
+      actions: ['bedrock:ApplyGuardrail'],
+      resources: [{{.Resource}}],
+    }));
+{{- end}}
+
+    // Agent instruction
+    const instruction = ` + "`" + `{{.Instructions}}` + "`" + `;
+
+    // Create the Bedrock Agent
+    this.agent = new bedrock.CfnAgent(this, 'Agent', {
+      agentName: '{{.Name}}',
+      description: '{{.Description}}',
+      foundationModel: foundationModel,
+      instruction: instruction,
+      agentResourceRoleArn: agentRole.roleArn,
+      idleSessionTtlInSeconds: 600,
+      autoPrepare: true,
+{{- with .Guardrail}}
+      guardrailConfiguration: {
+        guardrailIdentifier: '{{.ID}}',
+        guardrailVersion: '{{.Version}}',
+      },
+{{- end}}
+{{- with .MemoryStorageDays}}
+      memoryConfiguration: {
+        enabledMemoryTypes: ['SESSION_SUMMARY'],
+        storageDays: {{.}},
+      },
+{{- end}}
+{{- if .ActionGroups}}
+      // Action group stubs return control to the invoking application,
+      // which performs the tool call and sends back the result.
+      actionGroups: [
+{{- range .ActionGroups}}
+        {
+          actionGroupName: '{{.Name}}',
+          description: '{{.Description}}',
+          actionGroupExecutor: { customControl: 'RETURN_CONTROL' },
+          functionSchema: {
+            functions: [
+              {
+                name: '{{.Name}}',
+                description: '{{.Description}}',
+                parameters: {
+{{- range .Parameters}}
+                  {{.Name}}: { type: '{{.Type}}', description: '{{.Description}}', required: {{.Required}} },
+{{- end}}
+                },
+              },
+            ],
+          },
+        },
+{{- end}}
+      ],
+{{- end}}
+{{- if .ResourceAttrs}}
+      tags: {
+{{- range .ResourceAttrs}}
+        '{{.Key}}': '{{.Value}}',
+{{- end}}
+      },
+{{- end}}
+    });
+
+    // Create agent alias for invocation
+    this.agentAlias = new bedrock.CfnAgentAlias(this, 'AgentAlias', {
+      agentId: this.agent.attrAgentId,
+      agentAliasName: 'live',
+    });
+
+    // Output the agent ID
+    new cdk.CfnOutput(this, '{{.NamePascal}}AgentId', {
+      value: this.agent.attrAgentId,
+      description: 'Agent ID for {{.Name}}',
+    });
+  }
+}
+`
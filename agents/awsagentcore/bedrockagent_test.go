@@ -0,0 +1,66 @@
+package awsagentcore
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestBedrockAgentGolden(t *testing.T) {
+	agent := testAgent().WithModel(core.ModelSonnet)
+	agent.Description = "Analyzes the team's data"
+
+	got, err := (&BedrockAgentAdapter{}).Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	golden := filepath.Join("testdata", "bedrock-agent.golden")
+	if *update {
+		if err := os.WriteFile(golden, got, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (run with -update to accept):\n%s", golden, got)
+	}
+}
+
+func TestWriteBedrockAgentProject(t *testing.T) {
+	dir := t.TempDir()
+	if err := WriteBedrockAgentProject("stats-team", []*core.Agent{testAgent()}, dir, nil); err != nil {
+		t.Fatalf("WriteBedrockAgentProject() error = %v", err)
+	}
+
+	construct, err := os.ReadFile(filepath.Join(dir, "lib", "agents", "data-analyst.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(construct), "customControl: 'RETURN_CONTROL'") {
+		t.Error("project should use Bedrock Agent constructs")
+	}
+	stack, err := os.ReadFile(filepath.Join(dir, "lib", "stats-team-stack.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(stack), "new DataAnalystAgent(this, 'DataAnalyst'") {
+		t.Errorf("stack does not instantiate the agent:\n%s", stack)
+	}
+
+	config := DefaultAgentCoreConfig()
+	config.ToolAnalytics = AnalyticsJSON
+	if err := WriteBedrockAgentProject("stats-team", []*core.Agent{testAgent()}, t.TempDir(), config); err == nil {
+		t.Error("expected error for tool analytics")
+	}
+}
@@ -0,0 +1,83 @@
+package awsagentcore
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Retention of agent memory in days: the default for a zero
+// MemoryConfig.TTLDays, and the most Bedrock allows.
+const (
+	DefaultMemoryTTLDays = 30
+	MaxMemoryTTLDays     = 365
+)
+
+// MemoryConfig configures Bedrock agent memory, which summarizes sessions
+// so later sessions can recall them.
+type MemoryConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// TTLDays is how many days session summaries are kept, at most
+	// MaxMemoryTTLDays. Zero uses DefaultMemoryTTLDays.
+	TTLDays int `json:"ttl_days,omitempty"`
+}
+
+// Validate checks that a guardrail is given with both its ID and version,
+// and that the memory TTL is within Bedrock's limits.
+func (c *AgentCoreConfig) Validate() error {
+	var errs []error
+	switch {
+	case c.GuardrailID != "" && c.GuardrailVersion == "":
+		errs = append(errs, fmt.Errorf("guardrail %s: GuardrailVersion is required when GuardrailID is set", c.GuardrailID))
+	case c.GuardrailID == "" && c.GuardrailVersion != "":
+		errs = append(errs, errors.New("GuardrailVersion is set without GuardrailID"))
+	}
+	if c.Memory != nil && (c.Memory.TTLDays < 0 || c.Memory.TTLDays > MaxMemoryTTLDays) {
+		errs = append(errs, fmt.Errorf("memory TTLDays %d must be between 1 and %d (0 for the default of %d)", c.Memory.TTLDays, MaxMemoryTTLDays, DefaultMemoryTTLDays))
+	}
+	return errors.Join(errs...)
+}
+
+// guardrail is the template data of a guardrail association, with values
+// quoted for the template's language.
+type guardrail struct {
+	ID      string
+	Version string
+
+	// Resource is the guardrail's ARN as an expression, for the agent
+	// role's bedrock:ApplyGuardrail permission.
+	Resource string
+}
+
+// addAgentOptions adds the guardrail and memory settings of config to the
+// template data of a CDK agent construct.
+func addAgentOptions(data map[string]interface{}, config *AgentCoreConfig) {
+	if config == nil {
+		return
+	}
+	if config.GuardrailID != "" {
+		g := guardrail{ID: escapeSingleQuoted(config.GuardrailID), Version: escapeSingleQuoted(config.GuardrailVersion)}
+		if strings.HasPrefix(config.GuardrailID, "arn:") {
+			g.Resource = "'" + g.ID + "'"
+		} else {
+			g.Resource = "`arn:aws:bedrock:${cdk.Stack.of(this).region}:${cdk.Stack.of(this).account}:guardrail/" + escapeString(config.GuardrailID) + "`"
+		}
+		data["Guardrail"] = g
+	}
+	if days := config.Memory.storageDays(); days > 0 {
+		data["MemoryStorageDays"] = days
+	}
+}
+
+// storageDays returns the days Bedrock keeps session summaries, or 0 if
+// memory is disabled.
+func (m *MemoryConfig) storageDays() int {
+	if m == nil || !m.Enabled {
+		return 0
+	}
+	if m.TTLDays == 0 {
+		return DefaultMemoryTTLDays
+	}
+	return m.TTLDays
+}
@@ -0,0 +1,92 @@
+package awsagentcore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestAgentCoreConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  AgentCoreConfig
+		wantErr string
+	}{
+		{"empty", AgentCoreConfig{}, ""},
+		{"guardrail", AgentCoreConfig{GuardrailID: "gr-123", GuardrailVersion: "2"}, ""},
+		{"guardrail without version", AgentCoreConfig{GuardrailID: "gr-123"}, "GuardrailVersion is required"},
+		{"version without guardrail", AgentCoreConfig{GuardrailVersion: "2"}, "without GuardrailID"},
+		{"default memory TTL", AgentCoreConfig{Memory: &MemoryConfig{Enabled: true}}, ""},
+		{"memory TTL too long", AgentCoreConfig{Memory: &MemoryConfig{Enabled: true, TTLDays: 400}}, "between 1 and 365"},
+		{"negative memory TTL", AgentCoreConfig{Memory: &MemoryConfig{TTLDays: -1}}, "between 1 and 365"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGuardrailAndMemory(t *testing.T) {
+	config := &AgentCoreConfig{
+		GuardrailID:      "gr-123",
+		GuardrailVersion: "2",
+		Memory:           &MemoryConfig{Enabled: true},
+	}
+	agents := []*core.Agent{testAgent()}
+
+	terraform, err := GenerateTerraformModule("stats-team", agents, config)
+	if err != nil {
+		t.Fatalf("GenerateTerraformModule() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		gen  func() ([]byte, error)
+		want []string
+	}{
+		{"aws-agentcore", func() ([]byte, error) { return generateAgentConstructWithConfig(testAgent(), "stats-team", config) }, []string{
+			"guardrailIdentifier: 'gr-123',\n        guardrailVersion: '2',",
+			"enabledMemoryTypes: ['SESSION_SUMMARY'],\n        storageDays: 30,",
+		}},
+		{"bedrock-agents", func() ([]byte, error) { return generateBedrockAgentConstruct(testAgent(), "stats-team", config) }, []string{
+			"actions: ['bedrock:ApplyGuardrail'],\n      resources: [`arn:aws:bedrock:${cdk.Stack.of(this).region}:${cdk.Stack.of(this).account}:guardrail/gr-123`],",
+			"guardrailIdentifier: 'gr-123',",
+			"storageDays: 30,",
+		}},
+		{"terraform agents", func() ([]byte, error) { return terraform["agents.tf"], nil }, []string{
+			"guardrail_identifier = \"gr-123\"\n    guardrail_version    = \"2\"",
+			"storage_days         = 30",
+		}},
+		{"terraform main", func() ([]byte, error) { return terraform["main.tf"], nil }, []string{
+			`Resource = "arn:${data.aws_partition.current.partition}:bedrock:${data.aws_region.current.name}:${data.aws_caller_identity.current.account_id}:guardrail/gr-123"`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.gen()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(got), want) {
+					t.Errorf("output missing %q:\n%s", want, got)
+				}
+			}
+		})
+	}
+
+	config.GuardrailVersion = ""
+	if err := WriteCDKProject("stats-team", agents, t.TempDir(), config); err == nil {
+		t.Error("WriteCDKProject() accepted a guardrail without a version")
+	}
+}
@@ -0,0 +1,61 @@
+package awsagentcore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// SecretsManagerResolver resolves secret:// references from AWS Secrets
+// Manager. It shells out to the AWS CLI so the module does not depend on
+// the AWS SDK; credentials come from the usual AWS CLI configuration.
+type SecretsManagerResolver struct {
+	// Region overrides the CLI's default region when set.
+	Region string
+
+	// run executes the AWS CLI. Tests replace it.
+	run func(ctx context.Context, args ...string) ([]byte, error)
+}
+
+// NewSecretsManagerResolver creates a resolver for the given region.
+func NewSecretsManagerResolver(region string) *SecretsManagerResolver {
+	return &SecretsManagerResolver{Region: region, run: runAWSCLI}
+}
+
+// Resolve returns the SecretString of the named secret.
+func (r *SecretsManagerResolver) Resolve(ctx context.Context, name string) (string, error) {
+	args := []string{
+		"secretsmanager", "get-secret-value",
+		"--secret-id", name,
+		"--query", "SecretString",
+		"--output", "text",
+	}
+	if r.Region != "" {
+		args = append(args, "--region", r.Region)
+	}
+
+	run := r.run
+	if run == nil {
+		run = runAWSCLI
+	}
+	out, err := run(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// runAWSCLI runs the aws command. Stdout carries the secret and is returned
+// to the caller only; stderr is included in errors for diagnostics.
+func runAWSCLI(ctx context.Context, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "aws", args...) //nolint:gosec // G204: fixed binary, arguments are not shell-interpreted
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("aws cli: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,33 @@
+package awsagentcore
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSecretsManagerResolver(t *testing.T) {
+	var gotArgs []string
+	r := &SecretsManagerResolver{
+		Region: "eu-west-1",
+		run: func(_ context.Context, args ...string) ([]byte, error) {
+			gotArgs = args
+			return []byte("s3cr3t\n"), nil
+		},
+	}
+
+	got, err := r.Resolve(context.Background(), "prod/api-key")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if got != "s3cr3t" {
+		t.Errorf("Resolve() = %q, want s3cr3t", got)
+	}
+
+	args := strings.Join(gotArgs, " ")
+	for _, want := range []string{"secretsmanager get-secret-value", "--secret-id prod/api-key", "--region eu-west-1"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("aws args %q missing %q", args, want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package awsagentcore
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// Template files looked up in AgentCoreConfig.TemplateDir. A file present
+// there replaces the built-in template of the same name; the others keep
+// their defaults. Overrides are rendered with the same data as the
+// built-ins.
+const (
+	AgentTemplateFile        = "agent.ts.tmpl"
+	BedrockAgentTemplateFile = "bedrock-agent.ts.tmpl"
+	StackTemplateFile        = "stack.ts.tmpl"
+	AppTemplateFile          = "app.ts.tmpl"
+)
+
+// requiredTemplateFields are the data fields an override must use, so the
+// generated files still fit together: constructs must be exported under the
+// class name the stack imports, and so on.
+var requiredTemplateFields = map[string][]string{
+	AgentTemplateFile:        {"Name", "NamePascal", "Instructions", "FoundationModel"},
+	BedrockAgentTemplateFile: {"Name", "NamePascal", "Instructions", "FoundationModel"},
+	StackTemplateFile:        {"TeamPascal", "Agents"},
+	AppTemplateFile:          {"TeamName", "TeamPascal", "StackName"},
+}
+
+// parseTemplate returns the template for file: the override in
+// config.TemplateDir if there is one, otherwise builtin. Overrides must
+// reference every required field, and fail on fields that do not exist.
+func parseTemplate(config *AgentCoreConfig, format, file, builtin string) (*template.Template, error) {
+	if config == nil || config.TemplateDir == "" {
+		tmpl, err := template.New(file).Parse(builtin)
+		if err != nil {
+			return nil, &core.MarshalError{Format: format, Err: err}
+		}
+		return tmpl, nil
+	}
+
+	path := filepath.Join(config.TemplateDir, file)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return parseTemplate(nil, format, file, builtin)
+	}
+	if err != nil {
+		return nil, &core.ReadError{Path: path, Err: err}
+	}
+
+	tmpl, err := template.New(file).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, &core.ParseError{Format: "template", Path: path, Err: err}
+	}
+
+	used := templateFields(tmpl)
+	var missing []string
+	for _, field := range requiredTemplateFields[file] {
+		if !used[field] {
+			missing = append(missing, "."+field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &core.ParseError{Format: "template", Path: path, Err: fmt.Errorf("override must use %s", strings.Join(missing, ", "))}
+	}
+	return tmpl, nil
+}
+
+// templateFields returns the names of the fields referenced anywhere in
+// tmpl and its associated templates, at any depth.
+func templateFields(tmpl *template.Template) map[string]bool {
+	fields := make(map[string]bool)
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.IfNode:
+			walk(&n.BranchNode)
+		case *parse.RangeNode:
+			walk(&n.BranchNode)
+		case *parse.WithNode:
+			walk(&n.BranchNode)
+		case *parse.BranchNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.TemplateNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.ChainNode:
+			walk(n.Node)
+		case *parse.FieldNode:
+			fields[n.Ident[0]] = true
+		case *parse.VariableNode:
+			if len(n.Ident) > 1 && n.Ident[0] == "$" {
+				fields[n.Ident[1]] = true
+			}
+		}
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			walk(t.Tree.Root)
+		}
+	}
+	return fields
+}
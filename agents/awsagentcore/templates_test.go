@@ -0,0 +1,68 @@
+package awsagentcore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestTemplateOverrides(t *testing.T) {
+	templates := t.TempDir()
+	override := "// house style\nexport class {{.NamePascal}}Agent {} // {{.Name}} {{.FoundationModel}} {{len .Instructions}}\n"
+	if err := os.WriteFile(filepath.Join(templates, AgentTemplateFile), []byte(override), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultAgentCoreConfig()
+	config.TemplateDir = templates
+	out := t.TempDir()
+	if err := WriteCDKProject("stats-team", []*core.Agent{testAgent()}, out, config); err != nil {
+		t.Fatalf("WriteCDKProject() error = %v", err)
+	}
+
+	agentTS, err := os.ReadFile(filepath.Join(out, "lib", "agents", "data-analyst.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(agentTS), "// house style\nexport class DataAnalystAgent {}") {
+		t.Errorf("agent construct should use the override:\n%s", agentTS)
+	}
+
+	stackTS, err := os.ReadFile(filepath.Join(out, "lib", "stats-team-stack.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(stackTS), "export class StatsTeamStack extends cdk.Stack") {
+		t.Errorf("stack should use the built-in template:\n%s", stackTS)
+	}
+}
+
+func TestTemplateOverrideValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"missing required field", "export class {{.NamePascal}}Agent {}", ".Name, .Instructions, .FoundationModel"},
+		{"unknown field", "{{.Name}} {{.NamePascal}} {{.Instructions}} {{.FoundationModel}} {{.VpcId}}", "VpcId"},
+		{"syntax error", "{{.Name", "template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultAgentCoreConfig()
+			config.TemplateDir = t.TempDir()
+			if err := os.WriteFile(filepath.Join(config.TemplateDir, AgentTemplateFile), []byte(tt.template), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := generateAgentConstructWithConfig(testAgent(), "", config)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,409 @@
+package awsagentcore
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// TerraformFiles are the files WriteTerraformModule writes, in order.
+var TerraformFiles = []string{"versions.tf", "variables.tf", "main.tf", "agents.tf", "outputs.tf"}
+
+// terraformAgent is the template data of one agent in agents.tf.
+type terraformAgent struct {
+	Name            string
+	Key             string // Quoted name, padded to align the output maps
+	Resource        string // Terraform resource name
+	Description     string // Quoted HCL string
+	Instructions    string // Heredoc or quoted HCL string
+	FoundationModel string
+	ActionGroups    []actionGroup
+	ResourceAttrs   []resourceAttribute
+}
+
+// WriteTerraformModule writes a Terraform module deploying agents as
+// Bedrock Agents, the Terraform counterpart of WriteBedrockAgentProject:
+// one aws_bedrockagent_agent per agent with RETURN_CONTROL action group
+// stubs and a "live" alias, sharing an IAM role. config supplies the
+// defaults of the region and foundation_model variables; tool analytics are
+// not supported.
+func WriteTerraformModule(teamName string, agents []*core.Agent, outputDir string, config *AgentCoreConfig) error {
+	if config == nil {
+		// Not DefaultAgentCoreConfig: its foundation model would override
+		// every agent's own model
+		config = &AgentCoreConfig{}
+	}
+	if config.ToolAnalytics != "" {
+		return &core.MarshalError{Format: "terraform", Err: fmt.Errorf("tool analytics are not supported for Terraform modules")}
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if err := core.CheckUniqueOutputNames(agents); err != nil {
+		return err
+	}
+
+	files, err := GenerateTerraformModule(teamName, agents, config)
+	if err != nil {
+		return err
+	}
+	for _, name := range TerraformFiles {
+		if err := core.WriteOutputFile(filepath.Join(outputDir, name), files[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateTerraformModule renders the files of WriteTerraformModule, keyed
+// by file name.
+func GenerateTerraformModule(teamName string, agents []*core.Agent, config *AgentCoreConfig) (map[string][]byte, error) {
+	region := "null" // Provider default (AWS_REGION, profile)
+	if config.Region != "" {
+		region = hclQuote(config.Region)
+	}
+	data := map[string]interface{}{
+		"TeamName":        hclQuote(teamName),
+		"Region":          region,
+		"FoundationModel": hclQuote(config.FoundationModel),
+	}
+	if config.GuardrailID != "" {
+		g := guardrail{ID: hclQuote(config.GuardrailID), Version: hclQuote(config.GuardrailVersion), Resource: hclQuote(config.GuardrailID)}
+		if !strings.HasPrefix(config.GuardrailID, "arn:") {
+			g.Resource = `"arn:${data.aws_partition.current.partition}:bedrock:${data.aws_region.current.name}:${data.aws_caller_identity.current.account_id}:guardrail/` + strings.Trim(g.ID, `"`) + `"`
+		}
+		data["Guardrail"] = g
+	}
+	if days := config.Memory.storageDays(); days > 0 {
+		data["MemoryStorageDays"] = days
+	}
+
+	var tfAgents []terraformAgent
+	resources := make(map[string]string, len(agents))
+	for _, agent := range agents {
+		resource := terraformIdentifier(agent.Name)
+		if other, ok := resources[resource]; ok {
+			return nil, &core.MarshalError{Format: "terraform", Err: fmt.Errorf("agents %s and %s map to the same resource name %s", other, agent.Name, resource)}
+		}
+		resources[resource] = agent.Name
+
+		var attrs []resourceAttribute
+		if len(config.ResourceAttributes) > 0 {
+			values, err := core.ResourceAttributes(agent, teamName, config.ResourceAttributes)
+			if err != nil {
+				return nil, &core.MarshalError{Format: "terraform", Err: err}
+			}
+			sorted := sortedResourceAttributes(values)
+			keys := make([]string, len(sorted))
+			for i, attr := range sorted {
+				keys[i] = hclQuote(attr.Key)
+			}
+			for i, key := range alignKeys(keys) {
+				attrs = append(attrs, resourceAttribute{Key: key, Value: hclQuote(values[sorted[i].Key])})
+			}
+		}
+
+		groups := getActionGroups(agent.Tools)
+		for i := range groups {
+			groups[i].Description = hclQuote(groups[i].Description)
+			params := make([]actionParameter, len(groups[i].Parameters))
+			for j, param := range groups[i].Parameters {
+				param.Description = hclQuote(param.Description)
+				params[j] = param
+			}
+			groups[i].Parameters = params
+		}
+
+		tfAgents = append(tfAgents, terraformAgent{
+			Name:            agent.Name,
+			Resource:        resource,
+			Description:     hclQuote(core.FormatDescriptionLine("terraform", agent.Description)),
+			Instructions:    hclText(agent.Instructions),
+			FoundationModel: getFoundationModel(agent.PrimaryModel()),
+			ActionGroups:    groups,
+			ResourceAttrs:   attrs,
+		})
+	}
+	keys := make([]string, len(tfAgents))
+	for i, agent := range tfAgents {
+		keys[i] = hclQuote(agent.Name)
+	}
+	for i, key := range alignKeys(keys) {
+		tfAgents[i].Key = key
+	}
+	data["Agents"] = tfAgents
+
+	files := make(map[string][]byte, len(TerraformFiles))
+	for _, name := range TerraformFiles {
+		tmpl, err := template.New(name).Parse(terraformTemplates[name])
+		if err != nil {
+			return nil, &core.MarshalError{Format: "terraform", Err: err}
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, &core.MarshalError{Format: "terraform", Err: err}
+		}
+		files[name] = buf.Bytes()
+	}
+	return files, nil
+}
+
+// terraformIdentifier converts an agent name to a Terraform resource name:
+// characters other than letters, digits and underscores become
+// underscores, and names not starting with a letter get an "agent_" prefix.
+func terraformIdentifier(name string) string {
+	id := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+	if id == "" || !(id[0] >= 'a' && id[0] <= 'z' || id[0] >= 'A' && id[0] <= 'Z') {
+		id = "agent_" + id
+	}
+	return id
+}
+
+// alignKeys pads keys to a common width, aligning the equals signs of a map
+// the way terraform fmt does.
+func alignKeys(keys []string) []string {
+	width := 0
+	for _, key := range keys {
+		width = max(width, len(key))
+	}
+	aligned := make([]string, len(keys))
+	for i, key := range keys {
+		aligned[i] = key + strings.Repeat(" ", width-len(key))
+	}
+	return aligned
+}
+
+// hclQuote returns s as a quoted HCL string, escaping interpolation and
+// template directives.
+func hclQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "${", "$${", "%{", "%%{")
+	return `"` + r.Replace(s) + `"`
+}
+
+// hclText returns s as an HCL heredoc, or as a quoted string if a line of s
+// would end the heredoc.
+func hclText(s string) string {
+	s = strings.TrimRight(s, "\n")
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) == "EOT" {
+			return hclQuote(s)
+		}
+	}
+	r := strings.NewReplacer("${", "$${", "%{", "%%{")
+	return "<<EOT\n" + r.Replace(s) + "\nEOT"
+}
+
+var terraformTemplates = map[string]string{
+	"versions.tf": `terraform {
+  required_version = ">= 1.5"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = ">= 5.70"
+    }
+  }
+}
+`,
+
+	"variables.tf": `variable "region" {
+  description = "AWS region to deploy the agents to; null uses the provider's default"
+  type        = string
+  default     = {{.Region}}
+}
+
+variable "foundation_model" {
+  description = "Foundation model for every agent; empty uses each agent's own model"
+  type        = string
+  default     = {{.FoundationModel}}
+}
+
+variable "name_prefix" {
+  description = "Prefix of the agent and IAM role names"
+  type        = string
+  default     = {{.TeamName}}
+}
+
+variable "tags" {
+  description = "Tags applied to every resource"
+  type        = map(string)
+  default     = {}
+}
+`,
+
+	"main.tf": `provider "aws" {
+  region = var.region
+
+  default_tags {
+    tags = var.tags
+  }
+}
+
+data "aws_caller_identity" "current" {}
+
+data "aws_partition" "current" {}
+
+data "aws_region" "current" {}
+
+# IAM role shared by the agents
+resource "aws_iam_role" "agent" {
+  name = "${var.name_prefix}-bedrock-agent"
+
+  assume_role_policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [{
+      Effect    = "Allow"
+      Action    = "sts:AssumeRole"
+      Principal = { Service = "bedrock.amazonaws.com" }
+      Condition = {
+        StringEquals = { "aws:SourceAccount" = data.aws_caller_identity.current.account_id }
+      }
+    }]
+  })
+}
+
+resource "aws_iam_role_policy" "invoke_model" {
+  name = "invoke-model"
+  role = aws_iam_role.agent.id
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [{
+      Effect   = "Allow"
+      Action   = "bedrock:InvokeModel"
+      Resource = "arn:${data.aws_partition.current.partition}:bedrock:${data.aws_region.current.name}::foundation-model/*"
+    }]
+  })
+}
+{{- with .Guardrail}}
+
+resource "aws_iam_role_policy" "apply_guardrail" {
+  name = "apply-guardrail"
+  role = aws_iam_role.agent.id
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [{
+      Effect   = "Allow"
+      Action   = "bedrock:ApplyGuardrail"
+      Resource = {{.Resource}}
+    }]
+  })
+}
+{{- end}}
+`,
+
+	"agents.tf": `{{- range $i, $agent := .Agents}}
+{{- if $i}}
+
+{{end -}}
+# Agent: {{.Name}}
+resource "aws_bedrockagent_agent" "{{.Resource}}" {
+  agent_name                  = "${var.name_prefix}-{{.Name}}"
+  description                 = {{.Description}}
+  agent_resource_role_arn     = aws_iam_role.agent.arn
+  foundation_model            = var.foundation_model != "" ? var.foundation_model : "{{.FoundationModel}}"
+  idle_session_ttl_in_seconds = 600
+  prepare_agent               = true
+{{- with $.Guardrail}}
+
+  guardrail_configuration {
+    guardrail_identifier = {{.ID}}
+    guardrail_version    = {{.Version}}
+  }
+{{- end}}
+{{- with $.MemoryStorageDays}}
+
+  memory_configuration {
+    enabled_memory_types = ["SESSION_SUMMARY"]
+    storage_days         = {{.}}
+  }
+{{- end}}
+{{- if .ResourceAttrs}}
+
+  tags = {
+{{- range .ResourceAttrs}}
+    {{.Key}} = {{.Value}}
+{{- end}}
+  }
+{{- end}}
+
+  instruction = {{.Instructions}}
+}
+{{- range .ActionGroups}}
+
+# Action group stub: returns control to the invoking application, which
+# performs the tool call and sends back the result.
+resource "aws_bedrockagent_agent_action_group" "{{$agent.Resource}}_{{.Name}}" {
+  agent_id          = aws_bedrockagent_agent.{{$agent.Resource}}.agent_id
+  agent_version     = "DRAFT"
+  action_group_name = "{{.Name}}"
+  description       = {{.Description}}
+
+  action_group_executor {
+    custom_control = "RETURN_CONTROL"
+  }
+
+  function_schema {
+    member_functions {
+      functions {
+        name        = "{{.Name}}"
+        description = {{.Description}}
+{{- range .Parameters}}
+
+        parameters {
+          map_block_key = "{{.Name}}"
+          type          = "{{.Type}}"
+          description   = {{.Description}}
+          required      = {{.Required}}
+        }
+{{- end}}
+      }
+    }
+  }
+}
+{{- end}}
+
+resource "aws_bedrockagent_agent_alias" "{{.Resource}}" {
+  agent_alias_name = "live"
+  agent_id         = aws_bedrockagent_agent.{{.Resource}}.agent_id
+{{- if .ActionGroups}}
+
+  depends_on = [
+{{- range .ActionGroups}}
+    aws_bedrockagent_agent_action_group.{{$agent.Resource}}_{{.Name}},
+{{- end}}
+  ]
+{{- end}}
+}
+{{- end}}
+`,
+
+	"outputs.tf": `output "agent_ids" {
+  description = "Agent IDs by agent name"
+  value = {
+{{- range .Agents}}
+    {{.Key}} = aws_bedrockagent_agent.{{.Resource}}.agent_id
+{{- end}}
+  }
+}
+
+output "agent_alias_arns" {
+  description = "ARNs of the live aliases by agent name"
+  value = {
+{{- range .Agents}}
+    {{.Key}} = aws_bedrockagent_agent_alias.{{.Resource}}.agent_alias_arn
+{{- end}}
+  }
+}
+`,
+}
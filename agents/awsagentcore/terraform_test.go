@@ -0,0 +1,112 @@
+package awsagentcore
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestTerraformGolden(t *testing.T) {
+	reviewer := core.NewAgent("code-reviewer", "Reviews ${changes}").
+		WithModel(core.ModelHaiku).
+		WithInstructions("Review the diff.\nQuote %{ verbatim.")
+	config := &AgentCoreConfig{
+		Region:             "us-west-2",
+		ResourceAttributes: []string{core.AttrServiceName, core.AttrTeam},
+	}
+
+	files, err := GenerateTerraformModule("stats-team", []*core.Agent{testAgent(), reviewer}, config)
+	if err != nil {
+		t.Fatalf("GenerateTerraformModule() error = %v", err)
+	}
+
+	for _, name := range TerraformFiles {
+		golden := filepath.Join("testdata", "terraform", name+".golden")
+		if *update {
+			if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(golden, files[name], 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(files[name], want) {
+			t.Errorf("%s differs from %s (run with -update to accept):\n%s", name, golden, files[name])
+		}
+	}
+}
+
+func TestWriteTerraformModule(t *testing.T) {
+	dir := t.TempDir()
+	if err := WriteTerraformModule("stats-team", []*core.Agent{testAgent()}, dir, nil); err != nil {
+		t.Fatalf("WriteTerraformModule() error = %v", err)
+	}
+	for _, name := range TerraformFiles {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("missing %s: %v", name, err)
+		}
+	}
+
+	variables, err := os.ReadFile(filepath.Join(dir, "variables.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(variables), "default     = null") {
+		t.Errorf("region should default to null without a configured region:\n%s", variables)
+	}
+}
+
+func TestWriteTerraformModuleErrors(t *testing.T) {
+	analytics := &AgentCoreConfig{ToolAnalytics: "cloudwatch"}
+
+	tests := []struct {
+		name   string
+		agents []*core.Agent
+		config *AgentCoreConfig
+		want   string
+	}{
+		{"tool analytics", []*core.Agent{testAgent()}, analytics, "tool analytics"},
+		{"resource collision", []*core.Agent{
+			core.NewAgent("data.analyst", "A").WithInstructions("A."),
+			core.NewAgent("data-analyst", "B").WithInstructions("B."),
+		}, nil, "same resource name data_analyst"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := WriteTerraformModule("team", tt.agents, t.TempDir(), tt.config)
+			var me *core.MarshalError
+			if !errors.As(err, &me) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("WriteTerraformModule() error = %v, want MarshalError containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHCLEscaping(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"quote", hclQuote("say \"hi\"\n${var.x} %{if}"), `"say \"hi\"\n$${var.x} %%{if}"`},
+		{"identifier", terraformIdentifier("1st.agent"), "agent_1st_agent"},
+		{"heredoc", hclText("Line ${one}\n"), "<<EOT\nLine $${one}\nEOT"},
+		{"heredoc terminator", hclText("a\nEOT\nb"), `"a\nEOT\nb"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/agentplexus/assistantkit/agents/core"
@@ -33,16 +34,34 @@ func (a *Adapter) DefaultDir() string {
 	return "agents"
 }
 
+// LossyFields returns the canonical fields Claude agent files do not hold.
+func (a *Adapter) LossyFields() []string {
+	return []string{"ModelFallback", "MaxTurns", "Timeouts", "Retry", "AllowedTools", "Requires", "Arguments", "Triggers"}
+}
+
+// frontmatterKeys are the frontmatter keys Parse maps to Agent fields.
+// Other keys are metadata.
+var frontmatterKeys = map[string]bool{
+	"name":         true,
+	"description":  true,
+	"version":      true,
+	"model":        true,
+	"tools":        true,
+	"skills":       true,
+	"dependencies": true,
+}
+
 // Parse converts Claude agent Markdown bytes to canonical Agent.
 func (a *Adapter) Parse(data []byte) (*core.Agent, error) {
 	frontmatter, body := parseFrontmatter(data)
 
-	agent := &core.Agent{
+	agent := &core.Agent{Spec: core.Spec{
 		Name:         frontmatter["name"],
 		Description:  frontmatter["description"],
 		Model:        core.Model(frontmatter["model"]),
 		Instructions: strings.TrimSpace(body),
-	}
+	}}
+	agent.Version = frontmatter["version"]
 
 	// Parse tools if present
 	if tools, ok := frontmatter["tools"]; ok {
@@ -59,6 +78,17 @@ func (a *Adapter) Parse(data []byte) (*core.Agent, error) {
 		agent.Dependencies = parseList(deps)
 	}
 
+	// Keep any other keys as metadata
+	for key, value := range frontmatter {
+		if frontmatterKeys[key] {
+			continue
+		}
+		if agent.Metadata == nil {
+			agent.Metadata = make(map[string]string)
+		}
+		agent.Metadata[key] = value
+	}
+
 	return agent, nil
 }
 
@@ -69,10 +99,14 @@ func (a *Adapter) Marshal(agent *core.Agent) ([]byte, error) {
 	// Write YAML frontmatter
 	buf.WriteString("---\n")
 	buf.WriteString(fmt.Sprintf("name: %s\n", agent.Name))
-	buf.WriteString(fmt.Sprintf("description: %s\n", agent.Description))
+	buf.WriteString(fmt.Sprintf("description: %s\n", core.FormatDescriptionLine(a.Name(), agent.Description)))
 
-	if agent.Model != "" {
-		buf.WriteString(fmt.Sprintf("model: %s\n", agent.Model))
+	if agent.Version != "" {
+		buf.WriteString(fmt.Sprintf("version: %s\n", agent.Version))
+	}
+
+	if model := agent.PrimaryModel(); model != "" {
+		buf.WriteString(fmt.Sprintf("model: %s\n", model))
 	}
 
 	if len(agent.Tools) > 0 {
@@ -87,6 +121,18 @@ func (a *Adapter) Marshal(agent *core.Agent) ([]byte, error) {
 		buf.WriteString(fmt.Sprintf("dependencies: [%s]\n", strings.Join(agent.Dependencies, ", ")))
 	}
 
+	// Metadata follows as extra frontmatter keys, which Claude Code ignores
+	keys := make([]string, 0, len(agent.Metadata))
+	for key := range agent.Metadata {
+		if !frontmatterKeys[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		buf.WriteString(fmt.Sprintf("%s: %s\n", key, agent.Metadata[key]))
+	}
+
 	buf.WriteString("---\n\n")
 
 	// Write instructions directly (they already contain markdown formatting)
@@ -129,16 +175,7 @@ func (a *Adapter) WriteFile(agent *core.Agent, path string) error {
 		return err
 	}
 
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, core.DefaultDirMode); err != nil {
-		return &core.WriteError{Path: path, Err: err}
-	}
-
-	if err := os.WriteFile(path, data, core.DefaultFileMode); err != nil {
-		return &core.WriteError{Path: path, Err: err}
-	}
-
-	return nil
+	return core.WriteOutputFile(path, data)
 }
 
 // parseFrontmatter extracts YAML frontmatter and body from Markdown.
@@ -166,8 +203,10 @@ func parseFrontmatter(data []byte) (map[string]string, string) {
 		if idx > 0 {
 			key := strings.TrimSpace(line[:idx])
 			value := strings.TrimSpace(line[idx+1:])
-			// Remove quotes if present
-			value = strings.Trim(value, "\"'")
+			// Remove enclosing quotes if present
+			if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+				value = value[1 : len(value)-1]
+			}
 			frontmatter[key] = value
 		}
 	}
@@ -0,0 +1,35 @@
+package claude
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestMetadataRoundTrip(t *testing.T) {
+	adapter := &Adapter{}
+	agent := core.NewAgent("reviewer", "Reviews code").WithTools("Read", "Grep")
+	agent.Metadata = map[string]string{"owner": "alice", "team": "quality", "model": "ignored"}
+
+	data, err := adapter.Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), "owner: alice\nteam: quality\n---") {
+		t.Errorf("metadata missing from frontmatter:\n%s", data)
+	}
+
+	parsed, err := adapter.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := map[string]string{"owner": "alice", "team": "quality"}
+	if !reflect.DeepEqual(parsed.Metadata, want) {
+		t.Errorf("Metadata = %v, want %v", parsed.Metadata, want)
+	}
+	if parsed.Model != "sonnet" {
+		t.Errorf("Model = %q, metadata must not override it", parsed.Model)
+	}
+}
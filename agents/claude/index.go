@@ -0,0 +1,50 @@
+package claude
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// IndexFile is the name of the index WriteIndex is conventionally given,
+// written alongside the agent files.
+const IndexFile = "CLAUDE.md"
+
+// indexCellReplacer escapes text for a Markdown table cell.
+var indexCellReplacer = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ")
+
+// GenerateIndex returns a Markdown index of agents: a table of each
+// agent's name, linked to its file, description and tools, sorted by name
+// so that adding an agent changes one row.
+func GenerateIndex(agents []*core.Agent) []byte {
+	sorted := make([]*core.Agent, len(agents))
+	copy(sorted, agents)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	adapter := &Adapter{}
+	var buf bytes.Buffer
+	buf.WriteString("# Agents\n\n")
+	buf.WriteString("| Agent | Description | Tools |\n")
+	buf.WriteString("|-------|-------------|-------|\n")
+	for _, agent := range sorted {
+		link := filepath.ToSlash(core.AgentPath(adapter, agent))
+		buf.WriteString(fmt.Sprintf("| [%s](%s) | %s | %s |\n",
+			agent.Name, link,
+			indexCellReplacer.Replace(agent.Description),
+			indexCellReplacer.Replace(strings.Join(agent.Tools, ", "))))
+	}
+	return buf.Bytes()
+}
+
+// WriteIndex writes the GenerateIndex index of agents to path. Agent names
+// must be unique, since each names a file.
+func WriteIndex(agents []*core.Agent, path string) error {
+	if err := core.CheckUniqueOutputNames(agents); err != nil {
+		return err
+	}
+	return core.WriteOutputFile(path, GenerateIndex(agents))
+}
@@ -0,0 +1,41 @@
+package claude
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestWriteIndex(t *testing.T) {
+	agents := []*core.Agent{
+		core.NewAgent("reviewer", "Reviews code | diffs\nand docs").WithTools("Read", "Grep"),
+		core.NewAgent("planner", "Plans work"),
+	}
+	path := filepath.Join(t.TempDir(), IndexFile)
+	if err := WriteIndex(agents, path); err != nil {
+		t.Fatalf("WriteIndex() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Agents
+
+| Agent | Description | Tools |
+|-------|-------------|-------|
+| [planner](planner.md) | Plans work |  |
+| [reviewer](reviewer.md) | Reviews code \| diffs and docs | Read, Grep |
+`
+	if string(data) != want {
+		t.Errorf("index =\n%s\nwant\n%s", data, want)
+	}
+
+	var dupErr *core.DuplicateNameError
+	if err := WriteIndex(append(agents, core.NewAgent("planner", "Plans more")), path); !errors.As(err, &dupErr) {
+		t.Errorf("WriteIndex() error = %v, want DuplicateNameError", err)
+	}
+}
@@ -33,16 +33,22 @@ func (a *Adapter) DefaultDir() string {
 	return "agents"
 }
 
+// LossyFields returns the canonical fields Codex agent files do not hold.
+func (a *Adapter) LossyFields() []string {
+	return []string{"ModelFallback", "MaxTurns", "Timeouts", "Retry", "AllowedTools", "Requires", "Arguments", "Triggers"}
+}
+
 // Parse converts Codex agent Markdown bytes to canonical Agent.
 func (a *Adapter) Parse(data []byte) (*core.Agent, error) {
 	frontmatter, body := parseFrontmatter(data)
 
-	agent := &core.Agent{
+	agent := &core.Agent{Spec: core.Spec{
 		Name:         frontmatter["name"],
 		Description:  frontmatter["description"],
 		Model:        mapCodexModelToCanonical(frontmatter["model"]),
 		Instructions: strings.TrimSpace(body),
-	}
+	}}
+	agent.Version = frontmatter["version"]
 
 	// Parse tools if present
 	if tools, ok := frontmatter["tools"]; ok {
@@ -69,10 +75,14 @@ func (a *Adapter) Marshal(agent *core.Agent) ([]byte, error) {
 	// Write YAML frontmatter
 	buf.WriteString("---\n")
 	buf.WriteString(fmt.Sprintf("name: %s\n", agent.Name))
-	buf.WriteString(fmt.Sprintf("description: %s\n", agent.Description))
+	buf.WriteString(fmt.Sprintf("description: %s\n", core.FormatDescriptionLine(a.Name(), agent.Description)))
+
+	if agent.Version != "" {
+		buf.WriteString(fmt.Sprintf("version: %s\n", agent.Version))
+	}
 
-	if agent.Model != "" {
-		buf.WriteString(fmt.Sprintf("model: %s\n", mapCanonicalModelToCodex(agent.Model)))
+	if model := agent.PrimaryModel(); model != "" {
+		buf.WriteString(fmt.Sprintf("model: %s\n", mapCanonicalModelToCodex(model)))
 	}
 
 	if len(agent.Tools) > 0 {
@@ -129,16 +139,7 @@ func (a *Adapter) WriteFile(agent *core.Agent, path string) error {
 		return err
 	}
 
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, core.DefaultDirMode); err != nil {
-		return &core.WriteError{Path: path, Err: err}
-	}
-
-	if err := os.WriteFile(path, data, core.DefaultFileMode); err != nil {
-		return &core.WriteError{Path: path, Err: err}
-	}
-
-	return nil
+	return core.WriteOutputFile(path, data)
 }
 
 // parseFrontmatter extracts YAML frontmatter and body from Markdown.
@@ -166,8 +167,10 @@ func parseFrontmatter(data []byte) (map[string]string, string) {
 		if idx > 0 {
 			key := strings.TrimSpace(line[:idx])
 			value := strings.TrimSpace(line[idx+1:])
-			// Remove quotes if present
-			value = strings.Trim(value, "\"'")
+			// Remove enclosing quotes if present
+			if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+				value = value[1 : len(value)-1]
+			}
 			frontmatter[key] = value
 		}
 	}
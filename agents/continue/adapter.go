@@ -0,0 +1,258 @@
+// Package continuedev provides the Continue (continue.dev) assistant
+// adapter. The package is not named continue, which is a Go keyword.
+package continuedev
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+const (
+	// SchemaVersion is the Continue config.yaml schema generated.
+	SchemaVersion = "v1"
+
+	// DefaultVersion is the version given to generated assistants whose
+	// agent has no Version, and to full configs.
+	DefaultVersion = "1.0.0"
+
+	// DefaultProvider is the model provider of Claude models, and of
+	// models given without a "provider/" prefix.
+	DefaultProvider = "anthropic"
+)
+
+// Roles are the Continue roles a generated model takes.
+var Roles = []string{"chat", "edit", "apply"}
+
+func init() {
+	core.Register(&Adapter{})
+}
+
+// Adapter converts between canonical Agent and a Continue assistant
+// (.continue/assistants/<name>.yaml): a config.yaml holding one chat model
+// whose system message is the agent's instructions. WriteFullConfig
+// groups a whole team into one config.yaml, one model per agent.
+type Adapter struct{}
+
+// Name returns the adapter identifier.
+func (a *Adapter) Name() string {
+	return "continue"
+}
+
+// FileExtension returns the file extension for Continue assistants.
+func (a *Adapter) FileExtension() string {
+	return ".yaml"
+}
+
+// DefaultDir returns the default directory name for Continue assistants.
+func (a *Adapter) DefaultDir() string {
+	return ".continue/assistants"
+}
+
+// LossyFields returns the canonical fields a Continue assistant does not
+// hold. Continue has no per-assistant tool list.
+func (a *Adapter) LossyFields() []string {
+	return []string{"Tools", "AllowedTools", "ModelFallback", "MaxTurns", "Timeouts", "Retry", "Skills", "Dependencies", "Requires", "Arguments", "Triggers"}
+}
+
+// Config is a Continue config.yaml.
+type Config struct {
+	Name    string  `yaml:"name"`
+	Version string  `yaml:"version"`
+	Schema  string  `yaml:"schema"`
+	Models  []Model `yaml:"models,omitempty"`
+}
+
+// Model is a model block of a Continue config.
+type Model struct {
+	Name        string       `yaml:"name"`
+	Provider    string       `yaml:"provider"`
+	Model       string       `yaml:"model"`
+	Roles       []string     `yaml:"roles,omitempty"`
+	ChatOptions *ChatOptions `yaml:"chatOptions,omitempty"`
+}
+
+// ChatOptions holds the chat settings of a model.
+type ChatOptions struct {
+	BaseSystemMessage string `yaml:"baseSystemMessage,omitempty"`
+}
+
+// Parse converts Continue config.yaml bytes to canonical Agent, taken from
+// the config's first model. The description is read from the config's
+// head comment.
+func (a *Adapter) Parse(data []byte) (*core.Agent, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, &core.ParseError{Format: "continue", Err: err}
+	}
+
+	agent := &core.Agent{Spec: core.Spec{Name: cfg.Name, Description: headComment(data)}}
+	if cfg.Version != DefaultVersion {
+		agent.Version = cfg.Version
+	}
+	if len(cfg.Models) > 0 {
+		agent.Model = mapContinueModelToCanonical(cfg.Models[0])
+		if opts := cfg.Models[0].ChatOptions; opts != nil {
+			agent.Instructions = opts.BaseSystemMessage
+		}
+	}
+	return agent, nil
+}
+
+// Marshal converts canonical Agent to Continue config.yaml bytes. Continue
+// has no description field, so the description is written as the config's
+// head comment.
+func (a *Adapter) Marshal(agent *core.Agent) ([]byte, error) {
+	version := agent.Version
+	if version == "" {
+		version = DefaultVersion
+	}
+	return marshalConfig(&Config{
+		Name:    agent.Name,
+		Version: version,
+		Schema:  SchemaVersion,
+		Models:  []Model{agentToModel(agent)},
+	}, agent.Description)
+}
+
+// ReadFile reads a Continue config.yaml and returns canonical Agent.
+func (a *Adapter) ReadFile(path string) (*core.Agent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &core.ReadError{Path: path, Err: err}
+	}
+
+	agent, err := a.Parse(data)
+	if err != nil {
+		if pe, ok := err.(*core.ParseError); ok {
+			pe.Path = path
+		}
+		return nil, err
+	}
+
+	// Infer name from filename if not set
+	if agent.Name == "" {
+		base := filepath.Base(path)
+		agent.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	return agent, nil
+}
+
+// WriteFile writes canonical Agent to a Continue config.yaml.
+func (a *Adapter) WriteFile(agent *core.Agent, path string) error {
+	data, err := a.Marshal(agent)
+	if err != nil {
+		return err
+	}
+
+	return core.WriteOutputFile(path, data)
+}
+
+// GenerateFullConfig creates one Continue config named name holding a
+// model per agent, so each agent is selectable in Continue's chat.
+func GenerateFullConfig(name string, agents []*core.Agent) *Config {
+	cfg := &Config{Name: name, Version: DefaultVersion, Schema: SchemaVersion}
+	for _, agent := range agents {
+		cfg.Models = append(cfg.Models, agentToModel(agent))
+	}
+	return cfg
+}
+
+// WriteFullConfig writes a Continue config.yaml holding every agent. Agent
+// names must be unique, since Continue lists models by name.
+func WriteFullConfig(name string, agents []*core.Agent, path string) error {
+	if err := core.CheckUniqueOutputNames(agents); err != nil {
+		return err
+	}
+	data, err := marshalConfig(GenerateFullConfig(name, agents), "")
+	if err != nil {
+		return err
+	}
+	return core.WriteOutputFile(path, data)
+}
+
+// marshalConfig encodes cfg as YAML indented by two spaces, headed by
+// comment when it is not empty.
+func marshalConfig(cfg *Config, comment string) ([]byte, error) {
+	var buf bytes.Buffer
+	if comment != "" {
+		for _, line := range strings.Split(comment, "\n") {
+			buf.WriteString(strings.TrimRight("# "+line, " ") + "\n")
+		}
+	}
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg); err != nil {
+		return nil, &core.MarshalError{Format: "continue", Err: err}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, &core.MarshalError{Format: "continue", Err: err}
+	}
+	return buf.Bytes(), nil
+}
+
+// headComment returns the comment lines directly above the first YAML
+// line of data without their comment markers. Earlier comment blocks, such
+// as a license header, end at a blank line and are skipped.
+func headComment(data []byte) string {
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" {
+			lines = nil
+			continue
+		}
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		line = strings.TrimPrefix(line, "#")
+		lines = append(lines, strings.TrimPrefix(line, " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// agentToModel returns the model block for agent.
+func agentToModel(agent *core.Agent) Model {
+	provider, model := mapCanonicalModelToContinue(agent.PrimaryModel())
+	m := Model{
+		Name:     agent.Name,
+		Provider: provider,
+		Model:    model,
+		Roles:    Roles,
+	}
+	if agent.Instructions != "" {
+		m.ChatOptions = &ChatOptions{BaseSystemMessage: agent.Instructions}
+	}
+	return m
+}
+
+// mapCanonicalModelToContinue maps a canonical model to a Continue
+// provider and model ID. Aliases resolve to Anthropic model IDs, defaulting
+// to sonnet; other models may name their provider as "provider/model".
+func mapCanonicalModelToContinue(model core.Model) (string, string) {
+	if model == "" {
+		model = core.ModelSonnet
+	}
+	if id, ok := core.ResolveModel(string(model)); ok {
+		return DefaultProvider, id
+	}
+	if provider, id, ok := strings.Cut(string(model), "/"); ok {
+		return provider, id
+	}
+	return DefaultProvider, string(model)
+}
+
+// mapContinueModelToCanonical maps a Continue model block back to a
+// canonical model.
+func mapContinueModelToCanonical(m Model) core.Model {
+	if m.Provider == DefaultProvider {
+		return core.Model(core.ModelAlias(m.Model))
+	}
+	return core.Model(m.Provider + "/" + m.Model)
+}
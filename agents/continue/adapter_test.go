@@ -0,0 +1,147 @@
+package continuedev
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestMarshal(t *testing.T) {
+	agent := core.NewAgent("reviewer", "Reviews code")
+	agent.Instructions = "Review the diff."
+
+	data, err := (&Adapter{}).Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	want := `# Reviews code
+name: reviewer
+version: 1.0.0
+schema: v1
+models:
+  - name: reviewer
+    provider: anthropic
+    model: claude-3-5-sonnet-20241022
+    roles:
+      - chat
+      - edit
+      - apply
+    chatOptions:
+      baseSystemMessage: Review the diff.
+`
+	if string(data) != want {
+		t.Errorf("Marshal() =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestModelMapping(t *testing.T) {
+	tests := []struct {
+		model        core.Model
+		wantProvider string
+		wantModel    string
+	}{
+		{"", DefaultProvider, ""},
+		{core.ModelOpus, DefaultProvider, ""},
+		{"ollama/llama3.1:8b", "ollama", "llama3.1:8b"},
+		{"gpt-4o", DefaultProvider, "gpt-4o"},
+	}
+	for _, tt := range tests {
+		provider, model := mapCanonicalModelToContinue(tt.model)
+		if provider != tt.wantProvider {
+			t.Errorf("provider of %q = %q, want %q", tt.model, provider, tt.wantProvider)
+		}
+		if tt.wantModel != "" && model != tt.wantModel {
+			t.Errorf("model of %q = %q, want %q", tt.model, model, tt.wantModel)
+		}
+		back := mapContinueModelToCanonical(Model{Provider: provider, Model: model})
+		want := tt.model
+		if want == "" {
+			want = core.ModelSonnet
+		}
+		if back != want {
+			t.Errorf("round trip of %q = %q", tt.model, back)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	adapter := &Adapter{}
+	agent := core.NewAgent("planner", "Plans work.\n\n# Not a heading")
+	agent.Model = core.ModelHaiku
+	agent.Instructions = "Plan the release.\n\nKeep it short."
+
+	data, err := adapter.Marshal(agent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := adapter.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got.Name != agent.Name || got.Description != agent.Description || got.Model != agent.Model || got.Instructions != agent.Instructions {
+		t.Errorf("Parse() = %+v, want name, description, model and instructions of %+v", got.Spec, agent.Spec)
+	}
+}
+
+func TestParseLicensedConfig(t *testing.T) {
+	adapter := &Adapter{}
+	data, err := adapter.Marshal(core.NewAgent("reviewer", "Reviews code"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data = core.AddLicenseHeader(".yaml", data, "SPDX-License-Identifier: MIT")
+
+	got, err := adapter.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got.Description != "Reviews code" {
+		t.Errorf("Description = %q, want Reviews code", got.Description)
+	}
+}
+
+func TestWriteFullConfig(t *testing.T) {
+	agents := []*core.Agent{
+		core.NewAgent("reviewer", "Reviews code"),
+		core.NewAgent("planner", "Plans work"),
+	}
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := WriteFullConfig("qa", agents, path); err != nil {
+		t.Fatalf("WriteFullConfig() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := (&Adapter{}).Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got.Name != "qa" {
+		t.Errorf("config name = %q, want qa", got.Name)
+	}
+	if n := strings.Count(string(data), "- name: "); n != 2 {
+		t.Errorf("config has %d models, want 2:\n%s", n, data)
+	}
+
+	dup := []*core.Agent{agents[0], core.NewAgent("reviewer", "Reviews docs")}
+	var dupErr *core.DuplicateNameError
+	if err := WriteFullConfig("qa", dup, path); !errors.As(err, &dupErr) {
+		t.Errorf("WriteFullConfig() error = %v, want DuplicateNameError", err)
+	}
+}
+
+func TestRegistered(t *testing.T) {
+	adapter, ok := core.GetAdapter("continue")
+	if !ok {
+		t.Fatal("continue adapter is not registered")
+	}
+	if adapter.DefaultDir() != ".continue/assistants" {
+		t.Errorf("DefaultDir() = %q", adapter.DefaultDir())
+	}
+}
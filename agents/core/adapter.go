@@ -3,15 +3,18 @@ package core
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 
 	multiagentspec "github.com/agentplexus/multi-agent-spec/sdk/go"
+	"gopkg.in/yaml.v3"
 )
 
 // DefaultFileMode is the default permission for generated files.
@@ -44,16 +47,38 @@ type Adapter interface {
 	WriteFile(agent *Agent, path string) error
 }
 
-// Registry manages adapter registration and lookup.
+// DirectoryAdapter is implemented by adapters whose output for an agent is
+// a directory of files (e.g., instructions.md and config.json) rather than
+// the single file WriteFile writes. Generators call WriteDir with
+// "<output>/<name>" instead of writing "<output>/<name><ext>".
+type DirectoryAdapter interface {
+	// WriteDir writes canonical Agent into dir, creating it if needed.
+	WriteDir(agent *Agent, dir string) error
+}
+
+// Registry manages adapter registration and lookup, along with the output
+// settings generation applies: line ending, content hash algorithm and
+// per-adapter description styles. Keeping them on a registry rather than in
+// package state lets callers, and tests, configure them independently.
 type Registry struct {
 	mu       sync.RWMutex
 	adapters map[string]Adapter
+
+	settingsMu        sync.RWMutex
+	lineEnding        LineEnding
+	hashAlgorithm     string
+	descriptionStyles map[string]DescriptionStyle
 }
 
-// NewRegistry creates a new adapter registry.
+// NewRegistry creates a new adapter registry with the default settings:
+// output written unchanged, SHA-256 content hashes and no description
+// styles.
 func NewRegistry() *Registry {
 	return &Registry{
-		adapters: make(map[string]Adapter),
+		adapters:          make(map[string]Adapter),
+		lineEnding:        LineEndingPreserve,
+		hashAlgorithm:     HashSHA256,
+		descriptionStyles: make(map[string]DescriptionStyle),
 	}
 }
 
@@ -64,16 +89,16 @@ func (r *Registry) Register(adapter Adapter) {
 	r.adapters[adapter.Name()] = adapter
 }
 
-// GetAdapter returns an adapter by name.
-func (r *Registry) GetAdapter(name string) (Adapter, bool) {
+// Get returns an adapter by name.
+func (r *Registry) Get(name string) (Adapter, bool) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	adapter, ok := r.adapters[name]
 	return adapter, ok
 }
 
-// AdapterNames returns all registered adapter names sorted alphabetically.
-func (r *Registry) AdapterNames() []string {
+// Names returns all registered adapter names sorted alphabetically.
+func (r *Registry) Names() []string {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 	names := make([]string, 0, len(r.adapters))
@@ -84,6 +109,17 @@ func (r *Registry) AdapterNames() []string {
 	return names
 }
 
+// GetAdapter returns an adapter by name. It is equivalent to Get.
+func (r *Registry) GetAdapter(name string) (Adapter, bool) {
+	return r.Get(name)
+}
+
+// AdapterNames returns all registered adapter names sorted alphabetically.
+// It is equivalent to Names.
+func (r *Registry) AdapterNames() []string {
+	return r.Names()
+}
+
 // DefaultRegistry is the global adapter registry.
 var DefaultRegistry = NewRegistry()
 
@@ -110,44 +146,42 @@ func ReadCanonicalFile(path string) (*Agent, error) {
 		return nil, &ReadError{Path: path, Err: err}
 	}
 
+	header, err := parseSpecHeader(path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var agent *Agent
+
 	// Detect format: if it starts with "---" or has .md extension, use multi-agent-spec loader
 	ext := filepath.Ext(path)
 	if ext == ".md" || (len(data) >= 3 && string(data[:3]) == "---") {
-		agent, err := multiagentspec.ParseAgentMarkdown(data)
+		agent, err = ParseMarkdownAgent(data, path)
 		if err != nil {
 			return nil, &ParseError{Format: "markdown", Path: path, Err: err}
 		}
-		// Infer name from filename if not set
-		if agent.Name == "" {
-			base := filepath.Base(path)
-			agent.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	} else {
+		// Fall back to JSON for .json files or other formats
+		agent = &Agent{}
+		if err := json.Unmarshal(data, agent); err != nil {
+			return nil, &ParseError{Format: "canonical", Path: path, Err: err}
 		}
-		return agent, nil
+		agent.syncInstructionSections()
 	}
 
-	// Fall back to JSON for .json files or other formats
-	var agent Agent
-	if err := json.Unmarshal(data, &agent); err != nil {
-		return nil, &ParseError{Format: "canonical", Path: path, Err: err}
+	agent.SourcePath = path
+	if agent.Group == "" {
+		agent.Group = header.Team
 	}
 
-	return &agent, nil
+	return agent, nil
 }
 
 // WriteCanonicalFile writes a canonical agent file in Markdown + YAML frontmatter format.
 func WriteCanonicalFile(agent *Agent, path string) error {
 	data := MarshalMarkdownAgent(agent)
 
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, DefaultDirMode); err != nil {
-		return &WriteError{Path: path, Err: err}
-	}
-
-	if err := os.WriteFile(path, data, DefaultFileMode); err != nil {
-		return &WriteError{Path: path, Err: err}
-	}
-
-	return nil
+	return WriteOutputFile(path, data)
 }
 
 // WriteCanonicalJSON writes a canonical agent.json file (for validation/schema compatibility).
@@ -157,61 +191,299 @@ func WriteCanonicalJSON(agent *Agent, path string) error {
 		return &MarshalError{Format: "canonical", Err: err}
 	}
 
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, DefaultDirMode); err != nil {
-		return &WriteError{Path: path, Err: err}
+	return WriteOutputFile(path, append(data, '\n'))
+}
+
+// ReadCanonicalDir reads all agent files (.md or .json) from a directory.
+// Markdown files are discovered recursively, with the namespace derived from
+// the subdirectory (matching multiagentspec.LoadAgentsFromDir); JSON files are
+// read from the top-level directory only.
+//
+// Specs may declare `extends: <base-agent>` to inherit from another agent
+// in the same directory, and `abstract: true` to serve only as a base.
+// Inheritance is resolved with ResolveInheritance before returning, so
+// callers always receive fully merged, concrete agents. Every merged
+// agent is then validated; failures are joined so all are reported at once,
+// each a *ValidationError.
+func ReadCanonicalDir(dir string) ([]*Agent, error) {
+	return readCanonical(dir, false)
+}
+
+// ReadCanonicalTree reads all agent files (.md or .json) under root,
+// recursively. Unlike ReadCanonicalDir, subdirectories only organize the
+// specs (e.g., agents/data/, agents/infra/): they do not set Namespace, so
+// output file names come from agent names alone. A name defined in two
+// files fails with a *DuplicateNameError naming both. Inheritance and
+// validation are as for ReadCanonicalDir.
+func ReadCanonicalTree(root string) ([]*Agent, error) {
+	return readCanonical(root, true)
+}
+
+// ReadCanonicalBundle reads a whole team of agents from one YAML (.yaml,
+// .yml) or JSON (.json) file holding a top-level list of agents, keyed by
+// the canonical field names (name, description, instructions, tools,
+// model, ...). Qualified names must be unique; inheritance and validation
+// are as for ReadCanonicalDir.
+//
+// It is the multi-agent counterpart of ReadCanonicalFile, which reads one
+// agent per file and keeps that name (also as agents.ReadCanonicalFile)
+// for compatibility.
+func ReadCanonicalBundle(path string) ([]*Agent, error) {
+	agents, err := readCanonicalBundle(path)
+	if err != nil {
+		return nil, err
 	}
+	return ResolveCanonical(agents)
+}
 
-	if err := os.WriteFile(path, append(data, '\n'), DefaultFileMode); err != nil {
-		return &WriteError{Path: path, Err: err}
+// ReadCanonicalSpecs reads the agents at path as ReadCanonicalBundle does
+// if path is a file, and otherwise as ReadCanonicalTree (with tree set) or
+// ReadCanonicalDir, but leaves their inheritance unresolved and does not
+// validate them. Specs read from several paths can then be merged with
+// MergeAgents before ResolveCanonical runs once over the result, so an
+// agent may extend a base defined under another path.
+func ReadCanonicalSpecs(path string, tree bool) ([]*Agent, error) {
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		return readCanonicalBundle(path)
 	}
+	return readCanonicalFiles(path, tree)
+}
 
-	return nil
+// readCanonicalBundle implements ReadCanonicalBundle up to resolving
+// inheritance.
+func readCanonicalBundle(path string) ([]*Agent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &ReadError{Path: path, Err: err}
+	}
+
+	var agents []*Agent
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &agents); err != nil {
+			return nil, &ParseError{Format: "yaml", Path: path, Err: err}
+		}
+	case ".json":
+		if err := json.Unmarshal(data, &agents); err != nil {
+			return nil, &ParseError{Format: "canonical", Path: path, Err: err}
+		}
+	default:
+		return nil, &ParseError{Format: "canonical", Path: path, Err: fmt.Errorf("unsupported agent bundle extension %q (want .yaml, .yml or .json)", ext)}
+	}
+
+	for i, agent := range agents {
+		if agent == nil {
+			return nil, &ParseError{Format: "canonical", Path: path, Err: fmt.Errorf("agent %d is empty", i+1)}
+		}
+		agent.SourcePath = path
+		agent.syncInstructionSections()
+	}
+	if err := CheckUniqueNames(agents); err != nil {
+		return nil, err
+	}
+	return agents, nil
 }
 
-// ReadCanonicalDir reads all agent files (.md or .json) from a directory.
-// This delegates to multiagentspec.LoadAgentsFromDir for markdown files.
-func ReadCanonicalDir(dir string) ([]*Agent, error) {
-	// Try multiagentspec loader first (handles .md files properly)
-	agents, err := multiagentspec.LoadAgentsFromDir(dir)
+// readCanonical implements ReadCanonicalDir and, with tree set,
+// ReadCanonicalTree.
+func readCanonical(dir string, tree bool) ([]*Agent, error) {
+	agents, err := readCanonicalFiles(dir, tree)
 	if err != nil {
-		return nil, &ReadError{Path: dir, Err: err}
+		return nil, err
 	}
+	return ResolveCanonical(agents)
+}
 
-	// Also load any .json files that multiagentspec loader skips
-	entries, err := os.ReadDir(dir)
+// readCanonicalFiles implements readCanonical up to resolving inheritance.
+func readCanonicalFiles(dir string, tree bool) ([]*Agent, error) {
+	var agents []*Agent
+	err := walkCanonicalFiles(dir, tree, func(agent *Agent) error {
+		agents = append(agents, agent)
+		return nil
+	})
 	if err != nil {
-		return nil, &ReadError{Path: dir, Err: err}
+		return nil, err
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
+	if tree {
+		// Subdirectories do not namespace agents, so names must be unique
+		if err := CheckUniqueNames(agents); err != nil {
+			return nil, err
+		}
+	}
+	return agents, nil
+}
+
+// WalkCanonical reads the agent files in dir like ReadCanonicalDir but
+// calls fn with each agent as it is read instead of returning them all, so
+// only the agents other specs extend are held in memory. Agents are
+// resolved, skipped if abstract, and validated as for ReadCanonicalDir,
+// except that walking stops at the first invalid agent. An error from fn
+// stops the walk and is returned as is.
+//
+// The files are read twice, or three times if any spec uses extends:
+// first only for their extends keys, then to load the agents that are
+// extended, then to call fn.
+func WalkCanonical(dir string, fn func(*Agent) error) error {
+	extended := make(map[string]bool)
+	err := walkCanonicalFiles(dir, false, func(agent *Agent) error {
+		if agent.Base != "" {
+			extended[agent.Base] = true
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-		ext := filepath.Ext(entry.Name())
-		if ext != ".json" {
-			continue // .md files already loaded by multiagentspec
+	var bases []*Agent
+	if len(extended) > 0 {
+		err := walkCanonicalFiles(dir, false, func(agent *Agent) error {
+			if extended[agent.Name] || extended[agent.QualifiedName()] {
+				bases = append(bases, agent)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
 		}
+	}
 
-		path := filepath.Join(dir, entry.Name())
+	return walkCanonicalFiles(dir, false, func(agent *Agent) error {
+		if agent.Abstract {
+			return nil
+		}
+		if agent.Base != "" {
+			resolved, err := ResolveInheritance(append(slices.Clip(bases), agent))
+			if err != nil {
+				return err
+			}
+			agent = resolved[len(resolved)-1]
+		}
+		if err := agent.Validate(); err != nil {
+			return err
+		}
+		return fn(agent)
+	})
+}
+
+// walkCanonicalFiles calls fn with each agent file read from dir, in the
+// order readCanonical reads them, without resolving inheritance. Errors
+// from fn are returned as is.
+func walkCanonicalFiles(dir string, tree bool, fn func(*Agent) error) error {
+	return walkCanonicalPaths(dir, tree, func(path string) error {
 		agent, err := ReadCanonicalFile(path)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		agents = append(agents, agent)
+
+		// Derive namespace from subdirectory if not explicitly set
+		if !tree && agent.Namespace == "" {
+			if rel, err := filepath.Rel(dir, filepath.Dir(path)); err == nil && rel != "." {
+				agent.Namespace = filepath.ToSlash(rel)
+			}
+		}
+
+		return fn(agent)
+	})
+}
+
+// walkCanonicalPaths calls fn with the path of each agent file in dir:
+// Markdown files recursively, then top-level JSON files, or with tree set
+// both recursively. Errors from fn are returned as is.
+func walkCanonicalPaths(dir string, tree bool, fn func(path string) error) error {
+	var fnErr error
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch ext := filepath.Ext(path); {
+		case ext == ".md":
+		case ext == ".json" && tree:
+		default:
+			return nil
+		}
+
+		if err := fn(path); err != nil {
+			fnErr = err
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		return &ReadError{Path: dir, Err: err}
+	}
+	if tree {
+		return nil
+	}
+
+	// Also visit any .json files in the top-level directory
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return &ReadError{Path: dir, Err: err}
 	}
 
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		if err := fn(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResolveCanonical resolves the inheritance of agents read from specs (see
+// ResolveInheritance) and validates the result, joining every failure so
+// all are reported at once, each a *ValidationError. The ReadCanonical
+// readers call it before returning; call it directly on agents read with
+// ReadCanonicalSpecs.
+func ResolveCanonical(agents []*Agent) ([]*Agent, error) {
+	agents, err := ResolveInheritance(agents)
+	if err != nil {
+		return nil, err
+	}
+
+	var invalid []error
+	for _, agent := range agents {
+		if err := agent.Validate(); err != nil {
+			invalid = append(invalid, err)
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, errors.Join(invalid...)
+	}
 	return agents, nil
 }
 
 // ParseMarkdownAgent parses a Markdown file with YAML frontmatter into an Agent.
-// Deprecated: Use multiagentspec.ParseAgentMarkdown directly.
+// If the frontmatter has no name, it is inferred from path.
 func ParseMarkdownAgent(data []byte, path string) (*Agent, error) {
-	agent, err := multiagentspec.ParseAgentMarkdown(data)
+	spec, err := multiagentspec.ParseAgentMarkdown(data)
 	if err != nil {
 		return nil, err
 	}
+	agent := &Agent{Spec: *spec}
+
+	// Decode assistantkit fields that are not part of multi-agent-spec, and
+	// keep the remaining keys as metadata
+	if frontmatter := extractFrontmatter(data); len(frontmatter) > 0 {
+		if err := yaml.Unmarshal(frontmatter, agent); err != nil {
+			return nil, err
+		}
+		if agent.Metadata, err = parseMetadata(frontmatter); err != nil {
+			return nil, err
+		}
+	}
 
 	// Infer name from filename if not set
 	if agent.Name == "" && path != "" {
@@ -219,6 +491,7 @@ func ParseMarkdownAgent(data []byte, path string) (*Agent, error) {
 		agent.Name = strings.TrimSuffix(base, filepath.Ext(base))
 	}
 
+	agent.syncInstructionSections()
 	return agent, nil
 }
 
@@ -228,11 +501,23 @@ func MarshalMarkdownAgent(agent *Agent) []byte {
 
 	// Write YAML frontmatter
 	buf.WriteString("---\n")
-	buf.WriteString(fmt.Sprintf("name: %s\n", agent.Name))
-	buf.WriteString(fmt.Sprintf("description: %s\n", agent.Description))
+	writeScalar(&buf, "name", agent.Name)
+	writeScalar(&buf, "description", agent.Description)
+
+	if agent.Version != "" {
+		writeScalar(&buf, "version", agent.Version)
+	}
 
 	if agent.Model != "" {
-		buf.WriteString(fmt.Sprintf("model: %s\n", string(agent.Model)))
+		writeScalar(&buf, "model", string(agent.Model))
+	}
+
+	if len(agent.ModelFallback) > 0 {
+		buf.WriteString(fmt.Sprintf("modelFallback: [%s]\n", strings.Join(agent.ModelFallback, ", ")))
+	}
+
+	if agent.MaxTurns != nil {
+		buf.WriteString(fmt.Sprintf("maxTurns: %d\n", *agent.MaxTurns))
 	}
 
 	if len(agent.Tools) > 0 {
@@ -251,6 +536,88 @@ func MarshalMarkdownAgent(agent *Agent) []byte {
 		buf.WriteString(fmt.Sprintf("requires: [%s]\n", strings.Join(agent.Requires, ", ")))
 	}
 
+	if len(agent.Files) > 0 {
+		buf.WriteString(fmt.Sprintf("files: [%s]\n", strings.Join(agent.Files, ", ")))
+	}
+
+	if agent.Base != "" {
+		writeScalar(&buf, "extends", agent.Base)
+	}
+
+	if agent.Abstract {
+		buf.WriteString("abstract: true\n")
+	}
+
+	if agent.PrependInstructions {
+		buf.WriteString("prependInstructions: true\n")
+	}
+
+	if agent.Group != "" {
+		writeScalar(&buf, "group", agent.Group)
+	}
+
+	if agent.Category != "" {
+		writeScalar(&buf, "category", agent.Category)
+	}
+
+	if len(agent.Tags) > 0 {
+		buf.WriteString(fmt.Sprintf("tags: [%s]\n", strings.Join(agent.Tags, ", ")))
+	}
+
+	if agent.Scope != "" {
+		writeScalar(&buf, "scope", agent.Scope)
+	}
+
+	if agent.Deprecated {
+		buf.WriteString("deprecated: true\n")
+	}
+
+	if agent.DeprecationMessage != "" {
+		writeScalar(&buf, "deprecationMessage", agent.DeprecationMessage)
+	}
+
+	if agent.Workspace != "" {
+		writeScalar(&buf, "workspace", agent.Workspace)
+	}
+
+	if len(agent.Descriptions) > 0 {
+		if data, err := yaml.Marshal(map[string]map[string]string{"descriptions": agent.Descriptions}); err == nil {
+			buf.Write(data)
+		}
+	}
+
+	if agent.Timeouts != nil {
+		if data, err := yaml.Marshal(map[string]*Timeouts{"timeouts": agent.Timeouts}); err == nil {
+			buf.Write(data)
+		}
+	}
+
+	if agent.Retry != nil {
+		if data, err := yaml.Marshal(map[string]*RetryConfig{"retry": agent.Retry}); err == nil {
+			buf.Write(data)
+		}
+	}
+
+	if len(agent.Arguments) > 0 {
+		// Arguments are nested objects, so let YAML handle quoting
+		if data, err := yaml.Marshal(map[string][]Argument{"arguments": agent.Arguments}); err == nil {
+			buf.Write(data)
+		}
+	}
+
+	if len(agent.Triggers) > 0 {
+		if data, err := yaml.Marshal(map[string][]Trigger{"triggers": agent.Triggers}); err == nil {
+			buf.Write(data)
+		}
+	}
+
+	if metadata := agent.metadataKeys(); len(metadata) > 0 {
+		// Metadata follows the spec keys as top-level keys, in key order
+		if data, err := yaml.Marshal(metadata); err == nil {
+			buf.Write(data)
+		}
+	}
+
 	buf.WriteString("---\n\n")
 
 	// Write instructions directly (they already contain markdown formatting)
@@ -262,6 +629,15 @@ func MarshalMarkdownAgent(agent *Agent) []byte {
 	return buf.Bytes()
 }
 
+// writeScalar writes "key: value" to buf as YAML, quoting value when it
+// would otherwise not read back as the same string (e.g., it contains ": "
+// or starts with "[").
+func writeScalar(buf *bytes.Buffer, key, value string) {
+	if data, err := yaml.Marshal(map[string]string{key: value}); err == nil {
+		buf.Write(data)
+	}
+}
+
 // WriteAgentsToDir writes multiple agents to a directory using the specified adapter.
 func WriteAgentsToDir(agents []*Agent, dir string, adapterName string) error {
 	adapter, ok := GetAdapter(adapterName)
@@ -274,8 +650,7 @@ func WriteAgentsToDir(agents []*Agent, dir string, adapterName string) error {
 	}
 
 	for _, agent := range agents {
-		filename := agent.Name + adapter.FileExtension()
-		path := filepath.Join(dir, filename)
+		path := filepath.Join(dir, AgentPath(adapter, agent))
 		if err := adapter.WriteFile(agent, path); err != nil {
 			return err
 		}
@@ -283,3 +658,77 @@ func WriteAgentsToDir(agents []*Agent, dir string, adapterName string) error {
 
 	return nil
 }
+
+// GenerateFiles renders agents with the named adapter without touching disk.
+// The result maps each output path (see AgentPath, slash-separated) to its
+// generated contents. The adapter is looked up in the default registry.
+func GenerateFiles(agents []*Agent, adapterName string) (map[string][]byte, error) {
+	return DefaultRegistry.GenerateFiles(agents, adapterName)
+}
+
+// GenerateFiles renders agents with the named adapter of the registry, as
+// the package-level GenerateFiles does.
+func (r *Registry) GenerateFiles(agents []*Agent, adapterName string) (map[string][]byte, error) {
+	adapter, ok := r.GetAdapter(adapterName)
+	if !ok {
+		return nil, &AdapterError{Name: adapterName}
+	}
+
+	files := make(map[string][]byte, len(agents))
+	for _, agent := range agents {
+		data, err := adapter.Marshal(agent)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.ToSlash(AgentPath(adapter, agent))] = data
+	}
+
+	return files, nil
+}
+
+// CheckUniqueNames verifies that no two agents share a qualified name
+// (namespace/name); agents in different namespaces may share a Name. The
+// returned DuplicateNameError lists every source path for the name.
+func CheckUniqueNames(agents []*Agent) error {
+	return checkUnique(agents, (*Agent).QualifiedName)
+}
+
+// CheckUniqueOutputNames verifies that no two agents share a Name, even in
+// different namespaces. Outputs address agents by Name alone: combined
+// single-file outputs (such as a full agentkit config or a CDK stack) key
+// sections by it and per-agent files are named after it, so a clash would
+// silently produce duplicate entries or overwrite a file.
+func CheckUniqueOutputNames(agents []*Agent) error {
+	return checkUnique(agents, func(agent *Agent) string { return agent.Name })
+}
+
+// checkUnique is CheckUniqueNames for agents identified by key.
+func checkUnique(agents []*Agent, key func(*Agent) string) error {
+	seen := make(map[string]*Agent, len(agents))
+	for _, agent := range agents {
+		name := key(agent)
+		first, ok := seen[name]
+		if !ok {
+			seen[name] = agent
+			continue
+		}
+
+		paths := []string{sourceLabel(first)}
+		for _, other := range agents {
+			if other != first && key(other) == name {
+				paths = append(paths, sourceLabel(other))
+			}
+		}
+		return &DuplicateNameError{Name: name, Paths: paths}
+	}
+	return nil
+}
+
+// sourceLabel returns the agent's source path, or a placeholder for agents
+// that were not loaded from disk.
+func sourceLabel(agent *Agent) string {
+	if agent.SourcePath == "" {
+		return "<in-memory>"
+	}
+	return agent.SourcePath
+}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadCanonicalTree(t *testing.T) {
+	dir := t.TempDir()
+	writeSpec(t, dir, "lead.md", "---\nname: lead\ndescription: Leads\n---\n\nLead.\n")
+	writeSpec(t, dir, "data/loader.md", "---\nname: loader\ndescription: Loads data\nextends: lead\n---\n\nLoad.\n")
+	writeSpec(t, dir, "infra/deep/pager.json", `{"name": "pager", "description": "Pages", "instructions": "Page."}`)
+
+	agents, err := ReadCanonicalTree(dir)
+	if err != nil {
+		t.Fatalf("ReadCanonicalTree() error = %v", err)
+	}
+	if len(agents) != 3 {
+		t.Fatalf("ReadCanonicalTree() = %d agents, want 3", len(agents))
+	}
+	for _, name := range []string{"lead", "loader", "pager"} {
+		agent := findAgent(agents, name)
+		if agent == nil {
+			t.Fatalf("agent %s not loaded", name)
+		}
+		if agent.Namespace != "" {
+			t.Errorf("agent %s namespace = %q, want none", name, agent.Namespace)
+		}
+	}
+
+	// ReadCanonicalDir reads JSON at the top level only
+	agents, err = ReadCanonicalDir(dir)
+	if err != nil {
+		t.Fatalf("ReadCanonicalDir() error = %v", err)
+	}
+	if len(agents) != 2 || findAgent(agents, "loader").Namespace != "data" {
+		t.Errorf("ReadCanonicalDir() = %d agents, want 2 with namespaced loader", len(agents))
+	}
+}
+
+func TestReadCanonicalBundle(t *testing.T) {
+	dir := t.TempDir()
+	writeSpec(t, dir, "agents.yaml", `- name: lead
+  description: Leads the team
+  model: opus
+  instructions: |
+    ## Role
+    Lead.
+- name: reviewer
+  description: Reviews code
+  tools: [Read, Grep]
+  extends: lead
+`)
+	writeSpec(t, dir, "agents.json", `[{"name": "pager", "description": "Pages", "instructions": "Page."}]`)
+	writeSpec(t, dir, "dupes.yml", "- {name: sync, description: Syncs}\n- {name: sync, description: Syncs again}\n")
+	writeSpec(t, dir, "agents.toml", "")
+	writeSpec(t, dir, "invalid.yaml", "- description: No name\n")
+
+	agents, err := ReadCanonicalBundle(filepath.Join(dir, "agents.yaml"))
+	if err != nil {
+		t.Fatalf("ReadCanonicalBundle(yaml) error = %v", err)
+	}
+	if len(agents) != 2 {
+		t.Fatalf("ReadCanonicalBundle(yaml) = %d agents, want 2", len(agents))
+	}
+	reviewer := findAgent(agents, "reviewer")
+	if reviewer.Model != ModelOpus || len(reviewer.Tools) != 2 || !strings.Contains(reviewer.Instructions, "Lead.") {
+		t.Errorf("reviewer = %+v, want inherited model and instructions", reviewer.Spec)
+	}
+	if reviewer.SourcePath != filepath.Join(dir, "agents.yaml") || len(findAgent(agents, "lead").InstructionSections) != 1 {
+		t.Errorf("lead = %+v, want source path and instruction sections", findAgent(agents, "lead"))
+	}
+
+	agents, err = ReadCanonicalBundle(filepath.Join(dir, "agents.json"))
+	if err != nil || len(agents) != 1 || agents[0].Instructions != "Page." {
+		t.Errorf("ReadCanonicalBundle(json) = %v, %v", agents, err)
+	}
+
+	var dupErr *DuplicateNameError
+	if _, err := ReadCanonicalBundle(filepath.Join(dir, "dupes.yml")); !errors.As(err, &dupErr) {
+		t.Errorf("ReadCanonicalBundle(dupes) error = %v, want *DuplicateNameError", err)
+	}
+	var parseErr *ParseError
+	if _, err := ReadCanonicalBundle(filepath.Join(dir, "agents.toml")); !errors.As(err, &parseErr) {
+		t.Errorf("ReadCanonicalBundle(toml) error = %v, want *ParseError", err)
+	}
+	var valErr *ValidationError
+	if _, err := ReadCanonicalBundle(filepath.Join(dir, "invalid.yaml")); !errors.As(err, &valErr) {
+		t.Errorf("ReadCanonicalBundle(invalid) error = %v, want *ValidationError", err)
+	}
+}
+
+func TestReadCanonicalTreeDuplicateNames(t *testing.T) {
+	dir := t.TempDir()
+	writeSpec(t, dir, "data/sync.md", "---\nname: sync\ndescription: Syncs data\n---\n\nSync.\n")
+	writeSpec(t, dir, "infra/sync.md", "---\nname: sync\ndescription: Syncs hosts\n---\n\nSync.\n")
+
+	_, err := ReadCanonicalTree(dir)
+	var dupErr *DuplicateNameError
+	if !errors.As(err, &dupErr) {
+		t.Fatalf("ReadCanonicalTree() error = %v, want *DuplicateNameError", err)
+	}
+	for _, path := range []string{filepath.Join(dir, "data", "sync.md"), filepath.Join(dir, "infra", "sync.md")} {
+		if !strings.Contains(err.Error(), path) {
+			t.Errorf("error %q missing %s", err, path)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&stubAdapter{name: "beta"})
+	r.Register(&stubAdapter{name: "alpha"})
+
+	if names := r.Names(); len(names) != 2 || names[0] != "alpha" || names[1] != "beta" {
+		t.Errorf("Names() = %v, want [alpha beta]", names)
+	}
+	if adapter, ok := r.Get("beta"); !ok || adapter.Name() != "beta" {
+		t.Errorf("Get(beta) = %v, %v", adapter, ok)
+	}
+	if _, ok := r.Get("gamma"); ok {
+		t.Error("Get(gamma) should not find an adapter")
+	}
+	if _, ok := DefaultRegistry.Get("alpha"); ok {
+		t.Error("adapters registered in r should not be in DefaultRegistry")
+	}
+}
+
+func TestRegistrySettingsAreIsolated(t *testing.T) {
+	r := NewRegistry()
+	r.SetLineEnding(LineEndingCRLF)
+	r.SetDescriptionStyle("kiro", DescriptionStyle{MaxLength: 8, Mode: DescriptionTruncate})
+	if err := r.SetHashAlgorithm(HashSHA512); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.LineEnding() != LineEndingCRLF || r.HashAlgorithm() != HashSHA512 || r.DescriptionStyle("kiro").MaxLength != 8 {
+		t.Errorf("settings not stored: %q, %q, %+v", r.LineEnding(), r.HashAlgorithm(), r.DescriptionStyle("kiro"))
+	}
+	if OutputLineEnding() != LineEndingPreserve || HashAlgorithm() != HashSHA256 || DescriptionStyleFor("kiro").MaxLength != 0 {
+		t.Error("settings of r leaked into DefaultRegistry")
+	}
+}
+
+func TestCheckUniqueNamesNamespaces(t *testing.T) {
+	agents := []*Agent{
+		NewAgent("reviewer", "Reviews Go").WithNamespace("go"),
+		NewAgent("reviewer", "Reviews Python").WithNamespace("python"),
+	}
+	if err := CheckUniqueNames(agents); err != nil {
+		t.Errorf("CheckUniqueNames(namespaced) error = %v", err)
+	}
+
+	var dupErr *DuplicateNameError
+	if err := CheckUniqueOutputNames(agents); !errors.As(err, &dupErr) || dupErr.Name != "reviewer" {
+		t.Errorf("CheckUniqueOutputNames(namespaced) error = %v, want DuplicateNameError for reviewer", err)
+	}
+
+	agents = append(agents, NewAgent("reviewer", "Reviews Go again").WithNamespace("go"))
+	if err := CheckUniqueNames(agents); !errors.As(err, &dupErr) || dupErr.Name != "go/reviewer" {
+		t.Errorf("CheckUniqueNames(same namespace) error = %v, want DuplicateNameError for go/reviewer", err)
+	}
+}
@@ -1,15 +1,132 @@
 // Package core provides the canonical agent definition types.
-// Agent definitions use the multi-agent-spec types as the canonical form,
+// Agent definitions embed the multi-agent-spec types as the canonical form,
 // which maps losslessly to Claude Code, Kiro CLI, and OpenAI Codex.
 package core
 
 import (
+	"fmt"
+
 	multiagentspec "github.com/agentplexus/multi-agent-spec/sdk/go"
+
+	mcpcore "github.com/agentplexus/assistantkit/mcp/core"
+	pluginscore "github.com/agentplexus/assistantkit/plugins/core"
 )
 
-// Agent is an alias for multiagentspec.Agent.
-// This is the canonical agent definition type used across all platforms.
-type Agent = multiagentspec.Agent
+// Spec is an alias for multiagentspec.Agent.
+// It holds the portable agent definition embedded in Agent.
+type Spec = multiagentspec.Agent
+
+// Agent is the canonical agent definition type used across all platforms.
+// It embeds the multi-agent-spec definition, so all spec fields (Name,
+// Description, Tools, ...) are promoted, and adds assistantkit-specific
+// fields that are not part of the spec.
+type Agent struct {
+	Spec `yaml:",inline"`
+
+	// Version is the version of the agent's spec (e.g., 1.2.0), carried
+	// into generated output so a generated file can be traced back to the
+	// spec that produced it.
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+
+	// Descriptions holds localized descriptions keyed by locale (e.g., "de",
+	// "pt-BR"). Description is the default when no locale matches.
+	Descriptions map[string]string `json:"descriptions,omitempty" yaml:"descriptions,omitempty"`
+
+	// Workspace is the agent's working directory, relative to the
+	// deployment workspace root. Empty means the root itself.
+	Workspace string `json:"workspace,omitempty" yaml:"workspace,omitempty"`
+
+	// Base names the agent this one extends (spec key "extends"). Unset
+	// fields are inherited from the base; see ResolveInheritance.
+	Base string `json:"extends,omitempty" yaml:"extends,omitempty"`
+
+	// Abstract marks a base-only agent that other agents extend but that is
+	// not generated itself.
+	Abstract bool `json:"abstract,omitempty" yaml:"abstract,omitempty"`
+
+	// PrependInstructions makes an agent with a Base keep the base's
+	// instructions, followed by its own, instead of replacing them.
+	PrependInstructions bool `json:"prependInstructions,omitempty" yaml:"prependInstructions,omitempty"`
+
+	// Group assigns the agent to a sub-team within a project, so deployment
+	// targets can select subsets of agents. The spec key "team" is accepted
+	// as an alias.
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+
+	// ModelFallback lists model aliases to try in order (e.g., opus, then
+	// sonnet, then haiku). When set it takes precedence over Model; formats
+	// without fallback support use the first entry.
+	ModelFallback []string `json:"modelFallback,omitempty" yaml:"modelFallback,omitempty"`
+
+	// MaxTurns caps the number of turns in the agent's loop, guarding
+	// against runaway iterations. Nil leaves the runtime default; formats
+	// without a turn limit ignore it.
+	MaxTurns *int `json:"maxTurns,omitempty" yaml:"maxTurns,omitempty"`
+
+	// Timeouts overrides the runtime's global operation timeouts for this
+	// agent. Formats without per-agent timeouts ignore it.
+	Timeouts *Timeouts `json:"timeouts,omitempty" yaml:"timeouts,omitempty"`
+
+	// Retry is the agent's retry policy for flaky tool and model calls.
+	// Formats without retry support ignore it.
+	Retry *RetryConfig `json:"retry,omitempty" yaml:"retry,omitempty"`
+
+	// Scope is the install scope, ScopeWorkspace (the default when empty)
+	// or ScopeGlobal. Formats without scopes ignore it.
+	Scope string `json:"scope,omitempty" yaml:"scope,omitempty"`
+
+	// Deprecated marks an agent that is being retired. It is still
+	// generated (with a notice) unless deprecated agents are skipped.
+	Deprecated bool `json:"deprecated,omitempty" yaml:"deprecated,omitempty"`
+
+	// DeprecationMessage explains the deprecation, e.g. naming the
+	// replacement agent.
+	DeprecationMessage string `json:"deprecationMessage,omitempty" yaml:"deprecationMessage,omitempty"`
+
+	// Category tags the agent with a marketplace category (see
+	// pluginscore.MarketplaceCategories) for publishing.
+	Category string `json:"category,omitempty" yaml:"category,omitempty"`
+
+	// Tags are free-form labels (e.g., "data") used to bundle agents, such
+	// as one Claude plugin per tag.
+	Tags []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+
+	// Arguments are the inputs a parameterized agent accepts. Formats with
+	// parameter support emit them as prompt arguments or function
+	// parameters; others ignore them.
+	Arguments []Argument `json:"arguments,omitempty" yaml:"arguments,omitempty"`
+
+	// Triggers run shell commands on agent lifecycle events (e.g., a
+	// formatter after each write). Formats without hooks ignore them.
+	Triggers []Trigger `json:"triggers,omitempty" yaml:"triggers,omitempty"`
+
+	// Files lists supporting files bundled with the agent in formats that
+	// support them, such as Claude Skills. Paths are relative to the spec
+	// file and are not inherited through extends.
+	Files []string `json:"files,omitempty" yaml:"files,omitempty"`
+
+	// MCPServers declares MCP servers the agent uses, keyed by server name.
+	MCPServers map[string]mcpcore.Server `json:"mcpServers,omitempty" yaml:"mcpServers,omitempty"`
+
+	// InstructionSections are the level-two Markdown sections of
+	// Instructions (e.g., "## Role", "## Guidelines"), in order, for formats
+	// that structure instructions. Instructions stays the flattened form
+	// and is authoritative; the sections are derived from it when specs are
+	// read and whenever core rewrites it. Nil when the instructions have no
+	// sections.
+	InstructionSections []InstructionSection `json:"-" yaml:"-"`
+
+	// Metadata holds passthrough key/value pairs such as owner or
+	// cost-center. In Markdown specs they are the frontmatter keys that are
+	// not spec keys (see IsSpecKey); JSON specs use a "metadata" object.
+	// Formats with room for arbitrary metadata carry them; others ignore
+	// them.
+	Metadata map[string]string `json:"metadata,omitempty" yaml:"-"`
+
+	// SourcePath is the file the agent was loaded from, if any.
+	// It is informational only and never serialized.
+	SourcePath string `json:"-" yaml:"-"`
+}
 
 // Task is an alias for multiagentspec.Task.
 type Task = multiagentspec.Task
@@ -26,5 +143,71 @@ const (
 
 // NewAgent creates a new Agent with the given name and description.
 func NewAgent(name, description string) *Agent {
-	return multiagentspec.NewAgent(name, description)
+	return &Agent{Spec: *multiagentspec.NewAgent(name, description)}
+}
+
+// WithModel sets the agent's model and returns the agent for chaining.
+func (a *Agent) WithModel(model Model) *Agent {
+	a.Model = model
+	return a
+}
+
+// WithTools sets the agent's tools and returns the agent for chaining.
+func (a *Agent) WithTools(tools ...string) *Agent {
+	a.Tools = tools
+	return a
+}
+
+// WithInstructions sets the agent's instructions and returns the agent for chaining.
+func (a *Agent) WithInstructions(instructions string) *Agent {
+	a.Instructions = instructions
+	a.syncInstructionSections()
+	return a
+}
+
+// WithNamespace sets the agent's namespace and returns the agent for chaining.
+func (a *Agent) WithNamespace(namespace string) *Agent {
+	a.Namespace = namespace
+	return a
+}
+
+// FilterGroups returns the agents whose Group is one of groups, preserving
+// order. An empty groups list selects every agent.
+func FilterGroups(agents []*Agent, groups []string) []*Agent {
+	if len(groups) == 0 {
+		return agents
+	}
+	want := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		want[g] = true
+	}
+	var out []*Agent
+	for _, agent := range agents {
+		if want[agent.Group] {
+			out = append(out, agent)
+		}
+	}
+	return out
+}
+
+// CheckCategories returns an error for the first agent whose Category is
+// not a known marketplace category.
+func CheckCategories(agents []*Agent) error {
+	for _, agent := range agents {
+		if err := pluginscore.ValidateCategory(agent.Name, agent.Category); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckMaxTurns returns an error for the first agent with a negative
+// MaxTurns.
+func CheckMaxTurns(agents []*Agent) error {
+	for _, agent := range agents {
+		if agent.MaxTurns != nil && *agent.MaxTurns < 0 {
+			return fmt.Errorf("agent %s: maxTurns must be non-negative, got %d", agent.Name, *agent.MaxTurns)
+		}
+	}
+	return nil
 }
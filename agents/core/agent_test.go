@@ -1,6 +1,11 @@
 package core
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestNewAgent(t *testing.T) {
 	agent := NewAgent("release-coordinator", "Orchestrates releases")
@@ -36,3 +41,303 @@ func TestAgentWithInstructions(t *testing.T) {
 		t.Errorf("expected Instructions 'Do the thing', got '%s'", agent.Instructions)
 	}
 }
+
+func TestParseMarkdownAgentWorkspace(t *testing.T) {
+	data := []byte("---\nname: api\ndescription: API agent\nworkspace: services/api\n---\n\nBody\n")
+
+	agent, err := ParseMarkdownAgent(data, "api.md")
+	if err != nil {
+		t.Fatalf("ParseMarkdownAgent() error = %v", err)
+	}
+	if agent.Workspace != "services/api" {
+		t.Errorf("Workspace = %q, want services/api", agent.Workspace)
+	}
+	if agent.Instructions != "Body" {
+		t.Errorf("Instructions = %q, want Body", agent.Instructions)
+	}
+}
+
+func TestFilterGroups(t *testing.T) {
+	data := &Agent{Spec: Spec{Name: "etl"}, Group: "data"}
+	ml := &Agent{Spec: Spec{Name: "trainer"}, Group: "ml"}
+	ops := &Agent{Spec: Spec{Name: "oncall"}}
+	all := []*Agent{data, ml, ops}
+
+	if got := FilterGroups(all, nil); len(got) != 3 {
+		t.Errorf("FilterGroups(nil) returned %d agents, want 3", len(got))
+	}
+
+	got := FilterGroups(all, []string{"ml", "data"})
+	if len(got) != 2 || got[0] != data || got[1] != ml {
+		t.Errorf("FilterGroups(ml, data) = %v, want [etl trainer] in input order", got)
+	}
+}
+
+func TestReadCanonicalFileTeamAlias(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "etl.md")
+	if err := os.WriteFile(path, []byte("---\nname: etl\ndescription: ETL\nteam: data\n---\n\nBody\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	agent, err := ReadCanonicalFile(path)
+	if err != nil {
+		t.Fatalf("ReadCanonicalFile() error = %v", err)
+	}
+	if agent.Group != "data" {
+		t.Errorf("Group = %q, want data", agent.Group)
+	}
+}
+
+func TestParseMarkdownAgentMCPServers(t *testing.T) {
+	data := []byte("---\nname: docs\nmcpServers:\n  fs:\n    command: npx\n    args: [server-filesystem, .]\n  search:\n    url: https://mcp.example.com/mcp\n---\n")
+
+	agent, err := ParseMarkdownAgent(data, "docs.md")
+	if err != nil {
+		t.Fatalf("ParseMarkdownAgent() error = %v", err)
+	}
+	if fs := agent.MCPServers["fs"]; fs.Command != "npx" || len(fs.Args) != 2 || !fs.IsStdio() {
+		t.Errorf("fs server = %+v", fs)
+	}
+	if search := agent.MCPServers["search"]; !search.IsHTTP() {
+		t.Errorf("search server = %+v, want http", search)
+	}
+}
+
+func TestCheckCategories(t *testing.T) {
+	data := []byte("---\nname: auditor\ndescription: Audits code\ncategory: security\n---\n\nBody\n")
+	agent, err := ParseMarkdownAgent(data, "auditor.md")
+	if err != nil {
+		t.Fatalf("ParseMarkdownAgent() error = %v", err)
+	}
+	if agent.Category != "security" {
+		t.Errorf("Category = %q, want security", agent.Category)
+	}
+
+	uncategorized := NewAgent("helper", "Helps")
+	if err := CheckCategories([]*Agent{agent, uncategorized}); err != nil {
+		t.Errorf("CheckCategories() error = %v", err)
+	}
+
+	agent.Category = "games"
+	if err := CheckCategories([]*Agent{agent}); err == nil {
+		t.Error("CheckCategories() should reject unknown category")
+	}
+}
+
+func TestModelChain(t *testing.T) {
+	agent := NewAgent("planner", "Plans").WithModel(ModelSonnet)
+	if got := agent.PrimaryModel(); got != ModelSonnet {
+		t.Errorf("PrimaryModel() = %q, want sonnet", got)
+	}
+
+	// ModelFallback takes precedence over Model
+	agent.ModelFallback = []string{"opus", "sonnet", "haiku"}
+	chain := agent.ModelChain()
+	if len(chain) != 3 || chain[0] != ModelOpus || chain[2] != ModelHaiku {
+		t.Errorf("ModelChain() = %v", chain)
+	}
+	if got := agent.PrimaryModel(); got != ModelOpus {
+		t.Errorf("PrimaryModel() = %q, want opus", got)
+	}
+
+	if got := (&Agent{}).ModelChain(); got != nil {
+		t.Errorf("ModelChain() without model = %v, want nil", got)
+	}
+}
+
+func TestCheckModelFallbacks(t *testing.T) {
+	tests := []struct {
+		name     string
+		fallback []string
+		wantErr  bool
+	}{
+		{"unset", nil, false},
+		{"aliases", []string{"opus", "sonnet", "haiku"}, false},
+		{"unknown alias", []string{"opus", "gpt-4o"}, true},
+		{"duplicate", []string{"sonnet", "sonnet"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := NewAgent("planner", "Plans")
+			agent.ModelFallback = tt.fallback
+			err := CheckModelFallbacks([]*Agent{agent})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckModelFallbacks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveModel(t *testing.T) {
+	tests := []struct {
+		model  string
+		want   string
+		wantOK bool
+		alias  string
+	}{
+		{"sonnet", "claude-3-5-sonnet-20241022", true, "sonnet"},
+		{"opus", "claude-3-opus-20240229", true, "opus"},
+		{"claude-3-haiku-20240307", "claude-3-haiku-20240307", true, "haiku"},
+		{"gpt-4o", "gpt-4o", false, "gpt-4o"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			got, ok := ResolveModel(tt.model)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("ResolveModel(%q) = %q, %v, want %q, %v", tt.model, got, ok, tt.want, tt.wantOK)
+			}
+			if alias := ModelAlias(got); alias != tt.alias {
+				t.Errorf("ModelAlias(%q) = %q, want %q", got, alias, tt.alias)
+			}
+		})
+	}
+}
+
+func TestAgentWarnings(t *testing.T) {
+	agent := NewAgent("planner", "Plans").WithModel("sonnet")
+	if warnings := agent.Warnings(); len(warnings) != 0 {
+		t.Errorf("Warnings() = %v, want none", warnings)
+	}
+
+	agent.Model = "gpt-4o"
+	warnings := agent.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `model "gpt-4o"`) {
+		t.Errorf("Warnings() = %v, want an unknown model warning", warnings)
+	}
+}
+
+func TestApplyModelOverrides(t *testing.T) {
+	reviewer := NewAgent("reviewer", "Reviews code").WithModel(ModelHaiku)
+	reviewer.ModelFallback = []string{"haiku", "sonnet"}
+	planner := NewAgent("planner", "Plans work").WithNamespace("ops")
+	writer := NewAgent("writer", "Writes docs")
+	agents := []*Agent{reviewer, planner, writer}
+
+	got := ApplyModelOverrides(agents, map[string]string{"reviewer": "opus", "ops/planner": "haiku", "ghost": "opus"})
+	if got[0].PrimaryModel() != ModelOpus || got[1].Model != ModelHaiku {
+		t.Errorf("models = %s, %s; want opus, haiku", got[0].PrimaryModel(), got[1].Model)
+	}
+	if got[2] != writer {
+		t.Error("agents without an override should be returned as is")
+	}
+	if reviewer.Model != ModelHaiku || len(reviewer.ModelFallback) != 2 || planner.Model != ModelSonnet {
+		t.Error("ApplyModelOverrides modified its input")
+	}
+}
+
+func TestMaxTurns(t *testing.T) {
+	data := []byte("---\nname: looper\ndescription: Loops\nmaxTurns: 12\n---\n\nBody\n")
+	agent, err := ParseMarkdownAgent(data, "looper.md")
+	if err != nil {
+		t.Fatalf("ParseMarkdownAgent() error = %v", err)
+	}
+	if agent.MaxTurns == nil || *agent.MaxTurns != 12 {
+		t.Fatalf("MaxTurns = %v, want 12", agent.MaxTurns)
+	}
+
+	back, err := ParseMarkdownAgent(MarshalMarkdownAgent(agent), "looper.md")
+	if err != nil {
+		t.Fatalf("round trip error = %v", err)
+	}
+	if back.MaxTurns == nil || *back.MaxTurns != 12 {
+		t.Errorf("round trip MaxTurns = %v, want 12", back.MaxTurns)
+	}
+
+	if err := CheckMaxTurns([]*Agent{agent, NewAgent("plain", "No limit")}); err != nil {
+		t.Errorf("CheckMaxTurns() error = %v", err)
+	}
+	negative := -1
+	agent.MaxTurns = &negative
+	if err := CheckMaxTurns([]*Agent{agent}); err == nil {
+		t.Error("CheckMaxTurns() should reject a negative limit")
+	}
+}
+
+func TestCheckScopes(t *testing.T) {
+	tests := []struct {
+		scope   string
+		wantErr bool
+	}{
+		{"", false},
+		{ScopeWorkspace, false},
+		{ScopeGlobal, false},
+		{"user", true},
+	}
+
+	for _, tt := range tests {
+		agent := NewAgent("helper", "Helps")
+		agent.Scope = tt.scope
+		if err := CheckScopes([]*Agent{agent}); (err != nil) != tt.wantErr {
+			t.Errorf("CheckScopes(%q) error = %v, wantErr %v", tt.scope, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckTimeouts(t *testing.T) {
+	data := []byte("---\nname: indexer\ndescription: Indexes\ntimeouts:\n  agentInvoke: 30m\n---\n\nBody\n")
+	agent, err := ParseMarkdownAgent(data, "indexer.md")
+	if err != nil {
+		t.Fatalf("ParseMarkdownAgent() error = %v", err)
+	}
+	if agent.Timeouts == nil || agent.Timeouts.AgentInvoke != "30m" {
+		t.Fatalf("Timeouts = %+v", agent.Timeouts)
+	}
+	back, err := ParseMarkdownAgent(MarshalMarkdownAgent(agent), "indexer.md")
+	if err != nil || back.Timeouts == nil || *back.Timeouts != *agent.Timeouts {
+		t.Errorf("round trip Timeouts = %+v, %v", back.Timeouts, err)
+	}
+
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"90s", false},
+		{"1h30m", false},
+		{"10", true},
+		{"-5m", true},
+		{"soon", true},
+	}
+	for _, tt := range tests {
+		agent.Timeouts = &Timeouts{FileRead: tt.value}
+		if err := CheckTimeouts([]*Agent{agent}); (err != nil) != tt.wantErr {
+			t.Errorf("CheckTimeouts(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCheckRetries(t *testing.T) {
+	data := []byte("---\nname: fetcher\ndescription: Fetches\nretry:\n  maxAttempts: 3\n  backoff: 2s\n---\n\nBody\n")
+	agent, err := ParseMarkdownAgent(data, "fetcher.md")
+	if err != nil {
+		t.Fatalf("ParseMarkdownAgent() error = %v", err)
+	}
+	if agent.Retry == nil || agent.Retry.MaxAttempts != 3 || agent.Retry.Backoff != "2s" {
+		t.Fatalf("Retry = %+v", agent.Retry)
+	}
+	back, err := ParseMarkdownAgent(MarshalMarkdownAgent(agent), "fetcher.md")
+	if err != nil || back.Retry == nil || *back.Retry != *agent.Retry {
+		t.Errorf("round trip Retry = %+v, %v", back.Retry, err)
+	}
+
+	tests := []struct {
+		retry   RetryConfig
+		wantErr bool
+	}{
+		{RetryConfig{MaxAttempts: 1}, false},
+		{RetryConfig{MaxAttempts: 5, Backoff: "500ms"}, false},
+		{RetryConfig{MaxAttempts: 0}, true},
+		{RetryConfig{MaxAttempts: -2}, true},
+		{RetryConfig{MaxAttempts: 3, Backoff: "0s"}, true},
+		{RetryConfig{MaxAttempts: 3, Backoff: "-1s"}, true},
+		{RetryConfig{MaxAttempts: 3, Backoff: "later"}, true},
+	}
+	for _, tt := range tests {
+		retry := tt.retry
+		agent.Retry = &retry
+		if err := CheckRetries([]*Agent{agent}); (err != nil) != tt.wantErr {
+			t.Errorf("CheckRetries(%+v) error = %v, wantErr %v", tt.retry, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Argument is an input a parameterized agent accepts, such as a file path
+// or a ticket ID. Formats without parameter support ignore arguments.
+type Argument struct {
+	// Name identifies the argument; it must be unique within the agent.
+	Name string `json:"name" yaml:"name"`
+
+	// Description tells the caller what to pass.
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+
+	// Required marks arguments the caller must supply.
+	Required bool `json:"required,omitempty" yaml:"required,omitempty"`
+}
+
+// argumentNamePattern matches names usable as MCP prompt arguments and
+// function parameters.
+var argumentNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// CheckArguments verifies that every argument has a valid name and that no
+// agent declares the same argument twice.
+func CheckArguments(agents []*Agent) error {
+	for _, agent := range agents {
+		seen := make(map[string]bool, len(agent.Arguments))
+		for _, arg := range agent.Arguments {
+			if !argumentNamePattern.MatchString(arg.Name) {
+				return fmt.Errorf("agent %s: invalid argument name %q", agent.Name, arg.Name)
+			}
+			if seen[arg.Name] {
+				return fmt.Errorf("agent %s: argument %q declared twice", agent.Name, arg.Name)
+			}
+			seen[arg.Name] = true
+		}
+	}
+	return nil
+}
+
+// MCPPrompt is an agent exposed as an MCP prompt, in the shape returned by
+// prompts/list.
+type MCPPrompt struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Arguments   []Argument `json:"arguments,omitempty"`
+}
+
+// MCPPrompt returns the agent as an MCP prompt whose arguments are the
+// agent's Arguments.
+func (a *Agent) MCPPrompt() MCPPrompt {
+	return MCPPrompt{
+		Name:        a.Name,
+		Description: a.Description,
+		Arguments:   append([]Argument(nil), a.Arguments...),
+	}
+}
+
+// ParametersSchema returns the agent's arguments as a JSON Schema object,
+// the form function-calling APIs use for parameters. Every argument is a
+// string. It returns nil if the agent takes no arguments.
+func (a *Agent) ParametersSchema() map[string]any {
+	if len(a.Arguments) == 0 {
+		return nil
+	}
+
+	properties := make(map[string]any, len(a.Arguments))
+	required := []string{}
+	for _, arg := range a.Arguments {
+		prop := map[string]any{"type": "string"}
+		if arg.Description != "" {
+			prop["description"] = arg.Description
+		}
+		properties[arg.Name] = prop
+		if arg.Required {
+			required = append(required, arg.Name)
+		}
+	}
+
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestArgumentsRoundTrip(t *testing.T) {
+	data := []byte(`---
+name: triager
+description: Triages a ticket
+arguments:
+  - name: ticket_id
+    description: "Ticket to triage, e.g. ENG-42"
+    required: true
+  - name: severity
+---
+
+Triage the ticket.
+`)
+	agent, err := ParseMarkdownAgent(data, "triager.md")
+	if err != nil {
+		t.Fatalf("ParseMarkdownAgent() error = %v", err)
+	}
+	if len(agent.Arguments) != 2 || !agent.Arguments[0].Required || agent.Arguments[1].Required {
+		t.Fatalf("Arguments = %+v", agent.Arguments)
+	}
+
+	back, err := ParseMarkdownAgent(MarshalMarkdownAgent(agent), "triager.md")
+	if err != nil {
+		t.Fatalf("round trip error = %v", err)
+	}
+	if len(back.Arguments) != 2 || back.Arguments[0] != agent.Arguments[0] {
+		t.Errorf("round trip Arguments = %+v", back.Arguments)
+	}
+}
+
+func TestCheckArguments(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []Argument
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"valid", []Argument{{Name: "ticket_id", Required: true}, {Name: "severity"}}, false},
+		{"empty name", []Argument{{Description: "Unnamed"}}, true},
+		{"invalid name", []Argument{{Name: "ticket id"}}, true},
+		{"duplicate", []Argument{{Name: "path"}, {Name: "path", Required: true}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := NewAgent("triager", "Triages")
+			agent.Arguments = tt.args
+			if err := CheckArguments([]*Agent{agent}); (err != nil) != tt.wantErr {
+				t.Errorf("CheckArguments() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestArgumentOutputs(t *testing.T) {
+	agent := NewAgent("triager", "Triages a ticket")
+	agent.Arguments = []Argument{
+		{Name: "ticket_id", Description: "Ticket to triage", Required: true},
+		{Name: "severity"},
+	}
+
+	prompt, err := json.Marshal(agent.MCPPrompt())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"name":"triager","description":"Triages a ticket","arguments":[{"name":"ticket_id","description":"Ticket to triage","required":true},{"name":"severity"}]}`
+	if string(prompt) != want {
+		t.Errorf("MCPPrompt() = %s, want %s", prompt, want)
+	}
+
+	schema, err := json.Marshal(agent.ParametersSchema())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `{"properties":{"severity":{"type":"string"},"ticket_id":{"description":"Ticket to triage","type":"string"}},"required":["ticket_id"],"type":"object"}`
+	if string(schema) != want {
+		t.Errorf("ParametersSchema() = %s, want %s", schema, want)
+	}
+
+	if NewAgent("plain", "No inputs").ParametersSchema() != nil {
+		t.Error("ParametersSchema() should be nil without arguments")
+	}
+}
@@ -0,0 +1,51 @@
+package core
+
+// Catalog is a JSON-serializable listing of agents together with their
+// rendered output for every registered format.
+type Catalog struct {
+	Agents []CatalogEntry `json:"agents"`
+}
+
+// CatalogEntry holds one agent's canonical definition and its output per
+// format. Outputs only contains formats whose adapter marshaled the agent;
+// the errors of the others are recorded in Errors.
+type CatalogEntry struct {
+	Agent   *Agent            `json:"agent"`
+	Outputs map[string]string `json:"outputs"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
+
+// BuildCatalog renders every agent with every adapter in the default
+// registry. A marshal error affects only that agent and format, so one
+// unsupported combination never fails the whole catalog.
+func BuildCatalog(agents []*Agent) *Catalog {
+	return DefaultRegistry.BuildCatalog(agents)
+}
+
+// BuildCatalog renders every agent with every adapter in the registry.
+func (r *Registry) BuildCatalog(agents []*Agent) *Catalog {
+	catalog := &Catalog{Agents: make([]CatalogEntry, 0, len(agents))}
+	names := r.AdapterNames()
+
+	for _, agent := range agents {
+		entry := CatalogEntry{
+			Agent:   agent,
+			Outputs: make(map[string]string, len(names)),
+		}
+		for _, name := range names {
+			adapter, _ := r.GetAdapter(name)
+			data, err := adapter.Marshal(agent)
+			if err != nil {
+				if entry.Errors == nil {
+					entry.Errors = make(map[string]string)
+				}
+				entry.Errors[name] = err.Error()
+				continue
+			}
+			entry.Outputs[name] = string(data)
+		}
+		catalog.Agents = append(catalog.Agents, entry)
+	}
+
+	return catalog
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// catalogAdapter marshals agents to "format:name", failing for names in fail.
+type catalogAdapter struct {
+	stubAdapter
+	fail map[string]bool
+}
+
+func (a *catalogAdapter) Marshal(agent *Agent) ([]byte, error) {
+	if a.fail[agent.Name] {
+		return nil, errors.New("cannot render " + agent.Name)
+	}
+	return []byte(a.name + ":" + agent.Name), nil
+}
+
+func TestBuildCatalog(t *testing.T) {
+	r := NewRegistry()
+	r.Register(&catalogAdapter{stubAdapter: stubAdapter{name: "plain"}})
+	r.Register(&catalogAdapter{stubAdapter: stubAdapter{name: "picky"}, fail: map[string]bool{"odd": true}})
+
+	catalog := r.BuildCatalog([]*Agent{NewAgent("reviewer", "Reviews"), NewAgent("odd", "Odd one")})
+	if len(catalog.Agents) != 2 {
+		t.Fatalf("len(Agents) = %d, want 2", len(catalog.Agents))
+	}
+
+	reviewer := catalog.Agents[0]
+	if reviewer.Outputs["plain"] != "plain:reviewer" || reviewer.Outputs["picky"] != "picky:reviewer" {
+		t.Errorf("reviewer outputs = %v", reviewer.Outputs)
+	}
+	if reviewer.Errors != nil {
+		t.Errorf("reviewer errors = %v, want none", reviewer.Errors)
+	}
+
+	odd := catalog.Agents[1]
+	if _, ok := odd.Outputs["picky"]; ok {
+		t.Error("failed format should be omitted from outputs")
+	}
+	if odd.Outputs["plain"] != "plain:odd" {
+		t.Errorf("odd outputs = %v", odd.Outputs)
+	}
+	if !strings.Contains(odd.Errors["picky"], "cannot render odd") {
+		t.Errorf("odd errors = %v", odd.Errors)
+	}
+
+	data, err := json.Marshal(catalog)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"name":"reviewer"`) {
+		t.Errorf("catalog JSON missing canonical fields: %s", data)
+	}
+}
@@ -0,0 +1,24 @@
+package core
+
+// ConfigAdapter is implemented by adapters backed by a typed config struct
+// C (e.g. *kiro.AgentConfig). FromCore returns the value Marshal encodes,
+// letting tooling inspect or modify it without a marshal/unmarshal round
+// trip. Adapters following this pattern also provide a ToCore method for
+// the reverse conversion.
+type ConfigAdapter[C any] interface {
+	Adapter
+
+	// FromCore converts canonical Agent to the adapter's config type.
+	FromCore(agent *Agent) C
+}
+
+// ToConfig converts agent to the typed config of adapter. It reports false
+// if adapter is not a ConfigAdapter for C.
+func ToConfig[C any](adapter Adapter, agent *Agent) (C, bool) {
+	ca, ok := adapter.(ConfigAdapter[C])
+	if !ok {
+		var zero C
+		return zero, false
+	}
+	return ca.FromCore(agent), true
+}
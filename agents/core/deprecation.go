@@ -0,0 +1,48 @@
+package core
+
+// DefaultDeprecationMessage is used when a deprecated agent has no
+// DeprecationMessage.
+const DefaultDeprecationMessage = "This agent is deprecated and will be removed."
+
+// DeprecationNotice returns the notice prepended to a deprecated agent's
+// instructions, or "" if the agent is not deprecated.
+func (a *Agent) DeprecationNotice() string {
+	if !a.Deprecated {
+		return ""
+	}
+	msg := a.DeprecationMessage
+	if msg == "" {
+		msg = DefaultDeprecationMessage
+	}
+	return "> **Deprecated:** " + msg
+}
+
+// WithDeprecationNotice returns a copy of agent whose instructions start
+// with its deprecation notice. Agents that are not deprecated are returned
+// unchanged.
+func WithDeprecationNotice(agent *Agent) *Agent {
+	notice := agent.DeprecationNotice()
+	if notice == "" {
+		return agent
+	}
+	marked := *agent
+	if marked.Instructions == "" {
+		marked.Instructions = notice
+	} else {
+		marked.Instructions = notice + "\n\n" + marked.Instructions
+	}
+	marked.syncInstructionSections()
+	return &marked
+}
+
+// FilterDeprecated returns the agents that are not deprecated, preserving
+// order.
+func FilterDeprecated(agents []*Agent) []*Agent {
+	out := make([]*Agent, 0, len(agents))
+	for _, agent := range agents {
+		if !agent.Deprecated {
+			out = append(out, agent)
+		}
+	}
+	return out
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeprecation(t *testing.T) {
+	data := []byte("---\nname: old-reviewer\ndescription: Reviews code\ndeprecated: true\ndeprecationMessage: Use reviewer instead.\n---\n\nReview the diff.\n")
+	agent, err := ParseMarkdownAgent(data, "old-reviewer.md")
+	if err != nil {
+		t.Fatalf("ParseMarkdownAgent() error = %v", err)
+	}
+	if !agent.Deprecated || agent.DeprecationMessage != "Use reviewer instead." {
+		t.Fatalf("Deprecated = %v, DeprecationMessage = %q", agent.Deprecated, agent.DeprecationMessage)
+	}
+
+	back, err := ParseMarkdownAgent(MarshalMarkdownAgent(agent), "old-reviewer.md")
+	if err != nil {
+		t.Fatalf("round trip error = %v", err)
+	}
+	if !back.Deprecated || back.DeprecationMessage != agent.DeprecationMessage {
+		t.Errorf("round trip lost deprecation: %+v", back)
+	}
+
+	marked := WithDeprecationNotice(agent)
+	if want := "> **Deprecated:** Use reviewer instead.\n\nReview the diff."; marked.Instructions != want {
+		t.Errorf("Instructions = %q, want %q", marked.Instructions, want)
+	}
+	if agent.Instructions != "Review the diff." {
+		t.Error("WithDeprecationNotice() modified the original agent")
+	}
+
+	agent.DeprecationMessage = ""
+	if notice := agent.DeprecationNotice(); !strings.Contains(notice, DefaultDeprecationMessage) {
+		t.Errorf("DeprecationNotice() = %q, want default message", notice)
+	}
+
+	current := NewAgent("reviewer", "Reviews code")
+	if WithDeprecationNotice(current) != current {
+		t.Error("WithDeprecationNotice() should return current agents unchanged")
+	}
+	if got := FilterDeprecated([]*Agent{agent, current}); len(got) != 1 || got[0] != current {
+		t.Errorf("FilterDeprecated() = %v", got)
+	}
+}
+
+func TestMarshalMarkdownAgentQuotesScalars(t *testing.T) {
+	agent := NewAgent("old-reviewer", "Reviews code: style, tests")
+	agent.Deprecated = true
+	agent.DeprecationMessage = "Deprecated: use reviewer instead."
+	agent.Workspace = "#shared"
+	agent.Group = "[platform]"
+	agent.Category = "code: review"
+	agent.Scope = "@project"
+	agent.Base = "base: reviewer"
+
+	back, err := ParseMarkdownAgent(MarshalMarkdownAgent(agent), "old-reviewer.md")
+	if err != nil {
+		t.Fatalf("round trip error = %v", err)
+	}
+	if back.Description != agent.Description || back.DeprecationMessage != agent.DeprecationMessage ||
+		back.Workspace != agent.Workspace || back.Group != agent.Group || back.Category != agent.Category ||
+		back.Scope != agent.Scope || back.Base != agent.Base {
+		t.Errorf("round trip = %+v, want %+v", back.Spec, agent.Spec)
+	}
+}
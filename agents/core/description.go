@@ -0,0 +1,144 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DescriptionMode selects how an over-long description is shortened.
+type DescriptionMode string
+
+const (
+	// DescriptionTruncate cuts the description and appends an ellipsis.
+	DescriptionTruncate DescriptionMode = "truncate"
+
+	// DescriptionWrap breaks the description into lines of at most MaxLength.
+	DescriptionWrap DescriptionMode = "wrap"
+)
+
+// Ellipsis is appended to truncated descriptions.
+const Ellipsis = "…"
+
+// DescriptionStyle limits the description emitted by an adapter. The zero
+// value leaves descriptions unchanged. Lengths count runes, not bytes.
+type DescriptionStyle struct {
+	MaxLength int
+	Mode      DescriptionMode
+}
+
+// Apply returns desc adjusted to the style.
+func (s DescriptionStyle) Apply(desc string) string {
+	if s.MaxLength <= 0 {
+		return desc
+	}
+	if s.Mode == DescriptionWrap {
+		return WrapText(desc, s.MaxLength)
+	}
+	return TruncateText(desc, s.MaxLength)
+}
+
+// ParseDescriptionStyle parses "adapter:mode:max" (e.g., "kiro:truncate:80")
+// and returns the adapter name and style.
+func ParseDescriptionStyle(s string) (string, DescriptionStyle, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return "", DescriptionStyle{}, fmt.Errorf("invalid description style %q (expected adapter:mode:max)", s)
+	}
+
+	mode := DescriptionMode(parts[1])
+	if mode != DescriptionTruncate && mode != DescriptionWrap {
+		return "", DescriptionStyle{}, fmt.Errorf("invalid description mode %q (expected truncate or wrap)", parts[1])
+	}
+	maxLen, err := strconv.Atoi(parts[2])
+	if err != nil || maxLen <= 0 {
+		return "", DescriptionStyle{}, fmt.Errorf("invalid description max length %q", parts[2])
+	}
+
+	return parts[0], DescriptionStyle{MaxLength: maxLen, Mode: mode}, nil
+}
+
+// TruncateText shortens s to at most maxLen runes, ending with Ellipsis when
+// cut. It never splits a multibyte character.
+func TruncateText(s string, maxLen int) string {
+	if maxLen <= 0 || utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	keep := maxLen - utf8.RuneCountInString(Ellipsis)
+	if keep <= 0 {
+		return string([]rune(Ellipsis)[:maxLen])
+	}
+	runes := []rune(s)
+	return strings.TrimRightFunc(string(runes[:keep]), unicode.IsSpace) + Ellipsis
+}
+
+// WrapText breaks s at whitespace into lines of at most width runes. Words
+// longer than width are placed on a line of their own.
+func WrapText(s string, width int) string {
+	words := strings.Fields(s)
+	if width <= 0 || len(words) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	lineLen := 0
+	for _, word := range words {
+		n := utf8.RuneCountInString(word)
+		switch {
+		case lineLen == 0:
+		case lineLen+1+n > width:
+			b.WriteByte('\n')
+			lineLen = 0
+		default:
+			b.WriteByte(' ')
+			lineLen++
+		}
+		b.WriteString(word)
+		lineLen += n
+	}
+	return b.String()
+}
+
+// SetDescriptionStyle sets the description style of the named adapter in r.
+func (r *Registry) SetDescriptionStyle(adapter string, style DescriptionStyle) {
+	r.settingsMu.Lock()
+	defer r.settingsMu.Unlock()
+	r.descriptionStyles[adapter] = style
+}
+
+// DescriptionStyle returns the description style of the named adapter in r.
+func (r *Registry) DescriptionStyle(adapter string) DescriptionStyle {
+	r.settingsMu.RLock()
+	defer r.settingsMu.RUnlock()
+	return r.descriptionStyles[adapter]
+}
+
+// SetDescriptionStyle sets the description style of the named adapter in
+// DefaultRegistry, which FormatDescription applies.
+func SetDescriptionStyle(adapter string, style DescriptionStyle) {
+	DefaultRegistry.SetDescriptionStyle(adapter, style)
+}
+
+// DescriptionStyleFor returns the description style of the named adapter
+// in DefaultRegistry.
+func DescriptionStyleFor(adapter string) DescriptionStyle {
+	return DefaultRegistry.DescriptionStyle(adapter)
+}
+
+// FormatDescription returns desc as the named adapter should emit it, using
+// the description styles of DefaultRegistry. The canonical Description is
+// never modified.
+func FormatDescription(adapter, desc string) string {
+	return DescriptionStyleFor(adapter).Apply(desc)
+}
+
+// FormatDescriptionLine is FormatDescription for formats that hold the
+// description on a single line, such as Markdown frontmatter; wrap styles
+// truncate instead.
+func FormatDescriptionLine(adapter, desc string) string {
+	style := DescriptionStyleFor(adapter)
+	style.Mode = DescriptionTruncate
+	return style.Apply(desc)
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		max  int
+		want string
+	}{
+		{"short", "Reviews code", 20, "Reviews code"},
+		{"exact", "Reviews code", 12, "Reviews code"},
+		{"ascii", "Reviews pull requests", 10, "Reviews p…"},
+		{"trims space before ellipsis", "Reviews pull requests", 9, "Reviews…"},
+		{"cjk", "コードをレビューします", 6, "コードをレ…"},
+		{"emoji", "🚀🚀🚀🚀 launch", 3, "🚀🚀…"},
+		{"accented", "Résumé généré", 7, "Résumé…"},
+		{"max one", "Reviews code", 1, "…"},
+		{"disabled", "Reviews code", 0, "Reviews code"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateText(tt.in, tt.max)
+			if got != tt.want {
+				t.Errorf("TruncateText(%q, %d) = %q, want %q", tt.in, tt.max, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("TruncateText(%q, %d) produced invalid UTF-8", tt.in, tt.max)
+			}
+			if tt.max > 0 && utf8.RuneCountInString(got) > tt.max {
+				t.Errorf("TruncateText(%q, %d) = %q exceeds max", tt.in, tt.max, got)
+			}
+		})
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		width int
+		want  string
+	}{
+		{"fits", "Reviews code", 20, "Reviews code"},
+		{"wraps", "Reviews pull requests for style", 14, "Reviews pull\nrequests for\nstyle"},
+		{"long word", "a supercalifragilistic word", 8, "a\nsupercalifragilistic\nword"},
+		{"multibyte counts runes", "ééé ééé ééé", 7, "ééé ééé\nééé"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WrapText(tt.in, tt.width); got != tt.want {
+				t.Errorf("WrapText(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDescriptionStyle(t *testing.T) {
+	name, style, err := ParseDescriptionStyle("kiro:truncate:80")
+	if err != nil {
+		t.Fatalf("ParseDescriptionStyle() error = %v", err)
+	}
+	if name != "kiro" || style.Mode != DescriptionTruncate || style.MaxLength != 80 {
+		t.Errorf("ParseDescriptionStyle() = %q, %+v", name, style)
+	}
+
+	for _, s := range []string{"kiro", "kiro:cut:80", "kiro:wrap:0", "kiro:wrap:x"} {
+		if _, _, err := ParseDescriptionStyle(s); err == nil {
+			t.Errorf("ParseDescriptionStyle(%q) should fail", s)
+		}
+	}
+}
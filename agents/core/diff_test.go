@@ -0,0 +1,77 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// markdownAdapter marshals agents as canonical Markdown.
+type markdownAdapter struct{ Adapter }
+
+func (markdownAdapter) Marshal(agent *Agent) ([]byte, error) {
+	return MarshalMarkdownAgent(agent), nil
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{
+			name: "changed line",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:    "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate hunks",
+			a:    "a\n1\n2\n3\n4\n5\n6\n7\nz\n",
+			b:    "A\n1\n2\n3\n4\n5\n6\n7\nZ\n",
+			want: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n@@ -6,4 +6,4 @@\n 5\n 6\n 7\n-z\n+Z\n",
+		},
+		{
+			name: "new file",
+			a:    "",
+			b:    "x\ny\n",
+			want: "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+x\n+y\n",
+		},
+		{
+			name: "missing final newline",
+			a:    "x",
+			b:    "x\n",
+			want: "--- a\n+++ b\n@@ -1 +1 @@\n-x\n\\ No newline at end of file\n+x\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("a", "b", tt.a, tt.b); got != tt.want {
+				t.Errorf("unifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	adapter := markdownAdapter{}
+	agent := NewAgent("reviewer", "Reviews code").WithInstructions("Review the diff.")
+	path := filepath.Join(t.TempDir(), "reviewer.md")
+
+	diff, changed, err := Diff(adapter, agent, path)
+	if err != nil || !changed || diff[:len("--- /dev/null\n")] != "--- /dev/null\n" {
+		t.Errorf("Diff(missing) = %q, %v, %v", diff, changed, err)
+	}
+
+	if err := os.WriteFile(path, MarshalMarkdownAgent(agent), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if diff, changed, err := Diff(adapter, agent, path); err != nil || changed || diff != "" {
+		t.Errorf("Diff(in sync) = %q, %v, %v", diff, changed, err)
+	}
+
+	agent.Instructions = "Review the whole file."
+	if _, changed, err := Diff(adapter, agent, path); err != nil || !changed {
+		t.Errorf("Diff(changed) = %v, %v", changed, err)
+	}
+}
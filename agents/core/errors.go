@@ -1,6 +1,14 @@
 package core
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotSupported is wrapped by adapters for operations they do not
+// implement, such as parsing generate-only output formats.
+var ErrNotSupported = errors.New("not supported")
 
 // ReadError indicates a failure to read a file.
 type ReadError struct {
@@ -70,3 +78,102 @@ type AdapterError struct {
 func (e *AdapterError) Error() string {
 	return fmt.Sprintf("unknown adapter: %s", e.Name)
 }
+
+// InheritanceError indicates an `extends` reference that cannot be resolved,
+// either because the base agent does not exist or because the chain loops.
+type InheritanceError struct {
+	Agent string
+	Base  string   // Missing base agent name
+	Chain []string // Agent chain forming a cycle
+}
+
+func (e *InheritanceError) Error() string {
+	if len(e.Chain) > 0 {
+		return fmt.Sprintf("inheritance cycle for agent %s: %s", e.Agent, strings.Join(e.Chain, " -> "))
+	}
+	return fmt.Sprintf("agent %s extends unknown agent %s", e.Agent, e.Base)
+}
+
+// UndefinedVariableError indicates a ${NAME} placeholder in an agent whose
+// variable is not defined.
+type UndefinedVariableError struct {
+	Agent string
+	Field string // Field holding the placeholder (e.g., "instructions")
+	Name  string
+}
+
+func (e *UndefinedVariableError) Error() string {
+	return fmt.Sprintf("agent %s: undefined variable %s in %s", e.Agent, e.Name, e.Field)
+}
+
+// DuplicateNameError indicates that several agents share the same name.
+type DuplicateNameError struct {
+	Name  string
+	Paths []string // Source paths of every agent with this name
+}
+
+func (e *DuplicateNameError) Error() string {
+	return fmt.Sprintf("duplicate agent name %q: defined in %s", e.Name, strings.Join(e.Paths, " and "))
+}
+
+// UnknownToolError indicates an agent tool outside an adapter's known set.
+type UnknownToolError struct {
+	Agent  string
+	Tool   string
+	Format string
+}
+
+func (e *UnknownToolError) Error() string {
+	return fmt.Sprintf("agent %s: unknown tool %q for %s", e.Agent, e.Tool, e.Format)
+}
+
+// UnknownHashError indicates a hash algorithm that is not registered.
+type UnknownHashError struct {
+	Algorithm string
+}
+
+func (e *UnknownHashError) Error() string {
+	return fmt.Sprintf("unknown hash algorithm %q (available: %s)", e.Algorithm, strings.Join(HashAlgorithms(), ", "))
+}
+
+// FieldError is a problem with a single agent field.
+type FieldError struct {
+	Field   string // Spec key, with an index for list entries (e.g., "tools[1]")
+	Value   string // Offending value; empty for missing fields
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	if e.Value != "" {
+		return fmt.Sprintf("%s %q %s", e.Field, e.Value, e.Message)
+	}
+	return fmt.Sprintf("%s %s", e.Field, e.Message)
+}
+
+// ValidationError indicates an agent that failed Validate. It lists every
+// field-level problem found.
+type ValidationError struct {
+	Agent    string
+	Path     string // Source file, if known
+	Problems []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = p.Error()
+	}
+	name := e.Agent
+	if e.Path != "" {
+		name = fmt.Sprintf("%s (%s)", e.Agent, e.Path)
+	}
+	return fmt.Sprintf("invalid agent %s: %s", name, strings.Join(msgs, "; "))
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Problems))
+	for i, p := range e.Problems {
+		errs[i] = p
+	}
+	return errs
+}
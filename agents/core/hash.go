@@ -0,0 +1,197 @@
+package core
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Built-in content hash algorithms.
+const (
+	// HashSHA256 is the default content hash algorithm.
+	HashSHA256 = "sha256"
+
+	// HashSHA512 is faster than SHA-256 on most 64-bit CPUs.
+	HashSHA512 = "sha512"
+)
+
+var (
+	hashMu  sync.RWMutex
+	hashers = map[string]func() hash.Hash{HashSHA256: sha256.New, HashSHA512: sha512.New}
+)
+
+// RegisterHasher makes a hash algorithm available under name, e.g. to plug
+// in BLAKE3 for very large repositories.
+func RegisterHasher(name string, newHash func() hash.Hash) {
+	hashMu.Lock()
+	defer hashMu.Unlock()
+	hashers[name] = newHash
+}
+
+// HashAlgorithms returns the registered hash algorithm names, sorted.
+func HashAlgorithms() []string {
+	hashMu.RLock()
+	defer hashMu.RUnlock()
+	names := make([]string, 0, len(hashers))
+	for name := range hashers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetHashAlgorithm sets the algorithm r.ContentHash uses. It fails if the
+// algorithm is not registered.
+func (r *Registry) SetHashAlgorithm(name string) error {
+	hashMu.RLock()
+	_, ok := hashers[name]
+	hashMu.RUnlock()
+	if !ok {
+		return &UnknownHashError{Algorithm: name}
+	}
+	r.settingsMu.Lock()
+	defer r.settingsMu.Unlock()
+	r.hashAlgorithm = name
+	return nil
+}
+
+// HashAlgorithm returns the algorithm r.ContentHash uses.
+func (r *Registry) HashAlgorithm() string {
+	r.settingsMu.RLock()
+	defer r.settingsMu.RUnlock()
+	return r.hashAlgorithm
+}
+
+// ContentHash returns the hex digest of data using the hash algorithm of r.
+func (r *Registry) ContentHash(data []byte) string {
+	digest, _ := HashWith(r.HashAlgorithm(), data)
+	return digest
+}
+
+// SetHashAlgorithm sets the hash algorithm of DefaultRegistry, which
+// ContentHash and NewLockfile use.
+func SetHashAlgorithm(name string) error {
+	return DefaultRegistry.SetHashAlgorithm(name)
+}
+
+// HashAlgorithm returns the hash algorithm of DefaultRegistry.
+func HashAlgorithm() string {
+	return DefaultRegistry.HashAlgorithm()
+}
+
+// ContentHash is Registry.ContentHash on DefaultRegistry.
+func ContentHash(data []byte) string {
+	return DefaultRegistry.ContentHash(data)
+}
+
+// HashWith returns the hex digest of data using the named algorithm.
+func HashWith(algorithm string, data []byte) (string, error) {
+	hashMu.RLock()
+	newHash, ok := hashers[algorithm]
+	hashMu.RUnlock()
+	if !ok {
+		return "", &UnknownHashError{Algorithm: algorithm}
+	}
+	h := newHash()
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Lockfile records content hashes of generated files, keyed by
+// slash-separated path relative to the lockfile's directory. The algorithm
+// is stored alongside the hashes so verification always recomputes them
+// the same way, whatever the current default.
+type Lockfile struct {
+	HashAlgorithm string            `json:"hashAlgorithm"`
+	Files         map[string]string `json:"files"`
+}
+
+// NewLockfile is Registry.NewLockfile on DefaultRegistry.
+func NewLockfile(base string, dirs []string) (*Lockfile, error) {
+	return DefaultRegistry.NewLockfile(base, dirs)
+}
+
+// NewLockfile hashes every file under dirs using the hash algorithm of r.
+// Paths are recorded relative to base.
+func (r *Registry) NewLockfile(base string, dirs []string) (*Lockfile, error) {
+	lock := &Lockfile{HashAlgorithm: r.HashAlgorithm(), Files: make(map[string]string)}
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(base, path)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			lock.Files[filepath.ToSlash(rel)], err = HashWith(lock.HashAlgorithm, data)
+			return err
+		})
+		if err != nil {
+			return nil, &ReadError{Path: dir, Err: err}
+		}
+	}
+	return lock, nil
+}
+
+// ReadLockfile reads a lockfile written by WriteLockfile.
+func ReadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &ReadError{Path: path, Err: err}
+	}
+	var lock Lockfile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, &ParseError{Format: "lockfile", Path: path, Err: err}
+	}
+	if lock.HashAlgorithm == "" {
+		lock.HashAlgorithm = HashSHA256
+	}
+	return &lock, nil
+}
+
+// WriteLockfile writes the lockfile as indented JSON.
+func WriteLockfile(lock *Lockfile, path string) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return &MarshalError{Format: "lockfile", Err: err}
+	}
+	return WriteOutputFile(path, append(data, '\n'))
+}
+
+// Verify rehashes the locked files under base with the lockfile's own
+// algorithm and returns the paths that changed or are missing, sorted.
+func (l *Lockfile) Verify(base string) ([]string, error) {
+	var drifted []string
+	for rel, want := range l.Files {
+		data, err := os.ReadFile(filepath.Join(base, filepath.FromSlash(rel)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				drifted = append(drifted, rel)
+				continue
+			}
+			return nil, &ReadError{Path: rel, Err: err}
+		}
+		got, err := HashWith(l.HashAlgorithm, data)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.EqualFold(got, want) {
+			drifted = append(drifted, rel)
+		}
+	}
+	sort.Strings(drifted)
+	return drifted, nil
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"crypto/md5" //nolint:gosec // test-only registered algorithm
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashAlgorithm(t *testing.T) {
+	r := NewRegistry()
+
+	if got := r.ContentHash([]byte("abc")); got != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("ContentHash() = %s, want sha256 digest", got)
+	}
+
+	var unknown *UnknownHashError
+	if err := r.SetHashAlgorithm("blake3"); !errors.As(err, &unknown) {
+		t.Errorf("SetHashAlgorithm(blake3) error = %v, want UnknownHashError", err)
+	}
+
+	RegisterHasher("md5", md5.New)
+	if err := r.SetHashAlgorithm("md5"); err != nil {
+		t.Fatalf("SetHashAlgorithm(md5) error = %v", err)
+	}
+	if got := r.ContentHash([]byte("abc")); got != "900150983cd24fb0d6963f7d28e17f72" {
+		t.Errorf("ContentHash() = %s, want md5 digest", got)
+	}
+	if got := HashAlgorithm(); got != HashSHA256 {
+		t.Errorf("DefaultRegistry HashAlgorithm() = %s, want unchanged %s", got, HashSHA256)
+	}
+}
+
+func TestLockfileVerify(t *testing.T) {
+	r := NewRegistry()
+
+	base := t.TempDir()
+	out := filepath.Join(base, "agents")
+	for name, content := range map[string]string{"a.md": "alpha", "b.md": "beta"} {
+		if err := WriteOutputFile(filepath.Join(out, name), []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := r.SetHashAlgorithm(HashSHA512); err != nil {
+		t.Fatal(err)
+	}
+	lock, err := r.NewLockfile(base, []string{out})
+	if err != nil {
+		t.Fatalf("NewLockfile() error = %v", err)
+	}
+	path := filepath.Join(base, "agents.lock")
+	if err := WriteLockfile(lock, path); err != nil {
+		t.Fatalf("WriteLockfile() error = %v", err)
+	}
+
+	// Verification uses the recorded algorithm, not the current default
+	if err := r.SetHashAlgorithm(HashSHA256); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadLockfile(path)
+	if err != nil {
+		t.Fatalf("ReadLockfile() error = %v", err)
+	}
+	if read.HashAlgorithm != HashSHA512 || len(read.Files) != 2 {
+		t.Fatalf("lockfile = %+v", read)
+	}
+	if drifted, err := read.Verify(base); err != nil || len(drifted) != 0 {
+		t.Errorf("Verify() = %v, %v; want no drift", drifted, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(out, "a.md"), []byte("changed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(out, "b.md")); err != nil {
+		t.Fatal(err)
+	}
+	drifted, err := read.Verify(base)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if len(drifted) != 2 || drifted[0] != "agents/a.md" || drifted[1] != "agents/b.md" {
+		t.Errorf("Verify() = %v, want both files", drifted)
+	}
+}
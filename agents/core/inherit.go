@@ -0,0 +1,150 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// specHeader holds canonical spec keys that are resolved at load time
+// and are not part of the agent definition itself.
+type specHeader struct {
+	// Team is an alias for the agent's group key.
+	Team string `json:"team,omitempty" yaml:"team,omitempty"`
+}
+
+// parseSpecHeader extracts load-time keys from a canonical spec file.
+func parseSpecHeader(path string, data []byte) (*specHeader, error) {
+	var header specHeader
+
+	if filepath.Ext(path) == ".json" {
+		if err := json.Unmarshal(data, &header); err != nil {
+			return nil, &ParseError{Format: "canonical", Path: path, Err: err}
+		}
+		return &header, nil
+	}
+
+	frontmatter := extractFrontmatter(data)
+	if len(frontmatter) == 0 {
+		return &header, nil
+	}
+	if err := yaml.Unmarshal(frontmatter, &header); err != nil {
+		return nil, &ParseError{Format: "markdown", Path: path, Err: err}
+	}
+	return &header, nil
+}
+
+// extractFrontmatter returns the YAML frontmatter between the leading "---"
+// delimiters, or nil if the data has no frontmatter.
+func extractFrontmatter(data []byte) []byte {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "---" {
+		return nil
+	}
+
+	var fm bytes.Buffer
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "---" {
+			return fm.Bytes()
+		}
+		fm.WriteString(line)
+		fm.WriteString("\n")
+	}
+	return nil
+}
+
+// ResolveInheritance merges every agent with a Base into a copy that
+// inherits the base's unset fields. The agent's instructions replace the
+// base's, unless it sets PrependInstructions to keep the base's first.
+// Bases are looked up by qualified name first, then by plain
+// name. Multi-level chains are resolved base-first, so the result does not
+// depend on order; a chain that loops fails with an *InheritanceError.
+//
+// The returned slice preserves the input order but omits Abstract agents.
+// Merged agents have Base cleared, so resolving them again is a no-op; the
+// input agents are not modified.
+func ResolveInheritance(agents []*Agent) ([]*Agent, error) {
+	byName := make(map[string]*Agent, len(agents))
+	for _, agent := range agents {
+		if _, ok := byName[agent.Name]; !ok {
+			byName[agent.Name] = agent
+		}
+	}
+	for _, agent := range agents {
+		byName[agent.QualifiedName()] = agent
+	}
+
+	resolved := make(map[*Agent]*Agent, len(agents))
+	visiting := make(map[*Agent]bool)
+
+	var resolve func(agent *Agent, chain []string) (*Agent, error)
+	resolve = func(agent *Agent, chain []string) (*Agent, error) {
+		if done, ok := resolved[agent]; ok {
+			return done, nil
+		}
+
+		chain = append(chain, agent.QualifiedName())
+		baseName := agent.Base
+		if baseName == "" {
+			resolved[agent] = agent
+			return agent, nil
+		}
+
+		if visiting[agent] {
+			return nil, &InheritanceError{Agent: chain[0], Chain: chain}
+		}
+		visiting[agent] = true
+		defer delete(visiting, agent)
+
+		base, ok := byName[baseName]
+		if !ok {
+			return nil, &InheritanceError{Agent: agent.QualifiedName(), Base: baseName}
+		}
+
+		resolvedBase, err := resolve(base, chain)
+		if err != nil {
+			return nil, err
+		}
+
+		merged := inheritAgent(resolvedBase, agent)
+		resolved[agent] = merged
+		return merged, nil
+	}
+
+	result := make([]*Agent, 0, len(agents))
+	for _, agent := range agents {
+		merged, err := resolve(agent, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !agent.Abstract {
+			result = append(result, merged)
+		}
+	}
+
+	return result, nil
+}
+
+// inheritAgent returns MergeAgent(base, child) with Base cleared and, if
+// child sets PrependInstructions, base's instructions prepended to its own.
+func inheritAgent(base, child *Agent) *Agent {
+	merged := MergeAgent(base, child)
+	merged.Base = ""
+	if child.PrependInstructions && child.Instructions != "" && base.Instructions != "" {
+		merged.Instructions = base.Instructions + "\n\n" + child.Instructions
+		merged.syncInstructionSections()
+	}
+	return merged
+}
+
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s...)
+}
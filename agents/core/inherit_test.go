@@ -0,0 +1,196 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSpec(t testing.TB, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+}
+
+func findAgent(agents []*Agent, name string) *Agent {
+	for _, a := range agents {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+func TestReadCanonicalDirExtends(t *testing.T) {
+	dir := t.TempDir()
+	writeSpec(t, dir, "base.md", `---
+name: base
+description: Base reviewer
+model: opus
+tools: [Read, Grep]
+---
+
+Review carefully.
+`)
+	writeSpec(t, dir, "mid.md", `---
+name: mid
+extends: base
+tools: [Read, Grep, Bash]
+---
+`)
+	writeSpec(t, dir, "leaf.md", `---
+name: leaf
+description: Leaf reviewer
+extends: mid
+model: haiku
+---
+
+Leaf instructions.
+`)
+
+	agents, err := ReadCanonicalDir(dir)
+	if err != nil {
+		t.Fatalf("ReadCanonicalDir() error = %v", err)
+	}
+	if len(agents) != 3 {
+		t.Fatalf("expected 3 agents, got %d", len(agents))
+	}
+
+	mid := findAgent(agents, "mid")
+	if mid.Description != "Base reviewer" {
+		t.Errorf("mid.Description = %q, want inherited %q", mid.Description, "Base reviewer")
+	}
+	if mid.Model != ModelOpus {
+		t.Errorf("mid.Model = %q, want %q", mid.Model, ModelOpus)
+	}
+	if len(mid.Tools) != 3 {
+		t.Errorf("mid.Tools = %v, want child override", mid.Tools)
+	}
+	if mid.Instructions != "Review carefully." {
+		t.Errorf("mid.Instructions = %q, want inherited", mid.Instructions)
+	}
+
+	leaf := findAgent(agents, "leaf")
+	if leaf.Description != "Leaf reviewer" {
+		t.Errorf("leaf.Description = %q, want override", leaf.Description)
+	}
+	if leaf.Model != ModelHaiku {
+		t.Errorf("leaf.Model = %q, want %q", leaf.Model, ModelHaiku)
+	}
+	if len(leaf.Tools) != 3 {
+		t.Errorf("leaf.Tools = %v, want tools inherited through mid", leaf.Tools)
+	}
+	if leaf.Instructions != "Leaf instructions." {
+		t.Errorf("leaf.Instructions = %q, want override", leaf.Instructions)
+	}
+	if leaf.Base != "" {
+		t.Errorf("leaf.Base = %q, want cleared after resolution", leaf.Base)
+	}
+
+	base := findAgent(agents, "base")
+	if len(base.Tools) != 2 {
+		t.Errorf("base.Tools = %v, base must not be modified by children", base.Tools)
+	}
+}
+
+func TestResolveInheritance(t *testing.T) {
+	base := NewAgent("base-reviewer", "Reviews code").WithTools("Read", "Grep").WithInstructions("Be thorough.")
+	base.Abstract = true
+	child := NewAgent("go-reviewer", "Reviews Go code").WithInstructions("Run go vet.")
+	child.Base = "base-reviewer"
+	child.PrependInstructions = true
+	child.Model = ""
+
+	agents, err := ResolveInheritance([]*Agent{base, child})
+	if err != nil {
+		t.Fatalf("ResolveInheritance() error = %v", err)
+	}
+	if len(agents) != 1 || agents[0].Name != "go-reviewer" {
+		t.Fatalf("ResolveInheritance() = %v, want only the concrete child", agents)
+	}
+	got := agents[0]
+	if got.Description != "Reviews Go code" || got.Model != base.Model || len(got.Tools) != 2 {
+		t.Errorf("merged = %+v, want the child's description and the base's model and tools", got.Spec)
+	}
+	if got.Instructions != "Be thorough.\n\nRun go vet." {
+		t.Errorf("Instructions = %q", got.Instructions)
+	}
+	if child.Base != "base-reviewer" || len(child.Tools) != 0 {
+		t.Error("ResolveInheritance modified its input")
+	}
+
+	again, err := ResolveInheritance(agents)
+	if err != nil || again[0].Instructions != got.Instructions {
+		t.Errorf("resolving again = %q, %v; want no change", again[0].Instructions, err)
+	}
+}
+
+func TestReadCanonicalDirAbstract(t *testing.T) {
+	dir := t.TempDir()
+	writeSpec(t, dir, "base.md", "---\nname: base\nabstract: true\ntools: [Read]\n---\n\nShared.\n")
+	writeSpec(t, dir, "child.json", `{"name": "child", "description": "Child", "extends": "base"}`)
+
+	agents, err := ReadCanonicalDir(dir)
+	if err != nil {
+		t.Fatalf("ReadCanonicalDir() error = %v", err)
+	}
+	if len(agents) != 1 || agents[0].Name != "child" || agents[0].Instructions != "Shared." {
+		t.Errorf("ReadCanonicalDir() = %+v, want only child inheriting from base", agents)
+	}
+}
+
+func TestReadCanonicalDirExtendsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   map[string]string
+		wantMsg string
+	}{
+		{
+			name: "missing base",
+			specs: map[string]string{
+				"a.md": "---\nname: a\nextends: nope\n---\n",
+			},
+			wantMsg: "extends unknown agent nope",
+		},
+		{
+			name: "cycle",
+			specs: map[string]string{
+				"a.md": "---\nname: a\nextends: b\n---\n",
+				"b.md": "---\nname: b\nextends: a\n---\n",
+			},
+			wantMsg: "a -> b -> a",
+		},
+		{
+			name: "self reference",
+			specs: map[string]string{
+				"a.md": "---\nname: a\nextends: a\n---\n",
+			},
+			wantMsg: "a -> a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.specs {
+				writeSpec(t, dir, name, content)
+			}
+
+			_, err := ReadCanonicalDir(dir)
+			var inhErr *InheritanceError
+			if !errors.As(err, &inhErr) {
+				t.Fatalf("expected InheritanceError, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("error = %q, want substring %q", err.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
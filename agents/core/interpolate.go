@@ -0,0 +1,50 @@
+package core
+
+import "regexp"
+
+// placeholderPattern matches ${NAME} placeholders.
+var placeholderPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// Interpolate returns a copy of a with the ${NAME} placeholders in its
+// Instructions and Description replaced by the values in vars. A
+// placeholder naming a variable vars does not define fails with an
+// *UndefinedVariableError. Other fields, and the configs adapters
+// generate (e.g., agentkit's "${ANTHROPIC_API_KEY}"), are not touched.
+func Interpolate(a *Agent, vars map[string]string) (*Agent, error) {
+	return interpolate(a, vars, false)
+}
+
+// InterpolatePartial is like Interpolate, but leaves the placeholders of
+// undefined variables as they are.
+func InterpolatePartial(a *Agent, vars map[string]string) *Agent {
+	out, _ := interpolate(a, vars, true)
+	return out
+}
+
+func interpolate(a *Agent, vars map[string]string, allowUndefined bool) (*Agent, error) {
+	out := *a
+	for _, field := range []struct {
+		name  string
+		value *string
+	}{
+		{"description", &out.Description},
+		{"instructions", &out.Instructions},
+	} {
+		var undefined string
+		*field.value = placeholderPattern.ReplaceAllStringFunc(*field.value, func(placeholder string) string {
+			name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+			if value, ok := vars[name]; ok {
+				return value
+			}
+			if undefined == "" {
+				undefined = name
+			}
+			return placeholder
+		})
+		if undefined != "" && !allowUndefined {
+			return nil, &UndefinedVariableError{Agent: a.Name, Field: field.name, Name: undefined}
+		}
+	}
+	out.syncInstructionSections()
+	return &out, nil
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	agent := NewAgent("deployer", "Deploys ${TEAM} services").
+		WithInstructions("Use ${BASE_URL}/api. Keep $HOME and ${ not a placeholder.")
+	vars := map[string]string{"TEAM": "payments", "BASE_URL": "https://pay.example.com"}
+
+	got, err := Interpolate(agent, vars)
+	if err != nil {
+		t.Fatalf("Interpolate() error = %v", err)
+	}
+	if got.Description != "Deploys payments services" {
+		t.Errorf("Description = %q", got.Description)
+	}
+	if got.Instructions != "Use https://pay.example.com/api. Keep $HOME and ${ not a placeholder." {
+		t.Errorf("Instructions = %q", got.Instructions)
+	}
+	if agent.Description != "Deploys ${TEAM} services" {
+		t.Error("Interpolate modified its input")
+	}
+}
+
+func TestInterpolateUndefined(t *testing.T) {
+	agent := NewAgent("deployer", "Deploys services").WithInstructions("Call ${API_URL} with ${TOKEN}.")
+	vars := map[string]string{"TOKEN": "t"}
+
+	_, err := Interpolate(agent, vars)
+	var undefined *UndefinedVariableError
+	if !errors.As(err, &undefined) || undefined.Name != "API_URL" || undefined.Field != "instructions" {
+		t.Errorf("Interpolate() error = %v, want undefined API_URL in instructions", err)
+	}
+
+	if got := InterpolatePartial(agent, vars); got.Instructions != "Call ${API_URL} with t." {
+		t.Errorf("InterpolatePartial() Instructions = %q", got.Instructions)
+	}
+}
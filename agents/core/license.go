@@ -0,0 +1,77 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LicenseField is the key that carries the license header in JSON output,
+// which has no comment syntax.
+const LicenseField = "_license"
+
+// spdxPattern matches SPDX license identifiers and simple expressions such
+// as "Apache-2.0" or "MIT OR Apache-2.0".
+var spdxPattern = regexp.MustCompile(`^[A-Za-z0-9.+-]+( (AND|OR|WITH) [A-Za-z0-9.+-]+)*$`)
+
+// SPDXHeader returns the header text for an SPDX license identifier.
+func SPDXHeader(id string) (string, error) {
+	if !spdxPattern.MatchString(id) {
+		return "", fmt.Errorf("invalid SPDX license identifier %q", id)
+	}
+	return "SPDX-License-Identifier: " + id, nil
+}
+
+// AddLicenseHeader prepends header to generated data as a comment (see
+// AddComment). JSON objects, which have no comments, get a leading
+// LicenseField member instead, which parsers ignore. Unknown extensions are
+// returned unchanged.
+func AddLicenseHeader(ext string, data []byte, header string) []byte {
+	if header == "" {
+		return data
+	}
+	if ext == ".json" {
+		return addJSONLicense(data, strings.TrimRight(header, "\n"))
+	}
+	return AddComment(ext, data, header)
+}
+
+// commentLines prefixes each line with marker, one per output line.
+func commentLines(marker string, lines []string) string {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(strings.TrimRight(marker+" "+line, " "))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// addJSONLicense inserts LicenseField as the first member of the top-level
+// object, matching the indentation of the existing members.
+func addJSONLicense(data []byte, text string) []byte {
+	open := bytes.IndexByte(data, '{')
+	if open < 0 {
+		return data
+	}
+	rest := data[open+1:]
+	member := strconv.Quote(LicenseField) + ": " + strconv.Quote(text)
+
+	if len(bytes.TrimSpace(rest)) > 0 && bytes.TrimSpace(rest)[0] == '}' {
+		// Empty object
+		return append(append(append([]byte{}, data[:open+1]...), member...), bytes.TrimLeft(rest, " \t\r\n")...)
+	}
+
+	if nl := bytes.IndexByte(rest, '\n'); nl >= 0 {
+		line := rest[nl+1:]
+		indent := string(line[:len(line)-len(bytes.TrimLeft(line, " \t"))])
+		out := append([]byte{}, data[:open+1]...)
+		out = append(out, '\n')
+		out = append(out, indent+member+","...)
+		return append(out, rest...)
+	}
+	out := append([]byte{}, data[:open+1]...)
+	out = append(out, member+","...)
+	return append(out, rest...)
+}
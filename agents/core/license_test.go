@@ -0,0 +1,57 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAddLicenseHeader(t *testing.T) {
+	header, err := SPDXHeader("Apache-2.0")
+	if err != nil {
+		t.Fatalf("SPDXHeader() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		ext  string
+		in   string
+		want string
+	}{
+		{"frontmatter", ".md", "---\nname: a\n---\n\nBody\n", "---\n# SPDX-License-Identifier: Apache-2.0\nname: a\n---\n\nBody\n"},
+		{"plain markdown", ".md", "# Title\n", "<!--\nSPDX-License-Identifier: Apache-2.0\n-->\n\n# Title\n"},
+		{"toml", ".toml", "name = \"a\"\n", "# SPDX-License-Identifier: Apache-2.0\n\nname = \"a\"\n"},
+		{"typescript", ".ts", "import * as cdk from 'aws-cdk-lib';\n", "// SPDX-License-Identifier: Apache-2.0\n\nimport * as cdk from 'aws-cdk-lib';\n"},
+		{"json", ".json", "{\n  \"name\": \"a\"\n}", "{\n  \"_license\": \"SPDX-License-Identifier: Apache-2.0\",\n  \"name\": \"a\"\n}"},
+		{"compact json", ".json", `{"name":"a"}`, `{"_license": "SPDX-License-Identifier: Apache-2.0","name":"a"}`},
+		{"empty json", ".json", "{}", `{"_license": "SPDX-License-Identifier: Apache-2.0"}`},
+		{"unknown", ".bin", "data", "data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(AddLicenseHeader(tt.ext, []byte(tt.in), header))
+			if got != tt.want {
+				t.Errorf("AddLicenseHeader() = %q, want %q", got, tt.want)
+			}
+			if tt.ext == ".json" && !json.Valid([]byte(got)) {
+				t.Errorf("output is not valid JSON: %s", got)
+			}
+		})
+	}
+}
+
+func TestAddLicenseHeaderMultiline(t *testing.T) {
+	got := string(AddLicenseHeader(".ts", []byte("x\n"), "Copyright Example Corp\n\nLicensed under MIT.\n"))
+	want := "// Copyright Example Corp\n//\n// Licensed under MIT.\n\nx\n"
+	if got != want {
+		t.Errorf("AddLicenseHeader() = %q, want %q", got, want)
+	}
+}
+
+func TestSPDXHeaderInvalid(t *testing.T) {
+	for _, id := range []string{"", "Apache 2.0", "MIT;rm"} {
+		if _, err := SPDXHeader(id); err == nil {
+			t.Errorf("SPDXHeader(%q) should fail", id)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	multiagentspec "github.com/agentplexus/multi-agent-spec/sdk/go"
+)
+
+// LintWarning is a possible problem in an agent spec found by Lint.
+type LintWarning struct {
+	Agent   string // Agent name
+	Source  string // Spec file, if known
+	Message string
+}
+
+// String formats the warning as "source: agent: message".
+func (w LintWarning) String() string {
+	if w.Source != "" {
+		return fmt.Sprintf("%s: %s: %s", w.Source, w.Agent, w.Message)
+	}
+	return fmt.Sprintf("%s: %s", w.Agent, w.Message)
+}
+
+// Lint runs every lint check on the agents and returns the warnings in
+// agent order.
+func Lint(agents []*Agent) []LintWarning {
+	var warnings []LintWarning
+	for _, agent := range agents {
+		msgs := append(lintToolMentions(agent), lintAgentKitCollapse(agent)...)
+		msgs = append(msgs, agent.Warnings()...)
+		for _, msg := range msgs {
+			warnings = append(warnings, LintWarning{Agent: agent.Name, Source: agent.SourcePath, Message: msg})
+		}
+	}
+	return warnings
+}
+
+// toolMentionPattern matches a word in backticks, or a word followed by
+// "tool" or "tools" (e.g., "`Bash`", "the Bash tool").
+var toolMentionPattern = regexp.MustCompile("`([A-Za-z]+)`|\\b([A-Za-z]+) tools?\\b")
+
+// lintToolMentions reports canonical tools that the instructions refer to
+// but the agent does not declare. To stay clear of ordinary prose ("Read
+// the file"), only exact tool names in backticks or followed by "tool" count.
+// Agents without a tools list have every tool and are not checked.
+func lintToolMentions(agent *Agent) []string {
+	if len(agent.Tools) == 0 {
+		return nil
+	}
+
+	canonical := CanonicalTools()
+	var msgs []string
+	seen := make(map[string]bool)
+	for _, match := range toolMentionPattern.FindAllStringSubmatch(agent.Instructions, -1) {
+		tool := match[1] + match[2]
+		if seen[tool] || !slices.Contains(canonical, tool) || slices.Contains(agent.Tools, tool) {
+			continue
+		}
+		seen[tool] = true
+		msgs = append(msgs, fmt.Sprintf("instructions mention %s, which is not in tools [%s]", tool, strings.Join(agent.Tools, ", ")))
+	}
+	return msgs
+}
+
+// CanonicalToolName returns the canonical spelling of tool if it matches a
+// canonical tool ignoring case (e.g., "bash" -> "Bash").
+func CanonicalToolName(tool string) (string, bool) {
+	for _, canonical := range CanonicalTools() {
+		if strings.EqualFold(tool, canonical) {
+			return canonical, true
+		}
+	}
+	return "", false
+}
+
+// lintAgentKitCollapse reports canonical tools that the default agentkit
+// mapping collapses into one agentkit tool (e.g., Bash, WebFetch and
+// WebSearch all become "shell"), so the agent ends up with fewer, broader
+// tools than it lists.
+func lintAgentKitCollapse(agent *Agent) []string {
+	collapsed := make(map[string][]string)
+	for _, tool := range agent.Tools {
+		mapped := multiagentspec.MapToolToAgentKit(multiagentspec.Tool(tool))
+		if mapped == "" || slices.Contains(collapsed[mapped], tool) {
+			continue
+		}
+		collapsed[mapped] = append(collapsed[mapped], tool)
+	}
+
+	targets := make([]string, 0, len(collapsed))
+	for mapped, tools := range collapsed {
+		if len(tools) > 1 {
+			targets = append(targets, mapped)
+		}
+	}
+	sort.Strings(targets)
+
+	msgs := make([]string, 0, len(targets))
+	for _, mapped := range targets {
+		msgs = append(msgs, fmt.Sprintf("tools [%s] all map to agentkit tool %q; confirm a single %s tool is intended",
+			strings.Join(collapsed[mapped], ", "), mapped, mapped))
+	}
+	return msgs
+}
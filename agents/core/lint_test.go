@@ -0,0 +1,97 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintToolMentions(t *testing.T) {
+	tests := []struct {
+		name         string
+		tools        []string
+		instructions string
+		want         []string // tools expected in warnings
+	}{
+		{"declared tool", []string{"Bash"}, "Use the Bash tool to run tests.", nil},
+		{"undeclared tool", []string{"Read"}, "Use the Bash tool to run tests.", []string{"Bash"}},
+		{"backticks", []string{"Read"}, "Search with `Grep` first.", []string{"Grep"}},
+		{"plain prose", []string{"Grep"}, "Read the file. Write a summary. Edit nothing.", nil},
+		{"reported once", []string{"Read"}, "Use `Bash`. Then the Bash tool again.", []string{"Bash"}},
+		{"not a canonical tool", []string{"Read"}, "Use the `Hammer` tool.", nil},
+		{"case sensitive", []string{"Read"}, "Use the bash tool.", nil},
+		{"no tools declared", nil, "Use the Bash tool.", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := NewAgent("worker", "Works").WithTools(tt.tools...).WithInstructions(tt.instructions)
+			msgs := lintToolMentions(agent)
+			if len(msgs) != len(tt.want) {
+				t.Fatalf("lintToolMentions() = %v, want warnings for %v", msgs, tt.want)
+			}
+			for i, tool := range tt.want {
+				if !strings.Contains(msgs[i], "mention "+tool+",") {
+					t.Errorf("warning %q should name %s", msgs[i], tool)
+				}
+			}
+		})
+	}
+}
+
+func TestLint(t *testing.T) {
+	agent := NewAgent("worker", "Works").WithTools("Read").WithInstructions("Run the Bash tool.")
+	agent.SourcePath = "agents/worker.md"
+
+	warnings := Lint([]*Agent{agent, NewAgent("idle", "Idles")})
+	if len(warnings) != 1 {
+		t.Fatalf("Lint() = %v, want 1 warning", warnings)
+	}
+	if got := warnings[0].String(); !strings.HasPrefix(got, "agents/worker.md: worker: ") {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestToolWarnings(t *testing.T) {
+	agent := NewAgent("worker", "Works").WithTools("bash", "Read", "WEBFETCH", "web_fetch", "Hammer")
+	agent.AllowedTools = []string{"read"}
+
+	msgs := agent.Warnings()
+	want := []string{
+		`tools[0] "bash" is not a known tool (did you mean "Bash"?)`,
+		`tools[2] "WEBFETCH" is not a known tool (did you mean "WebFetch"?)`,
+		`tools[3] "web_fetch" is not a known tool (did you mean "WebFetch"?)`,
+		`tools[4] "Hammer" is not a known tool; it is passed through unchanged`,
+		`allowedTools[0] "read" is not a known tool (did you mean "Read"?)`,
+	}
+	if strings.Join(msgs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Warnings() = %q, want %q", msgs, want)
+	}
+}
+
+func TestLintAgentKitCollapse(t *testing.T) {
+	tests := []struct {
+		name  string
+		tools []string
+		want  []string
+	}{
+		{"distinct tools", []string{"Read", "Write", "Bash"}, nil},
+		{"collapsed", []string{"Bash", "Read", "WebFetch"}, []string{`tools [Bash, WebFetch] all map to agentkit tool "shell"`}},
+		{"duplicates ignored", []string{"Bash", "Bash"}, nil},
+		{"unknown tools ignored", []string{"Hammer", "Bash"}, nil},
+		{"no tools", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msgs := lintAgentKitCollapse(NewAgent("worker", "Works").WithTools(tt.tools...))
+			if len(msgs) != len(tt.want) {
+				t.Fatalf("lintAgentKitCollapse() = %q, want %q", msgs, tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(msgs[i], want) {
+					t.Errorf("warning %q should start with %q", msgs[i], want)
+				}
+			}
+		})
+	}
+}
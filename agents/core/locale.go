@@ -0,0 +1,60 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// localePattern matches BCP 47 style locale codes such as "de", "pt-BR"
+// or "zh-Hant-TW".
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// ValidateLocale reports whether locale is a well-formed locale code.
+func ValidateLocale(locale string) error {
+	if !localePattern.MatchString(locale) {
+		return fmt.Errorf("invalid locale %q (expected a code like de or pt-BR)", locale)
+	}
+	return nil
+}
+
+// CheckLocales returns an error for the first agent with a malformed
+// Descriptions locale.
+func CheckLocales(agents []*Agent) error {
+	for _, agent := range agents {
+		for locale := range agent.Descriptions {
+			if err := ValidateLocale(locale); err != nil {
+				return fmt.Errorf("agent %s: %w", agent.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// LocalizedDescription returns the description for locale, trying the
+// full code, then each shorter prefix ("pt-BR", then "pt"), and finally
+// falling back to Description.
+func (a *Agent) LocalizedDescription(locale string) string {
+	for tag := locale; tag != ""; {
+		if desc, ok := a.Descriptions[tag]; ok && desc != "" {
+			return desc
+		}
+		i := strings.LastIndex(tag, "-")
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	return a.Description
+}
+
+// Localize returns a copy of agent whose Description is the text for
+// locale, so every adapter emits it. An empty locale returns agent as is.
+func Localize(agent *Agent, locale string) *Agent {
+	if locale == "" {
+		return agent
+	}
+	localized := *agent
+	localized.Description = agent.LocalizedDescription(locale)
+	return &localized
+}
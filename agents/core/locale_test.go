@@ -0,0 +1,55 @@
+package core
+
+import "testing"
+
+func TestLocalizedDescription(t *testing.T) {
+	data := []byte("---\nname: reviewer\ndescription: Reviews code\ndescriptions:\n  de: Prüft Code\n  pt: Revisa código\n  pt-BR: Revisa o código\n---\n\nReview.\n")
+	agent, err := ParseMarkdownAgent(data, "reviewer.md")
+	if err != nil {
+		t.Fatalf("ParseMarkdownAgent() error = %v", err)
+	}
+
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"", "Reviews code"},
+		{"de", "Prüft Code"},
+		{"de-AT", "Prüft Code"},
+		{"pt-BR", "Revisa o código"},
+		{"pt-PT", "Revisa código"},
+		{"fr", "Reviews code"},
+	}
+	for _, tt := range tests {
+		if got := agent.LocalizedDescription(tt.locale); got != tt.want {
+			t.Errorf("LocalizedDescription(%q) = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+
+	if got := Localize(agent, "de"); got.Description != "Prüft Code" || agent.Description != "Reviews code" {
+		t.Errorf("Localize() = %q, original %q", got.Description, agent.Description)
+	}
+
+	back, err := ParseMarkdownAgent(MarshalMarkdownAgent(agent), "reviewer.md")
+	if err != nil {
+		t.Fatalf("round trip error = %v", err)
+	}
+	if len(back.Descriptions) != 3 || back.Descriptions["pt-BR"] != "Revisa o código" {
+		t.Errorf("round trip Descriptions = %v", back.Descriptions)
+	}
+}
+
+func TestCheckLocales(t *testing.T) {
+	agent := NewAgent("reviewer", "Reviews code")
+	agent.Descriptions = map[string]string{"de": "Prüft Code", "zh-Hant-TW": "審查代碼"}
+	if err := CheckLocales([]*Agent{agent}); err != nil {
+		t.Errorf("CheckLocales() error = %v", err)
+	}
+
+	for _, bad := range []string{"German", "de_DE", "e", "de-"} {
+		agent.Descriptions = map[string]string{bad: "x"}
+		if err := CheckLocales([]*Agent{agent}); err == nil {
+			t.Errorf("CheckLocales() accepted %q", bad)
+		}
+	}
+}
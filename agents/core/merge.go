@@ -0,0 +1,151 @@
+package core
+
+import (
+	mcpcore "github.com/agentplexus/assistantkit/mcp/core"
+)
+
+// MergeAgents composes a team from a shared base and project-specific
+// override agents. An override agent replaces the base agent of the same
+// qualified name (namespace/name) in its place; the other override agents
+// follow the base agents in their order. Qualified names must be unique
+// within each list, or a DuplicateNameError is returned. Replacement is
+// whole-agent: to inherit unset fields from another agent, use MergeAgent
+// or `extends`.
+func MergeAgents(base, override []*Agent) ([]*Agent, error) {
+	qualifiedName := (*Agent).QualifiedName
+	if err := checkUnique(base, qualifiedName); err != nil {
+		return nil, err
+	}
+	if err := checkUnique(override, qualifiedName); err != nil {
+		return nil, err
+	}
+
+	replacements := make(map[string]*Agent, len(override))
+	for _, agent := range override {
+		replacements[agent.QualifiedName()] = agent
+	}
+	merged := make([]*Agent, 0, len(base)+len(override))
+	for _, agent := range base {
+		if replacement, ok := replacements[agent.QualifiedName()]; ok {
+			agent = replacement
+			delete(replacements, agent.QualifiedName())
+		}
+		merged = append(merged, agent)
+	}
+	for _, agent := range override {
+		if _, ok := replacements[agent.QualifiedName()]; ok {
+			merged = append(merged, agent)
+		}
+	}
+	return merged, nil
+}
+
+// MergeAgentsStrict is MergeAgents for teams whose parts must not shadow
+// each other: any qualified name defined twice, in either list or in both,
+// is a DuplicateNameError.
+func MergeAgentsStrict(base, override []*Agent) ([]*Agent, error) {
+	merged := make([]*Agent, 0, len(base)+len(override))
+	merged = append(merged, base...)
+	merged = append(merged, override...)
+	if err := CheckUniqueNames(merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// MergeAgent returns a copy of override with every unset field filled in
+// from base; neither agent is modified. Name, Namespace, Version, Base,
+// Abstract, PrependInstructions and the deprecation markers always come
+// from override. It is the field-level merge behind `extends` (see
+// ResolveInheritance); MergeAgents, by contrast, replaces whole agents.
+func MergeAgent(base, override *Agent) *Agent {
+	merged := *override
+
+	if merged.Description == "" {
+		merged.Description = base.Description
+	}
+	if merged.Descriptions == nil && base.Descriptions != nil {
+		merged.Descriptions = make(map[string]string, len(base.Descriptions))
+		for locale, desc := range base.Descriptions {
+			merged.Descriptions[locale] = desc
+		}
+	}
+	if merged.Icon == "" {
+		merged.Icon = base.Icon
+	}
+	if merged.Model == "" {
+		merged.Model = base.Model
+	}
+	if merged.Instructions == "" {
+		merged.Instructions = base.Instructions
+	}
+	merged.syncInstructionSections()
+	if merged.Tools == nil {
+		merged.Tools = cloneStrings(base.Tools)
+	}
+	if merged.AllowedTools == nil {
+		merged.AllowedTools = cloneStrings(base.AllowedTools)
+	}
+	if merged.Skills == nil {
+		merged.Skills = cloneStrings(base.Skills)
+	}
+	if merged.Dependencies == nil {
+		merged.Dependencies = cloneStrings(base.Dependencies)
+	}
+	if merged.Requires == nil {
+		merged.Requires = cloneStrings(base.Requires)
+	}
+	if merged.Workspace == "" {
+		merged.Workspace = base.Workspace
+	}
+	if merged.Group == "" {
+		merged.Group = base.Group
+	}
+	if merged.ModelFallback == nil {
+		merged.ModelFallback = cloneStrings(base.ModelFallback)
+	}
+	if merged.Timeouts == nil && base.Timeouts != nil {
+		timeouts := *base.Timeouts
+		merged.Timeouts = &timeouts
+	}
+	if merged.Retry == nil && base.Retry != nil {
+		retry := *base.Retry
+		merged.Retry = &retry
+	}
+	if merged.Scope == "" {
+		merged.Scope = base.Scope
+	}
+	if merged.MaxTurns == nil && base.MaxTurns != nil {
+		maxTurns := *base.MaxTurns
+		merged.MaxTurns = &maxTurns
+	}
+	if merged.Category == "" {
+		merged.Category = base.Category
+	}
+	if merged.Tags == nil {
+		merged.Tags = cloneStrings(base.Tags)
+	}
+	if merged.Arguments == nil && base.Arguments != nil {
+		merged.Arguments = append([]Argument(nil), base.Arguments...)
+	}
+	if merged.Triggers == nil && base.Triggers != nil {
+		merged.Triggers = append([]Trigger(nil), base.Triggers...)
+	}
+	if merged.Metadata == nil && base.Metadata != nil {
+		merged.Metadata = make(map[string]string, len(base.Metadata))
+		for key, value := range base.Metadata {
+			merged.Metadata[key] = value
+		}
+	}
+	if merged.MCPServers == nil && base.MCPServers != nil {
+		merged.MCPServers = make(map[string]mcpcore.Server, len(base.MCPServers))
+		for name, server := range base.MCPServers {
+			merged.MCPServers[name] = server
+		}
+	}
+	if merged.Tasks == nil && base.Tasks != nil {
+		merged.Tasks = append([]Task(nil), base.Tasks...)
+	}
+
+	return &merged
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMergeAgents(t *testing.T) {
+	base := []*Agent{NewAgent("reviewer", "Reviews code"), NewAgent("planner", "Plans work"), NewAgent("tester", "Tests")}
+	override := []*Agent{NewAgent("deployer", "Deploys"), NewAgent("planner", "Plans releases")}
+
+	merged, err := MergeAgents(base, override)
+	if err != nil {
+		t.Fatalf("MergeAgents() error = %v", err)
+	}
+	var got []string
+	for _, agent := range merged {
+		got = append(got, agent.Name+": "+agent.Description)
+	}
+	want := []string{"reviewer: Reviews code", "planner: Plans releases", "tester: Tests", "deployer: Deploys"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeAgents() = %q, want %q", got, want)
+	}
+
+	tests := []struct {
+		name           string
+		merge          func(base, override []*Agent) ([]*Agent, error)
+		base, override []*Agent
+		wantName       string
+	}{
+		{"duplicate in base", MergeAgents, append(base, NewAgent("tester", "Tests again")), override, "tester"},
+		{"duplicate in override", MergeAgents, base, append(override, NewAgent("deployer", "Deploys again")), "deployer"},
+		{"strict collision", MergeAgentsStrict, base, override, "planner"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dupErr *DuplicateNameError
+			if _, err := tt.merge(tt.base, tt.override); !errors.As(err, &dupErr) || dupErr.Name != tt.wantName {
+				t.Errorf("error = %v, want DuplicateNameError for %s", err, tt.wantName)
+			}
+		})
+	}
+
+	if merged, err := MergeAgentsStrict(base, override[:1]); err != nil || len(merged) != 4 {
+		t.Errorf("MergeAgentsStrict() = %d agents, %v, want 4", len(merged), err)
+	}
+}
+
+func TestMergeAgentsNamespaces(t *testing.T) {
+	base := []*Agent{
+		NewAgent("reviewer", "Reviews frontend code").WithNamespace("frontend"),
+		NewAgent("reviewer", "Reviews backend code").WithNamespace("backend"),
+	}
+	override := []*Agent{
+		NewAgent("reviewer", "Reviews backend APIs").WithNamespace("backend"),
+		NewAgent("reviewer", "Reviews infrastructure").WithNamespace("infra"),
+	}
+
+	merged, err := MergeAgents(base, override)
+	if err != nil {
+		t.Fatalf("MergeAgents() error = %v", err)
+	}
+	var got []string
+	for _, agent := range merged {
+		got = append(got, agent.QualifiedName()+": "+agent.Description)
+	}
+	want := []string{"frontend/reviewer: Reviews frontend code", "backend/reviewer: Reviews backend APIs", "infra/reviewer: Reviews infrastructure"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeAgents() = %q, want %q", got, want)
+	}
+}
+
+func TestMergeAgent(t *testing.T) {
+	base := NewAgent("base", "Shared reviewer").
+		WithModel("sonnet").
+		WithTools("Read", "Grep").
+		WithInstructions("Base instructions.")
+	base.Deprecated = true
+	override := NewAgent("reviewer", "").WithInstructions("Own instructions.")
+	override.Base = "base"
+
+	merged := MergeAgent(base, override)
+	if merged.Name != "reviewer" || merged.Base != "base" || merged.Deprecated {
+		t.Errorf("identity fields not kept from override: %+v", merged)
+	}
+	if merged.Description != "Shared reviewer" || merged.Model != "sonnet" || !reflect.DeepEqual(merged.Tools, []string{"Read", "Grep"}) {
+		t.Errorf("unset fields not filled from base: %+v", merged)
+	}
+	if merged.Instructions != "Own instructions." {
+		t.Errorf("Instructions = %q, want override's", merged.Instructions)
+	}
+
+	merged.Tools[0] = "Write"
+	if base.Tools[0] != "Read" || override.Description != "" {
+		t.Error("MergeAgent modified its inputs")
+	}
+}
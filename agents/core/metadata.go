@@ -0,0 +1,81 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// specKeys returns the frontmatter keys canonical specs define: the YAML
+// keys of Agent, including its embedded Spec, and the load-time keys of
+// specHeader. Every other key is metadata.
+var specKeys = sync.OnceValue(func() map[string]bool {
+	keys := make(map[string]bool)
+	var collect func(t reflect.Type)
+	collect = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			switch {
+			case field.Anonymous && strings.Contains(opts, "inline"):
+				collect(field.Type)
+			case name != "" && name != "-":
+				keys[name] = true
+			}
+		}
+	}
+	collect(reflect.TypeOf(Agent{}))
+	collect(reflect.TypeOf(specHeader{}))
+	return keys
+})
+
+// IsSpecKey reports whether key is a frontmatter key canonical specs
+// define, as opposed to passthrough metadata.
+func IsSpecKey(key string) bool {
+	return specKeys()[key]
+}
+
+// parseMetadata returns the frontmatter keys that are not spec keys, with
+// their values, or nil if there are none. Only scalar values are kept;
+// lists and mappings under unknown keys are ignored.
+func parseMetadata(frontmatter []byte) (map[string]string, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(frontmatter, &node); err != nil {
+		return nil, err
+	}
+	if len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	var metadata map[string]string
+	m := node.Content[0]
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		key, value := m.Content[i].Value, m.Content[i+1]
+		if IsSpecKey(key) || value.Kind != yaml.ScalarNode {
+			continue
+		}
+		if metadata == nil {
+			metadata = make(map[string]string)
+		}
+		metadata[key] = value.Value
+	}
+	return metadata, nil
+}
+
+// metadataKeys returns the agent's metadata without keys that would
+// collide with spec keys when written as frontmatter.
+func (a *Agent) metadataKeys() map[string]string {
+	var metadata map[string]string
+	for key, value := range a.Metadata {
+		if IsSpecKey(key) {
+			continue
+		}
+		if metadata == nil {
+			metadata = make(map[string]string, len(a.Metadata))
+		}
+		metadata[key] = value
+	}
+	return metadata
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCanonicalDirMetadata(t *testing.T) {
+	dir := t.TempDir()
+	writeSpec(t, dir, "base.md", "---\nname: base\ndescription: Base\nabstract: true\nowner: platform-team\n---\n\nBase.\n")
+	writeSpec(t, dir, "reviewer.md", "---\nname: reviewer\ndescription: Reviews code\ntags: [quality]\nowner: alice\ncost-center: \"4711\"\nlinks: [a, b]\n---\n\nReview.\n")
+	writeSpec(t, dir, "planner.md", "---\nname: planner\ndescription: Plans\nextends: base\n---\n\nPlan.\n")
+
+	agents, err := ReadCanonicalDir(dir)
+	if err != nil {
+		t.Fatalf("ReadCanonicalDir() error = %v", err)
+	}
+
+	// Spec keys and non-scalar values stay out of metadata
+	want := map[string]string{"owner": "alice", "cost-center": "4711"}
+	if got := findAgent(agents, "reviewer").Metadata; !reflect.DeepEqual(got, want) {
+		t.Errorf("reviewer Metadata = %v, want %v", got, want)
+	}
+	if got := findAgent(agents, "planner").Metadata; got["owner"] != "platform-team" {
+		t.Errorf("planner Metadata = %v, want owner inherited from base", got)
+	}
+}
+
+func TestMarshalMarkdownAgentMetadata(t *testing.T) {
+	agent := NewAgent("reviewer", "Reviews code")
+	agent.Metadata = map[string]string{"owner": "alice", "cost-center": "4711", "name": "ignored"}
+
+	data := MarshalMarkdownAgent(agent)
+	if strings.Count(string(data), "name:") != 1 {
+		t.Errorf("metadata overrode a spec key:\n%s", data)
+	}
+
+	parsed, err := ParseMarkdownAgent(data, "")
+	if err != nil {
+		t.Fatalf("ParseMarkdownAgent() error = %v", err)
+	}
+	want := map[string]string{"owner": "alice", "cost-center": "4711"}
+	if !reflect.DeepEqual(parsed.Metadata, want) {
+		t.Errorf("round trip Metadata = %v, want %v", parsed.Metadata, want)
+	}
+}
+
+func TestIsSpecKey(t *testing.T) {
+	for _, key := range []string{"name", "instructions", "modelFallback", "extends", "mcpServers", "team"} {
+		if !IsSpecKey(key) {
+			t.Errorf("IsSpecKey(%q) = false, want true", key)
+		}
+	}
+	for _, key := range []string{"owner", "metadata", "sourcePath"} {
+		if IsSpecKey(key) {
+			t.Errorf("IsSpecKey(%q) = true, want false", key)
+		}
+	}
+}
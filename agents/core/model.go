@@ -0,0 +1,141 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+)
+
+// modelAliases are the model tiers a ModelFallback entry may name.
+var modelAliases = []Model{ModelHaiku, ModelSonnet, ModelOpus}
+
+// modelIDs maps each model alias to the Anthropic model ID it resolves to.
+// Adapters that emit Anthropic model IDs share it, so a new model release
+// is a change here rather than in every adapter.
+var modelIDs = map[Model]string{
+	ModelHaiku:  "claude-3-haiku-20240307",
+	ModelSonnet: "claude-3-5-sonnet-20241022",
+	ModelOpus:   "claude-3-opus-20240229",
+}
+
+// ResolveModel returns the model ID for a model alias (e.g., "sonnet" ->
+// "claude-3-5-sonnet-20241022"). A registered model ID resolves to itself.
+// Any other model is returned unchanged with ok false, so platform-specific
+// model names pass through.
+func ResolveModel(alias string) (canonical string, ok bool) {
+	if id, ok := modelIDs[Model(alias)]; ok {
+		return id, true
+	}
+	for _, id := range modelIDs {
+		if id == alias {
+			return id, true
+		}
+	}
+	return alias, false
+}
+
+// ModelAlias returns the alias a registered model ID resolves from (e.g.,
+// "claude-3-opus-20240229" -> "opus"), for adapters parsing model IDs back
+// into canonical agents. Any other model is returned unchanged.
+func ModelAlias(id string) string {
+	for alias, mapped := range modelIDs {
+		if mapped == id {
+			return string(alias)
+		}
+	}
+	return id
+}
+
+// Warnings returns problems with the agent that do not make it invalid,
+// because every adapter passes the value through unchanged: a model that
+// is neither a model alias nor a registered model ID, and tools that are
+// not canonical tools (see toolWarnings).
+func (a *Agent) Warnings() []string {
+	warnings := a.toolWarnings()
+	for _, m := range a.ModelChain() {
+		if _, ok := ResolveModel(string(m)); !ok {
+			warnings = append(warnings, fmt.Sprintf("model %q is not a model alias (haiku, sonnet, opus) or known model ID; it is passed through unchanged", m))
+		}
+	}
+	return warnings
+}
+
+// FallbackSupporter is implemented by adapters whose output can carry a
+// whole model fallback chain. Other adapters emit only the first model.
+type FallbackSupporter interface {
+	// SupportsModelFallback reports whether ModelFallback is emitted.
+	SupportsModelFallback() bool
+}
+
+// SupportsModelFallback reports whether the adapter emits model fallback
+// chains.
+func SupportsModelFallback(adapter Adapter) bool {
+	fs, ok := adapter.(FallbackSupporter)
+	return ok && fs.SupportsModelFallback()
+}
+
+// ModelChain returns the models to try, in order: ModelFallback when set,
+// otherwise Model alone. It is empty if the agent names no model.
+func (a *Agent) ModelChain() []Model {
+	if len(a.ModelFallback) > 0 {
+		chain := make([]Model, len(a.ModelFallback))
+		for i, m := range a.ModelFallback {
+			chain[i] = Model(m)
+		}
+		return chain
+	}
+	if a.Model != "" {
+		return []Model{a.Model}
+	}
+	return nil
+}
+
+// PrimaryModel returns the first model of the chain, which formats without
+// fallback support use as the agent's model.
+func (a *Agent) PrimaryModel() Model {
+	if chain := a.ModelChain(); len(chain) > 0 {
+		return chain[0]
+	}
+	return ""
+}
+
+// CheckModelFallbacks verifies that every ModelFallback entry is a model
+// alias (haiku, sonnet, opus) and that none repeats.
+func CheckModelFallbacks(agents []*Agent) error {
+	for _, agent := range agents {
+		seen := make(map[string]bool)
+		for _, m := range agent.ModelFallback {
+			if !slices.Contains(modelAliases, Model(m)) {
+				return fmt.Errorf("agent %s: modelFallback entry %q is not a model alias (haiku, sonnet, opus)", agent.Name, m)
+			}
+			if seen[m] {
+				return fmt.Errorf("agent %s: modelFallback lists %q twice", agent.Name, m)
+			}
+			seen[m] = true
+		}
+	}
+	return nil
+}
+
+// ApplyModelOverrides returns agents with the model of each agent named in
+// overrides (by name or qualified name) replaced, e.g., {"reviewer":
+// "opus"}. An override also clears the agent's ModelFallback, which would
+// otherwise take precedence. Overridden agents are copies, so the input
+// agents are never modified; the others are returned as is.
+func ApplyModelOverrides(agents []*Agent, overrides map[string]string) []*Agent {
+	out := make([]*Agent, len(agents))
+	for i, agent := range agents {
+		model, ok := overrides[agent.QualifiedName()]
+		if !ok {
+			model, ok = overrides[agent.Name]
+		}
+		if !ok {
+			out[i] = agent
+			continue
+		}
+		overridden := *agent
+		overridden.Model = Model(model)
+		overridden.ModelFallback = nil
+		out[i] = &overridden
+	}
+	return out
+}
@@ -0,0 +1,339 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// specKeyOrder is the canonical order of spec keys. Unknown keys follow in
+// alphabetical order.
+var specKeyOrder = []string{
+	"name",
+	"namespace",
+	"description",
+	"descriptions",
+	"version",
+	"icon",
+	"model",
+	"modelFallback",
+	"maxTurns",
+	"timeouts",
+	"retry",
+	"extends",
+	"abstract",
+	"group",
+	"team",
+	"category",
+	"tags",
+	"workspace",
+	"scope",
+	"deprecated",
+	"deprecationMessage",
+	"tools",
+	"allowedTools",
+	"skills",
+	"dependencies",
+	"requires",
+	"files",
+	"arguments",
+	"triggers",
+	"instructions",
+	"tasks",
+}
+
+// sortedSpecLists are list keys whose order carries no meaning and is sorted.
+var sortedSpecLists = map[string]bool{
+	"tools":        true,
+	"allowedTools": true,
+}
+
+// NormalizeSpec rewrites a canonical spec file into its canonical on-disk
+// form: frontmatter keys in canonical order, tool lists sorted, scalar lists
+// in flow style, LF line endings, one blank line before the body, and a
+// single final newline. Comments and unknown keys are kept. The result is
+// checked to parse to the same agent as the input, and normalizing it again
+// returns it unchanged.
+func NormalizeSpec(path string, data []byte) ([]byte, error) {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	var out []byte
+	var err error
+	if filepath.Ext(path) == ".json" {
+		out, err = normalizeJSONSpec(data)
+	} else {
+		out, err = normalizeMarkdownSpec(data)
+	}
+	if err != nil {
+		return nil, &ParseError{Format: "canonical", Path: path, Err: err}
+	}
+
+	if err := checkSameSpec(path, data, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func normalizeMarkdownSpec(data []byte) ([]byte, error) {
+	frontmatter, body, ok := splitSpecFrontmatter(data)
+	if !ok {
+		return nil, fmt.Errorf("missing YAML frontmatter")
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(frontmatter, &doc); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	if len(doc.Content) > 0 {
+		root := doc.Content[0]
+		if root.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("frontmatter must be a mapping")
+		}
+		normalizeSpecMapping(root)
+
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(&doc); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteString("---\n")
+
+	if body = strings.Trim(body, "\n"); strings.TrimSpace(body) != "" {
+		buf.WriteString("\n")
+		buf.WriteString(body)
+		buf.WriteString("\n")
+	}
+
+	return buf.Bytes(), nil
+}
+
+// splitSpecFrontmatter splits data into its frontmatter and body.
+func splitSpecFrontmatter(data []byte) ([]byte, string, bool) {
+	s := string(data)
+	if !strings.HasPrefix(s, "---\n") {
+		return nil, "", false
+	}
+	rest := s[len("---\n"):]
+
+	if strings.HasPrefix(rest, "---\n") || rest == "---" {
+		return nil, strings.TrimPrefix(rest, "---"), true
+	}
+	end := strings.Index(rest, "\n---\n")
+	if end < 0 {
+		if !strings.HasSuffix(rest, "\n---") {
+			return nil, "", false
+		}
+		end = len(rest) - len("\n---")
+		return []byte(rest[:end+1]), "", true
+	}
+	return []byte(rest[:end+1]), rest[end+len("\n---\n"):], true
+}
+
+// normalizeSpecMapping reorders keys and sorts tool lists in place.
+func normalizeSpecMapping(m *yaml.Node) {
+	rank := make(map[string]int, len(specKeyOrder))
+	for i, key := range specKeyOrder {
+		rank[key] = i
+	}
+
+	type pair struct{ key, value *yaml.Node }
+	pairs := make([]pair, 0, len(m.Content)/2)
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		pairs = append(pairs, pair{m.Content[i], m.Content[i+1]})
+	}
+
+	sort.SliceStable(pairs, func(i, j int) bool {
+		ri, iKnown := rank[pairs[i].key.Value]
+		rj, jKnown := rank[pairs[j].key.Value]
+		switch {
+		case iKnown && jKnown:
+			return ri < rj
+		case iKnown != jKnown:
+			return iKnown
+		default:
+			return pairs[i].key.Value < pairs[j].key.Value
+		}
+	})
+
+	m.Content = m.Content[:0]
+	for _, p := range pairs {
+		if p.value.Kind == yaml.SequenceNode && scalarSequence(p.value) {
+			if sortedSpecLists[p.key.Value] {
+				sort.SliceStable(p.value.Content, func(i, j int) bool {
+					return p.value.Content[i].Value < p.value.Content[j].Value
+				})
+			}
+			p.value.Style = yaml.FlowStyle
+		}
+		m.Content = append(m.Content, p.key, p.value)
+	}
+}
+
+func scalarSequence(n *yaml.Node) bool {
+	for _, item := range n.Content {
+		if item.Kind != yaml.ScalarNode || item.HeadComment != "" || item.LineComment != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeJSONSpec(data []byte) ([]byte, error) {
+	var spec map[string]interface{}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+
+	for key := range sortedSpecLists {
+		list, ok := spec[key].([]interface{})
+		if !ok {
+			continue
+		}
+		sort.SliceStable(list, func(i, j int) bool {
+			return fmt.Sprint(list[i]) < fmt.Sprint(list[j])
+		})
+	}
+
+	out, err := json.MarshalIndent(spec, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// checkSameSpec verifies that normalization did not change the agent
+// definition, other than the order of sorted lists.
+func checkSameSpec(path string, before, after []byte) error {
+	load := func(data []byte) (*Agent, *specHeader, error) {
+		header, err := parseSpecHeader(path, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		var agent *Agent
+		if filepath.Ext(path) == ".json" {
+			agent = &Agent{}
+			err = json.Unmarshal(data, agent)
+		} else {
+			agent, err = ParseMarkdownAgent(data, path)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		sort.Strings(agent.Tools)
+		sort.Strings(agent.AllowedTools)
+		return agent, header, nil
+	}
+
+	want, wantHeader, err := load(before)
+	if err != nil {
+		return &ParseError{Format: "canonical", Path: path, Err: err}
+	}
+	got, gotHeader, err := load(after)
+	if err != nil {
+		return &ParseError{Format: "canonical", Path: path, Err: fmt.Errorf("normalized output: %w", err)}
+	}
+
+	if !reflect.DeepEqual(want, got) || *wantHeader != *gotHeader {
+		return &ParseError{Format: "canonical", Path: path, Err: fmt.Errorf("normalization would change the agent definition")}
+	}
+	return nil
+}
+
+// toolSpecKeys are the spec keys holding canonical tool names.
+var toolSpecKeys = []string{"tools", "allowedTools"}
+
+// FixToolCasing rewrites tools and allowedTools entries of a canonical
+// spec file that differ from a canonical tool only in case. It reports
+// whether anything changed; unchanged input is returned as is. Markdown
+// bodies are kept verbatim, while the frontmatter is re-encoded (comments
+// and key order survive). JSON specs are re-encoded as by NormalizeSpec.
+func FixToolCasing(path string, data []byte) ([]byte, bool, error) {
+	fix := func(tool string) (string, bool) {
+		canonical, ok := CanonicalToolName(tool)
+		return canonical, ok && canonical != tool
+	}
+
+	if filepath.Ext(path) == ".json" {
+		var spec map[string]interface{}
+		if err := json.Unmarshal(data, &spec); err != nil {
+			return nil, false, &ParseError{Format: "canonical", Path: path, Err: err}
+		}
+		changed := false
+		for _, key := range toolSpecKeys {
+			list, _ := spec[key].([]interface{})
+			for i, item := range list {
+				if tool, ok := item.(string); ok {
+					if canonical, ok := fix(tool); ok {
+						list[i] = canonical
+						changed = true
+					}
+				}
+			}
+		}
+		if !changed {
+			return data, false, nil
+		}
+		out, err := json.MarshalIndent(spec, "", "  ")
+		if err != nil {
+			return nil, false, &MarshalError{Format: "canonical", Err: err}
+		}
+		return append(out, '\n'), true, nil
+	}
+
+	frontmatter, body, ok := splitSpecFrontmatter(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")))
+	if !ok || len(frontmatter) == 0 {
+		return data, false, nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(frontmatter, &doc); err != nil {
+		return nil, false, &ParseError{Format: "markdown", Path: path, Err: err}
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return data, false, nil
+	}
+
+	changed := false
+	root := doc.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if !slices.Contains(toolSpecKeys, root.Content[i].Value) || root.Content[i+1].Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, item := range root.Content[i+1].Content {
+			if canonical, ok := fix(item.Value); ok && item.Kind == yaml.ScalarNode {
+				item.Value = canonical
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return data, false, nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, false, &MarshalError{Format: "markdown", Err: err}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, false, &MarshalError{Format: "markdown", Err: err}
+	}
+	buf.WriteString("---\n")
+	buf.WriteString(body)
+	return buf.Bytes(), true, nil
+}
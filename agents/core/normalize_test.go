@@ -0,0 +1,106 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSpec(t *testing.T) {
+	input := "---\r\n" +
+		"tools: [Write, Bash, Read]\r\n" +
+		"x-owner: platform # custom key\r\n" +
+		"description: Reviews code\r\n" +
+		"skills:\r\n" +
+		"  - review\r\n" +
+		"  - lint\r\n" +
+		"name: reviewer\r\n" +
+		"---\r\n" +
+		"\r\n\r\n" +
+		"# Reviewer\r\n" +
+		"\r\n" +
+		"Review carefully.\r\n\r\n"
+
+	want := "---\n" +
+		"name: reviewer\n" +
+		"description: Reviews code\n" +
+		"tools: [Bash, Read, Write]\n" +
+		"skills: [review, lint]\n" +
+		"x-owner: platform # custom key\n" +
+		"---\n" +
+		"\n" +
+		"# Reviewer\n" +
+		"\n" +
+		"Review carefully.\n"
+
+	got, err := NormalizeSpec("reviewer.md", []byte(input))
+	if err != nil {
+		t.Fatalf("NormalizeSpec() error = %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("NormalizeSpec() =\n%s\nwant:\n%s", got, want)
+	}
+
+	again, err := NormalizeSpec("reviewer.md", got)
+	if err != nil {
+		t.Fatalf("second NormalizeSpec() error = %v", err)
+	}
+	if string(again) != string(got) {
+		t.Errorf("NormalizeSpec is not idempotent:\n%s", again)
+	}
+}
+
+func TestNormalizeSpecJSON(t *testing.T) {
+	input := `{"tools":["Write","Read"],"name":"reviewer","description":"Reviews code"}`
+
+	got, err := NormalizeSpec("reviewer.json", []byte(input))
+	if err != nil {
+		t.Fatalf("NormalizeSpec() error = %v", err)
+	}
+	want := "{\n  \"description\": \"Reviews code\",\n  \"name\": \"reviewer\",\n  \"tools\": [\n    \"Read\",\n    \"Write\"\n  ]\n}\n"
+	if string(got) != want {
+		t.Errorf("NormalizeSpec() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestNormalizeSpecErrors(t *testing.T) {
+	for name, input := range map[string]string{
+		"no frontmatter":     "# Just markdown\n",
+		"invalid yaml":       "---\nname: [unclosed\n---\n",
+		"non-mapping header": "---\n- a\n- b\n---\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NormalizeSpec("agent.md", []byte(input)); err == nil {
+				t.Error("expected error")
+			} else if !strings.Contains(err.Error(), "agent.md") {
+				t.Errorf("error %q should name the file", err)
+			}
+		})
+	}
+}
+
+func TestFixToolCasing(t *testing.T) {
+	in := "---\nname: worker # the worker\ndescription: Works\ntools: [bash, Read, grep]\nallowedTools:\n  - read\n---\n\nUse  the   Bash tool.\n"
+	out, changed, err := FixToolCasing("worker.md", []byte(in))
+	if err != nil {
+		t.Fatalf("FixToolCasing() error = %v", err)
+	}
+	if !changed {
+		t.Fatal("FixToolCasing() reported no change")
+	}
+	want := "---\nname: worker # the worker\ndescription: Works\ntools: [Bash, Read, Grep]\nallowedTools:\n  - Read\n---\n\nUse  the   Bash tool.\n"
+	if string(out) != want {
+		t.Errorf("FixToolCasing() =\n%s\nwant\n%s", out, want)
+	}
+
+	if again, changed, _ := FixToolCasing("worker.md", out); changed || string(again) != string(out) {
+		t.Error("FixToolCasing() should leave fixed specs unchanged")
+	}
+
+	jsonOut, changed, err := FixToolCasing("worker.json", []byte(`{"name": "worker", "tools": ["webSearch"]}`))
+	if err != nil || !changed {
+		t.Fatalf("FixToolCasing(json) = %v, %v", changed, err)
+	}
+	if !strings.Contains(string(jsonOut), `"WebSearch"`) {
+		t.Errorf("FixToolCasing(json) = %s", jsonOut)
+	}
+}
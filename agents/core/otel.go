@@ -0,0 +1,80 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// OpenTelemetry resource attribute keys that can be derived from an agent.
+const (
+	AttrServiceName      = "service.name"      // Agent name
+	AttrServiceNamespace = "service.namespace" // Deployment team
+	AttrTeam             = "team"              // Deployment team
+	AttrAgentID          = "agent.id"          // Qualified name (namespace/name)
+	AttrAgentGroup       = "agent.group"       // Sub-team group
+	AttrAgentModel       = "agent.model"       // Canonical model tier
+)
+
+// DefaultResourceAttributes is the attribute set emitted when observability
+// is enabled without an explicit list.
+var DefaultResourceAttributes = []string{AttrServiceName, AttrTeam, AttrAgentID}
+
+var attributeEscaper = strings.NewReplacer("%", "%25", ",", "%2C", "=", "%3D")
+
+var resourceAttributeValues = map[string]func(agent *Agent, team string) string{
+	AttrServiceName:      func(a *Agent, _ string) string { return a.Name },
+	AttrServiceNamespace: func(_ *Agent, team string) string { return team },
+	AttrTeam:             func(_ *Agent, team string) string { return team },
+	AttrAgentID:          func(a *Agent, _ string) string { return a.QualifiedName() },
+	AttrAgentGroup:       func(a *Agent, _ string) string { return a.Group },
+	AttrAgentModel:       func(a *Agent, _ string) string { return string(a.Model) },
+}
+
+// ResourceAttributeKeys returns every supported attribute key, sorted.
+func ResourceAttributeKeys() []string {
+	keys := make([]string, 0, len(resourceAttributeValues))
+	for key := range resourceAttributeValues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ResourceAttributes derives the requested OpenTelemetry resource attributes
+// for an agent deployed as part of team. Attributes with empty values are
+// omitted. An empty keys list returns nil, meaning observability is off.
+func ResourceAttributes(agent *Agent, team string, keys []string) (map[string]string, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	attrs := make(map[string]string, len(keys))
+	for _, key := range keys {
+		value, ok := resourceAttributeValues[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown resource attribute %q (available: %s)", key, strings.Join(ResourceAttributeKeys(), ", "))
+		}
+		if v := value(agent, team); v != "" {
+			attrs[key] = v
+		}
+	}
+	return attrs, nil
+}
+
+// FormatResourceAttributes renders attributes in OTEL_RESOURCE_ATTRIBUTES
+// form ("k1=v1,k2=v2"), sorted by key. Values are percent-encoded where
+// they would otherwise break the list syntax.
+func FormatResourceAttributes(attrs map[string]string) string {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + attributeEscaper.Replace(attrs[key])
+	}
+	return strings.Join(pairs, ",")
+}
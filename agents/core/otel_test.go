@@ -0,0 +1,33 @@
+package core
+
+import "testing"
+
+func TestResourceAttributes(t *testing.T) {
+	agent := NewAgent("reviewer", "Reviews code").WithNamespace("qa")
+
+	attrs, err := ResourceAttributes(agent, "", []string{AttrServiceName, AttrTeam, AttrAgentID})
+	if err != nil {
+		t.Fatalf("ResourceAttributes() error = %v", err)
+	}
+	if _, ok := attrs[AttrTeam]; ok {
+		t.Error("empty team should be omitted")
+	}
+	if attrs[AttrAgentID] != "qa/reviewer" {
+		t.Errorf("agent.id = %q, want qa/reviewer", attrs[AttrAgentID])
+	}
+
+	if attrs, _ := ResourceAttributes(agent, "team", nil); attrs != nil {
+		t.Errorf("no keys should yield nil, got %v", attrs)
+	}
+	if _, err := ResourceAttributes(agent, "team", []string{"bogus"}); err == nil {
+		t.Error("expected error for unknown key")
+	}
+}
+
+func TestFormatResourceAttributes(t *testing.T) {
+	got := FormatResourceAttributes(map[string]string{"team": "a,b", "service.name": "x=y"})
+	want := "service.name=x%3Dy,team=a%2Cb"
+	if got != want {
+		t.Errorf("FormatResourceAttributes() = %q, want %q", got, want)
+	}
+}
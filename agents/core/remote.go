@@ -0,0 +1,176 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GitSourcePrefix marks a spec location as a Git repository, e.g.
+// "git+https://github.com/org/agents//specs@v1.2.0".
+const GitSourcePrefix = "git+"
+
+// GitSource is a spec directory inside a Git repository.
+type GitSource struct {
+	// URL is the repository URL passed to git (without the git+ prefix).
+	URL string
+
+	// Subpath is the spec directory within the repository. Empty means
+	// the repository root.
+	Subpath string
+
+	// Ref is a branch, tag, or commit. Empty means the remote's HEAD.
+	Ref string
+}
+
+// IsGitSource reports whether spec names a Git repository rather than a
+// local path.
+func IsGitSource(spec string) bool {
+	return strings.HasPrefix(spec, GitSourcePrefix)
+}
+
+// ParseGitSource parses "git+<url>[//<subpath>][@<ref>]".
+func ParseGitSource(spec string) (*GitSource, error) {
+	if !IsGitSource(spec) {
+		return nil, fmt.Errorf("not a git source: %s", spec)
+	}
+	rest := strings.TrimPrefix(spec, GitSourcePrefix)
+
+	// The ref follows the last "@" in the final path element, so user@host
+	// in the URL is left alone.
+	src := &GitSource{}
+	if i := strings.LastIndex(rest, "@"); i > strings.LastIndex(rest, "/") {
+		src.Ref = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	// The subpath follows the first "//" after the scheme separator
+	start := 0
+	if i := strings.Index(rest, "://"); i >= 0 {
+		start = i + len("://")
+	}
+	if i := strings.Index(rest[start:], "//"); i >= 0 {
+		src.Subpath = rest[start+i+2:]
+		rest = rest[:start+i]
+	}
+	src.URL = rest
+
+	if src.URL == "" {
+		return nil, fmt.Errorf("git source %s: missing repository URL", spec)
+	}
+	if src.Subpath != "" && !filepath.IsLocal(src.Subpath) {
+		return nil, fmt.Errorf("git source %s: subpath must be relative and stay within the repository", spec)
+	}
+	return src, nil
+}
+
+// cacheKey identifies a checkout of URL at Ref.
+func (s *GitSource) cacheKey() string {
+	sum := sha256.Sum256([]byte(s.URL + "@" + s.Ref))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// Fetch makes a shallow checkout of the source under cacheRoot and returns
+// the local spec directory. Checkouts are cached per URL and ref, so a ref
+// is cloned only once; remove the cache directory to pick up a moved branch.
+func (s *GitSource) Fetch(ctx context.Context, cacheRoot string) (string, error) {
+	dir := filepath.Join(cacheRoot, s.cacheKey())
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		if err := s.clone(ctx, cacheRoot, dir); err != nil {
+			return "", err
+		}
+	}
+
+	specDir := filepath.Join(dir, filepath.FromSlash(s.Subpath))
+	if info, err := os.Stat(specDir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("git source %s: %s is not a directory at %s", s.URL, s.Subpath, s.refName())
+	}
+	return specDir, nil
+}
+
+// clone fetches Ref at depth 1 into a temporary directory and renames it
+// into place, so an interrupted clone never leaves a partial cache entry.
+// Fetching by ref works for branches, tags, and (on most hosts) commits.
+func (s *GitSource) clone(ctx context.Context, cacheRoot, dir string) error {
+	if err := os.MkdirAll(cacheRoot, DefaultDirMode); err != nil {
+		return &WriteError{Path: cacheRoot, Err: err}
+	}
+	tmp, err := os.MkdirTemp(cacheRoot, "clone-")
+	if err != nil {
+		return &WriteError{Path: cacheRoot, Err: err}
+	}
+	defer os.RemoveAll(tmp)
+
+	steps := [][]string{
+		{"init", "--quiet"},
+		{"fetch", "--quiet", "--depth", "1", "--", s.URL, s.refName()},
+		{"checkout", "--quiet", "FETCH_HEAD"},
+	}
+	for _, args := range steps {
+		if err := runGit(ctx, tmp, args...); err != nil {
+			return fmt.Errorf("git source %s@%s: %w", s.URL, s.refName(), err)
+		}
+	}
+
+	if err := os.Rename(tmp, dir); err != nil {
+		// Another process may have populated the cache first
+		if _, statErr := os.Stat(filepath.Join(dir, ".git")); statErr == nil {
+			return nil
+		}
+		return &WriteError{Path: dir, Err: err}
+	}
+	return nil
+}
+
+func (s *GitSource) refName() string {
+	if s.Ref == "" {
+		return "HEAD"
+	}
+	return s.Ref
+}
+
+// runGit runs git in dir, including stderr in the error.
+func runGit(ctx context.Context, dir string, args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...) //nolint:gosec // G204: fixed binary, arguments are not shell-interpreted
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// DefaultSpecCacheDir returns the directory where Git spec sources are
+// cached (e.g., ~/.cache/assistantkit/specs on Linux).
+func DefaultSpecCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "assistantkit", "specs"), nil
+}
+
+// ResolveSpecDir returns a local directory for spec. Git sources are
+// fetched into DefaultSpecCacheDir; anything else is a local path and is
+// returned unchanged.
+func ResolveSpecDir(ctx context.Context, spec string) (string, error) {
+	if !IsGitSource(spec) {
+		return spec, nil
+	}
+	src, err := ParseGitSource(spec)
+	if err != nil {
+		return "", err
+	}
+	cacheRoot, err := DefaultSpecCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return src.Fetch(ctx, cacheRoot)
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseGitSource(t *testing.T) {
+	tests := []struct {
+		spec string
+		want GitSource
+	}{
+		{"git+https://github.com/org/repo", GitSource{URL: "https://github.com/org/repo"}},
+		{"git+https://github.com/org/repo//agents@v1.2.0", GitSource{URL: "https://github.com/org/repo", Subpath: "agents", Ref: "v1.2.0"}},
+		{"git+https://github.com/org/repo.git//plugins/spec/agents", GitSource{URL: "https://github.com/org/repo.git", Subpath: "plugins/spec/agents"}},
+		{"git+ssh://git@github.com/org/repo@main", GitSource{URL: "ssh://git@github.com/org/repo", Ref: "main"}},
+		{"git+git@github.com:org/repo.git//agents@abc123", GitSource{URL: "git@github.com:org/repo.git", Subpath: "agents", Ref: "abc123"}},
+		{"git+file:///srv/repo//agents", GitSource{URL: "file:///srv/repo", Subpath: "agents"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseGitSource(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseGitSource() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("ParseGitSource() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+
+	for _, spec := range []string{"plugins/spec/agents", "git+", "git+https://github.com/org/repo//../etc"} {
+		if _, err := ParseGitSource(spec); err == nil {
+			t.Errorf("ParseGitSource(%q) should fail", spec)
+		}
+	}
+}
+
+func TestGitSourceFetch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	// Build a repository with a spec committed and tagged v1, then changed
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	spec := filepath.Join(repo, "agents", "reviewer.md")
+	if err := os.MkdirAll(filepath.Dir(spec), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(spec, []byte("---\nname: reviewer\ndescription: v1\n---\n\nReview code.\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	git("init", "--quiet")
+	git("add", ".")
+	git("commit", "--quiet", "-m", "v1")
+	git("tag", "v1")
+	if err := os.WriteFile(spec, []byte("---\nname: reviewer\ndescription: v2\n---\n\nReview code.\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	git("commit", "--quiet", "-am", "v2")
+
+	src, err := ParseGitSource("git+file://" + filepath.ToSlash(repo) + "//agents@v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := t.TempDir()
+	dir, err := src.Fetch(context.Background(), cache)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	agents, err := ReadCanonicalDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(agents) != 1 || agents[0].Description != "v1" {
+		t.Fatalf("agents = %+v, want reviewer at v1", agents)
+	}
+
+	// A second fetch must reuse the cached checkout
+	if err := os.WriteFile(filepath.Join(dir, "marker"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	again, err := src.Fetch(context.Background(), cache)
+	if err != nil {
+		t.Fatalf("second Fetch() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(again, "marker")); err != nil {
+		t.Error("second Fetch() should reuse the cache instead of re-cloning")
+	}
+
+	missing := &GitSource{URL: src.URL, Subpath: "nope", Ref: "v1"}
+	if _, err := missing.Fetch(context.Background(), cache); err == nil {
+		t.Error("expected error for missing subpath")
+	}
+}
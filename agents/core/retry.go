@@ -0,0 +1,59 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// RetryConfig is an agent's retry policy for failed tool and model calls.
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int `json:"maxAttempts" yaml:"maxAttempts"`
+
+	// Backoff is the delay before the first retry as a Go duration string
+	// (e.g., "2s"), doubled after each attempt. Empty uses the runtime
+	// default.
+	Backoff string `json:"backoff,omitempty" yaml:"backoff,omitempty"`
+}
+
+// Validate checks that MaxAttempts is positive and that Backoff, if set,
+// is a positive duration.
+func (r *RetryConfig) Validate() error {
+	if r.MaxAttempts <= 0 {
+		return fmt.Errorf("retry.maxAttempts must be positive, got %d", r.MaxAttempts)
+	}
+	if r.Backoff != "" {
+		d, err := time.ParseDuration(r.Backoff)
+		if err != nil {
+			return fmt.Errorf("retry.backoff: %w", err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("retry.backoff: %q must be positive", r.Backoff)
+		}
+	}
+	return nil
+}
+
+// BackoffDuration returns Backoff as a duration, or 0 if it is unset or
+// invalid.
+func (r *RetryConfig) BackoffDuration() time.Duration {
+	d, err := time.ParseDuration(r.Backoff)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// CheckRetries returns an error for the first agent with an invalid retry
+// policy.
+func CheckRetries(agents []*Agent) error {
+	for _, agent := range agents {
+		if agent.Retry == nil {
+			continue
+		}
+		if err := agent.Retry.Validate(); err != nil {
+			return fmt.Errorf("agent %s: %w", agent.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// LossyAdapter is implemented by adapters whose format cannot represent
+// every canonical field, such as agentkit dropping tools it cannot map.
+type LossyAdapter interface {
+	// LossyFields returns the names of the Agent fields (e.g., "Tools",
+	// "Model") that do not survive Marshal followed by Parse.
+	LossyFields() []string
+}
+
+// LossyFields returns the fields the adapter declares lossy, or nil if it
+// does not implement LossyAdapter.
+func LossyFields(adapter Adapter) []string {
+	if la, ok := adapter.(LossyAdapter); ok {
+		return la.LossyFields()
+	}
+	return nil
+}
+
+// metadataFields are Agent fields that steer generation (e.g., output
+// paths, filtering, publishing) rather than describe the agent to a
+// runtime. Only canonical specs hold them, so round trips never compare
+// them.
+var metadataFields = []string{
+	"Namespace", "Icon", "Descriptions", "Workspace", "Group", "Scope",
+	"Deprecated", "DeprecationMessage", "Category", "Tags", "Files",
+	"Base", "Abstract", "PrependInstructions", "Metadata", "SourcePath",
+}
+
+// derivedFields are Agent fields computed from other fields, so round
+// trips compare them through those.
+var derivedFields = []string{"InstructionSections"}
+
+// unorderedFields are list fields whose order carries no meaning.
+var unorderedFields = map[string]bool{
+	"Tools":        true,
+	"AllowedTools": true,
+}
+
+// RoundTrip marshals agent with adapter and parses the result back.
+func RoundTrip(adapter Adapter, agent *Agent) (*Agent, error) {
+	data, err := adapter.Marshal(agent)
+	if err != nil {
+		return nil, err
+	}
+	return adapter.Parse(data)
+}
+
+// RoundTripDiff compares the canonical fields of want and got, skipping
+// generation metadata (Namespace, Group, Category, ...) and the adapter's
+// lossy fields, and describes each field that differs. Nil and empty lists
+// or maps are equal, tool lists are compared ignoring order, and
+// Instructions are compared without surrounding whitespace.
+func RoundTripDiff(adapter Adapter, want, got *Agent) []string {
+	skip := make(map[string]bool)
+	for _, fields := range [][]string{metadataFields, derivedFields, LossyFields(adapter)} {
+		for _, field := range fields {
+			skip[field] = true
+		}
+	}
+
+	var diffs []string
+	var compare func(w, g reflect.Value)
+	compare = func(w, g reflect.Value) {
+		for i := 0; i < w.NumField(); i++ {
+			field := w.Type().Field(i)
+			if field.Anonymous {
+				compare(w.Field(i), g.Field(i))
+				continue
+			}
+			if !field.IsExported() || skip[field.Name] {
+				continue
+			}
+			wv, gv := w.Field(i).Interface(), g.Field(i).Interface()
+			switch {
+			case field.Name == "Instructions":
+				wv, gv = strings.TrimSpace(wv.(string)), strings.TrimSpace(gv.(string))
+			case unorderedFields[field.Name]:
+				wv, gv = sortedCopy(wv.([]string)), sortedCopy(gv.([]string))
+			}
+			if !roundTripEqual(reflect.ValueOf(wv), reflect.ValueOf(gv)) {
+				diffs = append(diffs, fmt.Sprintf("%s: got %#v, want %#v", field.Name, gv, wv))
+			}
+		}
+	}
+	compare(reflect.ValueOf(want).Elem(), reflect.ValueOf(got).Elem())
+	return diffs
+}
+
+func sortedCopy(list []string) []string {
+	sorted := slices.Clone(list)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// roundTripEqual is reflect.DeepEqual, except that nil and empty lists or
+// maps are equal.
+func roundTripEqual(w, g reflect.Value) bool {
+	switch w.Kind() {
+	case reflect.Slice, reflect.Map:
+		if w.Len() == 0 && g.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(w.Interface(), g.Interface())
+}
+
+// AssertRoundTrip fails t if agent does not survive a round trip through
+// adapter, apart from the fields the adapter declares lossy.
+func AssertRoundTrip(t testing.TB, adapter Adapter, agent *Agent) {
+	t.Helper()
+	got, err := RoundTrip(adapter, agent)
+	if err != nil {
+		t.Errorf("%s round trip of %s: %v", adapter.Name(), agent.Name, err)
+		return
+	}
+	for _, diff := range RoundTripDiff(adapter, agent, got) {
+		t.Errorf("%s round trip of %s: %s", adapter.Name(), agent.Name, diff)
+	}
+}
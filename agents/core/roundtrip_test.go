@@ -0,0 +1,71 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// jsonAdapter round-trips agents through JSON, dropping the fields in drop
+// and declaring lossy the fields in lossy.
+type jsonAdapter struct {
+	stubAdapter
+	drop  func(*Agent)
+	lossy []string
+}
+
+func (a *jsonAdapter) Marshal(agent *Agent) ([]byte, error) {
+	clone := *agent
+	if a.drop != nil {
+		a.drop(&clone)
+	}
+	return json.Marshal(&clone)
+}
+
+func (a *jsonAdapter) Parse(data []byte) (*Agent, error) {
+	var agent Agent
+	err := json.Unmarshal(data, &agent)
+	return &agent, err
+}
+
+func (a *jsonAdapter) LossyFields() []string { return a.lossy }
+
+func TestRoundTripDiff(t *testing.T) {
+	agent := NewAgent("reviewer", "Reviews code").WithTools("Read", "Bash").WithInstructions("Review.\n")
+	agent.Group = "qa"
+	agent.Skills = []string{"linting"}
+
+	dropSkills := func(a *Agent) { a.Skills = nil }
+	reorderTools := func(a *Agent) { a.Tools = []string{"Bash", "Read"} }
+	tests := []struct {
+		name    string
+		adapter *jsonAdapter
+		want    []string
+	}{
+		{"lossless", &jsonAdapter{}, nil},
+		{"metadata ignored", &jsonAdapter{drop: func(a *Agent) { a.Group = "" }}, nil},
+		{"tool order ignored", &jsonAdapter{drop: reorderTools}, nil},
+		{"instructions whitespace ignored", &jsonAdapter{drop: func(a *Agent) { a.Instructions = "Review." }}, nil},
+		{"undeclared loss", &jsonAdapter{drop: dropSkills}, []string{"Skills: got []string(nil)"}},
+		{"declared loss", &jsonAdapter{drop: dropSkills, lossy: []string{"Skills"}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RoundTrip(tt.adapter, agent)
+			if err != nil {
+				t.Fatalf("RoundTrip() error = %v", err)
+			}
+			diffs := RoundTripDiff(tt.adapter, agent, got)
+			if len(diffs) != len(tt.want) {
+				t.Fatalf("RoundTripDiff() = %q, want %d diff(s)", diffs, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(diffs[i], want) {
+					t.Errorf("diff %q, want prefix %q", diffs[i], want)
+				}
+			}
+		})
+	}
+
+	AssertRoundTrip(t, &jsonAdapter{}, agent)
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Agent scopes. Formats such as Kiro install workspace agents with the
+// project and global agents in the user's home directory.
+const (
+	// ScopeWorkspace is the default scope: the agent belongs to the project.
+	ScopeWorkspace = "workspace"
+
+	// ScopeGlobal makes the agent available in every project for the user.
+	ScopeGlobal = "global"
+)
+
+// IsGlobal reports whether the agent uses the global scope. An empty Scope
+// means workspace.
+func (a *Agent) IsGlobal() bool {
+	return a.Scope == ScopeGlobal
+}
+
+// CheckScopes returns an error for the first agent whose Scope is neither
+// empty, "workspace", nor "global".
+func CheckScopes(agents []*Agent) error {
+	for _, agent := range agents {
+		switch agent.Scope {
+		case "", ScopeWorkspace, ScopeGlobal:
+		default:
+			return fmt.Errorf("agent %s: scope %q must be %q or %q", agent.Name, agent.Scope, ScopeWorkspace, ScopeGlobal)
+		}
+	}
+	return nil
+}
+
+// PathResolver is implemented by adapters that place some agents outside
+// the flat "<name><ext>" layout, e.g. in a scope-specific subdirectory.
+type PathResolver interface {
+	// AgentPath returns the agent's file path relative to the output
+	// directory, using forward slashes.
+	AgentPath(agent *Agent) string
+}
+
+// AgentPath returns the agent's output path relative to the output
+// directory: the adapter's PathResolver result, or "<name><ext>".
+func AgentPath(adapter Adapter, agent *Agent) string {
+	if pr, ok := adapter.(PathResolver); ok {
+		return filepath.FromSlash(pr.AgentPath(agent))
+	}
+	return agent.Name + adapter.FileExtension()
+}
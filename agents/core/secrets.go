@@ -0,0 +1,122 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SecretScheme prefixes config values that name a secret to be resolved at
+// generate time, e.g. "secret://anthropic-api-key".
+const SecretScheme = "secret://"
+
+// SecretResolver looks up secret values by name.
+// Implementations must not log or otherwise expose resolved values.
+type SecretResolver interface {
+	Resolve(ctx context.Context, name string) (string, error)
+}
+
+// SecretError indicates a secret reference that could not be resolved.
+// It carries the secret name only, never the value.
+type SecretError struct {
+	Name string
+	Err  error
+}
+
+func (e *SecretError) Error() string {
+	return fmt.Sprintf("failed to resolve secret %q: %v", e.Name, e.Err)
+}
+
+func (e *SecretError) Unwrap() error {
+	return e.Err
+}
+
+// EnvSecretResolver resolves secrets from environment variables. The
+// variable name is Prefix followed by the secret name upper-cased, with
+// "-", "." and "/" replaced by "_" (so "anthropic-api-key" reads
+// ANTHROPIC_API_KEY).
+type EnvSecretResolver struct {
+	Prefix string
+}
+
+// EnvVar returns the environment variable that holds the secret name.
+func (r EnvSecretResolver) EnvVar(name string) string {
+	return r.Prefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", "/", "_").Replace(name))
+}
+
+// Resolve returns the value of the environment variable for name.
+func (r EnvSecretResolver) Resolve(_ context.Context, name string) (string, error) {
+	key := r.EnvVar(name)
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", key)
+	}
+	return value, nil
+}
+
+// SecretName returns the secret name referenced by value and whether value
+// uses the secret:// scheme.
+func SecretName(value string) (string, bool) {
+	if !strings.HasPrefix(value, SecretScheme) {
+		return "", false
+	}
+	return strings.TrimPrefix(value, SecretScheme), true
+}
+
+// ResolveSecrets returns a deep copy of config with every secret:// string
+// value replaced by its resolved secret. Nested maps and slices are
+// traversed; the input is never modified.
+func ResolveSecrets(ctx context.Context, r SecretResolver, config map[string]interface{}) (map[string]interface{}, error) {
+	if config == nil {
+		return nil, nil
+	}
+	resolved, err := resolveSecretValue(ctx, r, config)
+	if err != nil {
+		return nil, err
+	}
+	return resolved.(map[string]interface{}), nil
+}
+
+func resolveSecretValue(ctx context.Context, r SecretResolver, v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		name, ok := SecretName(v)
+		if !ok {
+			return v, nil
+		}
+		if name == "" {
+			return nil, &SecretError{Name: name, Err: fmt.Errorf("empty secret name")}
+		}
+		value, err := r.Resolve(ctx, name)
+		if err != nil {
+			return nil, &SecretError{Name: name, Err: err}
+		}
+		return value, nil
+
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			resolved, err := resolveSecretValue(ctx, r, elem)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = resolved
+		}
+		return out, nil
+
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, elem := range v {
+			resolved, err := resolveSecretValue(ctx, r, elem)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolved
+		}
+		return out, nil
+
+	default:
+		return v, nil
+	}
+}
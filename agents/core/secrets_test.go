@@ -0,0 +1,72 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type mapSecretResolver map[string]string
+
+func (m mapSecretResolver) Resolve(_ context.Context, name string) (string, error) {
+	if v, ok := m[name]; ok {
+		return v, nil
+	}
+	return "", errors.New("not found")
+}
+
+func TestResolveSecrets(t *testing.T) {
+	resolver := mapSecretResolver{"api-key": "s3cr3t"}
+	config := map[string]interface{}{
+		"apiKey": "secret://api-key",
+		"region": "us-east-1",
+		"nested": map[string]interface{}{"token": "secret://api-key"},
+		"list":   []interface{}{"plain", "secret://api-key", 3.0},
+	}
+
+	got, err := ResolveSecrets(context.Background(), resolver, config)
+	if err != nil {
+		t.Fatalf("ResolveSecrets() error = %v", err)
+	}
+
+	if got["apiKey"] != "s3cr3t" || got["region"] != "us-east-1" {
+		t.Errorf("top-level values = %v", got)
+	}
+	if got["nested"].(map[string]interface{})["token"] != "s3cr3t" {
+		t.Errorf("nested value not resolved: %v", got["nested"])
+	}
+	if got["list"].([]interface{})[1] != "s3cr3t" {
+		t.Errorf("list value not resolved: %v", got["list"])
+	}
+	if config["apiKey"] != "secret://api-key" {
+		t.Error("input config must not be modified")
+	}
+}
+
+func TestResolveSecretsMissing(t *testing.T) {
+	_, err := ResolveSecrets(context.Background(), mapSecretResolver{}, map[string]interface{}{"apiKey": "secret://missing"})
+
+	var secretErr *SecretError
+	if !errors.As(err, &secretErr) || secretErr.Name != "missing" {
+		t.Fatalf("expected SecretError for missing, got %v", err)
+	}
+}
+
+func TestEnvSecretResolver(t *testing.T) {
+	t.Setenv("TEAM_ANTHROPIC_API_KEY", "s3cr3t")
+
+	r := EnvSecretResolver{Prefix: "TEAM_"}
+	got, err := r.Resolve(context.Background(), "anthropic-api-key")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if got != "s3cr3t" {
+		t.Errorf("Resolve() = %q, want s3cr3t", got)
+	}
+
+	_, err = r.Resolve(context.Background(), "unset")
+	if err == nil || strings.Contains(err.Error(), "s3cr3t") {
+		t.Errorf("unexpected error for unset secret: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package core
+
+import "strings"
+
+// InstructionSection is one labeled part of an agent's instructions: a
+// level-two Markdown heading (e.g., "Role") and the text below it.
+type InstructionSection struct {
+	Heading string `json:"heading,omitempty"`
+	Body    string `json:"body"`
+}
+
+// ParseInstructionSections splits instructions at their level-two headings
+// ("## Role") into ordered sections. Text before the first heading becomes
+// a section without a heading. Headings inside fenced code blocks do not
+// split. Instructions without any level-two heading return nil.
+func ParseInstructionSections(instructions string) []InstructionSection {
+	var sections []InstructionSection
+	var current *InstructionSection
+	var body []string
+	fenced := false
+	headings := 0
+
+	flush := func() {
+		text := strings.TrimSpace(strings.Join(body, "\n"))
+		if current != nil || text != "" {
+			section := InstructionSection{Body: text}
+			if current != nil {
+				section.Heading = current.Heading
+			}
+			sections = append(sections, section)
+		}
+		body = nil
+	}
+
+	for _, line := range strings.Split(instructions, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fenced = !fenced
+		}
+		if !fenced && strings.HasPrefix(line, "## ") {
+			flush()
+			current = &InstructionSection{Heading: strings.TrimSpace(line[3:])}
+			headings++
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+
+	if headings == 0 {
+		return nil
+	}
+	return sections
+}
+
+// JoinInstructionSections flattens sections back into Markdown
+// instructions, each headed section under a level-two heading.
+func JoinInstructionSections(sections []InstructionSection) string {
+	parts := make([]string, 0, len(sections))
+	for _, section := range sections {
+		switch {
+		case section.Heading == "":
+			parts = append(parts, section.Body)
+		case section.Body == "":
+			parts = append(parts, "## "+section.Heading)
+		default:
+			parts = append(parts, "## "+section.Heading+"\n\n"+section.Body)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// syncInstructionSections recomputes InstructionSections from Instructions,
+// after Instructions has changed.
+func (a *Agent) syncInstructionSections() {
+	a.InstructionSections = ParseInstructionSections(a.Instructions)
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseInstructionSections(t *testing.T) {
+	tests := []struct {
+		name         string
+		instructions string
+		want         []InstructionSection
+	}{
+		{"no headings", "Review the diff.\n\n# Title\n\n### Minor\n", nil},
+		{"sections", "## Role\n\nYou review code.\n\n## Guidelines\n\n- Be kind\n- Be brief\n", []InstructionSection{
+			{Heading: "Role", Body: "You review code."},
+			{Heading: "Guidelines", Body: "- Be kind\n- Be brief"},
+		}},
+		{"preamble", "Read carefully.\n\n## Role\n\nReviewer.", []InstructionSection{
+			{Body: "Read carefully."},
+			{Heading: "Role", Body: "Reviewer."},
+		}},
+		{"empty section", "## Role\n## Examples\n\nNone yet.", []InstructionSection{
+			{Heading: "Role"},
+			{Heading: "Examples", Body: "None yet."},
+		}},
+		{"fenced heading", "## Examples\n\n```markdown\n## Not a section\n```\n", []InstructionSection{
+			{Heading: "Examples", Body: "```markdown\n## Not a section\n```"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseInstructionSections(tt.instructions)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseInstructionSections() = %#v, want %#v", got, tt.want)
+			}
+			if got != nil {
+				if again := ParseInstructionSections(JoinInstructionSections(got)); !reflect.DeepEqual(again, got) {
+					t.Errorf("sections changed after JoinInstructionSections: %#v", again)
+				}
+			}
+		})
+	}
+}
+
+func TestReadCanonicalDirInstructionSections(t *testing.T) {
+	dir := t.TempDir()
+	writeSpec(t, dir, "base.md", "---\nname: base\ndescription: Base\nabstract: true\n---\n\n## Role\n\nYou are careful.\n")
+	writeSpec(t, dir, "reviewer.md", "---\nname: reviewer\ndescription: Reviews code\nextends: base\nprependInstructions: true\n---\n\n## Guidelines\n\nCite ${TEAM} style.\n")
+
+	agents, err := ReadCanonicalDir(dir)
+	if err != nil {
+		t.Fatalf("ReadCanonicalDir() error = %v", err)
+	}
+	reviewer := findAgent(agents, "reviewer")
+	want := []InstructionSection{
+		{Heading: "Role", Body: "You are careful."},
+		{Heading: "Guidelines", Body: "Cite ${TEAM} style."},
+	}
+	if !reflect.DeepEqual(reviewer.InstructionSections, want) {
+		t.Errorf("InstructionSections = %#v, want base sections first", reviewer.InstructionSections)
+	}
+
+	// Rewriting the instructions keeps the sections in step
+	interpolated, err := Interpolate(reviewer, map[string]string{"TEAM": "payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := interpolated.InstructionSections[1].Body; got != "Cite payments style." {
+		t.Errorf("interpolated section body = %q", got)
+	}
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// Timeouts overrides runtime operation timeouts for one agent. Each value
+// is a Go duration string (e.g., "10m"); empty fields keep the runtime's
+// global default.
+type Timeouts struct {
+	AgentInvoke   string `json:"agentInvoke,omitempty" yaml:"agentInvoke,omitempty"`
+	ShellCommand  string `json:"shellCommand,omitempty" yaml:"shellCommand,omitempty"`
+	FileRead      string `json:"fileRead,omitempty" yaml:"fileRead,omitempty"`
+	ParallelTotal string `json:"parallelTotal,omitempty" yaml:"parallelTotal,omitempty"`
+}
+
+// Validate checks that every set timeout parses with time.ParseDuration
+// and is positive.
+func (t *Timeouts) Validate() error {
+	for _, field := range []struct{ name, value string }{
+		{"agentInvoke", t.AgentInvoke},
+		{"shellCommand", t.ShellCommand},
+		{"fileRead", t.FileRead},
+		{"parallelTotal", t.ParallelTotal},
+	} {
+		if field.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(field.value)
+		if err != nil {
+			return fmt.Errorf("timeouts.%s: %w", field.name, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("timeouts.%s: %q must be positive", field.name, field.value)
+		}
+	}
+	return nil
+}
+
+// CheckTimeouts returns an error for the first agent with an invalid
+// timeout override.
+func CheckTimeouts(agents []*Agent) error {
+	for _, agent := range agents {
+		if agent.Timeouts == nil {
+			continue
+		}
+		if err := agent.Timeouts.Validate(); err != nil {
+			return fmt.Errorf("agent %s: %w", agent.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	multiagentspec "github.com/agentplexus/multi-agent-spec/sdk/go"
+)
+
+// ToolSupporter is implemented by adapters that map a fixed set of canonical
+// tools. Adapters that do not implement it are assumed to support exactly
+// the canonical tools (see CanonicalTools).
+type ToolSupporter interface {
+	// SupportedTools returns the canonical tool names the adapter can map.
+	SupportedTools() []string
+}
+
+// CanonicalTools returns the canonical tool names defined by multi-agent-spec,
+// sorted alphabetically.
+func CanonicalTools() []string {
+	tools := []string{
+		string(multiagentspec.ToolWebSearch),
+		string(multiagentspec.ToolWebFetch),
+		string(multiagentspec.ToolRead),
+		string(multiagentspec.ToolWrite),
+		string(multiagentspec.ToolGlob),
+		string(multiagentspec.ToolGrep),
+		string(multiagentspec.ToolBash),
+		string(multiagentspec.ToolEdit),
+		string(multiagentspec.ToolTask),
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+// KnownTools returns the set of canonical tool names, the single source of
+// truth that Validate and adapters check tools against.
+func KnownTools() map[string]bool {
+	known := make(map[string]bool)
+	for _, tool := range CanonicalTools() {
+		known[tool] = true
+	}
+	return known
+}
+
+// toolKeyReplacer strips the word separators toolKey ignores.
+var toolKeyReplacer = strings.NewReplacer("_", "", "-", "", " ", "")
+
+// toolKey folds the spellings of a tool name that NormalizeTools treats as
+// the same tool: case and word separators are ignored.
+func toolKey(tool string) string {
+	return strings.ToLower(toolKeyReplacer.Replace(strings.TrimSpace(tool)))
+}
+
+// NormalizeTools maps tools to their canonical names, ignoring case and
+// word separators (e.g., "bash", "webSearch" and "web_fetch" become Bash,
+// WebSearch and WebFetch). It returns the canonical tools in order without
+// duplicates, and the tools matching no canonical tool, unchanged.
+func NormalizeTools(tools []string) (known, unknown []string) {
+	byKey := make(map[string]string)
+	for _, tool := range CanonicalTools() {
+		byKey[toolKey(tool)] = tool
+	}
+
+	seen := make(map[string]bool)
+	for _, tool := range tools {
+		canonical, ok := byKey[toolKey(tool)]
+		if !ok {
+			unknown = append(unknown, tool)
+			continue
+		}
+		if !seen[canonical] {
+			seen[canonical] = true
+			known = append(known, canonical)
+		}
+	}
+	return known, unknown
+}
+
+// toolWarnings reports the tools and allowed tools that are not canonical
+// tools, suggesting the canonical spelling of those NormalizeTools
+// recognizes (e.g., "bash" or "web_fetch"). Unknown tools are not errors:
+// MCP and other tool-specific tools pass through adapters unchanged unless
+// CheckTools rejects them (-strict-tools).
+func (a *Agent) toolWarnings() []string {
+	known := KnownTools()
+	var warnings []string
+	for _, list := range []struct {
+		field string
+		tools []string
+	}{{"tools", a.Tools}, {"allowedTools", a.AllowedTools}} {
+		for i, tool := range list.tools {
+			if known[tool] {
+				continue
+			}
+			if normalized, _ := NormalizeTools([]string{tool}); len(normalized) == 1 {
+				warnings = append(warnings, fmt.Sprintf("%s[%d] %q is not a known tool (did you mean %q?)", list.field, i, tool, normalized[0]))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("%s[%d] %q is not a known tool; it is passed through unchanged", list.field, i, tool))
+			}
+		}
+	}
+	return warnings
+}
+
+// SupportedTools returns the canonical tools the adapter can map.
+func SupportedTools(adapter Adapter) []string {
+	if ts, ok := adapter.(ToolSupporter); ok {
+		return ts.SupportedTools()
+	}
+	return CanonicalTools()
+}
+
+// SupportsTool reports whether adapter can map tool, matching tool names
+// as in NormalizeTools. Declared is false, and supported meaningless, for
+// adapters that do not implement ToolSupporter and so do not say which
+// tools they map.
+func SupportsTool(adapter Adapter, tool string) (supported, declared bool) {
+	ts, ok := adapter.(ToolSupporter)
+	if !ok {
+		return false, false
+	}
+	key := toolKey(tool)
+	for _, t := range ts.SupportedTools() {
+		if toolKey(t) == key {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+// AdaptersSupporting returns the names of the registered adapters, sorted
+// alphabetically, that declare they can map tool (see SupportsTool).
+// Adapters that do not implement ToolSupporter are not included.
+func (r *Registry) AdaptersSupporting(tool string) []string {
+	var names []string
+	for _, name := range r.AdapterNames() {
+		adapter, _ := r.GetAdapter(name)
+		if supported, _ := SupportsTool(adapter, tool); supported {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// AdaptersSupporting returns the adapters of the default registry that can
+// map tool.
+func AdaptersSupporting(tool string) []string {
+	return DefaultRegistry.AdaptersSupporting(tool)
+}
+
+// ToolDropper is implemented by adapters that omit the tools they cannot
+// map instead of passing them through.
+type ToolDropper interface {
+	// DroppedTools returns the tools of agent that the adapter's output
+	// omits.
+	DroppedTools(agent *Agent) []string
+}
+
+// DroppedTools returns the tools of agent that adapter omits, or nil if
+// the adapter does not implement ToolDropper.
+func DroppedTools(adapter Adapter, agent *Agent) []string {
+	if td, ok := adapter.(ToolDropper); ok {
+		return td.DroppedTools(agent)
+	}
+	return nil
+}
+
+// CheckTools verifies that every tool and allowed tool of each agent is in
+// the adapter's supported set. It returns an UnknownToolError for the first
+// offending tool, for use where unknown tools should not pass through.
+func CheckTools(agents []*Agent, adapter Adapter) error {
+	known := make(map[string]bool)
+	for _, tool := range SupportedTools(adapter) {
+		known[tool] = true
+	}
+
+	for _, agent := range agents {
+		for _, tools := range [][]string{agent.Tools, agent.AllowedTools} {
+			for _, tool := range tools {
+				if !known[tool] {
+					return &UnknownToolError{Agent: agent.Name, Tool: tool, Format: adapter.Name()}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// FilterTools returns a copy of a whose tools and allowed tools are
+// restricted by allow and deny lists, e.g., to strip Bash and Write from
+// agents deployed to a restricted target. An empty allow list allows every
+// tool, and deny wins over allow. Tool names match as in NormalizeTools,
+// ignoring case and word separators. a itself is never modified.
+func FilterTools(a *Agent, allow, deny []string) *Agent {
+	allowed := toolKeySet(allow)
+	denied := toolKeySet(deny)
+	keep := func(tools []string) []string {
+		if tools == nil {
+			return nil
+		}
+		kept := make([]string, 0, len(tools))
+		for _, tool := range tools {
+			key := toolKey(tool)
+			if denied[key] || (len(allowed) > 0 && !allowed[key]) {
+				continue
+			}
+			kept = append(kept, tool)
+		}
+		return kept
+	}
+
+	filtered := *a
+	filtered.Tools = keep(a.Tools)
+	filtered.AllowedTools = keep(a.AllowedTools)
+	return &filtered
+}
+
+// toolKeySet returns the toolKey of each of tools as a set.
+func toolKeySet(tools []string) map[string]bool {
+	set := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		set[toolKey(tool)] = true
+	}
+	return set
+}
@@ -0,0 +1,160 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stubAdapter is a minimal Adapter for exercising adapter-agnostic helpers.
+type stubAdapter struct {
+	name  string
+	tools []string
+}
+
+func (a *stubAdapter) Name() string                    { return a.name }
+func (a *stubAdapter) FileExtension() string           { return ".txt" }
+func (a *stubAdapter) DefaultDir() string              { return "stub" }
+func (a *stubAdapter) Parse([]byte) (*Agent, error)    { return nil, nil }
+func (a *stubAdapter) Marshal(*Agent) ([]byte, error)  { return nil, nil }
+func (a *stubAdapter) ReadFile(string) (*Agent, error) { return nil, nil }
+func (a *stubAdapter) WriteFile(*Agent, string) error  { return nil }
+func (a *stubAdapter) SupportedTools() []string        { return a.tools }
+
+func TestCheckTools(t *testing.T) {
+	adapter := &stubAdapter{name: "stub", tools: []string{"Read", "Grep"}}
+
+	tests := []struct {
+		name     string
+		agent    *Agent
+		wantTool string
+	}{
+		{"all known", NewAgent("ok", "").WithTools("Read", "Grep"), ""},
+		{"unknown tool", NewAgent("bad", "").WithTools("Read", "Bash"), "Bash"},
+		{"unknown allowed tool", &Agent{Spec: Spec{Name: "bad", Tools: []string{"Read"}, AllowedTools: []string{"read"}}}, "read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTools([]*Agent{tt.agent}, adapter)
+			if tt.wantTool == "" {
+				if err != nil {
+					t.Fatalf("CheckTools() error = %v", err)
+				}
+				return
+			}
+			var toolErr *UnknownToolError
+			if !errors.As(err, &toolErr) {
+				t.Fatalf("expected UnknownToolError, got %v", err)
+			}
+			if toolErr.Agent != tt.agent.Name || toolErr.Tool != tt.wantTool || toolErr.Format != "stub" {
+				t.Errorf("error = %+v, want agent %s tool %s", toolErr, tt.agent.Name, tt.wantTool)
+			}
+		})
+	}
+}
+
+func TestSupportedToolsDefaultsToCanonical(t *testing.T) {
+	type plain struct{ Adapter }
+	got := SupportedTools(plain{&stubAdapter{name: "plain"}})
+	if len(got) != len(CanonicalTools()) {
+		t.Errorf("SupportedTools() = %v, want canonical tools", got)
+	}
+}
+
+func TestAdaptersSupporting(t *testing.T) {
+	type plain struct{ Adapter }
+	r := NewRegistry()
+	r.Register(&stubAdapter{name: "readonly", tools: []string{"Read", "Grep"}})
+	r.Register(&stubAdapter{name: "shell", tools: []string{"Read", "Bash"}})
+	r.Register(plain{&stubAdapter{name: "plain"}})
+
+	tests := []struct {
+		tool string
+		want []string
+	}{
+		{"Read", []string{"readonly", "shell"}},
+		{"bash", []string{"shell"}},
+		{"WebSearch", nil},
+	}
+	for _, tt := range tests {
+		if got := r.AdaptersSupporting(tt.tool); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("AdaptersSupporting(%q) = %v, want %v", tt.tool, got, tt.want)
+		}
+	}
+
+	if _, declared := SupportsTool(plain{&stubAdapter{name: "plain"}}, "Read"); declared {
+		t.Error("SupportsTool() declared = true for an adapter without ToolSupporter")
+	}
+}
+
+func TestNormalizeTools(t *testing.T) {
+	tests := []struct {
+		name        string
+		tools       []string
+		wantKnown   []string
+		wantUnknown []string
+	}{
+		{"canonical", []string{"Read", "Bash"}, []string{"Read", "Bash"}, nil},
+		{"case folded", []string{"read", "GREP", "webSearch"}, []string{"Read", "Grep", "WebSearch"}, nil},
+		{"separators ignored", []string{"web_fetch", "Web-Search", " bash "}, []string{"WebFetch", "WebSearch", "Bash"}, nil},
+		{"duplicates dropped", []string{"Read", "read", "READ"}, []string{"Read"}, nil},
+		{"unknown kept", []string{"Read", "run_command", "Teleport"}, []string{"Read"}, []string{"run_command", "Teleport"}},
+		{"empty", nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			known, unknown := NormalizeTools(tt.tools)
+			if strings.Join(known, ",") != strings.Join(tt.wantKnown, ",") {
+				t.Errorf("known = %v, want %v", known, tt.wantKnown)
+			}
+			if strings.Join(unknown, ",") != strings.Join(tt.wantUnknown, ",") {
+				t.Errorf("unknown = %v, want %v", unknown, tt.wantUnknown)
+			}
+		})
+	}
+}
+
+func TestKnownTools(t *testing.T) {
+	known := KnownTools()
+	for _, tool := range []string{"Read", "Write", "Glob", "Grep", "Bash", "Edit", "Task", "WebSearch", "WebFetch"} {
+		if !known[tool] {
+			t.Errorf("KnownTools() missing %s", tool)
+		}
+	}
+}
+
+func TestFilterTools(t *testing.T) {
+	tests := []struct {
+		name      string
+		allow     []string
+		deny      []string
+		wantTools []string
+	}{
+		{"no lists", nil, nil, []string{"Read", "Write", "Bash", "Grep"}},
+		{"deny", nil, []string{"Bash", "Write"}, []string{"Read", "Grep"}},
+		{"allow", []string{"Read", "Bash"}, nil, []string{"Read", "Bash"}},
+		{"deny wins", []string{"Read", "Bash"}, []string{"Bash"}, []string{"Read"}},
+		{"spellings", nil, []string{"bash", "WRITE"}, []string{"Read", "Grep"}},
+		{"none left", []string{"WebSearch"}, nil, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &Agent{Spec: Spec{Name: "coder", Tools: []string{"Read", "Write", "Bash", "Grep"}, AllowedTools: []string{"Bash", "Read"}}}
+			got := FilterTools(agent, tt.allow, tt.deny)
+			if strings.Join(got.Tools, ",") != strings.Join(tt.wantTools, ",") {
+				t.Errorf("Tools = %v, want %v", got.Tools, tt.wantTools)
+			}
+			for _, tool := range got.AllowedTools {
+				if !strings.Contains(","+strings.Join(tt.wantTools, ",")+",", ","+tool+",") {
+					t.Errorf("AllowedTools = %v keeps filtered tool %s", got.AllowedTools, tool)
+				}
+			}
+			if len(agent.Tools) != 4 || len(agent.AllowedTools) != 2 {
+				t.Errorf("input agent modified: %v, %v", agent.Tools, agent.AllowedTools)
+			}
+		})
+	}
+}
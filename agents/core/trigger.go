@@ -0,0 +1,72 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Trigger event names. Formats with agent hooks run a trigger's Action when
+// its Event occurs; others ignore triggers.
+const (
+	// TriggerSessionStart fires when the agent starts.
+	TriggerSessionStart = "on_session_start"
+
+	// TriggerBeforePrompt fires when the user submits a prompt, before the
+	// agent sees it.
+	TriggerBeforePrompt = "before_prompt"
+
+	// TriggerBeforeTool fires before a tool matching Pattern runs.
+	TriggerBeforeTool = "before_tool"
+
+	// TriggerAfterTool fires after a tool matching Pattern ran.
+	TriggerAfterTool = "after_tool"
+
+	// TriggerStop fires when the agent finishes responding.
+	TriggerStop = "on_stop"
+)
+
+// TriggerEvents lists the trigger events in lifecycle order.
+var TriggerEvents = []string{TriggerSessionStart, TriggerBeforePrompt, TriggerBeforeTool, TriggerAfterTool, TriggerStop}
+
+// Trigger runs a shell command on an agent lifecycle event, such as
+// formatting files after every write. Kiro maps triggers to agent hooks.
+type Trigger struct {
+	// Event is when the trigger fires; one of TriggerEvents.
+	Event string `json:"event" yaml:"event"`
+
+	// Pattern limits tool events to matching tools: a canonical tool name
+	// (e.g., Write) or "*" for every tool. Empty matches every tool. Other
+	// events take no pattern.
+	Pattern string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+
+	// Action is the shell command to run.
+	Action string `json:"action" yaml:"action"`
+}
+
+// IsToolEvent reports whether the trigger fires around tool use, the only
+// events a Pattern applies to.
+func (t Trigger) IsToolEvent() bool {
+	return t.Event == TriggerBeforeTool || t.Event == TriggerAfterTool
+}
+
+// triggerProblems returns a FieldError for each invalid trigger: unknown
+// events, missing actions, and patterns on events other than tool events.
+func triggerProblems(triggers []Trigger) []*FieldError {
+	var problems []*FieldError
+	for i, trigger := range triggers {
+		field := fmt.Sprintf("triggers[%d]", i)
+		switch {
+		case !slices.Contains(TriggerEvents, trigger.Event):
+			problems = append(problems, &FieldError{Field: field + ".event", Value: trigger.Event,
+				Message: fmt.Sprintf("is not a known trigger event (known: %s)", strings.Join(TriggerEvents, ", "))})
+		case trigger.Pattern != "" && !trigger.IsToolEvent():
+			problems = append(problems, &FieldError{Field: field + ".pattern", Value: trigger.Pattern,
+				Message: fmt.Sprintf("only applies to %s and %s triggers", TriggerBeforeTool, TriggerAfterTool)})
+		}
+		if strings.TrimSpace(trigger.Action) == "" {
+			problems = append(problems, &FieldError{Field: field + ".action", Message: "is required"})
+		}
+	}
+	return problems
+}
@@ -0,0 +1,39 @@
+package core
+
+import (
+	"regexp"
+)
+
+// agentNamePattern matches names that are safe as file names on every
+// platform adapters write to: no path separators, spaces or leading dots.
+var agentNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Validate checks the fields every adapter relies on: Name must be set and
+// usable as a file name, Instructions must be set, and triggers must have
+// a known event and an action. It reports every problem at once as a
+// *ValidationError.
+//
+// Unknown tools are not errors: they (e.g., MCP tools) pass through
+// adapters unchanged unless CheckTools rejects them (-strict-tools).
+// Warnings reports them instead, suggesting the canonical spelling.
+func (a *Agent) Validate() error {
+	var problems []*FieldError
+
+	switch {
+	case a.Name == "":
+		problems = append(problems, &FieldError{Field: "name", Message: "is required"})
+	case !agentNamePattern.MatchString(a.Name):
+		problems = append(problems, &FieldError{Field: "name", Value: a.Name, Message: "must contain only letters, digits, '.', '_' and '-', and start with a letter or digit"})
+	}
+
+	if a.Instructions == "" {
+		problems = append(problems, &FieldError{Field: "instructions", Message: "is required"})
+	}
+
+	problems = append(problems, triggerProblems(a.Triggers)...)
+
+	if len(problems) > 0 {
+		return &ValidationError{Agent: a.Name, Path: a.SourcePath, Problems: problems}
+	}
+	return nil
+}
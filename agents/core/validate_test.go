@@ -0,0 +1,85 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		agent  *Agent
+		fields []string
+	}{
+		{"valid", NewAgent("reviewer", "Reviews").WithInstructions("Review.").WithTools("Read", "Grep"), nil},
+		{"missing name", NewAgent("", "Reviews").WithInstructions("Review."), []string{"name"}},
+		{"unsafe name", NewAgent("../reviewer", "Reviews").WithInstructions("Review."), []string{"name"}},
+		{"missing instructions", NewAgent("reviewer", "Reviews"), []string{"instructions"}},
+		{"unknown tools pass", NewAgent("reviewer", "Reviews").WithInstructions("Review.").WithTools("Read", "bash", "mcp__github__create_issue"), nil},
+		{"everything", NewAgent("has space", "Reviews").WithTools("Nope"), []string{"name", "instructions"}},
+		{"valid triggers", withTriggers(Trigger{Event: TriggerAfterTool, Pattern: "Write", Action: "gofmt -w ."}, Trigger{Event: TriggerStop, Action: "make test"}), nil},
+		{"bad triggers", withTriggers(Trigger{Event: "on_save", Action: "lint"}, Trigger{Event: TriggerStop, Pattern: "Write", Action: "make"}, Trigger{Event: TriggerBeforeTool}), []string{"triggers[0].event", "triggers[1].pattern", "triggers[2].action"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.agent.Validate()
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			var valErr *ValidationError
+			if !errors.As(err, &valErr) {
+				t.Fatalf("Validate() error = %v, want *ValidationError", err)
+			}
+			var got []string
+			for _, p := range valErr.Problems {
+				got = append(got, p.Field)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("problem fields = %v, want %v", got, tt.fields)
+			}
+		})
+	}
+}
+
+func withTriggers(triggers ...Trigger) *Agent {
+	agent := NewAgent("reviewer", "Reviews").WithInstructions("Review.")
+	agent.Triggers = triggers
+	return agent
+}
+
+func TestValidateMessages(t *testing.T) {
+	err := NewAgent("has space", "Reviews").WithInstructions("Review.").Validate()
+	if err == nil || !strings.Contains(err.Error(), `name "has space" must contain only letters`) {
+		t.Errorf("Validate() error = %v", err)
+	}
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Value != "has space" {
+		t.Errorf("errors.As(*FieldError) = %+v", fieldErr)
+	}
+}
+
+func TestReadCanonicalDirValidates(t *testing.T) {
+	dir := t.TempDir()
+	writeSpec(t, dir, "ok.md", "---\nname: ok\ndescription: Fine\n---\n\nDo things.\n")
+	writeSpec(t, dir, "empty.md", "---\nname: empty\ndescription: No body\n---\n")
+	writeSpec(t, dir, "hooks.md", "---\nname: hooks\ndescription: Bad trigger\ntools: [Fly]\ntriggers:\n  - event: on_save\n    action: fly\n---\n\nFly.\n")
+
+	_, err := ReadCanonicalDir(dir)
+	if err == nil {
+		t.Fatal("ReadCanonicalDir() should fail on invalid agents")
+	}
+	for _, want := range []string{"invalid agent empty", "instructions is required", "invalid agent hooks", `triggers[0].event "on_save"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+	var valErr *ValidationError
+	if !errors.As(err, &valErr) {
+		t.Errorf("error = %v, want *ValidationError", err)
+	}
+}
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"bytes"
+	"strings"
+)
+
+// VersionMetadataKey is the metadata key holding the spec version in
+// formats that carry it as key/value metadata, such as OpenAI assistants
+// and Claude Skills.
+const VersionMetadataKey = "version"
+
+// AddVersionComment prepends a comment naming the spec version to data,
+// for formats with comments but no version field (see AddComment). Data is
+// returned unchanged when version is empty.
+func AddVersionComment(ext string, data []byte, version string) []byte {
+	if version == "" {
+		return data
+	}
+	return AddComment(ext, data, "Spec version: "+version)
+}
+
+// AddComment prepends text to generated data as a comment in the syntax of
+// the file extension ext: "#" lines inside Markdown and Cursor MDC
+// frontmatter (so it still parses), "#" for TOML and YAML, "//" for
+// TypeScript and JavaScript, and an HTML comment for plain Markdown. JSON
+// and unknown extensions have no comments, so their data is returned
+// unchanged.
+func AddComment(ext string, data []byte, text string) []byte {
+	if text == "" {
+		return data
+	}
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+
+	switch ext {
+	case ".md", ".mdc":
+		if bytes.HasPrefix(data, []byte("---\n")) {
+			return append([]byte("---\n"+commentLines("#", lines)), data[len("---\n"):]...)
+		}
+		return append([]byte("<!--\n"+strings.Join(lines, "\n")+"\n-->\n\n"), data...)
+	case ".toml", ".yaml", ".yml":
+		return append([]byte(commentLines("#", lines)+"\n"), data...)
+	case ".ts", ".js":
+		return append([]byte(commentLines("//", lines)+"\n"), data...)
+	default:
+		return data
+	}
+}
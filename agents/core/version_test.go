@@ -0,0 +1,27 @@
+package core
+
+import "testing"
+
+func TestAddVersionComment(t *testing.T) {
+	tests := []struct {
+		name    string
+		ext     string
+		in      string
+		version string
+		want    string
+	}{
+		{"frontmatter", ".mdc", "---\nname: a\n---\n", "1.2.0", "---\n# Spec version: 1.2.0\nname: a\n---\n"},
+		{"toml", ".toml", "name = \"a\"\n", "1.2.0", "# Spec version: 1.2.0\n\nname = \"a\"\n"},
+		{"typescript", ".ts", "x\n", "1.2.0", "// Spec version: 1.2.0\n\nx\n"},
+		{"json has no comments", ".json", "{}", "1.2.0", "{}"},
+		{"no version", ".toml", "name = \"a\"\n", "", "name = \"a\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(AddVersionComment(tt.ext, []byte(tt.in), tt.version))
+			if got != tt.want {
+				t.Errorf("AddVersionComment() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestWalkCanonical(t *testing.T) {
+	dir := t.TempDir()
+	writeSpec(t, dir, "base.md", "---\nname: base\ndescription: Base\nabstract: true\ntools: [Read]\n---\n\nBe careful.\n")
+	writeSpec(t, dir, "reviewer.md", "---\nname: reviewer\ndescription: Reviews\nextends: base\n---\n\nReview code.\n")
+	writeSpec(t, dir, "data/analyst.md", "---\nname: analyst\ndescription: Analyzes\n---\n\nAnalyze data.\n")
+	writeSpec(t, dir, "writer.json", `{"name": "writer", "description": "Writes", "instructions": "Write docs."}`)
+
+	want, err := ReadCanonicalDir(dir)
+	if err != nil {
+		t.Fatalf("ReadCanonicalDir() error = %v", err)
+	}
+
+	var got []*Agent
+	if err := WalkCanonical(dir, func(agent *Agent) error {
+		got = append(got, agent)
+		return nil
+	}); err != nil {
+		t.Fatalf("WalkCanonical() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WalkCanonical() = %+v, want %+v", got, want)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = WalkCanonical(dir, func(*Agent) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("WalkCanonical() error = %v after %d calls, want stop after 1", err, calls)
+	}
+}
+
+func TestWalkCanonicalInvalid(t *testing.T) {
+	dir := t.TempDir()
+	writeSpec(t, dir, "reviewer.md", "---\nname: reviewer\ndescription: Reviews\nextends: missing\n---\n\nReview code.\n")
+
+	err := WalkCanonical(dir, func(*Agent) error { return nil })
+	var inheritErr *InheritanceError
+	if !errors.As(err, &inheritErr) {
+		t.Errorf("WalkCanonical() error = %v, want *InheritanceError", err)
+	}
+
+	writeSpec(t, dir, "reviewer.md", "---\nname: reviewer\ndescription: Reviews\n---\n")
+	err = WalkCanonical(dir, func(*Agent) error { return nil })
+	var valErr *ValidationError
+	if !errors.As(err, &valErr) {
+		t.Errorf("WalkCanonical() error = %v, want *ValidationError", err)
+	}
+}
+
+// BenchmarkCanonicalDir compares reading a large spec directory into a
+// slice with streaming it through WalkCanonical.
+func BenchmarkCanonicalDir(b *testing.B) {
+	dir := b.TempDir()
+	for i := range 1000 {
+		writeSpec(b, dir, fmt.Sprintf("agent-%04d.md", i), fmt.Sprintf("---\nname: agent-%04d\ndescription: Agent %d\ntools: [Read, Grep]\n---\n\nDo task %d.\n", i, i, i))
+	}
+
+	b.Run("ReadCanonicalDir", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := ReadCanonicalDir(dir); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("WalkCanonical", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if err := WalkCanonical(dir, func(*Agent) error { return nil }); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
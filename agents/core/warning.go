@@ -0,0 +1,34 @@
+package core
+
+import "fmt"
+
+// Warning is a lossy conversion noted while marshaling an agent, such as a
+// tool the format has no equivalent for.
+type Warning struct {
+	Agent   string // Agent name
+	Field   string // Spec field affected (e.g., "tools", "model")
+	Message string
+}
+
+// String formats the warning as "agent: field: message".
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %s: %s", w.Agent, w.Field, w.Message)
+}
+
+// WarningMarshaler is implemented by adapters that report the lossy
+// conversions they make while marshaling.
+type WarningMarshaler interface {
+	// MarshalWithWarnings converts agent like Marshal, also returning the
+	// warnings noted on the way.
+	MarshalWithWarnings(agent *Agent) ([]byte, []Warning, error)
+}
+
+// MarshalWithWarnings marshals agent with adapter, returning the warnings
+// the adapter notes if it implements WarningMarshaler and none otherwise.
+func MarshalWithWarnings(adapter Adapter, agent *Agent) ([]byte, []Warning, error) {
+	if wm, ok := adapter.(WarningMarshaler); ok {
+		return wm.MarshalWithWarnings(agent)
+	}
+	data, err := adapter.Marshal(agent)
+	return data, nil, err
+}
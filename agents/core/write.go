@@ -0,0 +1,117 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LineEnding selects the line terminator used for generated files.
+type LineEnding string
+
+const (
+	// LineEndingPreserve writes adapter output unchanged.
+	LineEndingPreserve LineEnding = ""
+
+	// LineEndingLF normalizes output to "\n" and guarantees a final newline.
+	LineEndingLF LineEnding = "lf"
+
+	// LineEndingCRLF normalizes output to "\r\n" and guarantees a final newline.
+	LineEndingCRLF LineEnding = "crlf"
+)
+
+// ParseLineEnding parses a line ending name ("lf" or "crlf").
+// An empty string yields LineEndingPreserve.
+func ParseLineEnding(s string) (LineEnding, error) {
+	switch le := LineEnding(s); le {
+	case LineEndingPreserve, LineEndingLF, LineEndingCRLF:
+		return le, nil
+	default:
+		return "", fmt.Errorf("unknown line ending %q (expected lf or crlf)", s)
+	}
+}
+
+// Normalize rewrites every line terminator in data to le and ensures the
+// result ends with one. Empty input and LineEndingPreserve are returned as-is.
+func (le LineEnding) Normalize(data []byte) []byte {
+	if le == LineEndingPreserve || len(data) == 0 {
+		return data
+	}
+
+	lf := bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	if lf[len(lf)-1] != '\n' {
+		lf = append(lf, '\n')
+	}
+	if le == LineEndingCRLF {
+		return bytes.ReplaceAll(lf, []byte("\n"), []byte("\r\n"))
+	}
+	return lf
+}
+
+// SetLineEnding sets the line ending applied to files written through r.
+func (r *Registry) SetLineEnding(le LineEnding) {
+	r.settingsMu.Lock()
+	defer r.settingsMu.Unlock()
+	r.lineEnding = le
+}
+
+// LineEnding returns the line ending applied to files written through r.
+func (r *Registry) LineEnding() LineEnding {
+	r.settingsMu.RLock()
+	defer r.settingsMu.RUnlock()
+	return r.lineEnding
+}
+
+// SetLineEnding sets the line ending of DefaultRegistry, which
+// WriteOutputFile applies.
+func SetLineEnding(le LineEnding) {
+	DefaultRegistry.SetLineEnding(le)
+}
+
+// OutputLineEnding returns the line ending of DefaultRegistry, which
+// WriteOutputFile applies.
+func OutputLineEnding() LineEnding {
+	return DefaultRegistry.LineEnding()
+}
+
+// WriteOutputFile writes generated data to path, creating parent directories
+// and applying the line ending of DefaultRegistry. Adapters write through
+// this so output encoding is handled in one place. A file already holding
+// the data is left untouched (see WriteFileIfChanged).
+func WriteOutputFile(path string, data []byte) error {
+	_, err := WriteFileIfChanged(path, data)
+	return err
+}
+
+// WriteFileIfChanged is Registry.WriteFileIfChanged on DefaultRegistry.
+func WriteFileIfChanged(path string, data []byte) (bool, error) {
+	return DefaultRegistry.WriteFileIfChanged(path, data)
+}
+
+// WriteFileIfChanged writes data to path with the line ending of r
+// applied, creating parent directories, unless the file already holds
+// exactly those bytes. Skipping identical files keeps their modification
+// times, so regenerating unchanged agents causes no churn. It reports
+// whether the file was written.
+func (r *Registry) WriteFileIfChanged(path string, data []byte) (bool, error) {
+	data = r.LineEnding().Normalize(data)
+
+	existing, err := os.ReadFile(path)
+	if err == nil && bytes.Equal(existing, data) {
+		return false, nil
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, &ReadError{Path: path, Err: err}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), DefaultDirMode); err != nil {
+		return false, &WriteError{Path: path, Err: err}
+	}
+	if err := os.WriteFile(path, data, DefaultFileMode); err != nil {
+		return false, &WriteError{Path: path, Err: err}
+	}
+	return true, nil
+}
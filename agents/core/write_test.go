@@ -0,0 +1,105 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLineEndingNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		le   LineEnding
+		in   string
+		want string
+	}{
+		{"preserve", LineEndingPreserve, "a\r\nb", "a\r\nb"},
+		{"lf from mixed", LineEndingLF, "a\r\nb\nc", "a\nb\nc\n"},
+		{"lf keeps final newline", LineEndingLF, "a\n", "a\n"},
+		{"crlf from lf", LineEndingCRLF, "a\nb\n", "a\r\nb\r\n"},
+		{"crlf from mixed", LineEndingCRLF, "a\r\nb\nc", "a\r\nb\r\nc\r\n"},
+		{"crlf is idempotent", LineEndingCRLF, "a\r\n", "a\r\n"},
+		{"empty", LineEndingCRLF, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.le.Normalize([]byte(tt.in))); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLineEnding(t *testing.T) {
+	for _, s := range []string{"", "lf", "crlf"} {
+		if _, err := ParseLineEnding(s); err != nil {
+			t.Errorf("ParseLineEnding(%q) error = %v", s, err)
+		}
+	}
+	if _, err := ParseLineEnding("cr"); err == nil {
+		t.Error("ParseLineEnding(\"cr\") should fail")
+	}
+}
+
+func TestWriteFileIfChangedLineEndings(t *testing.T) {
+	// Mixed terminators and no final newline, as an adapter might produce
+	data := []byte("---\nname: reviewer\r\n---\n\nLine one\r\nLine two")
+
+	tests := []struct {
+		le   LineEnding
+		want string
+	}{
+		{LineEndingLF, "---\nname: reviewer\n---\n\nLine one\nLine two\n"},
+		{LineEndingCRLF, "---\r\nname: reviewer\r\n---\r\n\r\nLine one\r\nLine two\r\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.le), func(t *testing.T) {
+			r := NewRegistry()
+			r.SetLineEnding(tt.le)
+			path := filepath.Join(t.TempDir(), "out", "reviewer.md")
+			if _, err := r.WriteFileIfChanged(path, data); err != nil {
+				t.Fatalf("WriteFileIfChanged() error = %v", err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("file bytes = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteFileIfChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new", "dir", "reviewer.md")
+
+	changed, err := WriteFileIfChanged(path, []byte("v1\n"))
+	if err != nil || !changed {
+		t.Fatalf("first write: changed = %v, err = %v; want a write", changed, err)
+	}
+
+	// Backdate the file so a rewrite would be visible in its mtime
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	changed, err = WriteFileIfChanged(path, []byte("v1\n"))
+	if err != nil || changed {
+		t.Fatalf("identical write: changed = %v, err = %v; want a skip", changed, err)
+	}
+	if info, err := os.Stat(path); err != nil || !info.ModTime().Equal(old) {
+		t.Errorf("identical write touched the file: %v, %v", info.ModTime(), err)
+	}
+
+	changed, err = WriteFileIfChanged(path, []byte("v2\n"))
+	if err != nil || !changed {
+		t.Fatalf("changed write: changed = %v, err = %v; want a write", changed, err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "v2\n" {
+		t.Errorf("file = %q, want v2", got)
+	}
+}
@@ -0,0 +1,137 @@
+// Package cursor provides the Cursor rules adapter.
+package cursor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func init() {
+	core.Register(&Adapter{})
+}
+
+// Adapter converts between canonical Agent and Cursor project rules
+// (.cursor/rules/<name>.mdc). Agents become "agent requested" rules: not
+// always applied and without globs, so Cursor includes a rule when its
+// description matches the task.
+type Adapter struct{}
+
+// Name returns the adapter identifier.
+func (a *Adapter) Name() string {
+	return "cursor"
+}
+
+// FileExtension returns the file extension for Cursor rules.
+func (a *Adapter) FileExtension() string {
+	return ".mdc"
+}
+
+// DefaultDir returns the default directory name for Cursor rules.
+func (a *Adapter) DefaultDir() string {
+	return ".cursor/rules"
+}
+
+// LossyFields returns the canonical fields a Cursor rule does not hold:
+// rules carry only a description and instructions.
+func (a *Adapter) LossyFields() []string {
+	return []string{"Model", "ModelFallback", "MaxTurns", "Timeouts", "Retry", "Tools", "AllowedTools", "Skills", "Dependencies", "Requires", "Arguments", "Triggers", "Version"}
+}
+
+// frontmatter holds the MDC frontmatter keys. Cursor itself reads
+// description, globs and alwaysApply; name is ignored by Cursor and lets
+// Parse recover the agent name.
+type frontmatter struct {
+	Name        string `yaml:"name,omitempty"`
+	Description string `yaml:"description"`
+	Globs       string `yaml:"globs"`
+	AlwaysApply bool   `yaml:"alwaysApply"`
+}
+
+// Parse converts Cursor MDC bytes to canonical Agent.
+func (a *Adapter) Parse(data []byte) (*core.Agent, error) {
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(content, "---\n") {
+		return nil, &core.ParseError{Format: "cursor", Err: fmt.Errorf("missing frontmatter")}
+	}
+	rest := content[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return nil, &core.ParseError{Format: "cursor", Err: fmt.Errorf("unterminated frontmatter")}
+	}
+
+	var fm frontmatter
+	if err := yaml.Unmarshal([]byte(rest[:end]), &fm); err != nil {
+		return nil, &core.ParseError{Format: "cursor", Err: err}
+	}
+
+	return &core.Agent{Spec: core.Spec{
+		Name:         fm.Name,
+		Description:  fm.Description,
+		Instructions: strings.TrimSpace(rest[end+len("\n---"):]),
+	}}, nil
+}
+
+// Marshal converts canonical Agent to Cursor MDC bytes.
+func (a *Adapter) Marshal(agent *core.Agent) ([]byte, error) {
+	fm, err := yaml.Marshal(frontmatter{
+		Name:        agent.Name,
+		Description: core.FormatDescriptionLine(a.Name(), agent.Description),
+	})
+	if err != nil {
+		return nil, &core.MarshalError{Format: "cursor", Err: err}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(fm)
+	buf.WriteString("---\n\n")
+
+	if agent.Instructions != "" {
+		buf.WriteString(agent.Instructions)
+		buf.WriteString("\n")
+	}
+
+	// Rules have no version key, so the spec version is a comment
+	return core.AddVersionComment(a.FileExtension(), buf.Bytes(), agent.Version), nil
+}
+
+// ReadFile reads a Cursor rule file and returns canonical Agent.
+func (a *Adapter) ReadFile(path string) (*core.Agent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &core.ReadError{Path: path, Err: err}
+	}
+
+	agent, err := a.Parse(data)
+	if err != nil {
+		if pe, ok := err.(*core.ParseError); ok {
+			pe.Path = path
+		}
+		return nil, err
+	}
+
+	// Hand-written rules have no name; use the filename
+	if agent.Name == "" {
+		base := filepath.Base(path)
+		agent.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	return agent, nil
+}
+
+// WriteFile writes canonical Agent to a Cursor rule file.
+func (a *Adapter) WriteFile(agent *core.Agent, path string) error {
+	data, err := a.Marshal(agent)
+	if err != nil {
+		return err
+	}
+
+	return core.WriteOutputFile(path, data)
+}
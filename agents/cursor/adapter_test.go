@@ -0,0 +1,56 @@
+package cursor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestRoundTrip(t *testing.T) {
+	adapter := &Adapter{}
+	agent := core.NewAgent("go-reviewer", "Review Go code: style, errors and tests").
+		WithInstructions("# Go review\n\nCheck error wrapping.")
+
+	data, err := adapter.Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	for _, want := range []string{"description: 'Review Go code: style, errors and tests'\n", "globs: \"\"\n", "alwaysApply: false\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("output missing %q:\n%s", want, data)
+		}
+	}
+
+	back, err := adapter.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if back.Name != agent.Name || back.Description != agent.Description || back.Instructions != agent.Instructions {
+		t.Errorf("round trip = %+v, want %+v", back.Spec, agent.Spec)
+	}
+}
+
+func TestReadFileInfersName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testing.mdc")
+	if err := core.WriteOutputFile(path, []byte("---\ndescription: Testing conventions\nglobs:\nalwaysApply: false\n---\n\nUse table tests.\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	agent, err := (&Adapter{}).ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if agent.Name != "testing" || agent.Description != "Testing conventions" || agent.Instructions != "Use table tests." {
+		t.Errorf("ReadFile() = %+v", agent.Spec)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, data := range []string{"no frontmatter", "---\ndescription: x\n", "---\ndescription: [\n---\n"} {
+		if _, err := (&Adapter{}).Parse([]byte(data)); err == nil {
+			t.Errorf("Parse(%q) should fail", data)
+		}
+	}
+}
@@ -1,4 +1,7 @@
-// Package gemini provides the Gemini CLI agent adapter.
+// Package gemini provides the Gemini agent adapters: the Gemini CLI
+// adapter, registered as format "gemini", and the Vertex AI Gemini agent
+// adapter, registered as format "vertex" because "gemini" was already
+// taken (use -format=vertex for Vertex AI).
 package gemini
 
 import (
@@ -32,6 +35,11 @@ func (a *Adapter) DefaultDir() string {
 	return "agents"
 }
 
+// LossyFields returns the canonical fields Gemini agent files do not hold.
+func (a *Adapter) LossyFields() []string {
+	return []string{"ModelFallback", "MaxTurns", "Timeouts", "Retry", "AllowedTools", "Requires", "Arguments", "Triggers", "Version"}
+}
+
 // GeminiAgent represents a Gemini CLI agent in TOML format.
 type GeminiAgent struct {
 	Agent        AgentSection `toml:"agent"`
@@ -55,7 +63,7 @@ func (a *Adapter) Parse(data []byte) (*core.Agent, error) {
 		return nil, &core.ParseError{Format: "gemini", Err: err}
 	}
 
-	agent := &core.Agent{
+	agent := &core.Agent{Spec: core.Spec{
 		Name:         ga.Agent.Name,
 		Description:  ga.Agent.Description,
 		Model:        mapGeminiModelToCanonical(ga.Agent.Model),
@@ -63,7 +71,7 @@ func (a *Adapter) Parse(data []byte) (*core.Agent, error) {
 		Skills:       ga.Agent.Skills,
 		Dependencies: ga.Agent.Dependencies,
 		Instructions: ga.Instructions,
-	}
+	}}
 
 	return agent, nil
 }
@@ -73,8 +81,8 @@ func (a *Adapter) Marshal(agent *core.Agent) ([]byte, error) {
 	ga := GeminiAgent{
 		Agent: AgentSection{
 			Name:         agent.Name,
-			Description:  agent.Description,
-			Model:        mapCanonicalModelToGemini(agent.Model),
+			Description:  core.FormatDescription(a.Name(), agent.Description),
+			Model:        mapCanonicalModelToGemini(agent.PrimaryModel()),
 			Tools:        agent.Tools,
 			Skills:       agent.Skills,
 			Dependencies: agent.Dependencies,
@@ -87,7 +95,7 @@ func (a *Adapter) Marshal(agent *core.Agent) ([]byte, error) {
 		return nil, &core.MarshalError{Format: "gemini", Err: err}
 	}
 
-	return data, nil
+	return core.AddVersionComment(a.FileExtension(), data, agent.Version), nil
 }
 
 // ReadFile reads a Gemini agent TOML file and returns canonical Agent.
@@ -121,16 +129,7 @@ func (a *Adapter) WriteFile(agent *core.Agent, path string) error {
 		return err
 	}
 
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, core.DefaultDirMode); err != nil {
-		return &core.WriteError{Path: path, Err: err}
-	}
-
-	if err := os.WriteFile(path, data, core.DefaultFileMode); err != nil {
-		return &core.WriteError{Path: path, Err: err}
-	}
-
-	return nil
+	return core.WriteOutputFile(path, data)
 }
 
 // mapGeminiModelToCanonical maps Gemini model names to canonical names.
@@ -0,0 +1,280 @@
+package gemini
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func init() {
+	core.Register(&VertexAdapter{})
+}
+
+// VertexAdapter converts between canonical Agent and a Vertex AI Gemini
+// agent: the model, system instruction and tools of a generateContent
+// request, with a display name and description. Tools become function
+// declarations the caller implements, except WebSearch, which becomes
+// Google Search grounding.
+type VertexAdapter struct{}
+
+// Name returns the adapter identifier, "vertex". "gemini" is the Gemini
+// CLI adapter's, so Vertex AI output is selected with -format=vertex.
+func (a *VertexAdapter) Name() string {
+	return "vertex"
+}
+
+// FileExtension returns the file extension for Vertex agents.
+func (a *VertexAdapter) FileExtension() string {
+	return ".json"
+}
+
+// DefaultDir returns the default directory name for Vertex agents.
+func (a *VertexAdapter) DefaultDir() string {
+	return "vertex"
+}
+
+// LossyFields returns the canonical fields Vertex agents do not hold.
+func (a *VertexAdapter) LossyFields() []string {
+	return []string{"ModelFallback", "MaxTurns", "Timeouts", "Retry", "AllowedTools", "Skills", "Dependencies", "Requires", "Arguments", "Triggers", "Version"}
+}
+
+// VertexAgent represents a Vertex AI Gemini agent in JSON format.
+type VertexAgent struct {
+	DisplayName       string       `json:"displayName"`
+	Description       string       `json:"description,omitempty"`
+	Model             string       `json:"model"`
+	SystemInstruction *Content     `json:"systemInstruction,omitempty"`
+	Tools             []VertexTool `json:"tools,omitempty"`
+}
+
+// Content is a Gemini content block, such as the system instruction.
+type Content struct {
+	Parts []Part `json:"parts"`
+}
+
+// Part is one part of a Content block.
+type Part struct {
+	Text string `json:"text"`
+}
+
+// VertexTool is a Gemini tool: a set of function declarations or a built-in
+// tool such as {"googleSearch": {}}.
+type VertexTool struct {
+	FunctionDeclarations []FunctionDeclaration `json:"functionDeclarations,omitempty"`
+	GoogleSearch         *struct{}             `json:"googleSearch,omitempty"`
+}
+
+// FunctionDeclaration describes a function the model may call.
+type FunctionDeclaration struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// ModelMapping maps canonical model aliases to Vertex AI Gemini model IDs.
+var ModelMapping = map[core.Model]string{
+	core.ModelHaiku:  "gemini-1.5-flash",
+	core.ModelSonnet: "gemini-1.5-pro",
+	core.ModelOpus:   "gemini-2.5-pro",
+}
+
+// stringParams returns an object schema of required string parameters.
+func stringParams(names ...string) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for _, name := range names {
+		properties[name] = map[string]interface{}{"type": "string"}
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   names,
+	}
+}
+
+// functionDeclarations maps canonical tools to the function declarations
+// they become, named after the Gemini CLI's built-in tools.
+var functionDeclarations = map[string]FunctionDeclaration{
+	"Read":     {Name: "read_file", Description: "Read a file and return its content.", Parameters: stringParams("path")},
+	"Write":    {Name: "write_file", Description: "Write content to a file.", Parameters: stringParams("path", "content")},
+	"Edit":     {Name: "replace", Description: "Replace text in a file.", Parameters: stringParams("path", "old_string", "new_string")},
+	"Bash":     {Name: "run_shell_command", Description: "Run a shell command and return its output.", Parameters: stringParams("command")},
+	"Glob":     {Name: "glob", Description: "List the files matching a glob pattern.", Parameters: stringParams("pattern")},
+	"Grep":     {Name: "search_file_content", Description: "Search file contents for a regular expression.", Parameters: stringParams("pattern")},
+	"WebFetch": {Name: "web_fetch", Description: "Fetch a URL and return its content.", Parameters: stringParams("url")},
+}
+
+// SupportedTools returns the canonical tools with a Gemini equivalent.
+func (a *VertexAdapter) SupportedTools() []string {
+	tools := []string{"WebSearch"}
+	for tool := range functionDeclarations {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+// DroppedTools returns the tools of agent without a Gemini equivalent,
+// which Marshal omits.
+func (a *VertexAdapter) DroppedTools(agent *core.Agent) []string {
+	var dropped []string
+	for _, tool := range agent.Tools {
+		if _, ok := functionDeclarations[tool]; !ok && tool != "WebSearch" {
+			dropped = append(dropped, tool)
+		}
+	}
+	return dropped
+}
+
+// Parse converts Vertex agent JSON bytes to canonical Agent.
+func (a *VertexAdapter) Parse(data []byte) (*core.Agent, error) {
+	var va VertexAgent
+	if err := json.Unmarshal(data, &va); err != nil {
+		return nil, &core.ParseError{Format: "vertex", Err: err}
+	}
+
+	var instructions []string
+	if va.SystemInstruction != nil {
+		for _, part := range va.SystemInstruction.Parts {
+			instructions = append(instructions, part.Text)
+		}
+	}
+
+	return &core.Agent{Spec: core.Spec{
+		Name:         va.DisplayName,
+		Description:  va.Description,
+		Model:        mapVertexModelToCanonical(va.Model),
+		Tools:        mapVertexToolsToCanonical(va.Tools),
+		Instructions: strings.Join(instructions, "\n\n"),
+	}}, nil
+}
+
+// Marshal converts canonical Agent to Vertex agent JSON bytes. Tools
+// without a Gemini equivalent are dropped (see DroppedTools).
+func (a *VertexAdapter) Marshal(agent *core.Agent) ([]byte, error) {
+	va := VertexAgent{
+		DisplayName: agent.Name,
+		Description: core.FormatDescription(a.Name(), agent.Description),
+		Model:       mapCanonicalModelToVertex(agent.PrimaryModel()),
+		Tools:       mapCanonicalToolsToVertex(agent.Tools),
+	}
+	if agent.Instructions != "" {
+		va.SystemInstruction = &Content{Parts: []Part{{Text: agent.Instructions}}}
+	}
+
+	data, err := json.MarshalIndent(va, "", "  ")
+	if err != nil {
+		return nil, &core.MarshalError{Format: "vertex", Err: err}
+	}
+	return append(data, '\n'), nil
+}
+
+// ReadFile reads a Vertex agent JSON file and returns canonical Agent.
+func (a *VertexAdapter) ReadFile(path string) (*core.Agent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &core.ReadError{Path: path, Err: err}
+	}
+
+	agent, err := a.Parse(data)
+	if err != nil {
+		if pe, ok := err.(*core.ParseError); ok {
+			pe.Path = path
+		}
+		return nil, err
+	}
+
+	// Infer name from filename if not set
+	if agent.Name == "" {
+		base := filepath.Base(path)
+		agent.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	return agent, nil
+}
+
+// WriteFile writes canonical Agent to a Vertex agent JSON file.
+func (a *VertexAdapter) WriteFile(agent *core.Agent, path string) error {
+	data, err := a.Marshal(agent)
+	if err != nil {
+		return err
+	}
+
+	return core.WriteOutputFile(path, data)
+}
+
+// mapCanonicalToolsToVertex maps canonical tools to Gemini tools: one tool
+// holding the function declarations in order, then Google Search if the
+// agent has WebSearch.
+func mapCanonicalToolsToVertex(tools []string) []VertexTool {
+	var declarations []FunctionDeclaration
+	search := false
+	seen := make(map[string]bool)
+	for _, tool := range tools {
+		if seen[tool] {
+			continue
+		}
+		seen[tool] = true
+		if tool == "WebSearch" {
+			search = true
+		} else if fd, ok := functionDeclarations[tool]; ok {
+			declarations = append(declarations, fd)
+		}
+	}
+
+	var result []VertexTool
+	if len(declarations) > 0 {
+		result = append(result, VertexTool{FunctionDeclarations: declarations})
+	}
+	if search {
+		result = append(result, VertexTool{GoogleSearch: &struct{}{}})
+	}
+	return result
+}
+
+// mapVertexToolsToCanonical maps Gemini tools back to canonical tools.
+// Unknown function declarations have no canonical equivalent.
+func mapVertexToolsToCanonical(tools []VertexTool) []string {
+	byName := make(map[string]string)
+	for canonical, fd := range functionDeclarations {
+		byName[fd.Name] = canonical
+	}
+
+	var result []string
+	for _, tool := range tools {
+		for _, fd := range tool.FunctionDeclarations {
+			if canonical, ok := byName[fd.Name]; ok {
+				result = append(result, canonical)
+			}
+		}
+		if tool.GoogleSearch != nil {
+			result = append(result, "WebSearch")
+		}
+	}
+	return result
+}
+
+// mapCanonicalModelToVertex maps canonical model names to Vertex model IDs,
+// defaulting to the sonnet mapping.
+func mapCanonicalModelToVertex(model core.Model) string {
+	if model == "" {
+		model = core.ModelSonnet
+	}
+	if id, ok := ModelMapping[model]; ok {
+		return id
+	}
+	return string(model)
+}
+
+// mapVertexModelToCanonical maps Vertex model IDs to canonical model names.
+func mapVertexModelToCanonical(id string) core.Model {
+	for model, mapped := range ModelMapping {
+		if mapped == id {
+			return model
+		}
+	}
+	return core.Model(id)
+}
@@ -0,0 +1,104 @@
+package gemini
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestVertexMarshal(t *testing.T) {
+	agent := core.NewAgent("researcher", "Researches topics").
+		WithModel(core.ModelOpus).
+		WithTools("Read", "WebSearch", "Task", "Bash").
+		WithInstructions("Cite your sources.")
+
+	data, err := (&VertexAdapter{}).Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var va VertexAgent
+	if err := json.Unmarshal(data, &va); err != nil {
+		t.Fatal(err)
+	}
+	if va.DisplayName != "researcher" || va.Model != "gemini-2.5-pro" {
+		t.Errorf("Marshal() = %+v", va)
+	}
+	if va.SystemInstruction == nil || len(va.SystemInstruction.Parts) != 1 || va.SystemInstruction.Parts[0].Text != "Cite your sources." {
+		t.Errorf("systemInstruction = %+v", va.SystemInstruction)
+	}
+	if len(va.Tools) != 2 || va.Tools[1].GoogleSearch == nil {
+		t.Fatalf("tools = %s, want function declarations then googleSearch", data)
+	}
+	var names []string
+	for _, fd := range va.Tools[0].FunctionDeclarations {
+		names = append(names, fd.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"read_file", "run_shell_command"}) {
+		t.Errorf("function declarations = %v", names)
+	}
+}
+
+func TestVertexRoundTrip(t *testing.T) {
+	adapter := &VertexAdapter{}
+	agent := core.NewAgent("reviewer", "Reviews code").
+		WithModel(core.ModelHaiku).
+		WithTools("Read", "Grep", "Edit", "WebSearch").
+		WithInstructions("Be thorough.")
+
+	data, err := adapter.Marshal(agent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := adapter.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if parsed.Name != "reviewer" || parsed.Model != core.ModelHaiku || parsed.Instructions != "Be thorough." {
+		t.Errorf("Parse() = %+v", parsed.Spec)
+	}
+	if !reflect.DeepEqual(parsed.Tools, agent.Tools) {
+		t.Errorf("Tools = %v, want %v", parsed.Tools, agent.Tools)
+	}
+}
+
+func TestVertexModelMapping(t *testing.T) {
+	tests := []struct {
+		model core.Model
+		want  string
+	}{
+		{core.ModelHaiku, "gemini-1.5-flash"},
+		{core.ModelSonnet, "gemini-1.5-pro"},
+		{core.ModelOpus, "gemini-2.5-pro"},
+		{"", "gemini-1.5-pro"},
+		{"gemini-2.0-flash-001", "gemini-2.0-flash-001"},
+	}
+	for _, tt := range tests {
+		if got := mapCanonicalModelToVertex(tt.model); got != tt.want {
+			t.Errorf("mapCanonicalModelToVertex(%q) = %q, want %q", tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestVertexDroppedTools(t *testing.T) {
+	agent := core.NewAgent("planner", "Plans work").WithTools("Read", "Task", "WebSearch", "mcp__jira")
+
+	got := core.DroppedTools(&VertexAdapter{}, agent)
+	if !reflect.DeepEqual(got, []string{"Task", "mcp__jira"}) {
+		t.Errorf("DroppedTools() = %v, want [Task mcp__jira]", got)
+	}
+}
+
+func TestVertexRegistered(t *testing.T) {
+	adapter, ok := core.GetAdapter("vertex")
+	if !ok {
+		t.Fatal("vertex adapter is not registered")
+	}
+	if _, ok := adapter.(*VertexAdapter); !ok {
+		t.Errorf("GetAdapter(vertex) = %T", adapter)
+	}
+	if _, ok := core.GetAdapter("gemini"); !ok {
+		t.Error("gemini adapter is no longer registered")
+	}
+}
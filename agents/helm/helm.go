@@ -0,0 +1,301 @@
+// Package helm generates Helm charts that run canonical agents on
+// Kubernetes (EKS, AKS, GKE or any other cluster). Each agent gets a
+// Deployment, a Service and a ConfigMap holding its instructions, all
+// driven by the agents section of values.yaml.
+package helm
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// HelmConfig holds the chart settings taken from a deployment target.
+type HelmConfig struct {
+	// ImageRepository is the agent runtime image (e.g.,
+	// "ghcr.io/org/agent-runtime"). Required.
+	ImageRepository string `json:"image_repository"`
+
+	// ImageTag is the image tag and the chart's appVersion. Default
+	// "latest".
+	ImageTag string `json:"image_tag,omitempty"`
+
+	// Replicas is the replica count of each agent's Deployment. Default 1.
+	Replicas int `json:"replicas,omitempty"`
+
+	// Port is the container and Service port. Default 8080.
+	Port int `json:"port,omitempty"`
+}
+
+// Chart defaults applied to unset HelmConfig fields.
+const (
+	DefaultImageTag = "latest"
+	DefaultReplicas = 1
+	DefaultPort     = 8080
+
+	// ChartVersion is the version written to Chart.yaml.
+	ChartVersion = "0.1.0"
+)
+
+// dnsLabel matches Kubernetes resource names (RFC 1123 labels).
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// chartFile is Chart.yaml.
+type chartFile struct {
+	APIVersion  string `yaml:"apiVersion"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Type        string `yaml:"type"`
+	Version     string `yaml:"version"`
+	AppVersion  string `yaml:"appVersion"`
+}
+
+// valuesFile is values.yaml.
+type valuesFile struct {
+	Image        imageValues           `yaml:"image"`
+	ReplicaCount int                   `yaml:"replicaCount"`
+	Service      serviceValues         `yaml:"service"`
+	Agents       map[string]agentValue `yaml:"agents"`
+}
+
+type imageValues struct {
+	Repository string `yaml:"repository"`
+	Tag        string `yaml:"tag"`
+	PullPolicy string `yaml:"pullPolicy"`
+}
+
+type serviceValues struct {
+	Type string `yaml:"type"`
+	Port int    `yaml:"port"`
+}
+
+// agentValue is an agent's entry in values.yaml. Setting enabled to false
+// skips the agent's resources.
+type agentValue struct {
+	Enabled      bool     `yaml:"enabled"`
+	Description  string   `yaml:"description"`
+	Model        string   `yaml:"model,omitempty"`
+	Tools        []string `yaml:"tools,omitempty"`
+	Instructions string   `yaml:"instructions"`
+}
+
+// WriteHelmChart writes a Helm chart for agents to outputDir: Chart.yaml,
+// values.yaml with one entry per agent, and templates rendering a
+// Deployment, Service and ConfigMap for each enabled agent. The chart is
+// named after teamName.
+func WriteHelmChart(teamName string, agents []*core.Agent, outputDir string, cfg *HelmConfig) error {
+	if cfg == nil || cfg.ImageRepository == "" {
+		return &core.MarshalError{Format: "helm", Err: fmt.Errorf("an image repository is required")}
+	}
+	if cfg.Replicas < 0 || cfg.Port < 0 {
+		return &core.MarshalError{Format: "helm", Err: fmt.Errorf("replicas and port must not be negative")}
+	}
+
+	chartName := chartName(teamName)
+	if !dnsLabel.MatchString(chartName) {
+		return &core.MarshalError{Format: "helm", Err: fmt.Errorf("team name %q does not yield a valid chart name", teamName)}
+	}
+
+	values := valuesFile{
+		Image:        imageValues{Repository: cfg.ImageRepository, Tag: withDefault(cfg.ImageTag, DefaultImageTag), PullPolicy: "IfNotPresent"},
+		ReplicaCount: cfg.Replicas,
+		Service:      serviceValues{Type: "ClusterIP", Port: cfg.Port},
+		Agents:       make(map[string]agentValue, len(agents)),
+	}
+	if values.ReplicaCount == 0 {
+		values.ReplicaCount = DefaultReplicas
+	}
+	if values.Service.Port == 0 {
+		values.Service.Port = DefaultPort
+	}
+
+	for _, agent := range agents {
+		if !dnsLabel.MatchString(agent.Name) || len(agent.Name) > 40 {
+			return &core.MarshalError{Format: "helm", Err: fmt.Errorf("agent name %q must be a lowercase DNS label of at most 40 characters", agent.Name)}
+		}
+		if _, dup := values.Agents[agent.Name]; dup {
+			return &core.MarshalError{Format: "helm", Err: fmt.Errorf("duplicate agent name %q", agent.Name)}
+		}
+		values.Agents[agent.Name] = agentValue{
+			Enabled:      true,
+			Description:  agent.Description,
+			Model:        string(agent.PrimaryModel()),
+			Tools:        agent.Tools,
+			Instructions: agent.Instructions,
+		}
+	}
+
+	chart := chartFile{
+		APIVersion:  "v2",
+		Name:        chartName,
+		Description: fmt.Sprintf("Agents of team %s", teamName),
+		Type:        "application",
+		Version:     ChartVersion,
+		AppVersion:  values.Image.Tag,
+	}
+
+	files := map[string]interface{}{
+		"Chart.yaml":  chart,
+		"values.yaml": values,
+	}
+	for name, content := range files {
+		data, err := yaml.Marshal(content)
+		if err != nil {
+			return &core.MarshalError{Format: "helm", Err: err}
+		}
+		if err := core.WriteOutputFile(filepath.Join(outputDir, name), data); err != nil {
+			return err
+		}
+	}
+
+	for name, content := range chartTemplates {
+		if err := core.WriteOutputFile(filepath.Join(outputDir, "templates", name), []byte(content)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chartName converts a team name to a chart name (e.g., "Stats Team" ->
+// "stats-team").
+func chartName(teamName string) string {
+	return strings.Trim(strings.Join(strings.FieldsFunc(strings.ToLower(teamName), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}), "-"), "-")
+}
+
+func withDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// chartTemplates are the files written to templates/. They range over
+// .Values.agents, so editing values.yaml is enough to tune or disable an
+// agent without regenerating the chart.
+var chartTemplates = map[string]string{
+	"_helpers.tpl": `{{/*
+Resource name of an agent: <release>-<agent>, truncated to 63 characters.
+Called with (list $ $name).
+*/}}
+{{- define "agents.name" -}}
+{{- $root := index . 0 -}}
+{{- printf "%s-%s" $root.Release.Name (index . 1) | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+
+{{/*
+Labels shared by an agent's resources. Called with (list $ $name).
+*/}}
+{{- define "agents.labels" -}}
+{{- $root := index . 0 -}}
+app.kubernetes.io/name: {{ index . 1 }}
+app.kubernetes.io/instance: {{ $root.Release.Name }}
+app.kubernetes.io/part-of: {{ $root.Chart.Name }}
+app.kubernetes.io/managed-by: {{ $root.Release.Service }}
+helm.sh/chart: {{ printf "%s-%s" $root.Chart.Name $root.Chart.Version }}
+{{- end -}}
+
+{{/*
+Selector labels of an agent. Called with (list $ $name).
+*/}}
+{{- define "agents.selectorLabels" -}}
+{{- $root := index . 0 -}}
+app.kubernetes.io/name: {{ index . 1 }}
+app.kubernetes.io/instance: {{ $root.Release.Name }}
+{{- end -}}
+`,
+
+	"configmap.yaml": `{{- range $name, $agent := .Values.agents }}
+{{- if $agent.enabled }}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ include "agents.name" (list $ $name) }}
+  labels:
+    {{- include "agents.labels" (list $ $name) | nindent 4 }}
+data:
+  instructions.md: |
+    {{- $agent.instructions | nindent 4 }}
+{{- end }}
+{{- end }}
+`,
+
+	"deployment.yaml": `{{- range $name, $agent := .Values.agents }}
+{{- if $agent.enabled }}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "agents.name" (list $ $name) }}
+  labels:
+    {{- include "agents.labels" (list $ $name) | nindent 4 }}
+spec:
+  replicas: {{ $agent.replicaCount | default $.Values.replicaCount }}
+  selector:
+    matchLabels:
+      {{- include "agents.selectorLabels" (list $ $name) | nindent 6 }}
+  template:
+    metadata:
+      labels:
+        {{- include "agents.selectorLabels" (list $ $name) | nindent 8 }}
+      annotations:
+        checksum/instructions: {{ $agent.instructions | sha256sum }}
+    spec:
+      containers:
+        - name: agent
+          image: "{{ $.Values.image.repository }}:{{ $.Values.image.tag }}"
+          imagePullPolicy: {{ $.Values.image.pullPolicy }}
+          ports:
+            - name: http
+              containerPort: {{ $.Values.service.port }}
+          env:
+            - name: AGENT_NAME
+              value: {{ $name | quote }}
+            - name: AGENT_DESCRIPTION
+              value: {{ $agent.description | quote }}
+            - name: AGENT_MODEL
+              value: {{ $agent.model | default "" | quote }}
+            - name: AGENT_TOOLS
+              value: {{ join "," ($agent.tools | default list) | quote }}
+            - name: AGENT_INSTRUCTIONS_FILE
+              value: /etc/agent/instructions.md
+          volumeMounts:
+            - name: instructions
+              mountPath: /etc/agent
+              readOnly: true
+      volumes:
+        - name: instructions
+          configMap:
+            name: {{ include "agents.name" (list $ $name) }}
+{{- end }}
+{{- end }}
+`,
+
+	"service.yaml": `{{- range $name, $agent := .Values.agents }}
+{{- if $agent.enabled }}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ include "agents.name" (list $ $name) }}
+  labels:
+    {{- include "agents.labels" (list $ $name) | nindent 4 }}
+spec:
+  type: {{ $.Values.service.type }}
+  ports:
+    - name: http
+      port: {{ $.Values.service.port }}
+      targetPort: http
+  selector:
+    {{- include "agents.selectorLabels" (list $ $name) | nindent 4 }}
+{{- end }}
+{{- end }}
+`,
+}
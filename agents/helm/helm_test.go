@@ -0,0 +1,85 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestWriteHelmChart(t *testing.T) {
+	dir := t.TempDir()
+	agents := []*core.Agent{
+		core.NewAgent("researcher", "Finds sources").WithModel(core.ModelSonnet).WithTools("WebSearch").WithInstructions("Research.\n\nCite sources."),
+		core.NewAgent("writer", "Writes: reports").WithInstructions("Write."),
+	}
+	cfg := &HelmConfig{ImageRepository: "ghcr.io/example/runtime", ImageTag: "1.2.3", Replicas: 2}
+	if err := WriteHelmChart("Stats Team", agents, dir, cfg); err != nil {
+		t.Fatalf("WriteHelmChart() error = %v", err)
+	}
+
+	var chart chartFile
+	readYAML(t, filepath.Join(dir, "Chart.yaml"), &chart)
+	if chart.Name != "stats-team" || chart.APIVersion != "v2" || chart.AppVersion != "1.2.3" {
+		t.Errorf("Chart.yaml = %+v", chart)
+	}
+
+	var values valuesFile
+	readYAML(t, filepath.Join(dir, "values.yaml"), &values)
+	if values.ReplicaCount != 2 || values.Service.Port != DefaultPort || values.Image.Repository != cfg.ImageRepository {
+		t.Errorf("values.yaml = %+v", values)
+	}
+	researcher := values.Agents["researcher"]
+	if !researcher.Enabled || researcher.Model != "sonnet" || researcher.Instructions != "Research.\n\nCite sources." {
+		t.Errorf("researcher values = %+v", researcher)
+	}
+	if values.Agents["writer"].Description != "Writes: reports" {
+		t.Errorf("writer values = %+v", values.Agents["writer"])
+	}
+
+	for _, name := range []string{"_helpers.tpl", "configmap.yaml", "deployment.yaml", "service.yaml"} {
+		if _, err := os.Stat(filepath.Join(dir, "templates", name)); err != nil {
+			t.Errorf("templates/%s: %v", name, err)
+		}
+	}
+}
+
+func TestWriteHelmChartErrors(t *testing.T) {
+	valid := &HelmConfig{ImageRepository: "runtime"}
+	tests := []struct {
+		name   string
+		team   string
+		agent  string
+		config *HelmConfig
+	}{
+		{"no config", "team", "agent", nil},
+		{"no image", "team", "agent", &HelmConfig{}},
+		{"negative replicas", "team", "agent", &HelmConfig{ImageRepository: "runtime", Replicas: -1}},
+		{"uppercase agent", "team", "Agent", valid},
+		{"agent with underscore", "team", "my_agent", valid},
+		{"empty team", "!!!", "agent", valid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agents := []*core.Agent{core.NewAgent(tt.agent, "Agent")}
+			if err := WriteHelmChart(tt.team, agents, t.TempDir(), tt.config); err == nil {
+				t.Error("WriteHelmChart() should fail")
+			}
+		})
+	}
+}
+
+func readYAML(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := yaml.Unmarshal(data, v); err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+}
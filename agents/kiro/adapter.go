@@ -2,11 +2,15 @@ package kiro
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/agentplexus/assistantkit/agents/core"
+	mcpcore "github.com/agentplexus/assistantkit/mcp/core"
 )
 
 const (
@@ -18,8 +22,34 @@ const (
 
 	// ProjectConfigDir is the project config directory.
 	ProjectConfigDir = ".kiro"
+
+	// GlobalDir is the output subdirectory for global-scope agents, which
+	// install to ~/.kiro/agents rather than the workspace.
+	GlobalDir = "global"
+)
+
+// Kiro agent hook events.
+const (
+	HookAgentSpawn       = "agentSpawn"
+	HookUserPromptSubmit = "userPromptSubmit"
+	HookPreToolUse       = "preToolUse"
+	HookPostToolUse      = "postToolUse"
+	HookStop             = "stop"
 )
 
+// HookEvents lists the Kiro agent hook events in lifecycle order. Each
+// canonical trigger event has one (see triggerEventToHook).
+var HookEvents = []string{HookAgentSpawn, HookUserPromptSubmit, HookPreToolUse, HookPostToolUse, HookStop}
+
+// triggerEventToHook maps canonical trigger events to Kiro hook events.
+var triggerEventToHook = map[string]string{
+	core.TriggerSessionStart: HookAgentSpawn,
+	core.TriggerBeforePrompt: HookUserPromptSubmit,
+	core.TriggerBeforeTool:   HookPreToolUse,
+	core.TriggerAfterTool:    HookPostToolUse,
+	core.TriggerStop:         HookStop,
+}
+
 func init() {
 	core.Register(&Adapter{})
 }
@@ -42,12 +72,23 @@ func (a *Adapter) DefaultDir() string {
 	return AgentsDir
 }
 
-// Parse converts Kiro agent JSON bytes to canonical Agent.
+// LossyFields returns the canonical fields Kiro agent configs do not hold.
+func (a *Adapter) LossyFields() []string {
+	return []string{"ModelFallback", "MaxTurns", "Timeouts", "Retry", "Skills", "Dependencies", "Requires", "Arguments", "Version"}
+}
+
+// Parse converts Kiro agent JSON bytes to canonical Agent. Hooks on events
+// other than HookEvents are an error.
 func (a *Adapter) Parse(data []byte) (*core.Agent, error) {
 	var kiroCfg AgentConfig
 	if err := json.Unmarshal(data, &kiroCfg); err != nil {
 		return nil, &core.ParseError{Format: AdapterName, Err: err}
 	}
+	for event := range kiroCfg.Hooks {
+		if !slices.Contains(HookEvents, event) {
+			return nil, &core.ParseError{Format: AdapterName, Err: fmt.Errorf("unknown hook event %q (supported: %s)", event, strings.Join(HookEvents, ", "))}
+		}
+	}
 
 	return a.ToCore(&kiroCfg), nil
 }
@@ -58,7 +99,18 @@ func (a *Adapter) Marshal(agent *core.Agent) ([]byte, error) {
 	return json.MarshalIndent(kiroCfg, "", "  ")
 }
 
+// AgentPath places global-scope agents in the GlobalDir subdirectory and
+// workspace agents directly in the output directory.
+func (a *Adapter) AgentPath(agent *core.Agent) string {
+	name := agent.Name + a.FileExtension()
+	if agent.IsGlobal() {
+		return GlobalDir + "/" + name
+	}
+	return name
+}
+
 // ReadFile reads a Kiro agent JSON file and returns canonical Agent.
+// Files in a GlobalDir directory are read as global-scope agents.
 func (a *Adapter) ReadFile(path string) (*core.Agent, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -79,6 +131,10 @@ func (a *Adapter) ReadFile(path string) (*core.Agent, error) {
 		agent.Name = strings.TrimSuffix(base, filepath.Ext(base))
 	}
 
+	if filepath.Base(filepath.Dir(path)) == GlobalDir {
+		agent.Scope = core.ScopeGlobal
+	}
+
 	return agent, nil
 }
 
@@ -89,25 +145,16 @@ func (a *Adapter) WriteFile(agent *core.Agent, path string) error {
 		return err
 	}
 
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, core.DefaultDirMode); err != nil {
-		return &core.WriteError{Path: path, Err: err}
-	}
-
-	if err := os.WriteFile(path, data, core.DefaultFileMode); err != nil {
-		return &core.WriteError{Path: path, Err: err}
-	}
-
-	return nil
+	return core.WriteOutputFile(path, data)
 }
 
 // ToCore converts Kiro agent config to canonical Agent.
 func (a *Adapter) ToCore(kiroCfg *AgentConfig) *core.Agent {
-	agent := &core.Agent{
+	agent := &core.Agent{Spec: core.Spec{
 		Name:         kiroCfg.Name,
 		Description:  kiroCfg.Description,
 		Instructions: kiroCfg.Prompt,
-	}
+	}}
 
 	// Map Kiro model names to canonical model names
 	if kiroCfg.Model != "" {
@@ -124,6 +171,31 @@ func (a *Adapter) ToCore(kiroCfg *AgentConfig) *core.Agent {
 		agent.AllowedTools = mapKiroToolsToCanonical(kiroCfg.AllowedTools)
 	}
 
+	// Map agent-scoped MCP servers
+	for name, server := range kiroCfg.MCPServers {
+		if agent.MCPServers == nil {
+			agent.MCPServers = make(map[string]mcpcore.Server, len(kiroCfg.MCPServers))
+		}
+		agent.MCPServers[name] = mcpcore.Server{
+			Command: server.Command,
+			Args:    server.Args,
+			Env:     server.Env,
+			URL:     server.URL,
+			Headers: server.Headers,
+		}
+	}
+
+	// Map hooks to triggers, grouped by event in lifecycle order
+	for _, event := range HookEvents {
+		for _, hook := range kiroCfg.Hooks[event] {
+			agent.Triggers = append(agent.Triggers, core.Trigger{
+				Event:   hookToTriggerEvent(event),
+				Pattern: mapKiroMatcherToCanonical(hook.Matcher),
+				Action:  hook.Command,
+			})
+		}
+	}
+
 	// Store resources as skills (closest mapping)
 	// Note: Resources in Kiro load context files, similar to skill dependencies
 
@@ -134,13 +206,13 @@ func (a *Adapter) ToCore(kiroCfg *AgentConfig) *core.Agent {
 func (a *Adapter) FromCore(agent *core.Agent) *AgentConfig {
 	kiroCfg := &AgentConfig{
 		Name:        agent.Name,
-		Description: agent.Description,
+		Description: core.FormatDescription(a.Name(), agent.Description),
 		Prompt:      agent.Instructions,
 	}
 
 	// Map canonical model to Kiro model name
-	if agent.Model != "" {
-		kiroCfg.Model = mapCanonicalModelToKiro(agent.Model)
+	if model := agent.PrimaryModel(); model != "" {
+		kiroCfg.Model = mapCanonicalModelToKiro(model)
 	}
 
 	// Map canonical tools to Kiro tools
@@ -158,9 +230,69 @@ func (a *Adapter) FromCore(agent *core.Agent) *AgentConfig {
 		kiroCfg.Resources = mapSkillsToResources(agent.Skills)
 	}
 
+	// Map agent-scoped MCP servers
+	for name, server := range agent.MCPServers {
+		if kiroCfg.MCPServers == nil {
+			kiroCfg.MCPServers = make(map[string]MCPServerConfig, len(agent.MCPServers))
+		}
+		kiroCfg.MCPServers[name] = MCPServerConfig{
+			Command: server.Command,
+			Args:    server.Args,
+			Env:     server.Env,
+			URL:     server.URL,
+			Headers: server.Headers,
+		}
+	}
+
+	// Map triggers to hooks; Validate rejects unknown trigger events
+	for _, trigger := range agent.Triggers {
+		event, ok := triggerEventToHook[trigger.Event]
+		if !ok {
+			continue
+		}
+		if kiroCfg.Hooks == nil {
+			kiroCfg.Hooks = make(map[string][]HookConfig)
+		}
+		kiroCfg.Hooks[event] = append(kiroCfg.Hooks[event], HookConfig{
+			Matcher: mapCanonicalMatcherToKiro(trigger.Pattern),
+			Command: trigger.Action,
+		})
+	}
+
 	return kiroCfg
 }
 
+// hookToTriggerEvent maps a Kiro hook event to its canonical trigger event.
+func hookToTriggerEvent(event string) string {
+	for trigger, hook := range triggerEventToHook {
+		if hook == event {
+			return trigger
+		}
+	}
+	return event
+}
+
+// mapCanonicalMatcherToKiro maps a trigger pattern naming a canonical tool
+// to the Kiro tool name. Other patterns, such as "*", are kept.
+func mapCanonicalMatcherToKiro(pattern string) string {
+	if mapped, ok := canonicalToKiroTools[pattern]; ok {
+		return mapped
+	}
+	return pattern
+}
+
+// mapKiroMatcherToCanonical maps a hook matcher naming a Kiro tool back to
+// the canonical tool name. Other matchers, such as "*", are kept.
+func mapKiroMatcherToCanonical(matcher string) string {
+	for canonical, kiroTool := range canonicalToKiroTools {
+		// Edit also maps to fs_write; Write is its canonical name
+		if kiroTool == matcher && canonical != "Edit" {
+			return canonical
+		}
+	}
+	return matcher
+}
+
 // mapKiroModelToCanonical maps Kiro model names to canonical names.
 func mapKiroModelToCanonical(kiroModel string) core.Model {
 	switch kiroModel {
@@ -228,36 +360,47 @@ func mapKiroToolsToCanonical(kiroTools []string) []string {
 	return canonical
 }
 
-// mapCanonicalToolsToKiro maps canonical tool names to Kiro names.
-func mapCanonicalToolsToKiro(tools []string) []string {
-	toolMap := map[string]string{
-		// Core tools
-		"Bash":      "execute_bash",
-		"Read":      "fs_read",
-		"Write":     "fs_write",
-		"Edit":      "fs_write", // Edit maps to fs_write in Kiro
-		"Grep":      "grep",
-		"Glob":      "glob",
-		"WebSearch": "web_search",
-		"WebFetch":  "web_fetch",
-		// Advanced tools
-		"Code":        "code",
-		"AWS":         "use_aws",
-		"Task":        "use_subagent",
-		"Introspect":  "introspect",
-		"ReportIssue": "report_issue",
-		// Experimental tools
-		"Knowledge": "knowledge",
-		"Thinking":  "thinking",
-		"TodoList":  "todo_list",
-		"Delegate":  "delegate",
+// canonicalToKiroTools maps canonical tool names to Kiro tool names.
+var canonicalToKiroTools = map[string]string{
+	// Core tools
+	"Bash":      "execute_bash",
+	"Read":      "fs_read",
+	"Write":     "fs_write",
+	"Edit":      "fs_write", // Edit maps to fs_write in Kiro
+	"Grep":      "grep",
+	"Glob":      "glob",
+	"WebSearch": "web_search",
+	"WebFetch":  "web_fetch",
+	// Advanced tools
+	"Code":        "code",
+	"AWS":         "use_aws",
+	"Task":        "use_subagent",
+	"Introspect":  "introspect",
+	"ReportIssue": "report_issue",
+	// Experimental tools
+	"Knowledge": "knowledge",
+	"Thinking":  "thinking",
+	"TodoList":  "todo_list",
+	"Delegate":  "delegate",
+}
+
+// SupportedTools returns the canonical tools with a Kiro mapping.
+func (a *Adapter) SupportedTools() []string {
+	tools := make([]string, 0, len(canonicalToKiroTools))
+	for tool := range canonicalToKiroTools {
+		tools = append(tools, tool)
 	}
+	sort.Strings(tools)
+	return tools
+}
 
+// mapCanonicalToolsToKiro maps canonical tool names to Kiro names.
+func mapCanonicalToolsToKiro(tools []string) []string {
 	seen := make(map[string]bool)
 	var kiroTools []string
 	for _, tool := range tools {
 		var kiroTool string
-		if mapped, ok := toolMap[tool]; ok {
+		if mapped, ok := canonicalToKiroTools[tool]; ok {
 			kiroTool = mapped
 		} else {
 			// Lowercase with underscore for unknown tools
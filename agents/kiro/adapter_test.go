@@ -1,8 +1,11 @@
 package kiro
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -79,14 +82,14 @@ func TestAdapter_Parse(t *testing.T) {
 func TestAdapter_Marshal(t *testing.T) {
 	adapter := &Adapter{}
 
-	agent := &core.Agent{
+	agent := &core.Agent{Spec: core.Spec{
 		Name:         "test-agent",
 		Description:  "A test agent",
 		Model:        "sonnet",
 		Tools:        []string{"Read", "Write", "Bash", "Grep"},
 		Skills:       []string{"version-analysis"},
 		Instructions: "You are a helpful assistant.",
-	}
+	}}
 
 	data, err := adapter.Marshal(agent)
 	if err != nil {
@@ -132,13 +135,13 @@ func TestAdapter_Marshal(t *testing.T) {
 func TestAdapter_RoundTrip(t *testing.T) {
 	adapter := &Adapter{}
 
-	original := &core.Agent{
+	original := &core.Agent{Spec: core.Spec{
 		Name:         "round-trip-agent",
 		Description:  "Tests round-trip conversion",
 		Model:        "opus",
 		Tools:        []string{"Read", "Write"},
 		Instructions: "System instructions here.",
-	}
+	}}
 
 	// Marshal to Kiro format
 	data, err := adapter.Marshal(original)
@@ -177,13 +180,13 @@ func TestAdapter_WriteFile_ReadFile(t *testing.T) {
 	}
 	defer os.RemoveAll(tmpDir)
 
-	agent := &core.Agent{
+	agent := &core.Agent{Spec: core.Spec{
 		Name:         "file-test-agent",
 		Description:  "Tests file operations",
 		Model:        "haiku",
 		Tools:        []string{"Read", "Grep", "Glob"},
 		Instructions: "You help with file operations.",
-	}
+	}}
 
 	// Write to file
 	path := filepath.Join(tmpDir, "file-test-agent.json")
@@ -267,3 +270,89 @@ func TestReverseToolMapping(t *testing.T) {
 		}
 	}
 }
+
+func TestAdapter_Scope(t *testing.T) {
+	adapter := &Adapter{}
+	tmpDir := t.TempDir()
+
+	workspace := core.NewAgent("reviewer", "Reviews code")
+	global := core.NewAgent("helper", "Helps everywhere")
+	global.Scope = core.ScopeGlobal
+
+	if got := core.AgentPath(adapter, workspace); got != "reviewer.json" {
+		t.Errorf("AgentPath(workspace) = %q, want reviewer.json", got)
+	}
+	if got := core.AgentPath(adapter, global); got != filepath.Join(GlobalDir, "helper.json") {
+		t.Errorf("AgentPath(global) = %q", got)
+	}
+
+	if err := core.WriteAgentsToDir([]*core.Agent{workspace, global}, tmpDir, AdapterName); err != nil {
+		t.Fatalf("WriteAgentsToDir() error = %v", err)
+	}
+
+	readGlobal, err := adapter.ReadFile(filepath.Join(tmpDir, GlobalDir, "helper.json"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !readGlobal.IsGlobal() {
+		t.Errorf("Scope = %q, want global", readGlobal.Scope)
+	}
+
+	readWorkspace, err := adapter.ReadFile(filepath.Join(tmpDir, "reviewer.json"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if readWorkspace.Scope != "" {
+		t.Errorf("Scope = %q, want workspace default", readWorkspace.Scope)
+	}
+}
+
+func TestAdapter_Hooks(t *testing.T) {
+	adapter := &Adapter{}
+
+	agent := core.NewAgent("formatter", "Formats code")
+	agent.Triggers = []core.Trigger{
+		{Event: core.TriggerSessionStart, Action: "git status"},
+		{Event: core.TriggerAfterTool, Pattern: "Write", Action: "gofmt -w ."},
+		{Event: core.TriggerAfterTool, Pattern: "*", Action: "echo done"},
+	}
+
+	data, err := adapter.Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var cfg AgentConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	want := map[string][]HookConfig{
+		HookAgentSpawn:  {{Command: "git status"}},
+		HookPostToolUse: {{Matcher: "fs_write", Command: "gofmt -w ."}, {Matcher: "*", Command: "echo done"}},
+	}
+	if !reflect.DeepEqual(cfg.Hooks, want) {
+		t.Errorf("Hooks = %+v, want %+v", cfg.Hooks, want)
+	}
+
+	parsed, err := adapter.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !reflect.DeepEqual(parsed.Triggers, agent.Triggers) {
+		t.Errorf("Triggers = %+v, want %+v", parsed.Triggers, agent.Triggers)
+	}
+}
+
+func TestAdapter_ParseUnknownHookEvent(t *testing.T) {
+	adapter := &Adapter{}
+
+	_, err := adapter.Parse([]byte(`{"name": "formatter", "hooks": {"onSave": [{"command": "gofmt -w ."}]}}`))
+
+	var parseErr *core.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Parse() error = %v, want *core.ParseError", err)
+	}
+	if !strings.Contains(err.Error(), `unknown hook event "onSave"`) {
+		t.Errorf("Parse() error = %v", err)
+	}
+}
@@ -32,6 +32,19 @@ type AgentConfig struct {
 
 	// IncludeMcpJson determines whether to inherit servers from workspace/user config.
 	IncludeMcpJson bool `json:"includeMcpJson,omitempty"`
+
+	// Hooks maps hook events (see HookEvents) to the commands run on them.
+	Hooks map[string][]HookConfig `json:"hooks,omitempty"`
+}
+
+// HookConfig represents a command run on an agent hook event.
+type HookConfig struct {
+	// Matcher limits preToolUse and postToolUse hooks to matching tools,
+	// by Kiro tool name (e.g., "fs_write") or "*" for every tool.
+	Matcher string `json:"matcher,omitempty"`
+
+	// Command is the shell command to run.
+	Command string `json:"command"`
 }
 
 // MCPServerConfig represents an MCP server configuration within an agent.
@@ -0,0 +1,280 @@
+// Package openai provides the OpenAI Assistants API adapter.
+package openai
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func init() {
+	core.Register(&Adapter{})
+}
+
+// Adapter converts between canonical Agent and the OpenAI Assistants API
+// create payload (POST /v1/assistants). Parse also accepts assistant
+// objects returned by the API, ignoring fields such as id and created_at.
+type Adapter struct{}
+
+// Name returns the adapter identifier.
+func (a *Adapter) Name() string {
+	return "openai"
+}
+
+// FileExtension returns the file extension for assistant payloads.
+func (a *Adapter) FileExtension() string {
+	return ".json"
+}
+
+// DefaultDir returns the default directory name for assistant payloads.
+func (a *Adapter) DefaultDir() string {
+	return "assistants"
+}
+
+// LossyFields returns the canonical fields an assistant payload does not
+// hold. Tools collapse into OpenAI tool types, so several canonical tools
+// parse back as one.
+func (a *Adapter) LossyFields() []string {
+	return []string{"ModelFallback", "MaxTurns", "Timeouts", "Retry", "Tools", "AllowedTools", "Skills", "Dependencies", "Requires", "Arguments", "Triggers"}
+}
+
+// Assistant is the OpenAI Assistants API create payload.
+type Assistant struct {
+	Model        string `json:"model"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+	Instructions string `json:"instructions,omitempty"`
+	Tools        []Tool `json:"tools,omitempty"`
+
+	// Metadata holds the assistant's key/value metadata. The spec version
+	// is kept under core.VersionMetadataKey.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Tool is an assistant tool, such as {"type": "code_interpreter"}.
+type Tool struct {
+	Type     string    `json:"type"`
+	Function *Function `json:"function,omitempty"`
+}
+
+// Function describes a function tool. The assistant's caller implements
+// it and returns its output to the run.
+type Function struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters,omitempty"`
+}
+
+// Tool types.
+const (
+	ToolCodeInterpreter = "code_interpreter"
+	ToolFunction        = "function"
+)
+
+// codeInterpreterTools are the canonical tools the code interpreter covers.
+var codeInterpreterTools = map[string]bool{
+	"Bash":  true,
+	"Read":  true,
+	"Write": true,
+}
+
+// functionTools maps canonical tools the Assistants API has no built-in for
+// to placeholder functions the caller implements.
+var functionTools = map[string]Function{
+	"WebSearch": {
+		Name:        "web_search",
+		Description: "Search the web and return the most relevant results.",
+		Parameters: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"query": map[string]interface{}{"type": "string"}},
+			"required":   []string{"query"},
+		},
+	},
+	"WebFetch": {
+		Name:        "web_fetch",
+		Description: "Fetch a URL and return its content.",
+		Parameters: map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"url": map[string]interface{}{"type": "string"}},
+			"required":   []string{"url"},
+		},
+	},
+}
+
+// SupportedTools returns the canonical tools with an OpenAI mapping.
+func (a *Adapter) SupportedTools() []string {
+	tools := make([]string, 0, len(codeInterpreterTools)+len(functionTools))
+	for tool := range codeInterpreterTools {
+		tools = append(tools, tool)
+	}
+	for tool := range functionTools {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+// DroppedTools returns the tools of agent without an OpenAI mapping, which
+// Marshal omits.
+func (a *Adapter) DroppedTools(agent *core.Agent) []string {
+	var dropped []string
+	for _, tool := range agent.Tools {
+		if _, ok := functionTools[tool]; !ok && !codeInterpreterTools[tool] {
+			dropped = append(dropped, tool)
+		}
+	}
+	return dropped
+}
+
+// Parse converts assistant JSON bytes to canonical Agent.
+func (a *Adapter) Parse(data []byte) (*core.Agent, error) {
+	var asst Assistant
+	if err := json.Unmarshal(data, &asst); err != nil {
+		return nil, &core.ParseError{Format: "openai", Err: err}
+	}
+
+	return &core.Agent{
+		Spec: core.Spec{
+			Name:         asst.Name,
+			Description:  asst.Description,
+			Model:        mapOpenAIModelToCanonical(asst.Model),
+			Tools:        mapOpenAIToolsToCanonical(asst.Tools),
+			Instructions: asst.Instructions,
+		},
+		Version: asst.Metadata[core.VersionMetadataKey],
+	}, nil
+}
+
+// Marshal converts canonical Agent to assistant JSON bytes. Tools without
+// an OpenAI mapping are dropped (see DroppedTools).
+func (a *Adapter) Marshal(agent *core.Agent) ([]byte, error) {
+	asst := Assistant{
+		Model:        mapCanonicalModelToOpenAI(agent.PrimaryModel()),
+		Name:         agent.Name,
+		Description:  core.FormatDescription(a.Name(), agent.Description),
+		Instructions: agent.Instructions,
+		Tools:        mapCanonicalToolsToOpenAI(agent.Tools),
+	}
+	if agent.Version != "" {
+		asst.Metadata = map[string]string{core.VersionMetadataKey: agent.Version}
+	}
+
+	data, err := json.MarshalIndent(asst, "", "  ")
+	if err != nil {
+		return nil, &core.MarshalError{Format: "openai", Err: err}
+	}
+	return append(data, '\n'), nil
+}
+
+// ReadFile reads an assistant JSON file and returns canonical Agent.
+func (a *Adapter) ReadFile(path string) (*core.Agent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &core.ReadError{Path: path, Err: err}
+	}
+
+	agent, err := a.Parse(data)
+	if err != nil {
+		if pe, ok := err.(*core.ParseError); ok {
+			pe.Path = path
+		}
+		return nil, err
+	}
+
+	// Infer name from filename if not set
+	if agent.Name == "" {
+		base := filepath.Base(path)
+		agent.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	return agent, nil
+}
+
+// WriteFile writes canonical Agent to an assistant JSON file.
+func (a *Adapter) WriteFile(agent *core.Agent, path string) error {
+	data, err := a.Marshal(agent)
+	if err != nil {
+		return err
+	}
+
+	return core.WriteOutputFile(path, data)
+}
+
+// mapCanonicalToolsToOpenAI maps canonical tools to assistant tools: one
+// code interpreter for every file and shell tool, then one function per web
+// tool, in first-seen order.
+func mapCanonicalToolsToOpenAI(tools []string) []Tool {
+	var result []Tool
+	seen := make(map[string]bool)
+	for _, tool := range tools {
+		switch {
+		case codeInterpreterTools[tool]:
+			if !seen[ToolCodeInterpreter] {
+				seen[ToolCodeInterpreter] = true
+				result = append(result, Tool{Type: ToolCodeInterpreter})
+			}
+		default:
+			fn, ok := functionTools[tool]
+			if ok && !seen[fn.Name] {
+				seen[fn.Name] = true
+				result = append(result, Tool{Type: ToolFunction, Function: &fn})
+			}
+		}
+	}
+	return result
+}
+
+// mapOpenAIToolsToCanonical maps assistant tools back to canonical tools.
+// The code interpreter becomes Bash; other built-in tools (e.g.,
+// file_search) and unknown functions have no canonical equivalent.
+func mapOpenAIToolsToCanonical(tools []Tool) []string {
+	var result []string
+	for _, tool := range tools {
+		switch tool.Type {
+		case ToolCodeInterpreter:
+			result = append(result, "Bash")
+		case ToolFunction:
+			if tool.Function == nil {
+				continue
+			}
+			for canonical, fn := range functionTools {
+				if fn.Name == tool.Function.Name {
+					result = append(result, canonical)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// mapCanonicalModelToOpenAI maps canonical model names to OpenAI models.
+func mapCanonicalModelToOpenAI(model core.Model) string {
+	switch model {
+	case core.ModelHaiku:
+		return "gpt-4o-mini"
+	case core.ModelSonnet, "":
+		return "gpt-4o"
+	case core.ModelOpus:
+		return "gpt-4.1"
+	default:
+		return string(model)
+	}
+}
+
+// mapOpenAIModelToCanonical maps OpenAI models to canonical model names.
+func mapOpenAIModelToCanonical(model string) core.Model {
+	switch model {
+	case "gpt-4o-mini":
+		return core.ModelHaiku
+	case "gpt-4o":
+		return core.ModelSonnet
+	case "gpt-4.1":
+		return core.ModelOpus
+	default:
+		return core.Model(model)
+	}
+}
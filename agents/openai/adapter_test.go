@@ -0,0 +1,69 @@
+package openai
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestMarshal(t *testing.T) {
+	agent := core.NewAgent("researcher", "Researches topics").
+		WithModel(core.ModelHaiku).
+		WithTools("Read", "Bash", "Grep", "WebSearch", "Write").
+		WithInstructions("Cite your sources.")
+
+	data, err := (&Adapter{}).Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var asst Assistant
+	if err := json.Unmarshal(data, &asst); err != nil {
+		t.Fatal(err)
+	}
+	if asst.Model != "gpt-4o-mini" || asst.Name != "researcher" || asst.Instructions != "Cite your sources." {
+		t.Errorf("Marshal() = %+v", asst)
+	}
+	if len(asst.Tools) != 2 || asst.Tools[0].Type != ToolCodeInterpreter || asst.Tools[1].Function == nil || asst.Tools[1].Function.Name != "web_search" {
+		t.Errorf("tools = %s, want code_interpreter then the web_search function", data)
+	}
+}
+
+func TestParseAPIResponse(t *testing.T) {
+	response := `{
+		"id": "asst_abc123",
+		"object": "assistant",
+		"created_at": 1698984975,
+		"name": "researcher",
+		"description": "Researches topics",
+		"model": "gpt-4o",
+		"instructions": "Cite your sources.",
+		"tools": [
+			{"type": "code_interpreter"},
+			{"type": "file_search", "file_search": {"max_num_results": 20}},
+			{"type": "function", "function": {"name": "web_fetch", "strict": false}}
+		],
+		"tool_resources": {},
+		"metadata": {},
+		"temperature": 1.0,
+		"response_format": "auto"
+	}`
+
+	agent, err := (&Adapter{}).Parse([]byte(response))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if agent.Name != "researcher" || agent.Model != core.ModelSonnet || agent.Instructions != "Cite your sources." {
+		t.Errorf("Parse() = %+v", agent.Spec)
+	}
+	if !reflect.DeepEqual(agent.Tools, []string{"Bash", "WebFetch"}) {
+		t.Errorf("Tools = %v, want [Bash WebFetch]", agent.Tools)
+	}
+}
+
+func TestRegistered(t *testing.T) {
+	if _, ok := core.GetAdapter("openai"); !ok {
+		t.Error("openai adapter is not registered")
+	}
+}
@@ -0,0 +1,283 @@
+package opencode
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func init() {
+	core.Register(&Adapter{})
+}
+
+// Adapter converts between canonical Agent and OpenCode agent config.
+type Adapter struct{}
+
+// Name returns the adapter identifier.
+func (a *Adapter) Name() string {
+	return "opencode"
+}
+
+// FileExtension returns the file extension for OpenCode agent configs.
+func (a *Adapter) FileExtension() string {
+	return ".json"
+}
+
+// DefaultDir returns the default directory name for OpenCode agents.
+func (a *Adapter) DefaultDir() string {
+	return ".opencode"
+}
+
+// LossyFields returns the canonical fields an OpenCode config does not
+// hold. AllowedTools survive only for tools with an OpenCode permission.
+func (a *Adapter) LossyFields() []string {
+	return []string{"ModelFallback", "MaxTurns", "Timeouts", "Retry", "AllowedTools", "Skills", "Dependencies", "Requires", "Arguments", "Triggers", "Version"}
+}
+
+// Parse converts an OpenCode config holding exactly one agent to canonical Agent.
+func (a *Adapter) Parse(data []byte) (*core.Agent, error) {
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, &core.ParseError{Format: "opencode", Err: err}
+	}
+	if len(cfg.Agent) != 1 {
+		return nil, &core.ParseError{Format: "opencode", Err: fmt.Errorf("expected exactly one agent, found %d", len(cfg.Agent))}
+	}
+
+	for name, agentCfg := range cfg.Agent {
+		return a.ToCore(name, agentCfg), nil
+	}
+	return nil, nil
+}
+
+// Marshal converts canonical Agent to an OpenCode config with one agent.
+func (a *Adapter) Marshal(agent *core.Agent) ([]byte, error) {
+	return marshalConfig(&Config{
+		Schema: SchemaURL,
+		Agent:  map[string]*AgentConfig{agent.Name: a.FromCore(agent)},
+	})
+}
+
+// ReadFile reads an OpenCode agent config file and returns canonical Agent.
+func (a *Adapter) ReadFile(path string) (*core.Agent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &core.ReadError{Path: path, Err: err}
+	}
+
+	agent, err := a.Parse(data)
+	if err != nil {
+		if pe, ok := err.(*core.ParseError); ok {
+			pe.Path = path
+		}
+		return nil, err
+	}
+	return agent, nil
+}
+
+// WriteFile writes canonical Agent to an OpenCode agent config file.
+func (a *Adapter) WriteFile(agent *core.Agent, path string) error {
+	data, err := a.Marshal(agent)
+	if err != nil {
+		return err
+	}
+
+	return core.WriteOutputFile(path, data)
+}
+
+// GenerateConfig combines agents into the agent section of one opencode.json.
+func GenerateConfig(agents []*core.Agent) ([]byte, error) {
+	a := &Adapter{}
+	cfg := &Config{Schema: SchemaURL, Agent: make(map[string]*AgentConfig, len(agents))}
+	for _, agent := range agents {
+		cfg.Agent[agent.Name] = a.FromCore(agent)
+	}
+	return marshalConfig(cfg)
+}
+
+func marshalConfig(cfg *Config) ([]byte, error) {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return nil, &core.MarshalError{Format: "opencode", Err: err}
+	}
+	return append(data, '\n'), nil
+}
+
+// ToCore converts an OpenCode agent config to canonical Agent.
+func (a *Adapter) ToCore(name string, cfg *AgentConfig) *core.Agent {
+	agent := &core.Agent{Spec: core.Spec{
+		Name:         name,
+		Description:  cfg.Description,
+		Model:        mapOpenCodeModelToCanonical(cfg.Model),
+		Instructions: cfg.Prompt,
+	}}
+
+	for tool, enabled := range cfg.Tools {
+		if enabled {
+			agent.Tools = append(agent.Tools, mapOpenCodeToolToCanonical(tool))
+		}
+	}
+	sort.Strings(agent.Tools)
+
+	if cfg.Permission != nil {
+		for _, tool := range agent.Tools {
+			if permissionFor(cfg.Permission, tool) == PermissionAllow {
+				agent.AllowedTools = append(agent.AllowedTools, tool)
+			}
+		}
+	}
+
+	return agent
+}
+
+// FromCore converts canonical Agent to an OpenCode agent config.
+//
+// When the agent lists tools, every mappable tool is switched on or off
+// explicitly, since OpenCode enables unlisted tools. Allowed tools become
+// "allow" permissions and the agent's other tools in the same category "ask".
+func (a *Adapter) FromCore(agent *core.Agent) *AgentConfig {
+	cfg := &AgentConfig{
+		Description: core.FormatDescription(a.Name(), agent.Description),
+		Mode:        ModeSubagent,
+		Model:       mapCanonicalModelToOpenCode(agent.PrimaryModel()),
+		Prompt:      agent.Instructions,
+	}
+
+	if len(agent.Tools) > 0 {
+		cfg.Tools = make(map[string]bool, len(canonicalToOpenCodeTools))
+		for _, tool := range canonicalToOpenCodeTools {
+			cfg.Tools[tool] = false
+		}
+		for _, tool := range agent.Tools {
+			cfg.Tools[mapCanonicalToolToOpenCode(tool)] = true
+		}
+	}
+
+	if len(agent.AllowedTools) > 0 {
+		perm := &Permission{}
+		for _, tool := range agent.Tools {
+			level := PermissionAsk
+			if slices.Contains(agent.AllowedTools, tool) {
+				level = PermissionAllow
+			}
+			setPermission(perm, tool, level)
+		}
+		if *perm != (Permission{}) {
+			cfg.Permission = perm
+		}
+	}
+
+	return cfg
+}
+
+// canonicalToOpenCodeTools maps canonical tool names to OpenCode tool names.
+var canonicalToOpenCodeTools = map[string]string{
+	"Bash":     "bash",
+	"Edit":     "edit",
+	"Glob":     "glob",
+	"Grep":     "grep",
+	"Read":     "read",
+	"Task":     "task",
+	"WebFetch": "webfetch",
+	"Write":    "write",
+}
+
+// SupportedTools returns the canonical tools with an OpenCode mapping.
+func (a *Adapter) SupportedTools() []string {
+	tools := make([]string, 0, len(canonicalToOpenCodeTools))
+	for tool := range canonicalToOpenCodeTools {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+func mapCanonicalToolToOpenCode(tool string) string {
+	if mapped, ok := canonicalToOpenCodeTools[tool]; ok {
+		return mapped
+	}
+	return strings.ToLower(tool)
+}
+
+func mapOpenCodeToolToCanonical(tool string) string {
+	for canonical, mapped := range canonicalToOpenCodeTools {
+		if mapped == tool {
+			return canonical
+		}
+	}
+	return tool
+}
+
+// permissionFor returns the permission governing a canonical tool, or ""
+// if OpenCode has no permission for it.
+func permissionFor(perm *Permission, tool string) string {
+	switch tool {
+	case "Edit", "Write":
+		return perm.Edit
+	case "Bash":
+		return perm.Bash
+	case "WebFetch":
+		return perm.WebFetch
+	default:
+		return ""
+	}
+}
+
+// setPermission records level for a canonical tool's category. When Edit
+// and Write disagree, "allow" wins only if both are allowed.
+func setPermission(perm *Permission, tool, level string) {
+	var field *string
+	switch tool {
+	case "Edit", "Write":
+		field = &perm.Edit
+	case "Bash":
+		field = &perm.Bash
+	case "WebFetch":
+		field = &perm.WebFetch
+	default:
+		return
+	}
+	if *field == "" || level == PermissionAsk {
+		*field = level
+	}
+}
+
+// anthropicProvider prefixes Anthropic model IDs in OpenCode configs.
+const anthropicProvider = "anthropic/"
+
+// mapOpenCodeModelToCanonical maps OpenCode model IDs to canonical names:
+// registered Anthropic model IDs to their alias (see core.ModelAlias), and
+// other Claude models by tier.
+func mapOpenCodeModelToCanonical(model string) core.Model {
+	if id, ok := strings.CutPrefix(model, anthropicProvider); ok {
+		if alias := core.ModelAlias(id); alias != id {
+			return core.Model(alias)
+		}
+	}
+	lower := strings.ToLower(model)
+	switch {
+	case strings.Contains(lower, "haiku"):
+		return core.ModelHaiku
+	case strings.Contains(lower, "sonnet"):
+		return core.ModelSonnet
+	case strings.Contains(lower, "opus"):
+		return core.ModelOpus
+	default:
+		return core.Model(model)
+	}
+}
+
+// mapCanonicalModelToOpenCode maps canonical model names to OpenCode model
+// IDs: aliases and registered model IDs resolve through core.ResolveModel
+// and get the Anthropic provider prefix; other models pass through.
+func mapCanonicalModelToOpenCode(model core.Model) string {
+	if id, ok := core.ResolveModel(string(model)); ok {
+		return anthropicProvider + id
+	}
+	return string(model)
+}
@@ -0,0 +1,133 @@
+package opencode
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestAdapter_Marshal(t *testing.T) {
+	adapter := &Adapter{}
+	agent := core.NewAgent("reviewer", "Reviews code").
+		WithModel(core.ModelSonnet).
+		WithTools("Read", "Grep", "Bash", "Edit").
+		WithInstructions("Review the diff.")
+	agent.AllowedTools = []string{"Read", "Bash"}
+
+	data, err := adapter.Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if cfg.Schema != SchemaURL {
+		t.Errorf("$schema = %q, want %q", cfg.Schema, SchemaURL)
+	}
+
+	got := cfg.Agent["reviewer"]
+	if got == nil {
+		t.Fatalf("agent reviewer missing:\n%s", data)
+	}
+	if got.Mode != ModeSubagent || got.Prompt != "Review the diff." || got.Model != "anthropic/claude-3-5-sonnet-20241022" {
+		t.Errorf("agent = %+v", got)
+	}
+
+	wantTools := map[string]bool{
+		"bash": true, "edit": true, "grep": true, "read": true,
+		"glob": false, "task": false, "webfetch": false, "write": false,
+	}
+	if !reflect.DeepEqual(got.Tools, wantTools) {
+		t.Errorf("Tools = %v, want %v", got.Tools, wantTools)
+	}
+
+	wantPerm := &Permission{Bash: PermissionAllow, Edit: PermissionAsk}
+	if !reflect.DeepEqual(got.Permission, wantPerm) {
+		t.Errorf("Permission = %+v, want %+v", got.Permission, wantPerm)
+	}
+}
+
+func TestAdapter_MarshalNoTools(t *testing.T) {
+	adapter := &Adapter{}
+	cfg := adapter.FromCore(core.NewAgent("helper", "Helps"))
+	if cfg.Tools != nil || cfg.Permission != nil {
+		t.Errorf("agent without tools should keep OpenCode defaults, got tools %v, permission %+v", cfg.Tools, cfg.Permission)
+	}
+}
+
+func TestAdapter_RoundTrip(t *testing.T) {
+	adapter := &Adapter{}
+	agent := core.NewAgent("reviewer", "Reviews code").
+		WithModel(core.ModelOpus).
+		WithTools("Bash", "Read", "WebFetch", "Write").
+		WithInstructions("Review the diff.")
+	agent.AllowedTools = []string{"Read", "WebFetch"}
+
+	data, err := adapter.Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	parsed, err := adapter.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if parsed.Name != agent.Name || parsed.Description != agent.Description || parsed.Instructions != agent.Instructions {
+		t.Errorf("identity fields changed: %+v", parsed.Spec)
+	}
+	if parsed.Model != core.ModelOpus {
+		t.Errorf("Model = %q, want opus", parsed.Model)
+	}
+	if !reflect.DeepEqual(parsed.Tools, agent.Tools) {
+		t.Errorf("Tools = %v, want %v", parsed.Tools, agent.Tools)
+	}
+	if want := []string{"WebFetch"}; !reflect.DeepEqual(parsed.AllowedTools, want) {
+		// Read has no OpenCode permission, so only WebFetch survives
+		t.Errorf("AllowedTools = %v, want %v", parsed.AllowedTools, want)
+	}
+}
+
+func TestModelMapping(t *testing.T) {
+	for _, alias := range []core.Model{core.ModelHaiku, core.ModelSonnet, core.ModelOpus} {
+		id, _ := core.ResolveModel(string(alias))
+		got := mapCanonicalModelToOpenCode(alias)
+		if got != "anthropic/"+id {
+			t.Errorf("mapCanonicalModelToOpenCode(%s) = %q, want anthropic/%s", alias, got, id)
+		}
+		if back := mapOpenCodeModelToCanonical(got); back != alias {
+			t.Errorf("mapOpenCodeModelToCanonical(%q) = %q, want %s", got, back, alias)
+		}
+	}
+	if got := mapCanonicalModelToOpenCode("openai/gpt-4o"); got != "openai/gpt-4o" {
+		t.Errorf("unknown model = %q, want it passed through", got)
+	}
+}
+
+func TestAdapter_ParseRequiresOneAgent(t *testing.T) {
+	adapter := &Adapter{}
+	if _, err := adapter.Parse([]byte(`{"agent": {}}`)); err == nil {
+		t.Error("expected error for config without agents")
+	}
+}
+
+func TestGenerateConfig(t *testing.T) {
+	data, err := GenerateConfig([]*core.Agent{
+		core.NewAgent("reviewer", "Reviews code"),
+		core.NewAgent("tester", "Writes tests"),
+	})
+	if err != nil {
+		t.Fatalf("GenerateConfig() error = %v", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Agent) != 2 || cfg.Agent["reviewer"] == nil || cfg.Agent["tester"] == nil {
+		t.Errorf("agents = %v", cfg.Agent)
+	}
+}
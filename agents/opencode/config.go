@@ -0,0 +1,51 @@
+// Package opencode provides the OpenCode agent adapter.
+package opencode
+
+// SchemaURL is the JSON schema of opencode.json.
+const SchemaURL = "https://opencode.ai/config.json"
+
+// Config represents the agent section of an opencode.json file.
+// Each generated file holds one agent; GenerateConfig combines several.
+type Config struct {
+	Schema string                  `json:"$schema,omitempty"`
+	Agent  map[string]*AgentConfig `json:"agent"`
+}
+
+// AgentConfig represents one OpenCode agent, keyed by name in Config.Agent.
+type AgentConfig struct {
+	// Description tells the primary agent when to use this agent.
+	Description string `json:"description,omitempty"`
+
+	// Mode is "primary", "subagent", or "all".
+	Mode string `json:"mode,omitempty"`
+
+	// Model is a provider-qualified model ID (e.g., "anthropic/claude-3-5-sonnet-20241022").
+	Model string `json:"model,omitempty"`
+
+	// Prompt is the agent's system prompt.
+	Prompt string `json:"prompt,omitempty"`
+
+	// Tools enables or disables individual tools. Tools not listed keep
+	// OpenCode's default (enabled).
+	Tools map[string]bool `json:"tools,omitempty"`
+
+	// Permission controls whether tool categories run without asking.
+	Permission *Permission `json:"permission,omitempty"`
+}
+
+// Permission values are "allow", "ask", or "deny".
+type Permission struct {
+	Edit     string `json:"edit,omitempty"`
+	Bash     string `json:"bash,omitempty"`
+	WebFetch string `json:"webfetch,omitempty"`
+}
+
+// Permission values.
+const (
+	PermissionAllow = "allow"
+	PermissionAsk   = "ask"
+	PermissionDeny  = "deny"
+)
+
+// ModeSubagent marks an agent that is invoked by a primary agent.
+const ModeSubagent = "subagent"
@@ -0,0 +1,224 @@
+// Package skill provides the Claude Skills adapter. Each agent becomes a
+// skill directory holding a SKILL.md and the agent's supporting files.
+package skill
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func init() {
+	core.Register(&Adapter{})
+}
+
+// SkillFileName is the skill definition file inside each skill directory.
+const SkillFileName = "SKILL.md"
+
+// filesHeading introduces the list of supporting files in the SKILL.md body.
+const filesHeading = "## Supporting files"
+
+// Adapter converts between canonical Agent and Claude Skills.
+type Adapter struct{}
+
+// Name returns the adapter identifier.
+func (a *Adapter) Name() string {
+	return "skill"
+}
+
+// FileExtension returns the file extension of SKILL.md.
+func (a *Adapter) FileExtension() string {
+	return ".md"
+}
+
+// DefaultDir returns the default directory name for Claude Skills.
+func (a *Adapter) DefaultDir() string {
+	return "skills"
+}
+
+// LossyFields returns the canonical fields a skill does not hold. Skills
+// run in the invoking agent's session, so they have no model or limits.
+func (a *Adapter) LossyFields() []string {
+	return []string{"Model", "ModelFallback", "MaxTurns", "Timeouts", "Retry", "AllowedTools", "Skills", "Dependencies", "Requires", "Arguments", "Triggers"}
+}
+
+// AgentPath places each agent's SKILL.md in its own skill directory.
+func (a *Adapter) AgentPath(agent *core.Agent) string {
+	return agent.Name + "/" + SkillFileName
+}
+
+// frontmatter holds the SKILL.md frontmatter keys. The spec version is
+// kept in metadata under core.VersionMetadataKey.
+type frontmatter struct {
+	Name         string            `yaml:"name"`
+	Description  string            `yaml:"description"`
+	AllowedTools string            `yaml:"allowed-tools,omitempty"`
+	Metadata     map[string]string `yaml:"metadata,omitempty"`
+}
+
+// Parse converts SKILL.md bytes to canonical Agent. A trailing supporting
+// files section, as written by Marshal, is read back into Files.
+func (a *Adapter) Parse(data []byte) (*core.Agent, error) {
+	content := string(data)
+	if !strings.HasPrefix(content, "---\n") {
+		return nil, &core.ParseError{Format: "skill", Err: fmt.Errorf("missing frontmatter")}
+	}
+	rest := content[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return nil, &core.ParseError{Format: "skill", Err: fmt.Errorf("unterminated frontmatter")}
+	}
+
+	var fm frontmatter
+	if err := yaml.Unmarshal([]byte(rest[:end]), &fm); err != nil {
+		return nil, &core.ParseError{Format: "skill", Err: err}
+	}
+
+	body := rest[end+len("\n---"):]
+	body, files := splitFiles(body)
+
+	agent := &core.Agent{Spec: core.Spec{
+		Name:         fm.Name,
+		Description:  fm.Description,
+		Instructions: strings.TrimSpace(body),
+	}}
+	agent.Version = fm.Metadata[core.VersionMetadataKey]
+	for _, tool := range strings.Split(fm.AllowedTools, ",") {
+		if tool = strings.TrimSpace(tool); tool != "" {
+			agent.Tools = append(agent.Tools, tool)
+		}
+	}
+	agent.Files = files
+	return agent, nil
+}
+
+// Marshal converts canonical Agent to SKILL.md bytes. Tools become
+// allowed-tools, and supporting files are linked from the end of the body
+// so Claude can find them.
+func (a *Adapter) Marshal(agent *core.Agent) ([]byte, error) {
+	if err := checkFiles(agent.Files); err != nil {
+		return nil, &core.MarshalError{Format: "skill", Err: fmt.Errorf("agent %s: %w", agent.Name, err)}
+	}
+
+	header := frontmatter{
+		Name:         agent.Name,
+		Description:  core.FormatDescriptionLine(a.Name(), agent.Description),
+		AllowedTools: strings.Join(agent.Tools, ", "),
+	}
+	if agent.Version != "" {
+		header.Metadata = map[string]string{core.VersionMetadataKey: agent.Version}
+	}
+	fm, err := yaml.Marshal(header)
+	if err != nil {
+		return nil, &core.MarshalError{Format: "skill", Err: err}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(fm)
+	buf.WriteString("---\n\n")
+
+	if agent.Instructions != "" {
+		buf.WriteString(agent.Instructions)
+		buf.WriteString("\n")
+	}
+
+	if len(agent.Files) > 0 {
+		buf.WriteString("\n" + filesHeading + "\n\n")
+		for _, file := range agent.Files {
+			buf.WriteString(fmt.Sprintf("- [%s](%s)\n", file, file))
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ReadFile reads a SKILL.md file and returns canonical Agent. The name
+// defaults to the skill directory name.
+func (a *Adapter) ReadFile(path string) (*core.Agent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &core.ReadError{Path: path, Err: err}
+	}
+
+	agent, err := a.Parse(data)
+	if err != nil {
+		if pe, ok := err.(*core.ParseError); ok {
+			pe.Path = path
+		}
+		return nil, err
+	}
+
+	if agent.Name == "" {
+		agent.Name = filepath.Base(filepath.Dir(path))
+	}
+	return agent, nil
+}
+
+// WriteFile writes the agent's SKILL.md to path and copies its supporting
+// files, resolved against the directory of the agent's spec file, next to
+// it.
+func (a *Adapter) WriteFile(agent *core.Agent, path string) error {
+	if len(agent.Files) > 0 && agent.SourcePath == "" {
+		return &core.WriteError{Path: path, Err: fmt.Errorf("agent %s declares files but has no spec file to resolve them against", agent.Name)}
+	}
+
+	data, err := a.Marshal(agent)
+	if err != nil {
+		return err
+	}
+	if err := core.WriteOutputFile(path, data); err != nil {
+		return err
+	}
+
+	srcDir := filepath.Dir(agent.SourcePath)
+	for _, file := range agent.Files {
+		src := filepath.Join(srcDir, filepath.FromSlash(file))
+		content, err := os.ReadFile(src)
+		if err != nil {
+			return &core.ReadError{Path: src, Err: err}
+		}
+		if err := core.WriteOutputFile(filepath.Join(filepath.Dir(path), filepath.FromSlash(file)), content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFiles verifies that every supporting file is a relative path that
+// stays inside the skill directory.
+func checkFiles(files []string) error {
+	for _, file := range files {
+		if !filepath.IsLocal(filepath.FromSlash(file)) || path.Clean(file) == SkillFileName {
+			return fmt.Errorf("supporting file %q must be a relative path inside the skill directory", file)
+		}
+	}
+	return nil
+}
+
+// splitFiles removes the supporting files section written by Marshal from
+// the end of body and returns the remaining body and the listed files.
+func splitFiles(body string) (string, []string) {
+	idx := strings.LastIndex(body, "\n"+filesHeading+"\n")
+	if idx < 0 {
+		return body, nil
+	}
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(body[idx+len(filesHeading)+2:]), "\n") {
+		line = strings.TrimSpace(line)
+		open := strings.Index(line, "](")
+		if !strings.HasPrefix(line, "- [") || open < 0 || !strings.HasSuffix(line, ")") {
+			return body, nil // Not our list; keep the section as instructions
+		}
+		files = append(files, line[open+2:len(line)-1])
+	}
+	return body[:idx], files
+}
@@ -0,0 +1,95 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestRoundTrip(t *testing.T) {
+	adapter := &Adapter{}
+	agent := core.NewAgent("pdf-tools", "Extract text: fill forms").
+		WithTools("Read", "Bash").
+		WithInstructions("# PDF tools\n\nUse the scripts to work with PDFs.")
+	agent.Files = []string{"scripts/extract.py", "REFERENCE.md"}
+
+	data, err := adapter.Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	for _, want := range []string{"allowed-tools: Read, Bash\n", "- [scripts/extract.py](scripts/extract.py)\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("output missing %q:\n%s", want, data)
+		}
+	}
+
+	back, err := adapter.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if back.Name != agent.Name || back.Description != agent.Description || back.Instructions != agent.Instructions {
+		t.Errorf("round trip = %+v", back.Spec)
+	}
+	if !reflect.DeepEqual(back.Tools, agent.Tools) || !reflect.DeepEqual(back.Files, agent.Files) {
+		t.Errorf("round trip Tools = %v, Files = %v", back.Tools, back.Files)
+	}
+}
+
+func TestParseKeepsOtherSections(t *testing.T) {
+	data := []byte("---\nname: notes\ndescription: Notes\n---\n\nIntro.\n\n## Supporting files\n\nNone yet.\n")
+	agent, err := (&Adapter{}).Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if agent.Files != nil || !strings.HasSuffix(agent.Instructions, "None yet.") {
+		t.Errorf("Parse() = %q, files %v", agent.Instructions, agent.Files)
+	}
+}
+
+func TestMarshalRejectsUnsafeFiles(t *testing.T) {
+	for _, file := range []string{"../secret.txt", "/etc/passwd", "SKILL.md"} {
+		agent := core.NewAgent("x", "X").WithInstructions("Do.")
+		agent.Files = []string{file}
+		if _, err := (&Adapter{}).Marshal(agent); err == nil {
+			t.Errorf("Marshal() should reject file %q", file)
+		}
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	specDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(specDir, "scripts"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(specDir, "scripts", "run.sh"), []byte("echo hi\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	adapter := &Adapter{}
+	agent := core.NewAgent("runner", "Runs").WithInstructions("Run it.")
+	agent.Files = []string{"scripts/run.sh"}
+	agent.SourcePath = filepath.Join(specDir, "runner.md")
+
+	outDir := t.TempDir()
+	path := filepath.Join(outDir, core.AgentPath(adapter, agent))
+	if err := adapter.WriteFile(agent, path); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(outDir, "runner", SkillFileName)); err != nil {
+		t.Errorf("SKILL.md not written: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(outDir, "runner", "scripts", "run.sh"))
+	if err != nil || string(got) != "echo hi\n" {
+		t.Errorf("supporting file = %q, %v", got, err)
+	}
+
+	back, err := adapter.ReadFile(path)
+	if err != nil || back.Name != "runner" {
+		t.Errorf("ReadFile() = %+v, %v", back, err)
+	}
+}
@@ -0,0 +1,124 @@
+// Package sqlitecatalog writes canonical agents into a SQLite database, for
+// services that query agents rather than read generated files.
+//
+// Agents are stored in a single agents table keyed by qualified name
+// (namespace/name). The table is created if absent, and writing upserts
+// every agent, so re-running with the same specs leaves the database
+// unchanged. Rows of agents no longer in the specs are kept.
+package sqlitecatalog
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	// Register the pure-Go "sqlite" database/sql driver.
+	_ "modernc.org/sqlite"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// Schema creates the agents table. Tools and tags are JSON arrays; tags
+// holds the agent's group, category and tags.
+const Schema = `CREATE TABLE IF NOT EXISTS agents (
+	id           TEXT PRIMARY KEY,
+	name         TEXT NOT NULL,
+	namespace    TEXT NOT NULL DEFAULT '',
+	description  TEXT NOT NULL DEFAULT '',
+	instructions TEXT NOT NULL DEFAULT '',
+	model        TEXT NOT NULL DEFAULT '',
+	tools        TEXT NOT NULL DEFAULT '[]',
+	tags         TEXT NOT NULL DEFAULT '[]'
+)`
+
+const upsert = `INSERT INTO agents (id, name, namespace, description, instructions, model, tools, tags)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+	name = excluded.name,
+	namespace = excluded.namespace,
+	description = excluded.description,
+	instructions = excluded.instructions,
+	model = excluded.model,
+	tools = excluded.tools,
+	tags = excluded.tags`
+
+// Write creates the agents table in db if absent and upserts agents in a
+// single transaction. Qualified agent names (namespace/name) must be
+// unique.
+func Write(ctx context.Context, db *sql.DB, agents []*core.Agent) error {
+	if err := core.CheckUniqueNames(agents); err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after Commit
+
+	if _, err := tx.ExecContext(ctx, Schema); err != nil {
+		return fmt.Errorf("creating agents table: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, upsert)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, agent := range agents {
+		tools, err := jsonArray(agent.Tools)
+		if err != nil {
+			return &core.MarshalError{Format: "sqlite", Err: err}
+		}
+		tags, err := jsonArray(Tags(agent))
+		if err != nil {
+			return &core.MarshalError{Format: "sqlite", Err: err}
+		}
+		if _, err := stmt.ExecContext(ctx,
+			agent.QualifiedName(), agent.Name, agent.Namespace, agent.Description,
+			agent.Instructions, string(agent.PrimaryModel()), tools, tags,
+		); err != nil {
+			return fmt.Errorf("writing agent %s: %w", agent.Name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// WriteFile writes agents into the SQLite database at path, creating the
+// file if it does not exist.
+func WriteFile(ctx context.Context, path string, agents []*core.Agent) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return &core.WriteError{Path: path, Err: err}
+	}
+	defer db.Close()
+
+	if err := Write(ctx, db, agents); err != nil {
+		return &core.WriteError{Path: path, Err: err}
+	}
+	return nil
+}
+
+// Tags returns the tags stored for agent: its group and category, if set,
+// then its tags, each once.
+func Tags(agent *core.Agent) []string {
+	tags := []string{}
+	for _, tag := range append([]string{agent.Group, agent.Category}, agent.Tags...) {
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func jsonArray(values []string) (string, error) {
+	if values == nil {
+		values = []string{}
+	}
+	data, err := json.Marshal(values)
+	return string(data), err
+}
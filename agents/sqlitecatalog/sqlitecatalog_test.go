@@ -0,0 +1,60 @@
+package sqlitecatalog
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestWriteFileUpserts(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "agents.db")
+
+	reviewer := core.NewAgent("reviewer", "Reviews code").WithTools("Read", "Grep").WithModel(core.ModelSonnet)
+	reviewer.Group = "quality"
+	reviewer.Tags = []string{"review", "quality", "review"}
+	planner := core.NewAgent("planner", "Plans work").WithNamespace("ops")
+
+	for i := 0; i < 2; i++ {
+		if err := WriteFile(ctx, path, []*core.Agent{reviewer, planner}); err != nil {
+			t.Fatalf("WriteFile() run %d error = %v", i+1, err)
+		}
+	}
+
+	reviewer.Description = "Reviews code and docs"
+	if err := WriteFile(ctx, path, []*core.Agent{reviewer}); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM agents`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("rows = %d, want 2", count)
+	}
+
+	var description, model, tools, tags string
+	err = db.QueryRow(`SELECT description, model, tools, tags FROM agents WHERE id = ?`, "reviewer").
+		Scan(&description, &model, &tools, &tags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if description != "Reviews code and docs" || model != "sonnet" || tools != `["Read","Grep"]` || tags != `["quality","review"]` {
+		t.Errorf("reviewer row = %q, %q, %q, %q", description, model, tools, tags)
+	}
+
+	var name string
+	if err := db.QueryRow(`SELECT name FROM agents WHERE id = ?`, "ops/planner").Scan(&name); err != nil {
+		t.Errorf("planner should be keyed by qualified name: %v", err)
+	}
+}
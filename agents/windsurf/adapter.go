@@ -0,0 +1,204 @@
+// Package windsurf provides the Windsurf rules adapter.
+package windsurf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+const (
+	// TriggerModelDecision applies a rule when Cascade judges its
+	// description relevant to the task.
+	TriggerModelDecision = "model_decision"
+
+	// MaxRuleSize is the largest rule file Windsurf accepts, in characters.
+	MaxRuleSize = 12000
+)
+
+func init() {
+	core.Register(&Adapter{})
+}
+
+// Adapter converts between canonical Agent and Windsurf workspace rules
+// (.windsurf/rules/<name>.md). Agents become "model decision" rules, which
+// Cascade applies when the description matches the task.
+type Adapter struct{}
+
+// Name returns the adapter identifier.
+func (a *Adapter) Name() string {
+	return "windsurf"
+}
+
+// FileExtension returns the file extension for Windsurf rules.
+func (a *Adapter) FileExtension() string {
+	return ".md"
+}
+
+// DefaultDir returns the default directory name for Windsurf rules.
+func (a *Adapter) DefaultDir() string {
+	return ".windsurf/rules"
+}
+
+// LossyFields returns the canonical fields a Windsurf rule does not hold.
+func (a *Adapter) LossyFields() []string {
+	return []string{"Model", "ModelFallback", "MaxTurns", "Timeouts", "Retry", "AllowedTools", "Skills", "Dependencies", "Requires", "Arguments", "Triggers", "Version"}
+}
+
+// frontmatter holds the rule frontmatter keys. Windsurf reads trigger,
+// description and globs; name and tools are ignored by Windsurf and let
+// Parse recover the agent.
+type frontmatter struct {
+	Name        string   `yaml:"name,omitempty"`
+	Trigger     string   `yaml:"trigger"`
+	Description string   `yaml:"description"`
+	Globs       string   `yaml:"globs,omitempty"`
+	Tools       []string `yaml:"tools,omitempty,flow"`
+}
+
+// Parse converts Windsurf rule bytes to canonical Agent.
+func (a *Adapter) Parse(data []byte) (*core.Agent, error) {
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if !strings.HasPrefix(content, "---\n") {
+		return nil, &core.ParseError{Format: "windsurf", Err: fmt.Errorf("missing frontmatter")}
+	}
+	rest := content[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return nil, &core.ParseError{Format: "windsurf", Err: fmt.Errorf("unterminated frontmatter")}
+	}
+
+	var fm frontmatter
+	if err := yaml.Unmarshal([]byte(rest[:end]), &fm); err != nil {
+		return nil, &core.ParseError{Format: "windsurf", Err: err}
+	}
+
+	agent := &core.Agent{Spec: core.Spec{
+		Name:         fm.Name,
+		Description:  fm.Description,
+		Instructions: strings.TrimSpace(rest[end+len("\n---"):]),
+	}}
+	for _, tool := range fm.Tools {
+		agent.Tools = append(agent.Tools, mapWindsurfToolToCanonical(tool))
+	}
+	return agent, nil
+}
+
+// Marshal converts canonical Agent to Windsurf rule bytes. Rules longer
+// than MaxRuleSize are rejected, since Windsurf would truncate them.
+func (a *Adapter) Marshal(agent *core.Agent) ([]byte, error) {
+	fm := frontmatter{
+		Name:        agent.Name,
+		Trigger:     TriggerModelDecision,
+		Description: core.FormatDescriptionLine(a.Name(), agent.Description),
+	}
+	seen := make(map[string]bool)
+	for _, tool := range agent.Tools {
+		mapped := mapCanonicalToolToWindsurf(tool)
+		if !seen[mapped] {
+			seen[mapped] = true
+			fm.Tools = append(fm.Tools, mapped)
+		}
+	}
+
+	header, err := yaml.Marshal(fm)
+	if err != nil {
+		return nil, &core.MarshalError{Format: "windsurf", Err: err}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.Write(header)
+	buf.WriteString("---\n\n")
+
+	if agent.Instructions != "" {
+		buf.WriteString(agent.Instructions)
+		buf.WriteString("\n")
+	}
+
+	// Rules have no version key, so the spec version is a comment
+	data := core.AddVersionComment(a.FileExtension(), buf.Bytes(), agent.Version)
+	if n := len([]rune(string(data))); n > MaxRuleSize {
+		return nil, &core.MarshalError{Format: "windsurf", Err: fmt.Errorf("rule %s is %d characters, over the %d character limit", agent.Name, n, MaxRuleSize)}
+	}
+	return data, nil
+}
+
+// ReadFile reads a Windsurf rule file and returns canonical Agent.
+func (a *Adapter) ReadFile(path string) (*core.Agent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &core.ReadError{Path: path, Err: err}
+	}
+
+	agent, err := a.Parse(data)
+	if err != nil {
+		if pe, ok := err.(*core.ParseError); ok {
+			pe.Path = path
+		}
+		return nil, err
+	}
+
+	// Hand-written rules have no name; use the filename
+	if agent.Name == "" {
+		base := filepath.Base(path)
+		agent.Name = strings.TrimSuffix(base, filepath.Ext(base))
+	}
+
+	return agent, nil
+}
+
+// WriteFile writes canonical Agent to a Windsurf rule file.
+func (a *Adapter) WriteFile(agent *core.Agent, path string) error {
+	data, err := a.Marshal(agent)
+	if err != nil {
+		return err
+	}
+
+	return core.WriteOutputFile(path, data)
+}
+
+// toolMapping maps canonical tool names to Windsurf Cascade tool names.
+var toolMapping = map[string]string{
+	"Bash":      "run_command",
+	"Edit":      "replace_file_content",
+	"Glob":      "find_by_name",
+	"Grep":      "grep_search",
+	"Read":      "view_file",
+	"WebFetch":  "read_url_content",
+	"WebSearch": "search_web",
+	"Write":     "write_to_file",
+}
+
+// SupportedTools returns the canonical tools with a Windsurf mapping.
+func (a *Adapter) SupportedTools() []string {
+	tools := make([]string, 0, len(toolMapping))
+	for tool := range toolMapping {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+	return tools
+}
+
+func mapCanonicalToolToWindsurf(tool string) string {
+	if mapped, ok := toolMapping[tool]; ok {
+		return mapped
+	}
+	return strings.ToLower(tool)
+}
+
+func mapWindsurfToolToCanonical(tool string) string {
+	for canonical, mapped := range toolMapping {
+		if mapped == tool {
+			return canonical
+		}
+	}
+	return tool
+}
@@ -0,0 +1,80 @@
+package windsurf
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestRoundTrip(t *testing.T) {
+	adapter := &Adapter{}
+	agent := core.NewAgent("go-reviewer", "Review Go code: style, errors and tests").
+		WithTools("Read", "Grep", "Bash").
+		WithInstructions("# Go review\n\nCheck error wrapping.")
+
+	data, err := adapter.Marshal(agent)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	for _, want := range []string{"trigger: model_decision\n", "description: 'Review Go code: style, errors and tests'\n", "tools: [view_file, grep_search, run_command]\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("output missing %q:\n%s", want, data)
+		}
+	}
+
+	back, err := adapter.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if back.Name != agent.Name || back.Description != agent.Description || back.Instructions != agent.Instructions || !reflect.DeepEqual(back.Tools, agent.Tools) {
+		t.Errorf("round trip = %+v, want %+v", back.Spec, agent.Spec)
+	}
+}
+
+func TestToolMapping(t *testing.T) {
+	tests := []struct {
+		canonical string
+		windsurf  string
+	}{
+		{"Bash", "run_command"},
+		{"Edit", "replace_file_content"},
+		{"WebFetch", "read_url_content"},
+		{"Custom", "custom"},
+	}
+	for _, tt := range tests {
+		if got := mapCanonicalToolToWindsurf(tt.canonical); got != tt.windsurf {
+			t.Errorf("mapCanonicalToolToWindsurf(%q) = %q, want %q", tt.canonical, got, tt.windsurf)
+		}
+	}
+	if got := mapWindsurfToolToCanonical("write_to_file"); got != "Write" {
+		t.Errorf("mapWindsurfToolToCanonical(write_to_file) = %q, want Write", got)
+	}
+}
+
+func TestReadFileInfersName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testing.md")
+	if err := core.WriteOutputFile(path, []byte("---\ntrigger: always_on\ndescription: Testing conventions\n---\n\nUse table tests.\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	agent, err := (&Adapter{}).ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if agent.Name != "testing" || agent.Description != "Testing conventions" || agent.Instructions != "Use table tests." {
+		t.Errorf("ReadFile() = %+v", agent.Spec)
+	}
+}
+
+func TestMarshalTooLong(t *testing.T) {
+	agent := core.NewAgent("long", "Long rule").WithInstructions(strings.Repeat("x", MaxRuleSize))
+	_, err := (&Adapter{}).Marshal(agent)
+	var me *core.MarshalError
+	if !errors.As(err, &me) {
+		t.Errorf("Marshal() error = %v, want MarshalError", err)
+	}
+}
@@ -26,6 +26,7 @@ var (
 	outputDir  string
 	platforms  []string
 	configFile string
+	pluginTags []string
 )
 
 var generatePluginsCmd = &cobra.Command{
@@ -39,8 +40,13 @@ The spec directory should contain:
   - skills/: Skill definitions (*.json)
   - agents/: Agent definitions (*.json)
 
+With --tags, one Claude plugin is generated per tag instead, bundling the
+agents with that tag (plugins/<name>-<tag>/). Every agent must carry at
+least one of the tags.
+
 Example:
-  assistantkit generate plugins --spec=plugins/spec --output=plugins --platforms=claude,kiro`,
+  assistantkit generate plugins --spec=plugins/spec --output=plugins --platforms=claude,kiro
+  assistantkit generate plugins --spec=plugins/spec --output=plugins --tags=data,ops`,
 	RunE: runGeneratePlugins,
 }
 
@@ -134,6 +140,7 @@ func init() {
 	generatePluginsCmd.Flags().StringVar(&outputDir, "output", "plugins", "Output directory for generated plugins")
 	generatePluginsCmd.Flags().StringSliceVar(&platforms, "platforms", []string{"claude", "kiro"}, "Platforms to generate (claude,kiro,gemini)")
 	generatePluginsCmd.Flags().StringVar(&configFile, "config", "", "Config file (default: assistantkit.yaml if exists)")
+	generatePluginsCmd.Flags().StringSliceVar(&pluginTags, "tags", nil, "Generate one Claude plugin per agent tag instead (e.g., data,ops)")
 
 	generateDeploymentCmd.Flags().StringVar(&deploymentSpecDir, "specs", "specs", "Path to multi-agent-spec directory")
 	generateDeploymentCmd.Flags().StringVar(&deploymentFile, "deployment", "", "Path to deployment definition file (required)")
@@ -216,6 +223,23 @@ func runGeneratePlugins(cmd *cobra.Command, args []string) error {
 	fmt.Println("=== AssistantKit Plugin Generator ===")
 	fmt.Printf("Spec directory: %s\n", absSpecDir)
 	fmt.Printf("Output directory: %s\n", absOutputDir)
+	if len(pluginTags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(pluginTags, ", "))
+		fmt.Println()
+
+		result, err := generate.ClaudePluginsByTag(absSpecDir, absOutputDir, pluginTags)
+		if err != nil {
+			return fmt.Errorf("generating plugins: %w", err)
+		}
+
+		fmt.Printf("Loaded: %d agents\n\n", result.AgentCount)
+		for _, tag := range pluginTags {
+			fmt.Printf("Generated %s: %s\n", tag, result.PluginDirs[tag])
+		}
+
+		fmt.Println("\nDone!")
+		return nil
+	}
 	fmt.Printf("Platforms: %s\n", strings.Join(platforms, ", "))
 	fmt.Println()
 
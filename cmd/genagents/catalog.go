@@ -0,0 +1,32 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// writeCatalog writes the catalog of agentList, rendered with every
+// registered adapter, as indented JSON to path.
+func writeCatalog(path string, agentList []*core.Agent, verbose bool) error {
+	catalog := core.BuildCatalog(agentList)
+
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		return &core.MarshalError{Format: "catalog", Err: err}
+	}
+	if err := core.WriteOutputFile(path, append(data, '\n')); err != nil {
+		return err
+	}
+
+	if verbose {
+		for _, entry := range catalog.Agents {
+			for format, msg := range entry.Errors {
+				fmt.Printf("  ! %s (%s): %s\n", entry.Agent.Name, format, msg)
+			}
+		}
+	}
+	fmt.Printf("Wrote catalog of %d agents to %s\n", len(catalog.Agents), path)
+	return nil
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// ciConfig holds what the -ci checks run against.
+type ciConfig struct {
+	SpecDir  string
+	Project  string // -project directory; replaces SpecDir and Targets
+	Priority string
+	Targets  []flagTarget // -targets, or -format with -output
+	Lockfile string       // Lockfile to verify, if any
+	Opts     options
+}
+
+// ciCheck is one check of the -ci preset. run returns a non-empty skip
+// reason when the check does not apply to the invocation.
+type ciCheck struct {
+	name string
+	run  func(w io.Writer) (skip string, err error)
+}
+
+// ciChecks returns the -ci checks in the order they run: spec validation
+// (the checks of -validate), lint, generated files being current, and
+// lockfile verification. Later checks use the agents loaded by validation.
+func ciChecks(cfg ciConfig) []ciCheck {
+	var agentList []*core.Agent
+
+	return []ciCheck{
+		{"validate", func(w io.Writer) (string, error) {
+			specDir := cfg.SpecDir
+			var targets []Target
+			if cfg.Project != "" {
+				specDir = filepath.Join(cfg.Project, "agents")
+				deployment, err := readDeployment(cfg.Project)
+				if err != nil {
+					return "", err
+				}
+				targets = deployment.Targets
+			}
+			formats := make([]string, len(cfg.Targets))
+			for i, target := range cfg.Targets {
+				formats[i] = target.Format
+			}
+			loaded, problems := validateSpecs(w, specDir, targets, formats, cfg.Opts)
+			if problems > 0 {
+				return "", fmt.Errorf("%d problem(s) in %s", problems, specDir)
+			}
+			if len(loaded) == 0 {
+				return "", fmt.Errorf("no agents found in %s", specDir)
+			}
+			agentList = loaded
+			fmt.Fprintf(w, "Validated %d agents in %s\n", len(loaded), specDir)
+			return "", nil
+		}},
+
+		{"lint", func(w io.Writer) (string, error) {
+			if agentList == nil {
+				return "specs did not validate", nil
+			}
+			if n := runLint(w, agentList); n > 0 {
+				return "", fmt.Errorf("%d lint warning(s)", n)
+			}
+			return "", nil
+		}},
+
+		{"generated-current", func(w io.Writer) (string, error) {
+			opts := cfg.Opts
+			opts.DryRun = &dryRunPlan{}
+			switch {
+			case cfg.Project != "":
+				if err := runProjectMode(w, w, cfg.Project, cfg.Priority, opts); err != nil {
+					return "", err
+				}
+			case len(cfg.Targets) == 0:
+				return "no -output, -targets or -project", nil
+			case agentList == nil:
+				return "specs did not validate", nil
+			default:
+				prepared, err := prepareAgents(w, agentList, opts)
+				if err != nil {
+					return "", err
+				}
+				for _, target := range cfg.Targets {
+					if err := generateAgents(w, w, prepared, target.Format, target.Output, opts); err != nil {
+						return "", fmt.Errorf("generating %s agents: %w", target.Format, err)
+					}
+				}
+			}
+
+			stale := opts.DryRun.changes()
+			for _, entry := range stale {
+				fmt.Fprintf(w, "%-9s %s\n", entry.Action, entry.Path)
+			}
+			if len(stale) > 0 {
+				return "", fmt.Errorf("%d generated file(s) out of date; regenerate and commit them", len(stale))
+			}
+			fmt.Fprintf(w, "Generated files are up to date (%d checked)\n", len(opts.DryRun.entries))
+			return "", nil
+		}},
+
+		{"verify-lock", func(w io.Writer) (string, error) {
+			if cfg.Lockfile == "" {
+				return "no -verify-lockfile or -lockfile", nil
+			}
+			return "", runVerifyLockfile(w, cfg.Lockfile)
+		}},
+	}
+}
+
+// runCI runs every check, printing each one's output under a header,
+// followed by a consolidated report. Skipped checks do not fail the run;
+// any failed check does, after all checks have run.
+func runCI(w io.Writer, checks []ciCheck) error {
+	type result struct {
+		status string
+		detail string
+	}
+	results := make([]result, len(checks))
+	failed := 0
+	for i, check := range checks {
+		fmt.Fprintf(w, "== %s\n", check.name)
+		skip, err := check.run(w)
+		switch {
+		case err != nil:
+			results[i] = result{"FAIL", err.Error()}
+			failed++
+		case skip != "":
+			results[i] = result{"SKIP", skip}
+		default:
+			results[i] = result{"PASS", ""}
+		}
+	}
+
+	fmt.Fprintln(w, "\nCI report:")
+	for i, check := range checks {
+		if results[i].detail == "" {
+			fmt.Fprintf(w, "  %s  %s\n", results[i].status, check.name)
+		} else {
+			fmt.Fprintf(w, "  %s  %s: %s\n", results[i].status, check.name, results[i].detail)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d CI checks failed", failed, len(checks))
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents"
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestRunCI(t *testing.T) {
+	dir := t.TempDir()
+	specDir := filepath.Join(dir, "specs")
+	spec := "---\nname: reviewer\ndescription: Reviews code\ntools: [Read]\n---\n\nRead the diff and review it.\n"
+	if err := core.WriteOutputFile(filepath.Join(specDir, "reviewer.md"), []byte(spec)); err != nil {
+		t.Fatal(err)
+	}
+	outputDir := filepath.Join(dir, "out")
+	agentList, err := agents.ReadCanonicalDir(specDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := generateAgents(io.Discard, io.Discard, agentList, "claude", outputDir, options{WriteConcurrency: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := ciConfig{
+		SpecDir: specDir,
+		Targets: []flagTarget{{Format: "claude", Output: outputDir}},
+		Opts:    options{WriteConcurrency: 1},
+	}
+	var out bytes.Buffer
+	if err := runCI(&out, ciChecks(cfg)); err != nil {
+		t.Fatalf("runCI() error = %v\n%s", err, out.String())
+	}
+	for _, want := range []string{"PASS  validate\n", "PASS  lint\n", "PASS  generated-current\n", "SKIP  verify-lock: "} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, out.String())
+		}
+	}
+
+	// A stale output fails its check, and the other checks still run
+	stale := filepath.Join(outputDir, "reviewer.md")
+	if err := os.WriteFile(stale, []byte("edited"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	err = runCI(&out, ciChecks(cfg))
+	if err == nil || !strings.Contains(err.Error(), "1 of 4 CI checks failed") {
+		t.Errorf("runCI() error = %v, want 1 failed check", err)
+	}
+	for _, want := range []string{"overwrite " + stale, "FAIL  generated-current: 1 generated file(s) out of date", "PASS  lint\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRunCIInvalidSpecs(t *testing.T) {
+	cfg := ciConfig{SpecDir: t.TempDir(), Targets: []flagTarget{{Format: "claude", Output: t.TempDir()}}}
+
+	var out bytes.Buffer
+	if err := runCI(&out, ciChecks(cfg)); err == nil {
+		t.Fatal("runCI() should fail without specs")
+	}
+	for _, want := range []string{"FAIL  validate: no agents found", "SKIP  lint: specs did not validate", "SKIP  generated-current: specs did not validate"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRunCIValidateChecks(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		opts options
+		want string
+	}{
+		{"strict tools", "---\nname: reviewer\ndescription: Reviews code\ntools: [Frobnicate]\n---\n\nReview.\n", options{StrictTools: true}, "format claude: "},
+		{"category", "---\nname: reviewer\ndescription: Reviews code\ncategory: nonsense\n---\n\nReview.\n", options{}, "-allow-unknown-category"},
+		{"max turns", "---\nname: reviewer\ndescription: Reviews code\nmaxTurns: -1\n---\n\nReview.\n", options{}, "maxTurns must be non-negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specDir := t.TempDir()
+			if err := core.WriteOutputFile(filepath.Join(specDir, "reviewer.md"), []byte(tt.spec)); err != nil {
+				t.Fatal(err)
+			}
+			tt.opts.WriteConcurrency = 1
+			cfg := ciConfig{SpecDir: specDir, Targets: []flagTarget{{Format: "claude", Output: t.TempDir()}}, Opts: tt.opts}
+
+			var out bytes.Buffer
+			if err := runCI(&out, ciChecks(cfg)); err == nil {
+				t.Fatalf("runCI() accepted the spec:\n%s", out.String())
+			}
+			if !strings.Contains(out.String(), "FAIL  validate: 1 problem(s)") || !strings.Contains(out.String(), tt.want) {
+				t.Errorf("report missing failed validate with %q:\n%s", tt.want, out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// defaultWriteConcurrency is the default -write-concurrency: enough to
+// overlap I/O, well below typical open file limits.
+const defaultWriteConcurrency = 8
+
+// forEachLimited calls fn for every index in [0, n), with at most limit
+// calls in flight so large generations cannot exhaust file descriptors
+// (EMFILE). A limit below 1 runs sequentially. All calls run even if some
+// fail; the error of the lowest failing index is returned.
+func forEachLimited(limit, n int, fn func(i int) error) error {
+	if limit < 1 {
+		limit = 1
+	}
+
+	errs := make([]error, n)
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// targetLog buffers what a -project target prints while targets generate
+// concurrently, so it can be flushed in target order.
+type targetLog struct {
+	out, warn bytes.Buffer
+
+	// plan holds the target's files under -dry-run.
+	plan *dryRunPlan
+}
+
+// flush writes the buffered output to w and warnings to warn, and adds the
+// target's planned files to plan.
+func (l *targetLog) flush(w, warn io.Writer, plan *dryRunPlan) {
+	w.Write(l.out.Bytes())
+	warn.Write(l.warn.Bytes())
+	if plan != nil && l.plan != nil {
+		plan.merge(l.plan)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestForEachLimited(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	err := forEachLimited(4, 100, func(i int) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		if i == 70 || i == 30 {
+			return fmt.Errorf("agent %d", i)
+		}
+		return nil
+	})
+	if peak.Load() > 4 {
+		t.Errorf("peak concurrency = %d, want <= 4", peak.Load())
+	}
+	if err == nil || err.Error() != "agent 30" {
+		t.Errorf("error = %v, want the lowest failing index", err)
+	}
+}
+
+func TestRunProjectModeConcurrent(t *testing.T) {
+	project := t.TempDir()
+	spec := "---\nname: reviewer\ndescription: Reviews code\ntools: [Read]\n---\n\nReview the diff.\n"
+	if err := core.WriteOutputFile(filepath.Join(project, "agents", "reviewer.md"), []byte(spec)); err != nil {
+		t.Fatal(err)
+	}
+	deployment := `{"team": "qa", "targets": [
+		{"name": "claude", "platform": "claude-code", "output": "out/claude"},
+		{"name": "kiro", "platform": "kiro-cli", "output": "out/kiro"},
+		{"name": "opencode", "platform": "opencode", "output": "out/opencode"},
+		{"name": "windsurf", "platform": "windsurf", "output": "out/windsurf"},
+		{"name": "helm", "platform": "kubernetes", "output": "out/helm", "config": {"imageRepository": "qa/agents"}}
+	]}`
+	if err := core.WriteOutputFile(filepath.Join(project, "deployment.json"), []byte(deployment)); err != nil {
+		t.Fatal(err)
+	}
+
+	var want string
+	for _, concurrency := range []int{1, 5} {
+		// Start from empty outputs so both runs write every file
+		if err := os.RemoveAll(filepath.Join(project, "out")); err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		opts := options{WriteConcurrency: 1, Concurrency: concurrency}
+		if err := runProjectMode(&out, &out, project, "", opts); err != nil {
+			t.Fatalf("runProjectMode() error = %v", err)
+		}
+		if concurrency == 1 {
+			want = out.String()
+			continue
+		}
+		if out.String() != want {
+			t.Errorf("concurrent output differs from serial output:\n%s\nwant:\n%s", out.String(), want)
+		}
+	}
+	if strings.Index(want, "claude agents") > strings.Index(want, "Helm chart") {
+		t.Errorf("targets logged out of order:\n%s", want)
+	}
+
+	// Every target runs, and the first failure in target order is reported
+	deployment = `{"team": "qa", "targets": [
+		{"name": "claude", "platform": "claude-code", "output": "out/claude"},
+		{"name": "first", "platform": "kubernetes", "output": "out/a"},
+		{"name": "second", "platform": "gcp-gke", "output": "out/b"}
+	]}`
+	if err := core.WriteOutputFile(filepath.Join(project, "deployment.json"), []byte(deployment)); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	err := runProjectMode(&out, &out, project, "", options{WriteConcurrency: 1, Concurrency: 3})
+	if err == nil || !strings.Contains(err.Error(), "failed to generate first") {
+		t.Errorf("runProjectMode() error = %v, want the first target's failure", err)
+	}
+	if !strings.Contains(out.String(), "Generated 1 claude agents") {
+		t.Errorf("output missing the successful target:\n%s", out.String())
+	}
+}
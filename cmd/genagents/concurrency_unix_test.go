@@ -0,0 +1,59 @@
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// TestGenerateAgentsManyFiles generates far more agents than the open file
+// limit allows at once, to check that writes are bounded.
+func TestGenerateAgentsManyFiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("stress test")
+	}
+
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		t.Skipf("cannot read open file limit: %v", err)
+	}
+	lowered := limit
+	lowered.Cur = 64
+	if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, &lowered); err != nil {
+		t.Skipf("cannot lower open file limit: %v", err)
+	}
+	defer syscall.Setrlimit(syscall.RLIMIT_NOFILE, &limit) //nolint:errcheck // best-effort restore
+
+	agentList := make([]*core.Agent, 2000)
+	for i := range agentList {
+		agentList[i] = core.NewAgent(fmt.Sprintf("agent-%04d", i), "Stress test agent").WithTools("Read")
+	}
+
+	dir := t.TempDir()
+	opts := options{WriteConcurrency: defaultWriteConcurrency}
+	if err := generateAgents(io.Discard, io.Discard, agentList, "claude", dir, opts); err != nil {
+		if errors.Is(err, syscall.EMFILE) {
+			t.Fatalf("descriptor exhaustion: %v", err)
+		}
+		t.Fatalf("generateAgents() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(agentList) {
+		t.Errorf("wrote %d files, want %d", len(entries), len(agentList))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "agent-1999.md")); err != nil {
+		t.Error(err)
+	}
+}
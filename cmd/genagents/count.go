@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// unsetKey labels agents without a value in a count breakdown.
+const unsetKey = "(none)"
+
+// agentCount is the -count inventory of a spec directory.
+type agentCount struct {
+	Total       int            `json:"total"`
+	ByModel     map[string]int `json:"byModel"`
+	ByGroup     map[string]int `json:"byGroup"`
+	ByCategory  map[string]int `json:"byCategory"`
+	ByNamespace map[string]int `json:"byNamespace"`
+}
+
+// countAgents tallies agents by model, group, category and namespace.
+func countAgents(agentList []*core.Agent) agentCount {
+	c := agentCount{
+		Total:       len(agentList),
+		ByModel:     make(map[string]int),
+		ByGroup:     make(map[string]int),
+		ByCategory:  make(map[string]int),
+		ByNamespace: make(map[string]int),
+	}
+	tally := func(m map[string]int, key string) {
+		if key == "" {
+			key = unsetKey
+		}
+		m[key]++
+	}
+	for _, agent := range agentList {
+		tally(c.ByModel, string(agent.PrimaryModel()))
+		tally(c.ByGroup, agent.Group)
+		tally(c.ByCategory, agent.Category)
+		tally(c.ByNamespace, agent.Namespace)
+	}
+	return c
+}
+
+// runCount prints the inventory of agentList loaded from specDir, as text
+// or, with asJSON, as a JSON object.
+func runCount(w io.Writer, specDir string, agentList []*core.Agent, asJSON bool) error {
+	c := countAgents(agentList)
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(c)
+	}
+
+	fmt.Fprintf(w, "%d agents in %s\n", c.Total, specDir)
+	for _, section := range []struct {
+		title  string
+		counts map[string]int
+	}{
+		{"model", c.ByModel},
+		{"group", c.ByGroup},
+		{"category", c.ByCategory},
+		{"namespace", c.ByNamespace},
+	} {
+		fmt.Fprintf(w, "\nBy %s:\n", section.title)
+		keys := make([]string, 0, len(section.counts))
+		for key := range section.counts {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(w, "  %-20s %d\n", key, section.counts[key])
+		}
+	}
+	return nil
+}
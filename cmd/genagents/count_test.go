@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestRunCount(t *testing.T) {
+	reviewer := core.NewAgent("reviewer", "Reviews code").WithModel(core.ModelSonnet)
+	reviewer.Group = "quality"
+	planner := core.NewAgent("planner", "Plans work").WithModel(core.ModelOpus)
+	planner.Group = "quality"
+	writer := core.NewAgent("writer", "Writes docs").WithModel(core.ModelSonnet)
+	agentList := []*core.Agent{reviewer, planner, writer}
+
+	var text bytes.Buffer
+	if err := runCount(&text, "specs", agentList, false); err != nil {
+		t.Fatalf("runCount() error = %v", err)
+	}
+	for _, want := range []string{"3 agents in specs", "By model:", "sonnet", "By group:", "quality", unsetKey} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, text.String())
+		}
+	}
+
+	var out bytes.Buffer
+	if err := runCount(&out, "specs", agentList, true); err != nil {
+		t.Fatalf("runCount(json) error = %v", err)
+	}
+	var got agentCount
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out.String())
+	}
+	if got.Total != 3 || got.ByModel["sonnet"] != 2 || got.ByModel["opus"] != 1 {
+		t.Errorf("model counts = %+v", got)
+	}
+	if got.ByGroup["quality"] != 2 || got.ByGroup[unsetKey] != 1 {
+		t.Errorf("group counts = %v", got.ByGroup)
+	}
+}
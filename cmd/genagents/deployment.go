@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// platforms lists the deployment target platforms generateForPlatform
+// handles, in the order of its cases.
+var platforms = []string{
+	"claude-code",
+	"kiro-cli",
+	"opencode",
+	"windsurf",
+	"openai-assistants",
+	"vertex-ai",
+	"continue",
+	"agentkit-local",
+	"aws-agentcore",
+	"bedrock-agents",
+	"terraform",
+	"aws-eks",
+	"azure-aks",
+	"gcp-gke",
+	"kubernetes",
+}
+
+// readDeployment reads and parses projectDir's deployment.json.
+func readDeployment(projectDir string) (*Deployment, error) {
+	data, err := os.ReadFile(filepath.Join(projectDir, "deployment.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deployment.json: %w", err)
+	}
+	var deployment Deployment
+	if err := json.Unmarshal(data, &deployment); err != nil {
+		return nil, fmt.Errorf("failed to parse deployment.json: %w", err)
+	}
+	return &deployment, nil
+}
+
+// checkPlatforms reports the first target whose platform is not one of
+// platforms, listing the valid ones.
+func checkPlatforms(targets []Target) error {
+	for _, target := range targets {
+		known := false
+		for _, platform := range platforms {
+			if target.Platform == platform {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("target %s: unknown platform %q (available: %s)", target.Name, target.Platform, strings.Join(platforms, ", "))
+		}
+	}
+	return nil
+}
+
+// jsonSchema is the subset of JSON Schema used to describe deployment.json.
+type jsonSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Properties  map[string]*jsonSchema `json:"properties,omitempty"`
+	Items       *jsonSchema            `json:"items,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+}
+
+// deploymentSchema returns the JSON Schema of Deployment, with the target
+// platforms restricted to those generateForPlatform handles.
+func deploymentSchema() *jsonSchema {
+	target := &jsonSchema{
+		Type:     "object",
+		Required: []string{"name", "platform", "output"},
+		Properties: map[string]*jsonSchema{
+			"name":     {Type: "string", Description: "Target name, used in messages"},
+			"platform": {Type: "string", Description: "Platform to generate for", Enum: platforms},
+			"priority": {Type: "string", Description: "Priority the -priority flag selects targets by (e.g., p1, p2, p3)"},
+			"output":   {Type: "string", Description: "Output directory, relative to the project directory"},
+			"config":   {Type: "object", Description: "Platform-specific options; string values may be secret:// references"},
+			"groups": {
+				Type:        "array",
+				Description: "Agent groups the target generates; empty selects all agents",
+				Items:       &jsonSchema{Type: "string"},
+			},
+		},
+	}
+	return &jsonSchema{
+		Schema:      "https://json-schema.org/draft/2020-12/schema",
+		Title:       "Deployment",
+		Description: "Deployment targets of a multi-agent-spec project (deployment.json), as read by genagents -project.",
+		Type:        "object",
+		Required:    []string{"team", "targets"},
+		Properties: map[string]*jsonSchema{
+			"$schema": {Type: "string"},
+			"team":    {Type: "string", Description: "Team name, used for generated stacks and charts"},
+			"targets": {Type: "array", Description: "Deployment targets", Items: target},
+		},
+	}
+}
+
+// runPrintSchema prints the deployment.json JSON Schema.
+func runPrintSchema(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(deploymentSchema())
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestPlatformsHandled(t *testing.T) {
+	agentList := []*core.Agent{core.NewAgent("reviewer", "Reviews code")}
+	for _, platform := range platforms {
+		target := Target{Name: platform, Platform: platform, Config: map[string]interface{}{"imageRepository": "qa/agents"}}
+		err := generateForPlatform(io.Discard, io.Discard, "qa", agentList, target, t.TempDir(), options{WriteConcurrency: 1})
+		if err != nil && strings.Contains(err.Error(), "unsupported platform") {
+			t.Errorf("platform %s is listed but not generated", platform)
+		}
+	}
+}
+
+func TestRunProjectModeUnknownPlatform(t *testing.T) {
+	project := t.TempDir()
+	deployment := `{"team": "qa", "targets": [{"name": "ide", "platform": "vscode", "output": "out"}]}`
+	if err := core.WriteOutputFile(filepath.Join(project, "deployment.json"), []byte(deployment)); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runProjectMode(io.Discard, io.Discard, project, "", options{})
+	if err == nil || !strings.Contains(err.Error(), `target ide: unknown platform "vscode" (available: claude-code, kiro-cli,`) {
+		t.Errorf("runProjectMode() error = %v, want an unknown platform error", err)
+	}
+}
+
+func TestRunPrintSchema(t *testing.T) {
+	var out bytes.Buffer
+	if err := runPrintSchema(&out); err != nil {
+		t.Fatal(err)
+	}
+	var schema struct {
+		Properties struct {
+			Targets struct {
+				Items struct {
+					Properties map[string]struct {
+						Enum []string `json:"enum"`
+					} `json:"properties"`
+				} `json:"items"`
+			} `json:"targets"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &schema); err != nil {
+		t.Fatalf("schema is not JSON: %v", err)
+	}
+	enum := schema.Properties.Targets.Items.Properties["platform"].Enum
+	if strings.Join(enum, ",") != strings.Join(platforms, ",") {
+		t.Errorf("platform enum = %v, want %v", enum, platforms)
+	}
+}
+
+func TestGenerateForPlatformDroppedTools(t *testing.T) {
+	agentList := []*core.Agent{core.NewAgent("planner", "Plans work").WithTools("Read", "Task")}
+	target := Target{Name: "gemini", Platform: "vertex-ai"}
+
+	var warn bytes.Buffer
+	if err := generateForPlatform(io.Discard, &warn, "qa", agentList, target, t.TempDir(), options{WriteConcurrency: 1}); err != nil {
+		t.Fatalf("generateForPlatform() error = %v", err)
+	}
+	if !strings.Contains(warn.String(), "Warning: vertex has no equivalent for tools of planner, omitting: Task") {
+		t.Errorf("warnings = %q, want the dropped Task tool reported", warn.String())
+	}
+}
+
+func TestGenerateForPlatformContinue(t *testing.T) {
+	agentList := []*core.Agent{core.NewAgent("reviewer", "Reviews code"), core.NewAgent("planner", "Plans work")}
+	target := Target{Name: "ide", Platform: "continue"}
+	outputDir := t.TempDir()
+
+	if err := generateForPlatform(io.Discard, io.Discard, "qa", agentList, target, outputDir, options{}); err != nil {
+		t.Fatalf("generateForPlatform() error = %v", err)
+	}
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "config.yaml" {
+		t.Fatalf("output = %v, want a single config.yaml", entries)
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"name: qa\n", "- name: reviewer\n", "- name: planner\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("config.yaml missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestGenerateForPlatformClaudeIndex(t *testing.T) {
+	agentList := []*core.Agent{core.NewAgent("reviewer", "Reviews code"), core.NewAgent("planner", "Plans work")}
+	target := Target{Name: "claude", Platform: "claude-code", Config: map[string]interface{}{"index": true}}
+	outputDir := t.TempDir()
+
+	opts := options{WriteConcurrency: 1, Only: []string{"reviewer"}}
+	if err := generateForPlatform(io.Discard, io.Discard, "qa", agentList, target, outputDir, opts); err != nil {
+		t.Fatalf("generateForPlatform() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "CLAUDE.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The index lists agents -only skipped
+	if !strings.Contains(string(data), "| [planner](planner.md) | Plans work |") {
+		t.Errorf("CLAUDE.md missing planner:\n%s", data)
+	}
+
+	target.Config["index"] = "yes"
+	if err := generateForPlatform(io.Discard, io.Discard, "qa", agentList, target, t.TempDir(), opts); err == nil {
+		t.Error("generateForPlatform() accepted a non-boolean index")
+	}
+}
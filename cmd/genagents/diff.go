@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// runDiff prints a unified diff between each agent's generated form and its
+// file in outputDir, skipping files that are in sync. Agents are rendered as
+// generation writes them (see renderFile and renderAgentDir), so output
+// templates, headers and line endings never show up as differences; every
+// file of a DirectoryAdapter's output is compared. It returns how many files
+// differ.
+func runDiff(w io.Writer, agentList []*core.Agent, format, outputDir string, opts options) (int, error) {
+	adapter, ok := opts.registry().GetAdapter(format)
+	if !ok {
+		available := opts.registry().AdapterNames()
+		return 0, fmt.Errorf("unknown format %q (available: %s)", format, strings.Join(available, ", "))
+	}
+	dirAdapter, writesDirs := adapter.(core.DirectoryAdapter)
+	if writesDirs && opts.OutputTemplate != nil {
+		return 0, fmt.Errorf("%s writes a directory per agent and does not support output templates", format)
+	}
+
+	differ := 0
+	diffFile := func(path string, data []byte) error {
+		diff, changed, err := opts.registry().DiffFile(path, data)
+		if err != nil {
+			return err
+		}
+		if changed {
+			differ++
+			fmt.Fprint(w, diff)
+		}
+		return nil
+	}
+	for _, agent := range agentList {
+		var err error
+		if writesDirs {
+			err = renderAgentDir(dirAdapter, agent, filepath.Join(outputDir, agent.Name), opts, diffFile)
+		} else {
+			var data []byte
+			if data, _, err = renderFile(adapter, agent, opts, io.Discard); err == nil {
+				err = diffFile(filepath.Join(outputDir, core.AgentPath(adapter, agent)), data)
+			}
+		}
+		if err != nil {
+			return differ, fmt.Errorf("diffing %s: %w", agent.Name, err)
+		}
+	}
+	return differ, nil
+}
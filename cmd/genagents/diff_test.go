@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestRunDiff(t *testing.T) {
+	agentList := []*core.Agent{
+		core.NewAgent("reviewer", "Reviews code").WithInstructions("Review the diff."),
+		core.NewAgent("tester", "Writes tests").WithInstructions("Write table tests."),
+	}
+	outputDir := t.TempDir()
+	opts := options{WriteConcurrency: 1}
+	if err := generateAgents(io.Discard, io.Discard, agentList, "claude", outputDir, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if differ, err := runDiff(&out, agentList, "claude", outputDir, opts); err != nil || differ != 0 || out.Len() != 0 {
+		t.Errorf("runDiff(in sync) = %d, %v, output %q", differ, err, out.String())
+	}
+
+	agentList[1].Instructions = "Write fuzz tests."
+	differ, err := runDiff(&out, agentList, "claude", outputDir, opts)
+	if err != nil || differ != 1 {
+		t.Fatalf("runDiff() = %d, %v, want 1 differing file", differ, err)
+	}
+	if !strings.Contains(out.String(), "tester.md\n") || !strings.Contains(out.String(), "-Write table tests.\n+Write fuzz tests.\n") {
+		t.Errorf("diff =\n%s", out.String())
+	}
+}
+
+func TestRunDiffMatchesGeneration(t *testing.T) {
+	claude, ok := core.GetAdapter("claude")
+	if !ok {
+		t.Fatal("claude adapter is not registered")
+	}
+	registry := core.NewRegistry()
+	registry.Register(claude)
+	registry.SetLineEnding(core.LineEndingCRLF)
+
+	agentList := []*core.Agent{core.NewAgent("reviewer", "Reviews code").WithInstructions("Review the diff.")}
+	outputDir := t.TempDir()
+	opts := options{WriteConcurrency: 1, Registry: registry, LicenseHeader: "SPDX-License-Identifier: MIT", StampCommit: "abc1234"}
+	if err := generateAgents(io.Discard, io.Discard, agentList, "claude", outputDir, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if differ, err := runDiff(&out, agentList, "claude", outputDir, opts); err != nil || differ != 0 {
+		t.Errorf("runDiff(generated) = %d, %v, output:\n%s", differ, err, out.String())
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "reviewer.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte("\r\n")) {
+		t.Errorf("registry line ending not applied: %q", data)
+	}
+
+	// Without the header, the generated files differ
+	if differ, err := runDiff(&out, agentList, "claude", outputDir, options{Registry: registry}); err != nil || differ != 1 {
+		t.Errorf("runDiff(no header) = %d, %v, want 1", differ, err)
+	}
+}
+
+func TestRunDiffDirectoryAdapter(t *testing.T) {
+	claude, ok := core.GetAdapter("claude")
+	if !ok {
+		t.Fatal("claude adapter is not registered")
+	}
+	registry := core.NewRegistry()
+	registry.Register(dirAdapter{claude})
+
+	agentList := []*core.Agent{core.NewAgent("reviewer", "Reviews code").WithInstructions("Review.")}
+	outputDir := t.TempDir()
+	opts := options{WriteConcurrency: 1, Registry: registry, LicenseHeader: "SPDX-License-Identifier: MIT"}
+	if err := generateAgents(io.Discard, io.Discard, agentList, "dir", outputDir, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if differ, err := runDiff(&out, agentList, "dir", outputDir, opts); err != nil || differ != 0 {
+		t.Errorf("runDiff(generated) = %d, %v, output:\n%s", differ, err, out.String())
+	}
+
+	config := filepath.Join(outputDir, "reviewer", "config.json")
+	if err := os.WriteFile(config, []byte("{}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	differ, err := runDiff(&out, agentList, "dir", outputDir, opts)
+	if err != nil || differ != 1 || !strings.Contains(out.String(), "+++ "+config+"\n") {
+		t.Errorf("runDiff(edited) = %d, %v, output:\n%s", differ, err, out.String())
+	}
+}
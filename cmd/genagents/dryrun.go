@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// Actions a dry run reports for each output file.
+const (
+	actionCreate    = "create"
+	actionOverwrite = "overwrite"
+	actionUnchanged = "unchanged"
+)
+
+// errNoChange is returned by -dry-run -error-on-nochange when every output
+// file is already up to date.
+var errNoChange = errors.New("dry run: no files would change")
+
+// dryRunPlan collects the files a -dry-run generation would write.
+type dryRunPlan struct {
+	// ErrorOnNoChange makes finish fail when nothing would change.
+	ErrorOnNoChange bool
+
+	mu      sync.Mutex
+	entries []planEntry
+}
+
+type planEntry struct {
+	Action string
+	Path   string
+}
+
+// planAction reports whether writing data to path would create the file,
+// overwrite it, or leave it unchanged. data is compared after le is
+// applied, as it would be written.
+func planAction(path string, data []byte, le core.LineEnding) (string, error) {
+	existing, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return actionCreate, nil
+	case err != nil:
+		return "", &core.ReadError{Path: path, Err: err}
+	case bytes.Equal(existing, le.Normalize(data)):
+		return actionUnchanged, nil
+	default:
+		return actionOverwrite, nil
+	}
+}
+
+// record adds a planned file.
+func (p *dryRunPlan) record(action, path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.entries = append(p.entries, planEntry{Action: action, Path: path})
+}
+
+// merge adds the files planned in other, in their planned order.
+func (p *dryRunPlan) merge(other *dryRunPlan) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.entries = append(p.entries, other.entries...)
+}
+
+// recordDir plans every file under genDir, a scratch directory the
+// generator wrote to, as if it had been written to outputDir with line
+// ending le instead.
+func (p *dryRunPlan) recordDir(genDir, outputDir string, le core.LineEnding) error {
+	return filepath.WalkDir(genDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(genDir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return &core.ReadError{Path: path, Err: err}
+		}
+		target := filepath.Join(outputDir, rel)
+		action, err := planAction(target, data, le)
+		if err != nil {
+			return err
+		}
+		p.record(action, target)
+		return nil
+	})
+}
+
+// changes returns the planned files that would be created or overwritten.
+func (p *dryRunPlan) changes() []planEntry {
+	var changed []planEntry
+	for _, entry := range p.entries {
+		if entry.Action != actionUnchanged {
+			changed = append(changed, entry)
+		}
+	}
+	return changed
+}
+
+// finish prints the plan and a summary to w.
+func (p *dryRunPlan) finish(w io.Writer) error {
+	counts := make(map[string]int)
+	for _, entry := range p.entries {
+		fmt.Fprintf(w, "%-9s %s\n", entry.Action, entry.Path)
+		counts[entry.Action]++
+	}
+	fmt.Fprintf(w, "Dry run: %d to create, %d to overwrite, %d unchanged\n",
+		counts[actionCreate], counts[actionOverwrite], counts[actionUnchanged])
+
+	if p.ErrorOnNoChange && counts[actionCreate]+counts[actionOverwrite] == 0 {
+		return errNoChange
+	}
+	return nil
+}
+
+// scratchDir returns the directory a project generator should write to:
+// outputDir normally, or a temporary directory under -dry-run. Pass the
+// generator's error to done: under -dry-run it removes the temporary
+// directory and, if generation succeeded, plans its files against
+// outputDir.
+func scratchDir(outputDir string, opts options) (dir string, done func(error) error, err error) {
+	if opts.DryRun == nil {
+		return outputDir, func(err error) error { return err }, nil
+	}
+	tmp, err := os.MkdirTemp("", "genagents-dry-run-")
+	if err != nil {
+		return "", nil, err
+	}
+	return tmp, func(err error) error {
+		defer os.RemoveAll(tmp)
+		if err != nil {
+			return err
+		}
+		return opts.DryRun.recordDir(tmp, outputDir, opts.registry().LineEnding())
+	}, nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestDryRunAgents(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "agents")
+	agentList := []*core.Agent{
+		core.NewAgent("alpha", "First").WithInstructions("Do A."),
+		core.NewAgent("beta", "Second").WithInstructions("Do B."),
+		core.NewAgent("gamma", "Third").WithInstructions("Do C."),
+	}
+
+	// alpha is up to date, beta is stale, gamma is missing
+	writeOpts := options{WriteConcurrency: 2}
+	if err := generateAgents(io.Discard, io.Discard, agentList[:2], "claude", outputDir, writeOpts); err != nil {
+		t.Fatalf("generateAgents() error = %v", err)
+	}
+	stale := filepath.Join(outputDir, "beta.md")
+	if err := os.WriteFile(stale, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	plan := &dryRunPlan{ErrorOnNoChange: true}
+	opts := options{WriteConcurrency: 2, DryRun: plan}
+	if err := generateAgents(io.Discard, io.Discard, agentList, "claude", outputDir, opts); err != nil {
+		t.Fatalf("generateAgents() dry run error = %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := finishOutputs(&out, []string{outputDir}, opts); err != nil {
+		t.Fatalf("finishOutputs() error = %v", err)
+	}
+	for _, want := range []string{
+		"unchanged " + filepath.Join(outputDir, "alpha.md"),
+		"overwrite " + stale,
+		"create    " + filepath.Join(outputDir, "gamma.md"),
+		"Dry run: 1 to create, 1 to overwrite, 1 unchanged",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	if data, _ := os.ReadFile(stale); string(data) != "old" {
+		t.Error("dry run overwrote a file")
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "gamma.md")); !os.IsNotExist(err) {
+		t.Error("dry run created a file")
+	}
+
+	// With everything up to date, -error-on-nochange fails
+	if err := generateAgents(io.Discard, io.Discard, agentList, "claude", outputDir, writeOpts); err != nil {
+		t.Fatal(err)
+	}
+	plan = &dryRunPlan{ErrorOnNoChange: true}
+	opts.DryRun = plan
+	if err := generateAgents(io.Discard, io.Discard, agentList, "claude", outputDir, opts); err != nil {
+		t.Fatal(err)
+	}
+	if err := finishOutputs(&out, nil, opts); !errors.Is(err, errNoChange) {
+		t.Errorf("finishOutputs() error = %v, want errNoChange", err)
+	}
+}
+
+func TestDryRunCreatesNoOutputDir(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "agents")
+	agentList := []*core.Agent{core.NewAgent("alpha", "First").WithInstructions("Do A.")}
+
+	plan := &dryRunPlan{}
+	if err := generateAgents(io.Discard, io.Discard, agentList, "claude", outputDir, options{DryRun: plan}); err != nil {
+		t.Fatalf("generateAgents() dry run error = %v", err)
+	}
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		t.Error("dry run created the output directory")
+	}
+	want := []planEntry{{actionCreate, filepath.Join(outputDir, "alpha.md")}}
+	if !reflect.DeepEqual(plan.entries, want) {
+		t.Errorf("plan = %v, want %v", plan.entries, want)
+	}
+}
+
+func TestDryRunProject(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "agentkit")
+	agentList := []*core.Agent{core.NewAgent("alpha", "First").WithInstructions("Do A.")}
+	target := Target{Name: "local", Platform: "agentkit-local"}
+
+	opts := options{DryRun: &dryRunPlan{}}
+	if err := generateForPlatform(io.Discard, io.Discard, "team", agentList, target, outputDir, opts); err != nil {
+		t.Fatalf("generateForPlatform() error = %v", err)
+	}
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote %s", outputDir)
+	}
+
+	want := planEntry{Action: actionCreate, Path: filepath.Join(outputDir, "config.json")}
+	if len(opts.DryRun.entries) != 1 || opts.DryRun.entries[0] != want {
+		t.Errorf("plan = %+v, want [%+v]", opts.DryRun.entries, want)
+	}
+}
+
+func TestGenerateAgentsSkipsUnchanged(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "agents")
+	agentList := []*core.Agent{
+		core.NewAgent("alpha", "First").WithInstructions("Do A."),
+		core.NewAgent("beta", "Second").WithInstructions("Do B."),
+	}
+	opts := options{WriteConcurrency: 1}
+
+	var out bytes.Buffer
+	if err := generateAgents(&out, io.Discard, agentList, "claude", outputDir, opts); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "(2 written, 0 unchanged)") {
+		t.Errorf("first run output = %q, want both files written", out.String())
+	}
+
+	agentList[1].Instructions = "Do B better."
+	out.Reset()
+	if err := generateAgents(&out, io.Discard, agentList, "claude", outputDir, opts); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "(1 written, 1 unchanged)") {
+		t.Errorf("second run output = %q, want only beta written", out.String())
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// envFlag collects repeated -env key=val flags.
+type envFlag map[string]string
+
+func (f envFlag) String() string {
+	return strings.Join(f.names(), ",")
+}
+
+// names returns the variable names, sorted.
+func (f envFlag) names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (f envFlag) Set(value string) error {
+	name, val, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("invalid -env %q (expected key=val)", value)
+	}
+	f[name] = val
+	return nil
+}
+
+// interpolationVars returns the variables ${NAME} placeholders resolve
+// from: the process environment with fromOS, overridden by env. It returns
+// nil, disabling interpolation, when neither supplies any.
+func interpolationVars(env envFlag, fromOS bool) map[string]string {
+	if len(env) == 0 && !fromOS {
+		return nil
+	}
+	vars := make(map[string]string)
+	if fromOS {
+		for _, kv := range os.Environ() {
+			if name, val, ok := strings.Cut(kv, "="); ok {
+				vars[name] = val
+			}
+		}
+	}
+	for name, val := range env {
+		vars[name] = val
+	}
+	return vars
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestEnvFlag(t *testing.T) {
+	env := envFlag{}
+	fs := flag.NewFlagSet("genagents", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(env, "env", "")
+	if err := fs.Parse([]string{"-env", "TEAM=qa", "-env", "URL=http://x?a=b"}); err != nil {
+		t.Fatal(err)
+	}
+	if env["TEAM"] != "qa" || env["URL"] != "http://x?a=b" {
+		t.Errorf("env = %v", env)
+	}
+	if err := fs.Parse([]string{"-env", "novalue"}); err == nil {
+		t.Error("expected an error for -env without =")
+	}
+
+	t.Setenv("TEAM", "from-os")
+	t.Setenv("REGION", "eu")
+	vars := interpolationVars(env, true)
+	if vars["TEAM"] != "qa" || vars["REGION"] != "eu" {
+		t.Errorf("-env should override the environment: %v", vars)
+	}
+	if interpolationVars(envFlag{}, false) != nil {
+		t.Error("interpolation should be off without -env or -env-from-os")
+	}
+}
+
+func TestPrepareAgentsInterpolates(t *testing.T) {
+	agentList := []*core.Agent{core.NewAgent("reviewer", "Reviews ${TEAM} code").WithInstructions("Post to ${CHANNEL}.")}
+
+	_, err := prepareAgents(io.Discard, agentList, options{Vars: map[string]string{"TEAM": "qa"}})
+	if err == nil || !strings.Contains(err.Error(), "undefined variable CHANNEL") {
+		t.Errorf("prepareAgents() error = %v, want undefined CHANNEL", err)
+	}
+
+	prepared, err := prepareAgents(io.Discard, agentList, options{Vars: map[string]string{"TEAM": "qa"}, AllowUndefined: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prepared[0].Description != "Reviews qa code" || prepared[0].Instructions != "Post to ${CHANNEL}." {
+		t.Errorf("prepared = %+v", prepared[0].Spec)
+	}
+
+	prepared, _ = prepareAgents(io.Discard, agentList, options{})
+	if prepared[0].Description != "Reviews ${TEAM} code" {
+		t.Error("placeholders should be left alone without variables")
+	}
+}
+
+func TestPrepareAgentsWarnsUnknownModel(t *testing.T) {
+	agentList := []*core.Agent{core.NewAgent("scout", "Explores").WithModel("gpt-9")}
+
+	var warn strings.Builder
+	if _, err := prepareAgents(&warn, agentList, options{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(warn.String(), `Warning: agent scout: model "gpt-9" is not a model alias`) {
+		t.Errorf("warnings = %q, want an unknown model warning", warn.String())
+	}
+}
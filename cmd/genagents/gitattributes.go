@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// generatedAttributes marks generated files: GitHub collapses them in diffs
+// and excludes them from language stats, and git keeps one side instead of
+// writing conflict markers into them (regenerate after merging).
+const generatedAttributes = "linguist-generated=true merge=binary"
+
+// markGenerated adds an entry for each output directory to the .gitattributes
+// file in the current directory. Directories that already have an entry are
+// left as they are, so repeated runs don't change the file.
+func markGenerated(w io.Writer, dirs []string, verbose bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	added, err := updateGitattributes(filepath.Join(cwd, ".gitattributes"), cwd, dirs)
+	if err != nil {
+		return err
+	}
+	if len(added) > 0 || verbose {
+		fmt.Fprintf(w, "Added %d .gitattributes entries\n", len(added))
+	}
+	if verbose {
+		for _, pattern := range added {
+			fmt.Fprintf(w, "  %s %s\n", pattern, generatedAttributes)
+		}
+	}
+	return nil
+}
+
+// updateGitattributes appends missing entries for dirs, relative to base, to
+// the .gitattributes file at path. It returns the patterns it added.
+func updateGitattributes(path, base string, dirs []string) ([]string, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, &core.ReadError{Path: path, Err: err}
+	}
+
+	present := map[string]bool{}
+	scanner := bufio.NewScanner(bytes.NewReader(existing))
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 && !strings.HasPrefix(fields[0], "#") {
+			present[fields[0]] = true
+		}
+	}
+
+	var added []string
+	var buf bytes.Buffer
+	for _, dir := range dirs {
+		pattern, err := gitattributesPattern(base, dir)
+		if err != nil {
+			return nil, err
+		}
+		if present[pattern] {
+			continue
+		}
+		present[pattern] = true
+		added = append(added, pattern)
+		fmt.Fprintf(&buf, "%s %s\n", pattern, generatedAttributes)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	out := existing
+	if len(out) > 0 && !bytes.HasSuffix(out, []byte("\n")) {
+		out = append(out, '\n')
+	}
+	out = append(out, buf.Bytes()...)
+
+	mode := core.DefaultFileMode
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.WriteFile(path, out, mode); err != nil {
+		return nil, &core.WriteError{Path: path, Err: err}
+	}
+	return added, nil
+}
+
+// gitattributesPattern returns the pattern matching every file under dir.
+func gitattributesPattern(base, dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(base, abs)
+	if err != nil || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("output directory %s is not below %s; cannot mark it in .gitattributes", dir, base)
+	}
+	if strings.ContainsAny(rel, " \t") {
+		return "", fmt.Errorf("output directory %s contains whitespace; cannot mark it in .gitattributes", dir)
+	}
+	return filepath.ToSlash(rel) + "/**", nil
+}
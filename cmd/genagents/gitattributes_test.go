@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUpdateGitattributes(t *testing.T) {
+	base := t.TempDir()
+	path := filepath.Join(base, ".gitattributes")
+	if err := os.WriteFile(path, []byte("*.png binary\nplugins/kiro/** -diff"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	dirs := []string{
+		filepath.Join(base, ".claude", "agents"),
+		filepath.Join(base, "plugins", "kiro"),
+	}
+	added, err := updateGitattributes(path, base, dirs)
+	if err != nil {
+		t.Fatalf("updateGitattributes() error = %v", err)
+	}
+	if len(added) != 1 || added[0] != ".claude/agents/**" {
+		t.Errorf("added = %v, want [.claude/agents/**]", added)
+	}
+
+	want := "*.png binary\nplugins/kiro/** -diff\n.claude/agents/** " + generatedAttributes + "\n"
+	got, _ := os.ReadFile(path)
+	if string(got) != want {
+		t.Errorf(".gitattributes = %q, want %q", got, want)
+	}
+
+	// A second run must not change the file
+	added, err = updateGitattributes(path, base, dirs)
+	if err != nil {
+		t.Fatalf("updateGitattributes() error = %v", err)
+	}
+	if len(added) != 0 {
+		t.Errorf("second run added %v", added)
+	}
+	if again, _ := os.ReadFile(path); string(again) != want {
+		t.Errorf("second run changed .gitattributes to %q", again)
+	}
+}
+
+func TestUpdateGitattributesOutsideBase(t *testing.T) {
+	base := t.TempDir()
+	_, err := updateGitattributes(filepath.Join(base, ".gitattributes"), base, []string{filepath.Dir(base)})
+	if err == nil {
+		t.Error("expected error for output directory outside base")
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// loadLicenseHeader returns the license header text for -license (an SPDX
+// identifier) or -license-file. Both empty means no header.
+func loadLicenseHeader(spdxID, path string) (string, error) {
+	switch {
+	case spdxID != "" && path != "":
+		return "", errors.New("-license and -license-file are mutually exclusive")
+	case spdxID != "":
+		return core.SPDXHeader(spdxID)
+	case path != "":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", &core.ReadError{Path: path, Err: err}
+		}
+		return strings.TrimSpace(string(data)), nil
+	default:
+		return "", nil
+	}
+}
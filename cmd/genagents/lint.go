@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// runLint prints one line per lint warning and a summary, and returns the
+// number of warnings. Warnings do not fail -lint; they do fail -ci.
+func runLint(w io.Writer, agentList []*core.Agent) int {
+	warnings := core.Lint(agentList)
+	for _, warning := range warnings {
+		fmt.Fprintln(w, warning)
+	}
+	fmt.Fprintf(w, "Lint: %d warning(s) in %d agents\n", len(warnings), len(agentList))
+	return len(warnings)
+}
+
+// runLintFix rewrites the spec files of agentList to fix tool casing and
+// lists the files it changed.
+func runLintFix(w io.Writer, agentList []*core.Agent) error {
+	seen := make(map[string]bool)
+	fixed := 0
+	for _, agent := range agentList {
+		path := agent.SourcePath
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return &core.ReadError{Path: path, Err: err}
+		}
+		out, changed, err := core.FixToolCasing(path, data)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		if err := writeSpec(path, path, out); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "fixed %s\n", path)
+		fixed++
+	}
+	fmt.Fprintf(w, "Fixed tool casing in %d file(s)\n", fixed)
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintFixMiscasedTool(t *testing.T) {
+	specDir := t.TempDir()
+	spec := filepath.Join(specDir, "reviewer.md")
+	if err := os.WriteFile(spec, []byte("---\nname: reviewer\ndescription: Reviews code\ntools: [bash, mcp__github__create_issue]\n---\n\nReview the diff.\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Loading must not reject the tools -fix is meant to repair
+	agentList, err := readSpecs(specDir, options{})
+	if err != nil {
+		t.Fatalf("readSpecs() error = %v", err)
+	}
+	if err := checkSpecs(agentList, options{}); err != nil {
+		t.Fatalf("checkSpecs() error = %v", err)
+	}
+
+	var out bytes.Buffer
+	// Unknown tools are reported, but only as warnings
+	if n := runLint(&out, agentList); n != 2 || !strings.Contains(out.String(), `tools[0] "bash" is not a known tool (did you mean "Bash"?)`) ||
+		!strings.Contains(out.String(), `tools[1] "mcp__github__create_issue" is not a known tool; it is passed through unchanged`) {
+		t.Errorf("runLint() = %d:\n%s", n, out.String())
+	}
+	if err := runLintFix(&out, agentList); err != nil {
+		t.Fatalf("runLintFix() error = %v", err)
+	}
+	data, err := os.ReadFile(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Bash") || strings.Contains(string(data), "bash,") {
+		t.Errorf("spec after -fix:\n%s", data)
+	}
+}
+
+func TestGeneratePassesUnknownTools(t *testing.T) {
+	specDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(specDir, "triager.md"), []byte("---\nname: triager\ndescription: Files issues\ntools: [Read, mcp__github__create_issue]\n---\n\nFile issues.\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	agentList, err := readSpecs(specDir, options{})
+	if err != nil {
+		t.Fatalf("readSpecs() error = %v", err)
+	}
+
+	outputDir := t.TempDir()
+	if err := generateAgents(io.Discard, io.Discard, agentList, "claude", outputDir, options{WriteConcurrency: 1}); err != nil {
+		t.Fatalf("generateAgents() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "triager.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "mcp__github__create_issue") {
+		t.Errorf("unknown tool not passed through:\n%s", data)
+	}
+
+	err = generateAgents(io.Discard, io.Discard, agentList, "claude", t.TempDir(), options{WriteConcurrency: 1, StrictTools: true})
+	if err == nil {
+		t.Error("generateAgents(-strict-tools) accepted an unknown tool")
+	}
+}
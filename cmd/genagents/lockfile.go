@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// writeLockfile records content hashes of every file in dirs at path,
+// using the hash algorithm of registry. Hashed paths are relative to the
+// lockfile's directory.
+func writeLockfile(w io.Writer, registry *core.Registry, path string, dirs []string, verbose bool) error {
+	lock, err := registry.NewLockfile(filepath.Dir(path), dirs)
+	if err != nil {
+		return err
+	}
+	if err := core.WriteLockfile(lock, path); err != nil {
+		return err
+	}
+	if verbose {
+		fmt.Fprintf(w, "Wrote %s lockfile for %d files to %s\n", lock.HashAlgorithm, len(lock.Files), path)
+	}
+	return nil
+}
+
+// runVerifyLockfile lists files that no longer match the lockfile at path,
+// hashed with the algorithm recorded in it. It fails if any drifted.
+func runVerifyLockfile(w io.Writer, path string) error {
+	lock, err := core.ReadLockfile(path)
+	if err != nil {
+		return err
+	}
+	drifted, err := lock.Verify(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	for _, rel := range drifted {
+		fmt.Fprintf(w, "drifted: %s\n", rel)
+	}
+	if len(drifted) > 0 {
+		return fmt.Errorf("%d of %d locked files changed since %s was written", len(drifted), len(lock.Files), path)
+	}
+	fmt.Fprintf(w, "All %d locked files match %s (%s)\n", len(lock.Files), path, lock.HashAlgorithm)
+	return nil
+}
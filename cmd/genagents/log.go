@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// newLogger returns the logger for -log-format and -log-level, writing to
+// w. Verbose lowers an unset level to debug, where the detailed progress
+// of generation is logged.
+func newLogger(w io.Writer, format, level string, verbose bool) (*slog.Logger, error) {
+	var lvl slog.Level
+	switch {
+	case level == "" && verbose:
+		lvl = slog.LevelDebug
+	case level == "":
+		lvl = slog.LevelWarn
+	default:
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("invalid -log-level %q (want debug, info, warn or error)", level)
+		}
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, handlerOpts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, handlerOpts)), nil
+	default:
+		return nil, fmt.Errorf("invalid -log-format %q (want text or json)", format)
+	}
+}
+
+// logger returns the logger set in o, or one discarding every record.
+func (o options) logger() *slog.Logger {
+	if o.Logger != nil {
+		return o.Logger
+	}
+	return slog.New(slog.DiscardHandler)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestNewLogger(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		level     string
+		verbose   bool
+		wantDebug bool
+		wantInfo  bool
+		wantErr   bool
+	}{
+		{name: "default", format: "text"},
+		{name: "verbose", format: "text", verbose: true, wantDebug: true, wantInfo: true},
+		{name: "info", format: "json", level: "info", wantInfo: true},
+		{name: "level overrides verbose", format: "text", level: "warn", verbose: true},
+		{name: "bad format", format: "xml", wantErr: true},
+		{name: "bad level", format: "text", level: "loud", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := newLogger(&buf, tt.format, tt.level, tt.verbose)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newLogger() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			logger.Debug("debug")
+			logger.Info("info")
+			if got := strings.Contains(buf.String(), "msg=debug") || strings.Contains(buf.String(), `"msg":"debug"`); got != tt.wantDebug {
+				t.Errorf("debug logged = %v, want %v:\n%s", got, tt.wantDebug, buf.String())
+			}
+			if got := strings.Contains(buf.String(), "info"); got != tt.wantInfo {
+				t.Errorf("info logged = %v, want %v:\n%s", got, tt.wantInfo, buf.String())
+			}
+		})
+	}
+}
+
+func TestRunProjectModeLogsTargetTimings(t *testing.T) {
+	project := t.TempDir()
+	spec := "---\nname: reviewer\ndescription: Reviews code\n---\n\nReview the diff.\n"
+	if err := core.WriteOutputFile(filepath.Join(project, "agents", "reviewer.md"), []byte(spec)); err != nil {
+		t.Fatal(err)
+	}
+	deployment := `{"team": "qa", "targets": [
+		{"name": "claude", "platform": "claude-code", "output": "out/claude"},
+		{"name": "kiro", "platform": "kiro-cli", "output": "out/kiro"}
+	]}`
+	if err := core.WriteOutputFile(filepath.Join(project, "deployment.json"), []byte(deployment)); err != nil {
+		t.Fatal(err)
+	}
+
+	var logs, out bytes.Buffer
+	logger, err := newLogger(&logs, "json", "info", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runProjectMode(&out, &out, project, "", options{Logger: logger}); err != nil {
+		t.Fatalf("runProjectMode() error = %v", err)
+	}
+
+	timed := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var record struct {
+			Msg      string  `json:"msg"`
+			Target   string  `json:"target"`
+			Duration float64 `json:"duration"`
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", line, err)
+		}
+		if record.Msg == "generated target" {
+			timed[record.Target] = true
+		}
+	}
+	if !timed["claude"] || !timed["kiro"] {
+		t.Errorf("targets timed = %v, want claude and kiro:\n%s", timed, logs.String())
+	}
+	if strings.Contains(logs.String(), `"level":"DEBUG"`) {
+		t.Errorf("debug records logged at info level:\n%s", logs.String())
+	}
+	if strings.Contains(out.String(), "level=") || !strings.Contains(out.String(), "Generated 1 claude agents") {
+		t.Errorf("output = %q, want the summary without log records", out.String())
+	}
+}
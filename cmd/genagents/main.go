@@ -5,61 +5,722 @@
 //	genagents -spec=plugins/spec/agents -output=.claude/agents -format=claude
 //	genagents -spec=plugins/spec/agents -output=plugins/kiro/agents -format=kiro
 //	genagents -spec=plugins/spec/agents -targets=claude:.claude/agents,kiro:plugins/kiro/agents
+//	genagents -spec=plugins/spec/agents -recursive -output=.claude/agents -format=claude
+//	genagents -spec=agents.yaml -output=.claude/agents -format=claude
+//
+// Generate a very large spec directory one agent at a time:
+//
+//	genagents -spec=plugins/spec/agents -stream -output=.claude/agents -format=claude
+//
+// Merge a shared agent library with project agents, which replace shared
+// agents of the same name:
+//
+//	genagents -spec=shared/agents -spec=agents -output=.claude/agents
 //
 // Multi-agent-spec format (reads deployment.json for targets):
 //
 //	genagents -project=examples/stats-agent-team
 //	genagents -project=examples/stats-agent-team -priority=p1
+//	genagents -project=examples/stats-agent-team -synth-check
+//	genagents -project=examples/stats-agent-team -merge
+//	genagents -project=examples/stats-agent-team -concurrency=2
+//	genagents -project=examples/stats-agent-team -manifest=build/generated.json
+//
+// Check a spec directory, or a project and its target platforms, without
+// generating anything (exit 1 on any problem):
+//
+//	genagents -spec=plugins/spec/agents -validate
+//	genagents -project=examples/stats-agent-team -validate
+//
+// Check staged spec files from a git pre-commit hook (no generation):
+//
+//	git diff --cached --name-only --diff-filter=ACM -- '*.md' '*.json' | xargs genagents -validate-only
+//
+// Read specs from a shared Git repository (shallow clone, cached per ref):
+//
+//	genagents -spec=git+https://github.com/org/agents//specs@v1.2.0 -output=.claude/agents
+//
+// Preview generated output in a browser while editing specs:
+//
+//	genagents -spec=plugins/spec/agents -serve=:8080
+//
+// Rewrite specs in canonical form, or verify they already are:
+//
+//	genagents -spec=plugins/spec/agents -normalize
+//	genagents -spec=plugins/spec/agents -normalize -check
+//
+// List the files a run would create or overwrite, without writing them
+// (-error-on-nochange exits 1 when everything is up to date):
+//
+//	genagents -spec=plugins/spec/agents -targets=claude:.claude/agents,kiro:plugins/kiro/agents -dry-run
+//	genagents -project=examples/stats-agent-team -dry-run -error-on-nochange
+//
+// Run every check in one CI step (validate, lint, generated files current,
+// lockfile), with a consolidated report and exit 1 on any failure:
+//
+//	genagents -spec=plugins/spec/agents -targets=claude:.claude/agents -verify-lockfile=agents.lock -ci
+//	genagents -project=examples/stats-agent-team -ci
+//
+// Log progress to stderr as JSON, with per-target timings at info level
+// (-verbose alone logs every file at debug level as text):
+//
+//	genagents -project=examples/stats-agent-team -log-format=json -log-level=info
+//
+// Show how generated files differ from those checked in (exit 1 if any do):
+//
+//	genagents -spec=plugins/spec/agents -format=claude -output=.claude/agents -diff
+//
+// Resolve ${NAME} placeholders in instructions and descriptions per
+// deployment (generated configs, such as agentkit's ${ANTHROPIC_API_KEY},
+// are left alone):
+//
+//	genagents -spec=plugins/spec/agents -output=.claude/agents -env TEAM=payments -env BASE_URL=https://pay.example.com
+//	genagents -project=examples/stats-agent-team -env-from-os -allow-undefined
+//
+// Regenerate whenever a spec changes while editing (Ctrl-C to stop):
+//
+//	genagents -spec=plugins/spec/agents -output=.claude/agents -watch
+//
+// Show the configuration a run would use, with secrets redacted:
+//
+//	genagents -project=examples/stats-agent-team -priority=p1 -print-config
+//
+// Summarize the specs without generating anything:
+//
+//	genagents -spec=plugins/spec/agents -count
+//	genagents -spec=plugins/spec/agents -count -json
+//
+// Report likely problems in specs without generating anything:
+//
+//	genagents -spec=plugins/spec/agents -lint
+//	genagents -spec=plugins/spec/agents -lint -fix
+//
+// Write a JSON catalog with each agent's output for every format:
+//
+//	genagents -spec=plugins/spec/agents -catalog=catalog.json
+//
+// Upsert the agents into a SQLite database for services that query them:
+//
+//	genagents -spec=plugins/spec/agents -db=agents.db
+//
+// Record content hashes of the output, and later check it for drift:
+//
+//	genagents -spec=plugins/spec/agents -output=.claude/agents -lockfile=agents.lock -hash-algo=sha512
+//	genagents -verify-lockfile=agents.lock
+//
+// Add a license header in each format's comment syntax (JSON formats get a
+// "_license" field):
+//
+//	genagents -spec=plugins/spec/agents -output=.claude/agents -license=Apache-2.0
+//
+// Stamp each generated file with the spec repository's commit in a comment
+// (formats with comments only):
+//
+//	genagents -spec=plugins/spec/agents -output=.claude/agents -stamp-version
+//
+// Wrap each generated agent file in a custom envelope (text/template with
+// .Content, .Agent and .Format):
+//
+//	genagents -spec=plugins/spec/agents -output=.claude/agents -output-template=header.tmpl
+//
+// Regenerate only the named agents (formats writing one file for the whole
+// team are still generated in full):
+//
+//	genagents -project=. -only=reviewer,planner
+//
+// Print the JSON Schema of deployment.json for editor validation:
+//
+//	genagents -print-schema > deployment.schema.json
+//
+// Check that every registered adapter round-trips the built-in samples:
+//
+//	genagents -selftest
+//
+// Print which canonical tools each registered format can represent:
+//
+//	genagents -tool-matrix
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/agentplexus/assistantkit/agents"
 	"github.com/agentplexus/assistantkit/agents/agentkit"
 	"github.com/agentplexus/assistantkit/agents/awsagentcore"
+	"github.com/agentplexus/assistantkit/agents/claude"
+	continuedev "github.com/agentplexus/assistantkit/agents/continue"
 	"github.com/agentplexus/assistantkit/agents/core"
+	"github.com/agentplexus/assistantkit/agents/helm"
+	"github.com/agentplexus/assistantkit/agents/sqlitecatalog"
 	"github.com/agentplexus/assistantkit/skills"
 	skillscore "github.com/agentplexus/assistantkit/skills/core"
 
 	// Import adapters to register them
-	_ "github.com/agentplexus/assistantkit/agents/claude"
 	_ "github.com/agentplexus/assistantkit/agents/kiro"
 	_ "github.com/agentplexus/assistantkit/skills/kiro"
 )
 
+// options holds generation settings shared by every target.
+type options struct {
+	Verbose bool
+
+	// StrictTools rejects tools outside the adapter's supported set.
+	StrictTools bool
+
+	// Only limits the agent files generated to these agent names. Formats
+	// writing one file for the whole team are still generated in full.
+	Only []string
+
+	// WarningsAsErrors fails generation when an adapter notes a lossy
+	// conversion (see core.Warning).
+	WarningsAsErrors bool
+
+	// Secrets resolves secret:// values in deployment target configs.
+	Secrets core.SecretResolver
+
+	// Gitattributes marks output directories as generated in .gitattributes.
+	Gitattributes bool
+
+	// Merge keeps the non-agent sections of an existing agentkit config
+	// and replaces only its agents.
+	Merge bool
+
+	// SynthCheck runs `cdk synth` on generated CDK projects.
+	SynthCheck bool
+
+	// Lockfile, if set, is where content hashes of the output are recorded.
+	Lockfile string
+
+	// OutputTemplate, if set, wraps each generated agent file.
+	OutputTemplate *template.Template
+
+	// LicenseHeader, if set, is prepended to each generated agent file in
+	// the format's comment syntax.
+	LicenseHeader string
+
+	// StampCommit, if set, is the spec repository's git short SHA, stamped
+	// into a comment in each generated agent file whose format has
+	// comments.
+	StampCommit string
+
+	// AllowUnknownCategory accepts agent categories that are not known
+	// marketplace categories.
+	AllowUnknownCategory bool
+
+	// Locale selects the localized description emitted for each agent.
+	Locale string
+
+	// NoDeprecated skips deprecated agents instead of generating them with
+	// a deprecation notice.
+	NoDeprecated bool
+
+	// WriteConcurrency bounds the agent files written at once.
+	WriteConcurrency int
+
+	// Concurrency bounds the -project targets generated at once.
+	Concurrency int
+
+	// Manifest is where -project generation writes its GenerationManifest
+	// (default <project>/generated.json).
+	Manifest string
+
+	// Recursive reads specs with ReadCanonicalTree instead of
+	// ReadCanonicalDir.
+	Recursive bool
+
+	// AllowOverlap lets -project targets share or nest output directories.
+	AllowOverlap bool
+
+	// DryRun, if set, collects the files generation would write instead
+	// of writing them.
+	DryRun *dryRunPlan
+
+	// Vars, if set, resolve ${NAME} placeholders in agent instructions and
+	// descriptions; nil leaves placeholders as they are.
+	Vars map[string]string
+
+	// AllowUndefined leaves placeholders of variables missing from Vars
+	// as they are instead of failing.
+	AllowUndefined bool
+
+	// Registry, if set, is where formats are looked up instead of
+	// core.DefaultRegistry.
+	Registry *core.Registry
+
+	// Logger, if set, receives generation progress: files and targets at
+	// debug level, per-target timings at info level. Nil discards it.
+	Logger *slog.Logger
+}
+
+// registry returns the adapter registry generation looks formats up in.
+func (o options) registry() *core.Registry {
+	if o.Registry != nil {
+		return o.Registry
+	}
+	return core.DefaultRegistry
+}
+
 func main() {
-	specDir := flag.String("spec", "plugins/spec/agents", "Directory containing canonical agent specs (.md files)")
+	specs := &specList{dirs: []string{"plugins/spec/agents"}}
+	flag.Var(specs, "spec", "Directory containing canonical agent specs (.md files), a single .yaml/.yml/.json file listing agents, or a Git source git+<url>//<subpath>@<ref>; repeat to merge specs, later ones replacing agents of the same name")
+	stream := flag.Bool("stream", false, "With -output, read and generate the spec directory one agent at a time instead of loading every spec first (for very large spec directories)")
+	recursive := flag.Bool("recursive", false, "Read .md and .json specs from every subdirectory of the spec directory, without namespacing agents by subdirectory (names must be unique)")
 	skillsDir := flag.String("skills", "", "Directory containing canonical skill specs (.md files)")
 	skillsOutput := flag.String("skills-output", "", "Output directory for generated skills/steering files")
 	outputDir := flag.String("output", "", "Output directory for generated agents")
-	format := flag.String("format", "claude", "Output format (claude, kiro, agentkit, aws-agentcore)")
+	format := flag.String("format", "claude", "Output format (claude, kiro, opencode, cursor, windsurf, openai, gemini, vertex, continue, agentkit, aws-agentcore, bedrock-agents); gemini is the Gemini CLI, vertex is Vertex AI Gemini agents")
 	targets := flag.String("targets", "", "Multiple targets as format:dir pairs (e.g., claude:.claude/agents,kiro:plugins/kiro/agents)")
 	project := flag.String("project", "", "Multi-agent-spec project directory (reads deployment.json)")
 	priority := flag.String("priority", "", "Filter by priority (p1, p2, p3) - only with -project")
 	install := flag.Bool("install", false, "Install generated files to user config directory (e.g., ~/.kiro/)")
 	prefix := flag.String("prefix", "", "Prefix for installed files (e.g., 'myteam' -> 'myteam_agent.json')")
-	verbose := flag.Bool("verbose", false, "Verbose output")
+	verbose := flag.Bool("verbose", false, "Verbose output (logs generation progress at debug level unless -log-level is set)")
+	logFormat := flag.String("log-format", "text", "Format of the progress log on stderr: text or json")
+	logLevel := flag.String("log-level", "", "Minimum level of the progress log: debug, info (adds per-target timings), warn or error (default warn)")
+	serve := flag.String("serve", "", "Serve a live preview of generated agents on this address (e.g., :8080)")
+	lineEndings := flag.String("line-endings", "", "Normalize generated files to lf or crlf line endings with a final newline (default: unchanged)")
+	outputTemplate := flag.String("output-template", "", "text/template file wrapping each generated agent file ({{.Content}}, {{.Agent}}, {{.Format}})")
+	allowUnknownCategory := flag.Bool("allow-unknown-category", false, "Accept agent categories that are not known marketplace categories")
+	gitattributes := flag.Bool("gitattributes", false, "Mark output directories as generated in ./.gitattributes (appends missing entries only)")
+	license := flag.String("license", "", "Prepend an SPDX-License-Identifier header for this license (e.g., Apache-2.0) to generated agent files")
+	licenseFile := flag.String("license-file", "", "Prepend the contents of this file as a license header to generated agent files")
+	stampVersion := flag.Bool("stamp-version", false, "Stamp generated agent files with the git short SHA of the spec repository in a comment (formats with comments only)")
+	merge := flag.Bool("merge", false, "For agentkit-local targets, replace only the agents in an existing config.json and keep its other sections")
+	synthCheck := flag.Bool("synth-check", false, "Run cdk synth on generated CDK projects and fail if it errors (skipped if the CDK CLI is unavailable)")
+	lockfile := flag.String("lockfile", "", "Write content hashes of the generated files to this lockfile")
+	verifyLockfile := flag.String("verify-lockfile", "", "Check generated files against this lockfile (using its recorded hash algorithm) and exit")
+	hashAlgo := flag.String("hash-algo", core.HashSHA256, "Hash algorithm for -lockfile (sha256, sha512)")
+	descriptionStyles := flag.String("description-style", "", "Per-format description limits as format:mode:max pairs, mode truncate or wrap (e.g., kiro:truncate:80)")
+	strictTools := flag.Bool("strict-tools", false, "Reject tools the target format cannot map instead of passing them through")
+	only := flag.String("only", "", "Generate only these agents (comma-separated names); formats writing one file for the whole team are still generated in full")
+	warningsAsErrors := flag.Bool("warnings-as-errors", false, "Fail generation if a format notes a lossy conversion (e.g., an unmapped tool or unknown model)")
+	selftest := flag.Bool("selftest", false, "Round-trip built-in sample agents through every registered adapter and exit")
+	normalize := flag.Bool("normalize", false, "Rewrite specs in canonical form (in place, or to -output)")
+	check := flag.Bool("check", false, "With -normalize, list specs that are not normalized and fail instead of rewriting")
+	validate := flag.Bool("validate", false, "Validate the specs in -spec (or -project, including its target platforms) without generating anything; print one line per problem and exit 1 on any")
+	validateOnly := flag.Bool("validate-only", false, "Parse, validate, and lint the spec files given as arguments (e.g., staged files in a pre-commit hook); exit 1 on any error")
+	catalog := flag.String("catalog", "", "Write a JSON catalog of every agent with its output for each registered format to this file and exit")
+	writeConcurrency := flag.Int("write-concurrency", defaultWriteConcurrency, "Maximum number of agent files written concurrently")
+	concurrency := flag.Int("concurrency", runtime.GOMAXPROCS(0), "Maximum number of -project targets generated concurrently")
+	dbPath := flag.String("db", "", "Upsert every agent into this SQLite database (creating the agents table if absent) and exit")
+	locale := flag.String("locale", "", "Emit agent descriptions for this locale (e.g., de, pt-BR), falling back to the default description")
+	noDeprecated := flag.Bool("no-deprecated", false, "Skip deprecated agents instead of generating them with a deprecation notice")
+	printSchema := flag.Bool("print-schema", false, "Print the JSON Schema of deployment.json and exit")
+	toolMatrix := flag.Bool("tool-matrix", false, "Print a table of canonical tools by format (yes, no, or unknown for formats that do not declare their tools) and exit")
+	printConfig := flag.Bool("print-config", false, "Print the resolved configuration (spec, targets, filters, options, secret sources) as JSON with secrets redacted, and exit")
+	count := flag.Bool("count", false, "Print how many agents load, by model, group, category and namespace, and exit")
+	jsonOutput := flag.Bool("json", false, "With -count, print the summary as JSON")
+	lint := flag.Bool("lint", false, "Report likely problems in specs (e.g., instructions mentioning undeclared tools) and exit")
+	fix := flag.Bool("fix", false, "With -lint, rewrite specs to use canonical tool casing (e.g., bash -> Bash)")
+	validateMCP := flag.Bool("validate-mcp", false, "Start or contact each MCP server declared by the specs and report unreachable ones")
+	mcpTimeout := flag.Duration("mcp-timeout", 10*time.Second, "Per-server timeout for -validate-mcp")
+	secrets := flag.String("secrets", "env", "Resolver for secret:// values in deployment configs (env, aws-secretsmanager)")
+	secretsRegion := flag.String("secrets-region", "", "AWS region for -secrets=aws-secretsmanager (default: AWS CLI configuration)")
+	env := envFlag{}
+	flag.Var(env, "env", "Resolve ${key} placeholders in agent instructions and descriptions to val (key=val, repeatable)")
+	envFromOS := flag.Bool("env-from-os", false, "Resolve ${NAME} placeholders in agent instructions and descriptions from the process environment (-env takes precedence)")
+	allowUndefined := flag.Bool("allow-undefined", false, "Leave placeholders of undefined variables as they are instead of failing")
+	watch := flag.Bool("watch", false, "Generate, then regenerate whenever a spec changes, until interrupted")
+	diff := flag.Bool("diff", false, "Print a unified diff between each agent's generated form and its file in -output, and exit 1 if any differ")
+	dryRun := flag.Bool("dry-run", false, "Print each file generation would create or overwrite, or leave unchanged, without writing anything")
+	manifest := flag.String("manifest", "", "Where -project writes a JSON manifest of each target's files and content hashes (default <project>/generated.json)")
+	allowOverlap := flag.Bool("allow-overlap", false, "Allow -project targets with the same or nested output directories (warn instead of failing)")
+	errorOnNoChange := flag.Bool("error-on-nochange", false, "With -dry-run, exit 1 if no file would change")
+	ci := flag.Bool("ci", false, "Validate and lint specs, check generated files are current, and verify the lockfile; print a consolidated report and exit 1 if any check fails")
 	flag.Parse()
 
+	if *printSchema {
+		if err := runPrintSchema(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *toolMatrix {
+		if err := runToolMatrix(os.Stdout, options{}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Handle adapter conformance check
+	if *selftest {
+		if err := runSelfTest(os.Stdout, *verbose, options{}); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Handle pre-commit validation of individual spec files
+	if *validateOnly {
+		opts := options{AllowUnknownCategory: *allowUnknownCategory}
+		if n := runValidateOnly(os.Stdout, flag.Args(), opts); n > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Handle lockfile verification (part of -ci otherwise)
+	if *verifyLockfile != "" && !*ci {
+		if err := runVerifyLockfile(os.Stdout, *verifyLockfile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Only generation reads more than one -spec
+	specDir, extraSpecs := &specs.dirs[0], specs.dirs[1:]
+	if len(extraSpecs) > 0 {
+		for _, mode := range []struct {
+			flag string
+			set  bool
+		}{{"project", *project != ""}, {"normalize", *normalize}, {"validate", *validate}, {"ci", *ci}, {"serve", *serve != ""}, {"watch", *watch}} {
+			if mode.set {
+				fmt.Fprintf(os.Stderr, "Error: -%s cannot be combined with more than one -spec\n", mode.flag)
+				os.Exit(1)
+			}
+		}
+	}
+
+	// Handle spec normalization
+	if *normalize {
+		if core.IsGitSource(*specDir) {
+			fmt.Fprintf(os.Stderr, "Error: -normalize rewrites local specs and cannot be used with a Git source\n")
+			os.Exit(1)
+		}
+		if err := runNormalize(os.Stdout, *specDir, *outputDir, *check, *verbose); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *errorOnNoChange && !*dryRun {
+		fmt.Fprintf(os.Stderr, "Error: -error-on-nochange requires -dry-run\n")
+		os.Exit(1)
+	}
+	if *dryRun && (*skillsDir != "" || *install) {
+		fmt.Fprintf(os.Stderr, "Error: -dry-run cannot be combined with -skills or -install\n")
+		os.Exit(1)
+	}
+
+	if *fix && (!*lint || core.IsGitSource(*specDir)) {
+		fmt.Fprintf(os.Stderr, "Error: -fix requires -lint and a local -spec directory\n")
+		os.Exit(1)
+	}
+
+	// Fetch Git spec sources into the local cache
+	specSource := ""
+	if core.IsGitSource(*specDir) {
+		specSource = *specDir
+		dir, err := core.ResolveSpecDir(context.Background(), *specDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if *verbose {
+			fmt.Printf("Using %s from %s\n", *specDir, dir)
+		}
+		*specDir = dir
+	}
+
+	resolver, err := secretResolver(*secrets, *secretsRegion)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	tmpl, err := loadOutputTemplate(*outputTemplate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	licenseHeader, err := loadLicenseHeader(*license, *licenseFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	stampCommit := ""
+	if *stampVersion {
+		stampDir := *specDir
+		if *project != "" {
+			stampDir = *project
+		}
+		if stampCommit, err = specCommit(stampDir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	opts := options{
+		Verbose:        *verbose,
+		StrictTools:    *strictTools,
+		Secrets:        resolver,
+		Gitattributes:  *gitattributes,
+		Lockfile:       *lockfile,
+		SynthCheck:     *synthCheck,
+		Merge:          *merge,
+		OutputTemplate: tmpl,
+		LicenseHeader:  licenseHeader,
+		StampCommit:    stampCommit,
+
+		AllowUnknownCategory: *allowUnknownCategory,
+		WarningsAsErrors:     *warningsAsErrors,
+		Only:                 parseOnly(*only),
+		NoDeprecated:         *noDeprecated,
+		Locale:               *locale,
+		WriteConcurrency:     *writeConcurrency,
+		Concurrency:          *concurrency,
+		AllowOverlap:         *allowOverlap,
+		Recursive:            *recursive,
+		Manifest:             *manifest,
+		Vars:                 interpolationVars(env, *envFromOS),
+		AllowUndefined:       *allowUndefined,
+	}
+	if *dryRun {
+		opts.DryRun = &dryRunPlan{ErrorOnNoChange: *errorOnNoChange}
+	}
+	if opts.Logger, err = newLogger(os.Stderr, *logFormat, *logLevel, *verbose); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := opts.registry().SetHashAlgorithm(*hashAlgo); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	le, err := core.ParseLineEnding(*lineEndings)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	opts.registry().SetLineEnding(le)
+
+	if *locale != "" {
+		if err := core.ValidateLocale(*locale); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := setDescriptionStyles(*descriptionStyles, opts.registry()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Handle configuration dump
+	if *printConfig {
+		cfg := effectiveConfig{
+			Spec:       *specDir,
+			SpecSource: specSource,
+			MergeSpecs: extraSpecs,
+			Format:     *format,
+			Output:     *outputDir,
+			Filters: effectiveFilters{
+				Priority:     *priority,
+				NoDeprecated: *noDeprecated,
+				Locale:       *locale,
+				Only:         opts.Only,
+			},
+			Options: effectiveOptions{
+				StrictTools:          *strictTools,
+				WarningsAsErrors:     *warningsAsErrors,
+				AllowUnknownCategory: *allowUnknownCategory,
+				Merge:                *merge,
+				SynthCheck:           *synthCheck,
+				Gitattributes:        *gitattributes,
+				Lockfile:             *lockfile,
+				HashAlgorithm:        opts.registry().HashAlgorithm(),
+				LineEndings:          *lineEndings,
+				DescriptionStyles:    *descriptionStyles,
+				OutputTemplate:       *outputTemplate,
+				LicenseHeader:        licenseHeader,
+				StampVersion:         *stampVersion,
+				SecretsResolver:      *secrets,
+				SecretsRegion:        *secretsRegion,
+				WriteConcurrency:     *writeConcurrency,
+				Concurrency:          *concurrency,
+				AllowOverlap:         *allowOverlap,
+				Recursive:            *recursive,
+				Stream:               *stream,
+				Manifest:             *manifest,
+				DryRun:               *dryRun,
+				EnvFromOS:            *envFromOS,
+				AllowUndefined:       *allowUndefined,
+			},
+		}
+		if len(env) > 0 {
+			cfg.Options.Env = env.names()
+		}
+		if cfg.Targets, err = parseFlagTargets(*targets); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := runPrintConfig(os.Stdout, cfg, *project); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Handle spec validation without generation
+	if *validate {
+		var conflicts []string
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "output", "targets", "format", "skills-output", "install", "dry-run", "diff", "watch", "lockfile", "manifest":
+				conflicts = append(conflicts, "-"+f.Name)
+			}
+		})
+		if len(conflicts) > 0 {
+			fmt.Fprintf(os.Stderr, "Error: -validate generates nothing and cannot be combined with %s\n", strings.Join(conflicts, ", "))
+			os.Exit(1)
+		}
+		validateDir := *specDir
+		var deploymentTargets []Target
+		if *project != "" {
+			validateDir = filepath.Join(*project, "agents")
+			deployment, err := readDeployment(*project)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			deploymentTargets = deployment.Targets
+		}
+		if n := runValidate(os.Stdout, validateDir, deploymentTargets, opts); n > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Handle the CI preset
+	if *ci {
+		cfg := ciConfig{SpecDir: *specDir, Project: *project, Priority: *priority, Lockfile: *verifyLockfile, Opts: opts}
+		if cfg.Lockfile == "" {
+			cfg.Lockfile = *lockfile
+		}
+		if cfg.Targets, err = parseFlagTargets(*targets); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if cfg.Targets == nil && *outputDir != "" {
+			cfg.Targets = []flagTarget{{Format: *format, Output: *outputDir}}
+		}
+		if err := runCI(os.Stdout, ciChecks(cfg)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Handle preview server mode
+	if *serve != "" {
+		if err := runServe(*serve, *specDir, *verbose, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Handle regeneration on spec changes
+	if *watch {
+		if specSource != "" || *dryRun {
+			fmt.Fprintf(os.Stderr, "Error: -watch requires a local -spec directory and cannot be combined with -dry-run\n")
+			os.Exit(1)
+		}
+		watchDir := *specDir
+		generate := func() error {
+			return runProjectMode(os.Stdout, os.Stderr, *project, *priority, opts)
+		}
+		if *project != "" {
+			watchDir = filepath.Join(*project, "agents")
+		} else {
+			flagTargets, err := parseFlagTargets(*targets)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if flagTargets == nil && *outputDir != "" {
+				flagTargets = []flagTarget{{Format: *format, Output: *outputDir}}
+			}
+			if len(flagTargets) == 0 {
+				fmt.Fprintf(os.Stderr, "Error: -watch requires -output, -targets or -project\n")
+				os.Exit(1)
+			}
+			generate = func() error {
+				return generateFlagTargets(os.Stdout, os.Stderr, *specDir, flagTargets, opts)
+			}
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if err := runWatch(ctx, os.Stdout, watchDir, generate); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Handle multi-agent-spec project mode
 	if *project != "" {
-		if err := runProjectMode(*project, *priority, *verbose); err != nil {
+		if err := runProjectMode(os.Stdout, os.Stderr, *project, *priority, opts); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 		return
 	}
 
-	// Read canonical agents from spec directory
-	agentList, err := agents.ReadCanonicalDir(*specDir)
+	// Handle streaming single-target generation
+	if *stream {
+		for _, mode := range []struct {
+			flag string
+			set  bool
+		}{{"targets", *targets != ""}, {"recursive", *recursive}, {"count", *count}, {"lint", *lint}, {"catalog", *catalog != ""}, {"db", *dbPath != ""}, {"validate-mcp", *validateMCP}, {"diff", *diff}, {"skills", *skillsDir != ""}, {"install", *install}} {
+			if mode.set {
+				fmt.Fprintf(os.Stderr, "Error: -stream cannot be combined with -%s\n", mode.flag)
+				os.Exit(1)
+			}
+		}
+		if *outputDir == "" || len(extraSpecs) > 0 {
+			fmt.Fprintf(os.Stderr, "Error: -stream requires -output and a single -spec directory\n")
+			os.Exit(1)
+		}
+		if err := streamAgents(os.Stdout, os.Stderr, *specDir, *format, *outputDir, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating agents: %v\n", err)
+			os.Exit(1)
+		}
+		if err := finishOutputs(os.Stdout, []string{*outputDir}, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Read canonical agents from the spec directories
+	agentList, err := mergeSpecs(append([]string{*specDir}, extraSpecs...), opts)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading spec directory %s: %v\n", *specDir, err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
@@ -69,14 +730,92 @@ func main() {
 	}
 
 	if *verbose {
-		fmt.Printf("Found %d agents in %s\n", len(agentList), *specDir)
-		for _, agent := range agentList {
-			fmt.Printf("  - %s: %s\n", agent.Name, agent.Description)
+		listAgents(os.Stdout, agentList, specs.dirs)
+	}
+
+	// Handle inventory summary
+	if *count {
+		if opts.NoDeprecated {
+			agentList = core.FilterDeprecated(agentList)
 		}
+		if err := runCount(os.Stdout, *specDir, agentList, *jsonOutput); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := checkSpecs(agentList, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Handle spec linting
+	if *lint {
+		runLint(os.Stdout, agentList)
+		if *fix {
+			if err := runLintFix(os.Stdout, agentList); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		return
+	}
+
+	// Handle catalog generation
+	if *catalog != "" {
+		if err := writeCatalog(*catalog, agentList, *verbose); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Handle SQLite catalog export
+	if *dbPath != "" {
+		if err := sqlitecatalog.WriteFile(context.Background(), *dbPath, agentList); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Wrote %d agents to %s\n", len(agentList), *dbPath)
+		return
+	}
+
+	// Handle MCP reachability check
+	if *validateMCP {
+		if err := runValidateMCP(os.Stdout, agentList, *mcpTimeout, *verbose); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	agentList, err = prepareAgents(os.Stderr, agentList, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Handle comparison against checked-in output
+	if *diff {
+		if *outputDir == "" {
+			fmt.Fprintf(os.Stderr, "Error: -diff requires -output\n")
+			os.Exit(1)
+		}
+		differ, err := runDiff(os.Stdout, agentList, *format, *outputDir, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if differ > 0 {
+			os.Exit(1)
+		}
+		return
 	}
 
 	// Handle multiple targets
 	if *targets != "" {
+		var generated []string
 		targetPairs := strings.Split(*targets, ",")
 		for _, pair := range targetPairs {
 			parts := strings.SplitN(pair, ":", 2)
@@ -87,10 +826,15 @@ func main() {
 			targetFormat := strings.TrimSpace(parts[0])
 			targetDir := strings.TrimSpace(parts[1])
 
-			if err := generateAgents(agentList, targetFormat, targetDir, *verbose); err != nil {
+			if err := generateAgents(os.Stdout, os.Stderr, agentList, targetFormat, targetDir, opts); err != nil {
 				fmt.Fprintf(os.Stderr, "Error generating %s agents: %v\n", targetFormat, err)
 				os.Exit(1)
 			}
+			generated = append(generated, targetDir)
+		}
+		if err := finishOutputs(os.Stdout, generated, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
 		return
 	}
@@ -103,10 +847,14 @@ func main() {
 	}
 
 	if *outputDir != "" {
-		if err := generateAgents(agentList, *format, *outputDir, *verbose); err != nil {
+		if err := generateAgents(os.Stdout, os.Stderr, agentList, *format, *outputDir, opts); err != nil {
 			fmt.Fprintf(os.Stderr, "Error generating agents: %v\n", err)
 			os.Exit(1)
 		}
+		if err := finishOutputs(os.Stdout, []string{*outputDir}, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
 	// Handle skills generation
@@ -188,35 +936,357 @@ func runSkillsGeneration(skillsDir, outputDir, format string, verbose bool) erro
 	return nil
 }
 
-func generateAgents(agentList []*core.Agent, format, outputDir string, verbose bool) error {
-	// Ensure output directory exists
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+// checkSpecs validates spec fields that every generation mode relies on,
+// and that every -only name is an agent.
+func checkSpecs(agentList []*core.Agent, opts options) error {
+	names := make([]string, len(agentList))
+	for i, agent := range agentList {
+		names[i] = agent.Name
+	}
+	if err := checkOnly(names, opts.Only); err != nil {
+		return err
+	}
+	return checkAgents(agentList, opts)
+}
+
+// checkAgents validates the spec fields of agentList that checkSpecs
+// checks, one agent at a time.
+func checkAgents(agentList []*core.Agent, opts options) error {
+	if err := core.CheckModelFallbacks(agentList); err != nil {
+		return err
+	}
+	if err := core.CheckMaxTurns(agentList); err != nil {
+		return err
+	}
+	if err := core.CheckScopes(agentList); err != nil {
+		return err
+	}
+	if err := core.CheckArguments(agentList); err != nil {
+		return err
+	}
+	if err := core.CheckLocales(agentList); err != nil {
+		return err
+	}
+	if err := core.CheckTimeouts(agentList); err != nil {
+		return err
+	}
+	if err := core.CheckRetries(agentList); err != nil {
+		return err
+	}
+	if !opts.AllowUnknownCategory {
+		if err := core.CheckCategories(agentList); err != nil {
+			return fmt.Errorf("%w (use -allow-unknown-category to override)", err)
+		}
+	}
+	return nil
+}
+
+// prepareAgents readies loaded agents for generation: model warnings (see
+// core.Agent.Warnings) are reported to w, descriptions are localized for
+// opts.Locale, placeholders are resolved from opts.Vars, and
+// deprecated agents produce a warning and are either dropped
+// (NoDeprecated) or get a deprecation notice prepended to their
+// instructions.
+func prepareAgents(w io.Writer, agentList []*core.Agent, opts options) ([]*core.Agent, error) {
+	out := make([]*core.Agent, 0, len(agentList))
+	for _, agent := range agentList {
+		for _, msg := range agent.Warnings() {
+			fmt.Fprintf(w, "Warning: agent %s: %s\n", agent.Name, msg)
+		}
+		agent = core.Localize(agent, opts.Locale)
+		switch {
+		case opts.Vars == nil:
+		case opts.AllowUndefined:
+			agent = core.InterpolatePartial(agent, opts.Vars)
+		default:
+			var err error
+			if agent, err = core.Interpolate(agent, opts.Vars); err != nil {
+				return nil, err
+			}
+		}
+		if !agent.Deprecated {
+			out = append(out, agent)
+			continue
+		}
+		if opts.NoDeprecated {
+			fmt.Fprintf(w, "Warning: skipping deprecated agent %s\n", agent.Name)
+			continue
+		}
+		fmt.Fprintf(w, "Warning: agent %s is deprecated\n", agent.Name)
+		out = append(out, core.WithDeprecationNotice(agent))
+	}
+	return out, nil
+}
+
+// warnModelFallback notes agents whose fallback chain the format cannot
+// express; only the first model is emitted for them.
+func warnModelFallback(w io.Writer, agentList []*core.Agent, adapter core.Adapter) {
+	if core.SupportsModelFallback(adapter) {
+		return
+	}
+	for _, agent := range agentList {
+		if len(agent.ModelFallback) > 1 {
+			fmt.Fprintf(w, "Warning: %s does not support model fallback; %s uses %s only\n", adapter.Name(), agent.Name, agent.ModelFallback[0])
+		}
+	}
+}
+
+// warnDroppedTools notes the tools of each agent that the format has no
+// equivalent for and omits.
+func warnDroppedTools(w io.Writer, agentList []*core.Agent, adapter core.Adapter) {
+	for _, agent := range agentList {
+		if dropped := core.DroppedTools(adapter, agent); len(dropped) > 0 {
+			fmt.Fprintf(w, "Warning: %s has no equivalent for tools of %s, omitting: %s\n", adapter.Name(), agent.Name, strings.Join(dropped, ", "))
+		}
+	}
+}
+
+func generateAgents(w, warn io.Writer, agentList []*core.Agent, format, outputDir string, opts options) error {
+	agentList = selectOnly(agentList, opts.Only)
+
+	g, err := newAgentGenerator(w, warn, format, outputDir, opts)
+	if err != nil {
+		return err
+	}
+	if err := g.check(agentList); err != nil {
+		return err
+	}
+
+	// Write the agents, at most opts.WriteConcurrency at a time
+	results := make([]agentResult, len(agentList))
+	err = forEachLimited(opts.WriteConcurrency, len(agentList), func(i int) error {
+		var err error
+		results[i], err = g.write(agentList[i])
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return g.finish(results)
+}
+
+// agentGenerator writes agents in one format to an output directory, one
+// agent at a time, so the agents can come from a slice or a stream.
+type agentGenerator struct {
+	w, warn    io.Writer
+	adapter    core.Adapter
+	dirAdapter core.DirectoryAdapter
+	writesDirs bool
+	format     string
+	outputDir  string
+	opts       options
+}
+
+// agentResult records what writing one agent did.
+type agentResult struct {
+	path     string
+	action   string
+	dirPlan  *dryRunPlan
+	changed  bool
+	warnings []core.Warning
+}
+
+// newAgentGenerator creates outputDir and looks up the format's adapter.
+func newAgentGenerator(w, warn io.Writer, format, outputDir string, opts options) (*agentGenerator, error) {
+	// Ensure output directory exists, unless planning a dry run
+	if opts.DryRun == nil {
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	// Get the adapter
+	adapter, ok := opts.registry().GetAdapter(format)
+	if !ok {
+		available := opts.registry().AdapterNames()
+		return nil, fmt.Errorf("unknown format %q (available: %s)", format, strings.Join(available, ", "))
+	}
+
+	dirAdapter, writesDirs := adapter.(core.DirectoryAdapter)
+	if writesDirs && opts.OutputTemplate != nil {
+		return nil, fmt.Errorf("%s writes a directory per agent and does not support output templates", format)
+	}
+
+	return &agentGenerator{
+		w:          w,
+		warn:       warn,
+		adapter:    adapter,
+		dirAdapter: dirAdapter,
+		writesDirs: writesDirs,
+		format:     format,
+		outputDir:  outputDir,
+		opts:       opts,
+	}, nil
+}
+
+// check rejects agents whose output files would clash and, under
+// -strict-tools, tools the format cannot map, and warns about what the
+// format drops from agentList.
+func (g *agentGenerator) check(agentList []*core.Agent) error {
+	// Each agent is written to a file or directory named after it
+	if err := core.CheckUniqueOutputNames(agentList); err != nil {
+		return err
+	}
+	if g.opts.StrictTools {
+		if err := core.CheckTools(agentList, g.adapter); err != nil {
+			return err
+		}
+	}
+	warnModelFallback(g.warn, agentList, g.adapter)
+	warnDroppedTools(g.warn, agentList, g.adapter)
+	return nil
+}
+
+// write writes one agent, or plans it under -dry-run. Files that already
+// hold the output are not rewritten. It is safe for concurrent use.
+func (g *agentGenerator) write(agent *core.Agent) (agentResult, error) {
+	var res agentResult
+	opts := g.opts
+	if g.writesDirs {
+		res.path = filepath.Join(g.outputDir, agent.Name)
+		if opts.DryRun != nil {
+			res.dirPlan = &dryRunPlan{}
+		}
+		var err error
+		res.changed, err = writeAgentDir(g.dirAdapter, agent, res.path, res.dirPlan, opts)
+		return res, err
+	}
+
+	res.path = filepath.Join(g.outputDir, core.AgentPath(g.adapter, agent))
+
+	data, warnings, err := renderFile(g.adapter, agent, opts, g.warn)
+	if err != nil {
+		return res, err
+	}
+	res.warnings = warnings
+	registry := opts.registry()
+	if opts.DryRun == nil && opts.OutputTemplate == nil && opts.LicenseHeader == "" && opts.StampCommit == "" &&
+		registry.LineEnding() == core.OutputLineEnding() {
+		// WriteFile may write more than the agent file (e.g., skill
+		// files), so let it write and compare the agent file first. It
+		// applies the line ending of core.DefaultRegistry, so this is
+		// only done when the registry's matches.
+		action, err := planAction(res.path, data, registry.LineEnding())
+		if err != nil {
+			return res, err
+		}
+		if err := g.adapter.WriteFile(agent, res.path); err != nil {
+			return res, fmt.Errorf("failed to write %s: %w", res.path, err)
+		}
+		res.changed = action != actionUnchanged
+		return res, nil
+	}
+
+	if opts.DryRun != nil {
+		res.action, err = planAction(res.path, data, registry.LineEnding())
+		return res, err
+	}
+	if res.changed, err = registry.WriteFileIfChanged(res.path, data); err != nil {
+		return res, fmt.Errorf("failed to write %s: %w", res.path, err)
+	}
+	return res, nil
+}
+
+// finish reports the conversion warnings of the written agents, then
+// records them in the dry-run plan or logs and summarizes them.
+func (g *agentGenerator) finish(results []agentResult) error {
+	warnings := make([][]core.Warning, len(results))
+	for i, res := range results {
+		warnings[i] = res.warnings
+	}
+	if err := reportWarnings(g.warn, g.format, warnings, g.opts.WarningsAsErrors); err != nil {
+		return err
+	}
+
+	if g.opts.DryRun != nil {
+		for _, res := range results {
+			if res.dirPlan != nil {
+				g.opts.DryRun.merge(res.dirPlan)
+			} else {
+				g.opts.DryRun.record(res.action, res.path)
+			}
+		}
+		return nil
+	}
+
+	logger := g.opts.logger()
+	written := 0
+	for _, res := range results {
+		if res.changed {
+			written++
+			logger.Debug("generated", "format", g.format, "path", res.path)
+		} else {
+			logger.Debug("unchanged", "format", g.format, "path", res.path)
+		}
+	}
+
+	fmt.Fprintf(g.w, "Generated %d %s agents in %s (%d written, %d unchanged)\n", len(results), g.format, g.outputDir, written, len(results)-written)
+	return nil
+}
+
+// renderFile renders agent with adapter as generation writes it: through
+// the output template, then with the license header and -stamp-version
+// comment of opts. The line ending is applied when the file is written.
+func renderFile(adapter core.Adapter, agent *core.Agent, opts options, warn io.Writer) ([]byte, []core.Warning, error) {
+	data, warnings, err := renderAgent(adapter, agent, opts.OutputTemplate, warn)
+	if err != nil {
+		return nil, nil, err
+	}
+	return opts.addHeaders(adapter.FileExtension(), data), warnings, nil
+}
+
+// writeAgentDir writes agent into dir with a DirectoryAdapter (see
+// renderAgentDir). Only files whose content changed are rewritten, and with
+// plan set nothing is written: the files are planned instead. It reports
+// whether any file was written.
+func writeAgentDir(adapter core.DirectoryAdapter, agent *core.Agent, dir string, plan *dryRunPlan, opts options) (bool, error) {
+	changed := false
+	err := renderAgentDir(adapter, agent, dir, opts, func(target string, data []byte) error {
+		if plan != nil {
+			action, err := planAction(target, data, opts.registry().LineEnding())
+			if err != nil {
+				return err
+			}
+			plan.record(action, target)
+			return nil
+		}
+		written, err := opts.registry().WriteFileIfChanged(target, data)
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		changed = changed || written
+		return nil
+	})
+	return changed, err
+}
+
+// renderAgentDir generates agent with a DirectoryAdapter into a scratch
+// directory, adds the license header and -stamp-version comment of opts to
+// each file, and calls fn with each file's path under dir and its content.
+func renderAgentDir(adapter core.DirectoryAdapter, agent *core.Agent, dir string, opts options, fn func(target string, data []byte) error) error {
+	tmp, err := os.MkdirTemp("", "genagents-")
+	if err != nil {
+		return err
 	}
+	defer os.RemoveAll(tmp)
 
-	// Get the adapter
-	adapter, ok := core.GetAdapter(format)
-	if !ok {
-		available := core.AdapterNames()
-		return fmt.Errorf("unknown format %q (available: %s)", format, strings.Join(available, ", "))
+	if err := adapter.WriteDir(agent, tmp); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dir, err)
 	}
-
-	// Write each agent
-	for _, agent := range agentList {
-		filename := agent.Name + adapter.FileExtension()
-		path := filepath.Join(outputDir, filename)
-
-		if err := adapter.WriteFile(agent, path); err != nil {
-			return fmt.Errorf("failed to write %s: %w", path, err)
+	return filepath.WalkDir(tmp, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
 		}
-
-		if verbose {
-			fmt.Printf("Generated %s\n", path)
+		rel, err := filepath.Rel(tmp, path)
+		if err != nil {
+			return err
 		}
-	}
-
-	fmt.Printf("Generated %d %s agents in %s\n", len(agentList), format, outputDir)
-	return nil
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return &core.ReadError{Path: path, Err: err}
+		}
+		return fn(filepath.Join(dir, rel), opts.addHeaders(filepath.Ext(path), data))
+	})
 }
 
 // Deployment represents deployment.json from multi-agent-spec format.
@@ -233,30 +1303,37 @@ type Target struct {
 	Priority string                 `json:"priority"`
 	Output   string                 `json:"output"`
 	Config   map[string]interface{} `json:"config"`
+
+	// Groups limits the target to agents in these groups. Empty selects all agents.
+	Groups []string `json:"groups,omitempty"`
 }
 
-// runProjectMode processes a multi-agent-spec project directory.
-func runProjectMode(projectDir, priorityFilter string, verbose bool) error {
-	// Read deployment.json
-	deploymentPath := filepath.Join(projectDir, "deployment.json")
-	deploymentData, err := os.ReadFile(deploymentPath)
+// readSpecs reads the canonical specs in dir, as one tree with -recursive.
+// A dir that is a file is read as a bundle listing every agent.
+func readSpecs(dir string, opts options) ([]*core.Agent, error) {
+	agentList, err := agents.ReadCanonicalSpecs(dir, opts.Recursive)
 	if err != nil {
-		return fmt.Errorf("failed to read deployment.json: %w", err)
+		return nil, err
 	}
+	return agents.ResolveCanonical(agentList)
+}
 
-	var deployment Deployment
-	if err := json.Unmarshal(deploymentData, &deployment); err != nil {
-		return fmt.Errorf("failed to parse deployment.json: %w", err)
+// runProjectMode processes a multi-agent-spec project directory.
+func runProjectMode(w, warn io.Writer, projectDir, priorityFilter string, opts options) error {
+	deployment, err := readDeployment(projectDir)
+	if err != nil {
+		return err
 	}
-
-	if verbose {
-		fmt.Printf("Processing project: %s\n", deployment.Team)
-		fmt.Printf("Found %d deployment targets\n", len(deployment.Targets))
+	if err := checkPlatforms(deployment.Targets); err != nil {
+		return fmt.Errorf("invalid deployment.json: %w", err)
 	}
 
+	logger := opts.logger()
+	logger.Debug("processing project", "team", deployment.Team, "targets", len(deployment.Targets))
+
 	// Read agents from agents/ directory
 	agentsDir := filepath.Join(projectDir, "agents")
-	agentList, err := agents.ReadCanonicalDir(agentsDir)
+	agentList, err := readSpecs(agentsDir, opts)
 	if err != nil {
 		return fmt.Errorf("failed to read agents: %w", err)
 	}
@@ -265,81 +1342,384 @@ func runProjectMode(projectDir, priorityFilter string, verbose bool) error {
 		return fmt.Errorf("no agents found in %s", agentsDir)
 	}
 
-	if verbose {
-		fmt.Printf("Found %d agents:\n", len(agentList))
-		for _, agent := range agentList {
-			fmt.Printf("  - %s\n", agent.Name)
+	if logger.Enabled(context.Background(), slog.LevelDebug) {
+		names := make([]string, len(agentList))
+		for i, agent := range agentList {
+			names[i] = agent.Name
 		}
+		logger.Debug("found agents", "count", len(agentList), "agents", names)
+	}
+
+	if err := validateTargetGroups(deployment.Targets, agentList); err != nil {
+		return err
 	}
 
-	// Process each target
+	// Only the targets generated in this run can overwrite each other
+	var selected []Target
 	for _, target := range deployment.Targets {
-		// Filter by priority if specified
-		if priorityFilter != "" && target.Priority != priorityFilter {
-			if verbose {
-				fmt.Printf("Skipping %s (priority %s, filter %s)\n", target.Name, target.Priority, priorityFilter)
-			}
-			continue
+		if priorityFilter == "" || target.Priority == priorityFilter {
+			selected = append(selected, target)
+		} else {
+			logger.Debug("skipping target", "target", target.Name, "priority", target.Priority, "filter", priorityFilter)
 		}
+	}
+	if err := checkOutputOverlap(warn, selected, projectDir, opts.AllowOverlap); err != nil {
+		return err
+	}
 
-		outputDir := filepath.Join(projectDir, target.Output)
+	if err := checkSpecs(agentList, opts); err != nil {
+		return err
+	}
+	agentList, err = prepareAgents(warn, agentList, opts)
+	if err != nil {
+		return err
+	}
 
-		if verbose {
-			fmt.Printf("\nProcessing target: %s (%s)\n", target.Name, target.Platform)
-			fmt.Printf("  Output: %s\n", outputDir)
+	// Resolve every target before generating any
+	for i, target := range selected {
+		// Resolve secret:// references on a copy; values are never logged
+		if opts.Secrets != nil {
+			resolved, err := core.ResolveSecrets(context.Background(), opts.Secrets, target.Config)
+			if err != nil {
+				return fmt.Errorf("target %s: %w", target.Name, err)
+			}
+			target.Config = resolved
+		}
+
+		if selected[i], err = resolveTemplateDir(target, projectDir); err != nil {
+			return err
 		}
+	}
+
+	// Targets sharing files (allowed by -allow-overlap) must not be
+	// written at the same time
+	concurrency := opts.Concurrency
+	if len(findOutputOverlaps(selected, projectDir)) > 0 {
+		concurrency = 1
+	}
 
-		if err := generateForPlatform(deployment.Team, agentList, target, outputDir, verbose); err != nil {
+	// Generate the targets concurrently, buffering each target's log so
+	// the output reads in target order
+	logs := make([]targetLog, len(selected))
+	generated := make([]string, len(selected))
+	agentCounts := make([]int, len(selected))
+	err = forEachLimited(concurrency, len(selected), func(i int) error {
+		target, log := selected[i], &logs[i]
+		outputDir := filepath.Join(projectDir, target.Output)
+		generated[i] = outputDir
+
+		start := time.Now()
+		targetLogger := logger.With("target", target.Name, "platform", target.Platform)
+		targetAgents := core.FilterGroups(agentList, target.Groups)
+		agentCounts[i] = len(targetAgents)
+		targetLogger.Debug("processing target", "output", outputDir, "groups", target.Groups, "agents", len(targetAgents))
+
+		targetOpts := opts
+		targetOpts.Logger = targetLogger
+		if opts.DryRun != nil {
+			log.plan = &dryRunPlan{}
+			targetOpts.DryRun = log.plan
+		}
+		if err := generateForPlatform(&log.out, &log.warn, deployment.Team, targetAgents, target, outputDir, targetOpts); err != nil {
 			return fmt.Errorf("failed to generate %s: %w", target.Name, err)
 		}
+		targetLogger.Info("generated target", "agents", len(targetAgents), "duration", time.Since(start))
+		return nil
+	})
+	for i := range logs {
+		logs[i].flush(w, warn, opts.DryRun)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := finishOutputs(w, generated, opts); err != nil || opts.DryRun != nil {
+		return err
 	}
 
+	manifestPath := opts.Manifest
+	if manifestPath == "" {
+		manifestPath = filepath.Join(projectDir, defaultManifest)
+	}
+	manifest, err := newGenerationManifest(opts.registry(), deployment.Team, projectDir, selected, agentCounts, filepath.Clean(manifestPath))
+	if err != nil {
+		return err
+	}
+	return writeManifest(w, manifestPath, manifest, opts.Verbose)
+}
+
+// finishOutputs runs the post-generation steps for the output directories:
+// marking them in .gitattributes and recording the lockfile, if enabled.
+// Under -dry-run it prints the plan instead.
+func finishOutputs(w io.Writer, dirs []string, opts options) error {
+	if opts.DryRun != nil {
+		return opts.DryRun.finish(w)
+	}
+	if opts.Gitattributes {
+		if err := markGenerated(w, dirs, opts.Verbose); err != nil {
+			return err
+		}
+	}
+	if opts.Lockfile != "" {
+		return writeLockfile(w, opts.registry(), opts.Lockfile, dirs, opts.Verbose)
+	}
 	return nil
 }
 
+// parseFlagTargets parses a -targets value of comma-separated format:dir
+// pairs.
+func parseFlagTargets(s string) ([]flagTarget, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var targets []flagTarget
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid target format: %s (expected format:dir)", pair)
+		}
+		targets = append(targets, flagTarget{Format: strings.TrimSpace(parts[0]), Output: strings.TrimSpace(parts[1])})
+	}
+	return targets, nil
+}
+
 // generateForPlatform generates output for a specific platform.
-func generateForPlatform(teamName string, agentList []*core.Agent, target Target, outputDir string, verbose bool) error {
+func generateForPlatform(w, warn io.Writer, teamName string, agentList []*core.Agent, target Target, outputDir string, opts options) error {
+	agentList, err := applyModelOverrides(warn, target, agentList)
+	if err != nil {
+		return err
+	}
+	agentList, err = applyToolFilters(warn, target, agentList)
+	if err != nil {
+		return err
+	}
+
 	switch target.Platform {
 	case "claude-code":
-		return generateAgents(agentList, "claude", outputDir, verbose)
+		index, err := configBool(target, "index")
+		if err != nil {
+			return err
+		}
+		if err := generateAgents(w, warn, agentList, "claude", outputDir, opts); err != nil {
+			return err
+		}
+		if !index {
+			return nil
+		}
+		// Index every agent, including those -only skips
+		indexPath := filepath.Join(outputDir, claude.IndexFile)
+		dir, done, err := scratchDir(outputDir, opts)
+		if err != nil {
+			return err
+		}
+		if err := done(claude.WriteIndex(agentList, filepath.Join(dir, claude.IndexFile))); err != nil {
+			return err
+		}
+		if opts.DryRun != nil {
+			return nil
+		}
+		fmt.Fprintf(w, "Generated Claude index: %s\n", indexPath)
+		return nil
 
 	case "kiro-cli":
-		return generateAgents(agentList, "kiro", outputDir, verbose)
+		return generateAgents(w, warn, agentList, "kiro", outputDir, opts)
+
+	case "opencode":
+		return generateAgents(w, warn, agentList, "opencode", outputDir, opts)
+
+	case "windsurf":
+		return generateAgents(w, warn, agentList, "windsurf", outputDir, opts)
+
+	case "openai-assistants":
+		return generateAgents(w, warn, agentList, "openai", outputDir, opts)
+
+	case "vertex-ai":
+		return generateAgents(w, warn, agentList, "vertex", outputDir, opts)
+
+	case "continue":
+		// Generate one Continue config holding every agent
+		if err := core.CheckUniqueOutputNames(agentList); err != nil {
+			return err
+		}
+		configPath := filepath.Join(outputDir, "config.yaml")
+		dir, done, err := scratchDir(outputDir, opts)
+		if err != nil {
+			return err
+		}
+		if err := done(continuedev.WriteFullConfig(teamName, agentList, filepath.Join(dir, "config.yaml"))); err != nil {
+			return err
+		}
+		if opts.DryRun != nil {
+			return nil
+		}
+		fmt.Fprintf(w, "Generated Continue config: %s\n", configPath)
+		return nil
 
 	case "agentkit-local":
 		// Generate full agentkit config
+		if err := core.CheckUniqueOutputNames(agentList); err != nil {
+			return err
+		}
+		if err := checkStrictTools(agentList, "agentkit", opts); err != nil {
+			return err
+		}
+		mapping, err := toolMappingOverrides(target)
+		if err != nil {
+			return err
+		}
+		format, err := configString(target, "toolAnalytics")
+		if err != nil {
+			return err
+		}
+		apiKey, err := configString(target, "apiKey")
+		if err != nil {
+			return err
+		}
+		cfg := agentkit.GenerateFullConfigWithMapping(agentList, mapping)
+		if format != "" {
+			if format != agentkit.AnalyticsJSON && format != agentkit.AnalyticsOTEL {
+				return fmt.Errorf("unknown toolAnalytics format %q (available: %s, %s)", format, agentkit.AnalyticsJSON, agentkit.AnalyticsOTEL)
+			}
+			cfg.Analytics = &agentkit.AnalyticsConfig{Format: format, ToolCalls: true}
+		}
+		if apiKey != "" {
+			cfg.LLM.APIKey = apiKey
+		}
+		attrKeys, err := resourceAttributeKeys(target)
+		if err != nil {
+			return err
+		}
+		if err := agentkit.ApplyResourceAttributes(cfg, agentList, teamName, attrKeys); err != nil {
+			return fmt.Errorf("target %s: %w", target.Name, err)
+		}
 		configPath := filepath.Join(outputDir, "config.json")
-		if err := agentkit.WriteFullConfig(agentList, configPath); err != nil {
+		if opts.Merge {
+			existing, err := agentkit.ReadConfig(configPath)
+			switch {
+			case err == nil:
+				cfg = agentkit.MergeAgents(existing, cfg)
+			case !errors.Is(err, fs.ErrNotExist):
+				return err
+			}
+		}
+		dir, done, err := scratchDir(outputDir, opts)
+		if err != nil {
+			return err
+		}
+		if err := done(agentkit.WriteConfig(cfg, filepath.Join(dir, "config.json"))); err != nil {
 			return err
 		}
-		fmt.Printf("Generated agentkit config: %s\n", configPath)
+		if opts.DryRun != nil {
+			return nil
+		}
+		fmt.Fprintf(w, "Generated agentkit config: %s\n", configPath)
 		return nil
 
-	case "aws-agentcore":
-		// Generate CDK project
+	case "aws-agentcore", "bedrock-agents", "terraform":
+		// Generate CDK project, or Terraform module
+		toolFormat := target.Platform
+		if toolFormat == "terraform" {
+			toolFormat = "bedrock-agents" // Same action group mapping
+		}
+		if err := checkStrictTools(agentList, toolFormat, opts); err != nil {
+			return err
+		}
 		config := &awsagentcore.AgentCoreConfig{
 			StackName: toPascalCase(teamName) + "Stack",
 		}
 		// Apply config from deployment.json if present
-		if region, ok := target.Config["region"].(string); ok {
-			config.Region = region
+		for _, option := range []struct {
+			key   string
+			field *string
+		}{
+			{"region", &config.Region},
+			{"foundationModel", &config.FoundationModel},
+			{"lambdaRuntime", &config.LambdaRuntime},
+			{"guardrailId", &config.GuardrailID},
+			{"guardrailVersion", &config.GuardrailVersion},
+		} {
+			value, err := configString(target, option.key)
+			if err != nil {
+				return err
+			}
+			*option.field = value
+		}
+		format, err := configString(target, "toolAnalytics")
+		if err != nil {
+			return err
+		}
+		if format != "" {
+			if _, known := awsagentcore.GetAnalyticsFormat(format); !known {
+				return fmt.Errorf("unknown toolAnalytics format %q (available: %s)", format, strings.Join(awsagentcore.AnalyticsFormatNames(), ", "))
+			}
+			config.ToolAnalytics = format
+		}
+		attrKeys, err := resourceAttributeKeys(target)
+		if err != nil {
+			return err
+		}
+		config.ResourceAttributes = attrKeys
+		if config.TemplateDir, err = configString(target, "templateDir"); err != nil {
+			return err
 		}
-		if model, ok := target.Config["foundationModel"].(string); ok {
-			config.FoundationModel = model
+		if config.Memory, err = configMemory(target); err != nil {
+			return err
 		}
-		if runtime, ok := target.Config["lambdaRuntime"].(string); ok {
-			config.LambdaRuntime = runtime
+		if err := config.Validate(); err != nil {
+			return fmt.Errorf("target %s: %w", target.Name, err)
 		}
 
-		if err := awsagentcore.WriteCDKProject(teamName, agentList, outputDir, config); err != nil {
+		writeProject := awsagentcore.WriteCDKProject
+		switch target.Platform {
+		case "bedrock-agents":
+			writeProject = awsagentcore.WriteBedrockAgentProject
+		case "terraform":
+			writeProject = awsagentcore.WriteTerraformModule
+		}
+		dir, done, err := scratchDir(outputDir, opts)
+		if err != nil {
+			return err
+		}
+		if err := done(writeProject(teamName, agentList, dir, config)); err != nil {
 			return err
 		}
-		fmt.Printf("Generated CDK project in %s\n", outputDir)
+		if opts.DryRun != nil {
+			return nil
+		}
+		if target.Platform == "terraform" {
+			fmt.Fprintf(w, "Generated Terraform module in %s\n", outputDir)
+			return nil
+		}
+		fmt.Fprintf(w, "Generated CDK project in %s\n", outputDir)
+		if opts.SynthCheck {
+			return runSynthCheck(context.Background(), w, outputDir)
+		}
 		return nil
 
 	case "aws-eks", "azure-aks", "gcp-gke", "kubernetes":
-		// TODO: Implement Helm chart generation
-		fmt.Printf("Kubernetes deployment not yet implemented for %s\n", target.Platform)
+		// Generate Helm chart
+		config := &helm.HelmConfig{}
+		var err error
+		if config.ImageRepository, err = configString(target, "imageRepository"); err != nil {
+			return err
+		}
+		if config.ImageTag, err = configString(target, "imageTag"); err != nil {
+			return err
+		}
+		if config.Replicas, err = configInt(target, "replicas"); err != nil {
+			return err
+		}
+		dir, done, err := scratchDir(outputDir, opts)
+		if err != nil {
+			return err
+		}
+		if err := done(helm.WriteHelmChart(teamName, agentList, dir, config)); err != nil {
+			return fmt.Errorf("target %s: %w", target.Name, err)
+		}
+		if opts.DryRun != nil {
+			return nil
+		}
+		fmt.Fprintf(w, "Generated Helm chart in %s\n", outputDir)
 		return nil
 
 	default:
@@ -347,6 +1727,127 @@ func generateForPlatform(teamName string, agentList []*core.Agent, target Target
 	}
 }
 
+// validateTargetGroups checks that every group a target selects is used by
+// at least one agent.
+func validateTargetGroups(targets []Target, agentList []*core.Agent) error {
+	known := make(map[string]bool)
+	for _, agent := range agentList {
+		if agent.Group != "" {
+			known[agent.Group] = true
+		}
+	}
+
+	for _, target := range targets {
+		for _, group := range target.Groups {
+			if !known[group] {
+				names := make([]string, 0, len(known))
+				for name := range known {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				return fmt.Errorf("target %s references unknown group %q (available: %s)", target.Name, group, strings.Join(names, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// resolveTemplateDir returns target with its "templateDir" option, a
+// directory of CDK template overrides, made relative to the project
+// directory. The config is copied, never modified.
+func resolveTemplateDir(target Target, projectDir string) (Target, error) {
+	dir, err := configString(target, "templateDir")
+	if err != nil || dir == "" || filepath.IsAbs(dir) {
+		return target, err
+	}
+	config := make(map[string]interface{}, len(target.Config))
+	for key, value := range target.Config {
+		config[key] = value
+	}
+	config["templateDir"] = filepath.Join(projectDir, dir)
+	target.Config = config
+	return target, nil
+}
+
+// resourceAttributeKeys reads the "otelResourceAttributes" target option:
+// true selects core.DefaultResourceAttributes, a list selects those keys,
+// and false or absent disables resource attributes.
+func resourceAttributeKeys(target Target) ([]string, error) {
+	switch v := target.Config["otelResourceAttributes"].(type) {
+	case nil:
+		return nil, nil
+	case bool:
+		if !v {
+			return nil, nil
+		}
+		return core.DefaultResourceAttributes, nil
+	case []interface{}:
+		keys := make([]string, 0, len(v))
+		for _, item := range v {
+			key, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("target %s: otelResourceAttributes entries must be strings", target.Name)
+			}
+			keys = append(keys, key)
+		}
+		return keys, nil
+	default:
+		return nil, fmt.Errorf("target %s: otelResourceAttributes must be a boolean or a list of attribute keys", target.Name)
+	}
+}
+
+// configMemory returns the target's memory config, an object such as
+// {"enabled": true, "ttlDays": 30}, or nil if it is absent.
+func configMemory(target Target) (*awsagentcore.MemoryConfig, error) {
+	raw, ok := target.Config["memory"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, configTypeError(target, "memory", `an object with "enabled" and "ttlDays"`, raw)
+	}
+
+	// Read the members as options named memory.<key> for their errors
+	members := Target{Name: target.Name, Config: make(map[string]interface{}, len(obj))}
+	for key, value := range obj {
+		members.Config["memory."+key] = value
+	}
+	enabled, err := configBool(members, "memory.enabled")
+	if err != nil {
+		return nil, err
+	}
+	ttlDays, err := configInt(members, "memory.ttlDays")
+	if err != nil {
+		return nil, err
+	}
+	return &awsagentcore.MemoryConfig{Enabled: enabled, TTLDays: ttlDays}, nil
+}
+
+// secretResolver returns the SecretResolver selected by the -secrets flag.
+func secretResolver(name, region string) (core.SecretResolver, error) {
+	switch name {
+	case "env":
+		return core.EnvSecretResolver{}, nil
+	case "aws-secretsmanager":
+		return awsagentcore.NewSecretsManagerResolver(region), nil
+	default:
+		return nil, fmt.Errorf("unknown secrets resolver %q (available: env, aws-secretsmanager)", name)
+	}
+}
+
+// checkStrictTools enforces the named adapter's tool set when -strict-tools is on.
+func checkStrictTools(agentList []*core.Agent, format string, opts options) error {
+	if !opts.StrictTools {
+		return nil
+	}
+	adapter, ok := opts.registry().GetAdapter(format)
+	if !ok {
+		return &core.AdapterError{Name: format}
+	}
+	return core.CheckTools(agentList, adapter)
+}
+
 // toPascalCase converts a hyphenated string to PascalCase.
 func toPascalCase(s string) string {
 	parts := strings.Split(s, "-")
@@ -478,3 +1979,116 @@ func prefixAgentName(data []byte, prefix string) ([]byte, error) {
 
 	return json.MarshalIndent(agent, "", "  ")
 }
+
+// applyModelOverrides applies the target's modelOverrides config, an
+// object of agent name to model, warning about names no agent has.
+func applyModelOverrides(warn io.Writer, target Target, agentList []*core.Agent) ([]*core.Agent, error) {
+	raw, ok := target.Config["modelOverrides"]
+	if !ok || raw == nil {
+		return agentList, nil
+	}
+	rawMap, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, configTypeError(target, "modelOverrides", "an object of agent name to model", raw)
+	}
+
+	known := make(map[string]bool, 2*len(agentList))
+	for _, agent := range agentList {
+		known[agent.Name] = true
+		known[agent.QualifiedName()] = true
+	}
+	names := make([]string, 0, len(rawMap))
+	for name := range rawMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	overrides := make(map[string]string, len(rawMap))
+	for _, name := range names {
+		model, ok := rawMap[name].(string)
+		if !ok {
+			return nil, fmt.Errorf("target %s: modelOverrides[%q] must be a string", target.Name, name)
+		}
+		if !known[name] {
+			fmt.Fprintf(warn, "Warning: target %s: modelOverrides names unknown agent %s\n", target.Name, name)
+		}
+		overrides[name] = model
+	}
+	return core.ApplyModelOverrides(agentList, overrides), nil
+}
+
+// applyToolFilters applies the target's allowTools and denyTools config,
+// lists of tools to keep and to strip from every agent (see
+// core.FilterTools). It warns of each agent left with no tools, which some
+// platforms treat as access to every tool.
+func applyToolFilters(warn io.Writer, target Target, agentList []*core.Agent) ([]*core.Agent, error) {
+	allow, err := configStrings(target, "allowTools")
+	if err != nil {
+		return nil, err
+	}
+	deny, err := configStrings(target, "denyTools")
+	if err != nil {
+		return nil, err
+	}
+	if allow == nil && deny == nil {
+		return agentList, nil
+	}
+
+	filtered := make([]*core.Agent, len(agentList))
+	for i, agent := range agentList {
+		filtered[i] = core.FilterTools(agent, allow, deny)
+		if len(filtered[i].Tools) == 0 {
+			fmt.Fprintf(warn, "Warning: target %s: agent %s has no tools after allowTools/denyTools\n", target.Name, agent.Name)
+		}
+	}
+	return filtered, nil
+}
+
+// toolMappingOverrides builds the agentkit tool mapping for a target, applying
+// any "toolMappingOverrides" from its config on top of the defaults. The
+// result is a fresh copy, so overrides never leak into other targets.
+func toolMappingOverrides(target Target) (agentkit.ToolMapping, error) {
+	raw, ok := target.Config["toolMappingOverrides"]
+	if !ok {
+		return agentkit.DefaultToolMapping(), nil
+	}
+
+	rawMap, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("target %s: toolMappingOverrides must be an object of tool name to agentkit tool", target.Name)
+	}
+
+	overrides := make(map[string]string, len(rawMap))
+	for tool, value := range rawMap {
+		mapped, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("target %s: toolMappingOverrides[%q] must be a string", target.Name, tool)
+		}
+		overrides[tool] = mapped
+	}
+
+	mapping, err := agentkit.DefaultToolMapping().WithOverrides(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("target %s: %w", target.Name, err)
+	}
+	return mapping, nil
+}
+
+// setDescriptionStyles sets a comma-separated list of format:mode:max
+// description styles on registry, where the formats must be registered.
+func setDescriptionStyles(spec string, registry *core.Registry) error {
+	if spec == "" {
+		return nil
+	}
+	for _, entry := range strings.Split(spec, ",") {
+		name, style, err := core.ParseDescriptionStyle(strings.TrimSpace(entry))
+		if err != nil {
+			return err
+		}
+		if _, ok := registry.Get(name); !ok {
+			return fmt.Errorf("unknown format in -description-style: %s", name)
+		}
+		registry.SetDescriptionStyle(name, style)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// defaultManifest is the manifest file written to the project directory
+// when -manifest is not set.
+const defaultManifest = "generated.json"
+
+// GenerationManifest summarizes a -project generation: what each target
+// generated and a content hash of every file in its output directory. It
+// holds no timestamps, so CI can diff manifests between runs to spot
+// unexpected churn.
+type GenerationManifest struct {
+	Team          string           `json:"team"`
+	HashAlgorithm string           `json:"hashAlgorithm"`
+	Targets       []ManifestTarget `json:"targets"`
+}
+
+// ManifestTarget is one generated deployment target.
+type ManifestTarget struct {
+	Name       string         `json:"name"`
+	Platform   string         `json:"platform"`
+	Output     string         `json:"output"` // Relative to the project directory
+	AgentCount int            `json:"agentCount"`
+	Files      []ManifestFile `json:"files"`
+}
+
+// ManifestFile is a file in a target's output directory.
+type ManifestFile struct {
+	Path string `json:"path"` // Slash-separated, relative to the project directory
+	Hash string `json:"hash"`
+}
+
+// newGenerationManifest hashes the files in each target's output directory
+// with the hash algorithm of registry. agentCounts holds the number of agents
+// generated for each target. The file at skip, the manifest itself, is not
+// listed.
+func newGenerationManifest(registry *core.Registry, team, projectDir string, targets []Target, agentCounts []int, skip string) (*GenerationManifest, error) {
+	manifest := &GenerationManifest{
+		Team:          team,
+		HashAlgorithm: registry.HashAlgorithm(),
+		Targets:       make([]ManifestTarget, len(targets)),
+	}
+	for i, target := range targets {
+		entry := ManifestTarget{
+			Name:       target.Name,
+			Platform:   target.Platform,
+			Output:     filepath.ToSlash(filepath.Clean(target.Output)),
+			AgentCount: agentCounts[i],
+			Files:      []ManifestFile{},
+		}
+		outputDir := filepath.Join(projectDir, target.Output)
+		err := filepath.WalkDir(outputDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || path == skip {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(projectDir, path)
+			if err != nil {
+				return err
+			}
+			entry.Files = append(entry.Files, ManifestFile{Path: filepath.ToSlash(rel), Hash: registry.ContentHash(data)})
+			return nil
+		})
+		if err != nil {
+			return nil, &core.ReadError{Path: outputDir, Err: err}
+		}
+		manifest.Targets[i] = entry
+	}
+	return manifest, nil
+}
+
+// writeManifest writes manifest to path as indented JSON.
+func writeManifest(w io.Writer, path string, manifest *GenerationManifest, verbose bool) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return &core.MarshalError{Format: "manifest", Err: err}
+	}
+	if err := core.WriteOutputFile(path, append(data, '\n')); err != nil {
+		return err
+	}
+	if verbose {
+		fmt.Fprintf(w, "Wrote generation manifest for %d targets to %s\n", len(manifest.Targets), path)
+	}
+	return nil
+}
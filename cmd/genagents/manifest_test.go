@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestRunProjectModeManifest(t *testing.T) {
+	project := t.TempDir()
+	for name, spec := range map[string]string{
+		"reviewer.md": "---\nname: reviewer\ndescription: Reviews code\ngroup: qa\n---\n\nReview the diff.\n",
+		"planner.md":  "---\nname: planner\ndescription: Plans work\n---\n\nPlan the work.\n",
+	} {
+		if err := core.WriteOutputFile(filepath.Join(project, "agents", name), []byte(spec)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deployment := `{"team": "qa", "targets": [
+		{"name": "claude", "platform": "claude-code", "output": "out/claude"},
+		{"name": "kiro", "platform": "kiro-cli", "output": "out/kiro", "groups": ["qa"]}
+	]}`
+	if err := core.WriteOutputFile(filepath.Join(project, "deployment.json"), []byte(deployment)); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := options{WriteConcurrency: 1, Concurrency: 2}
+	if err := runProjectMode(io.Discard, io.Discard, project, "", opts); err != nil {
+		t.Fatalf("runProjectMode() error = %v", err)
+	}
+	first, err := os.ReadFile(filepath.Join(project, defaultManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var manifest GenerationManifest
+	if err := json.Unmarshal(first, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Team != "qa" || len(manifest.Targets) != 2 {
+		t.Fatalf("manifest = %+v, want 2 qa targets", manifest)
+	}
+	kiro := manifest.Targets[1]
+	if kiro.Platform != "kiro-cli" || kiro.Output != "out/kiro" || kiro.AgentCount != 1 {
+		t.Errorf("kiro target = %+v", kiro)
+	}
+	if len(kiro.Files) != 1 || kiro.Files[0].Path != "out/kiro/reviewer.json" {
+		t.Fatalf("kiro files = %+v", kiro.Files)
+	}
+	data, err := os.ReadFile(filepath.Join(project, "out", "kiro", "reviewer.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kiro.Files[0].Hash != core.ContentHash(data) {
+		t.Errorf("hash = %s, want %s", kiro.Files[0].Hash, core.ContentHash(data))
+	}
+
+	// An unchanged regeneration writes an identical manifest
+	if err := runProjectMode(io.Discard, io.Discard, project, "", opts); err != nil {
+		t.Fatalf("runProjectMode() error = %v", err)
+	}
+	second, err := os.ReadFile(filepath.Join(project, defaultManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Errorf("manifest changed between identical runs:\n%s\n%s", first, second)
+	}
+}
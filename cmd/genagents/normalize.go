@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// runNormalize rewrites every spec under specDir into canonical form. With
+// outputDir set, normalized specs are written there instead of in place.
+// With check set, nothing is written; files that are not normalized are
+// listed (like gofmt -l) and an error is returned if there are any.
+func runNormalize(w io.Writer, specDir, outputDir string, check, verbose bool) error {
+	paths, err := specFiles(specDir)
+	if err != nil {
+		return err
+	}
+
+	changed := 0
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return &core.ReadError{Path: path, Err: err}
+		}
+
+		normalized, err := core.NormalizeSpec(path, data)
+		if err != nil {
+			return err
+		}
+
+		differs := !bytes.Equal(data, normalized)
+		if differs {
+			changed++
+		}
+
+		if check {
+			if differs {
+				fmt.Fprintln(w, path)
+			}
+			continue
+		}
+
+		dst := path
+		if outputDir != "" {
+			rel, err := filepath.Rel(specDir, path)
+			if err != nil {
+				return err
+			}
+			dst = filepath.Join(outputDir, rel)
+		} else if !differs {
+			continue
+		}
+
+		if err := writeSpec(dst, path, normalized); err != nil {
+			return err
+		}
+		if verbose {
+			fmt.Fprintf(w, "Normalized %s\n", dst)
+		}
+	}
+
+	if check && changed > 0 {
+		return fmt.Errorf("%d spec file(s) not normalized", changed)
+	}
+	if !check {
+		fmt.Fprintf(w, "Normalized %d of %d spec files\n", changed, len(paths))
+	}
+	return nil
+}
+
+// specFiles lists the canonical spec files ReadCanonicalDir would load:
+// Markdown files recursively and JSON files in the top-level directory.
+func specFiles(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch filepath.Ext(path) {
+		case ".md":
+			paths = append(paths, path)
+		case ".json":
+			if filepath.Dir(path) == filepath.Clean(dir) {
+				paths = append(paths, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, &core.ReadError{Path: dir, Err: err}
+	}
+	return paths, nil
+}
+
+// writeSpec writes a normalized spec, keeping the source file's permissions.
+func writeSpec(dst, src string, data []byte) error {
+	mode := core.DefaultFileMode
+	if info, err := os.Stat(src); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), core.DefaultDirMode); err != nil {
+		return &core.WriteError{Path: dst, Err: err}
+	}
+	if err := os.WriteFile(dst, data, mode); err != nil {
+		return &core.WriteError{Path: dst, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// parseOnly splits the -only flag into agent names, dropping empty entries.
+func parseOnly(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// checkOnly checks that every -only name is one of the agent names.
+func checkOnly(agentNames []string, only []string) error {
+	known := make(map[string]bool, len(agentNames))
+	for _, name := range agentNames {
+		known[name] = true
+	}
+	for _, name := range only {
+		if !known[name] {
+			names := make([]string, 0, len(known))
+			for name := range known {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("-only names unknown agent %q (available: %s)", name, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+// selectOnly returns the agents named in only, preserving order. An empty
+// only list selects every agent.
+func selectOnly(agentList []*core.Agent, only []string) []*core.Agent {
+	if len(only) == 0 {
+		return agentList
+	}
+	want := make(map[string]bool, len(only))
+	for _, name := range only {
+		want[name] = true
+	}
+	var out []*core.Agent
+	for _, agent := range agentList {
+		if want[agent.Name] {
+			out = append(out, agent)
+		}
+	}
+	return out
+}
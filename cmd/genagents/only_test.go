@@ -0,0 +1,76 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestParseOnly(t *testing.T) {
+	if got := parseOnly(" reviewer, ,planner "); !reflect.DeepEqual(got, []string{"reviewer", "planner"}) {
+		t.Errorf("parseOnly() = %q", got)
+	}
+	if got := parseOnly(""); got != nil {
+		t.Errorf("parseOnly(\"\") = %q, want nil", got)
+	}
+}
+
+func TestRunProjectModeOnly(t *testing.T) {
+	project := t.TempDir()
+	for name, spec := range map[string]string{
+		"reviewer.md": "---\nname: reviewer\ndescription: Reviews code\ngroup: qa\n---\n\nReview the diff.\n",
+		"planner.md":  "---\nname: planner\ndescription: Plans work\n---\n\nPlan the work.\n",
+	} {
+		if err := core.WriteOutputFile(filepath.Join(project, "agents", name), []byte(spec)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deployment := `{"team": "qa", "targets": [
+		{"name": "claude", "platform": "claude-code", "output": "out/claude"},
+		{"name": "kiro", "platform": "kiro-cli", "output": "out/kiro", "groups": ["qa"]},
+		{"name": "continue", "platform": "continue", "output": "out/continue"}
+	]}`
+	if err := core.WriteOutputFile(filepath.Join(project, "deployment.json"), []byte(deployment)); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := options{WriteConcurrency: 1, Concurrency: 1, Only: []string{"planner"}}
+	if err := runProjectMode(io.Discard, io.Discard, project, "", opts); err != nil {
+		t.Fatalf("runProjectMode() error = %v", err)
+	}
+
+	tests := []struct {
+		path   string
+		exists bool
+	}{
+		{"out/claude/planner.md", true},
+		{"out/claude/reviewer.md", false},
+		{"out/kiro/reviewer.json", false},
+	}
+	for _, tt := range tests {
+		_, err := os.Stat(filepath.Join(project, tt.path))
+		if exists := err == nil; exists != tt.exists {
+			t.Errorf("%s exists = %v, want %v", tt.path, exists, tt.exists)
+		}
+	}
+
+	// The team-wide Continue config still holds every agent
+	data, err := os.ReadFile(filepath.Join(project, "out", "continue", "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "name: reviewer") {
+		t.Errorf("config.yaml is missing reviewer:\n%s", data)
+	}
+
+	opts.Only = []string{"ghost"}
+	err = runProjectMode(io.Discard, io.Discard, project, "", opts)
+	if err == nil || !strings.Contains(err.Error(), `unknown agent "ghost" (available: planner, reviewer)`) {
+		t.Errorf("runProjectMode() error = %v, want unknown agent ghost", err)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// outputTemplateData is the data passed to an -output-template.
+type outputTemplateData struct {
+	Content string      // Adapter output
+	Agent   *core.Agent // Canonical agent
+	Format  string      // Adapter name
+}
+
+// loadOutputTemplate parses the -output-template file. An empty path
+// returns nil, meaning adapter output is written unchanged.
+func loadOutputTemplate(path string) (*template.Template, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &core.ReadError{Path: path, Err: err}
+	}
+	tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse output template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderAgent marshals agent with adapter and, if tmpl is set, wraps the
+// result with it, returning the adapter's conversion warnings too.
+// Templated output that the adapter can no longer parse is reported to warn
+// but still returned.
+func renderAgent(adapter core.Adapter, agent *core.Agent, tmpl *template.Template, warn io.Writer) ([]byte, []core.Warning, error) {
+	data, warnings, err := core.MarshalWithWarnings(adapter, agent)
+	if err != nil || tmpl == nil {
+		return data, warnings, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, outputTemplateData{
+		Content: string(data),
+		Agent:   agent,
+		Format:  adapter.Name(),
+	}); err != nil {
+		return nil, nil, fmt.Errorf("output template for %s: %w", agent.Name, err)
+	}
+
+	if _, err := adapter.Parse(buf.Bytes()); err != nil && !errors.Is(err, core.ErrNotSupported) {
+		fmt.Fprintf(warn, "Warning: templated %s output for %s no longer parses: %v\n", adapter.Name(), agent.Name, err)
+	}
+	return buf.Bytes(), warnings, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestRenderAgentOutputTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "envelope.tmpl")
+	tmpl := "{{.Content}}\n<!-- {{.Format}} output for {{.Agent.Name}}; generated, do not edit -->\n"
+	if err := os.WriteFile(path, []byte(tmpl), 0600); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := loadOutputTemplate(path)
+	if err != nil {
+		t.Fatalf("loadOutputTemplate() error = %v", err)
+	}
+
+	adapter, _ := core.GetAdapter("claude")
+	agent := core.NewAgent("reviewer", "Reviews code").WithInstructions("Review the diff.")
+
+	var warn bytes.Buffer
+	data, _, err := renderAgent(adapter, agent, parsed, &warn)
+	if err != nil {
+		t.Fatalf("renderAgent() error = %v", err)
+	}
+	if !strings.HasSuffix(string(data), "<!-- claude output for reviewer; generated, do not edit -->\n") {
+		t.Errorf("output missing envelope:\n%s", data)
+	}
+	if warn.Len() != 0 {
+		t.Errorf("unexpected warning: %s", warn.String())
+	}
+}
+
+func TestRenderAgentWarnsWhenUnparseable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "header.tmpl")
+	if err := os.WriteFile(path, []byte("// Copyright Example Corp\n{{.Content}}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := loadOutputTemplate(path)
+	if err != nil {
+		t.Fatalf("loadOutputTemplate() error = %v", err)
+	}
+
+	adapter, _ := core.GetAdapter("kiro")
+	var warn bytes.Buffer
+	if _, _, err := renderAgent(adapter, core.NewAgent("reviewer", "Reviews code"), parsed, &warn); err != nil {
+		t.Fatalf("renderAgent() error = %v", err)
+	}
+	if !strings.Contains(warn.String(), "no longer parses") {
+		t.Errorf("expected parse warning, got %q", warn.String())
+	}
+}
+
+func TestRenderAgentWithoutTemplate(t *testing.T) {
+	adapter, _ := core.GetAdapter("claude")
+	agent := core.NewAgent("reviewer", "Reviews code")
+
+	want, _ := adapter.Marshal(agent)
+	got, _, err := renderAgent(adapter, agent, nil, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("renderAgent() error = %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("output should be unchanged without a template")
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// outputOverlap is a pair of targets whose output directories are the
+// same or nested.
+type outputOverlap struct {
+	First, Second string // Target names
+	Nested        bool   // Second's output is inside First's, or vice versa
+}
+
+func (o outputOverlap) String() string {
+	if o.Nested {
+		return fmt.Sprintf("targets %s and %s have nested output directories", o.First, o.Second)
+	}
+	return fmt.Sprintf("targets %s and %s share an output directory", o.First, o.Second)
+}
+
+// findOutputOverlaps returns every pair of targets whose output
+// directories, resolved against projectDir, are equal or nested, in target
+// order.
+func findOutputOverlaps(targets []Target, projectDir string) []outputOverlap {
+	dirs := make([]string, len(targets))
+	for i, target := range targets {
+		dirs[i] = filepath.Clean(filepath.Join(projectDir, target.Output))
+	}
+
+	var overlaps []outputOverlap
+	for i := range targets {
+		for j := i + 1; j < len(targets); j++ {
+			switch {
+			case dirs[i] == dirs[j]:
+				overlaps = append(overlaps, outputOverlap{First: targets[i].Name, Second: targets[j].Name})
+			case isWithin(dirs[i], dirs[j]) || isWithin(dirs[j], dirs[i]):
+				overlaps = append(overlaps, outputOverlap{First: targets[i].Name, Second: targets[j].Name, Nested: true})
+			}
+		}
+	}
+	return overlaps
+}
+
+// isWithin reports whether path is strictly inside dir.
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// checkOutputOverlap fails if targets write to the same or nested output
+// directories, where one would overwrite the other's files. With allow set
+// the overlaps are only reported to w.
+func checkOutputOverlap(w io.Writer, targets []Target, projectDir string, allow bool) error {
+	overlaps := findOutputOverlaps(targets, projectDir)
+	if len(overlaps) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, len(overlaps))
+	for i, overlap := range overlaps {
+		msgs[i] = overlap.String()
+	}
+	if !allow {
+		return fmt.Errorf("%s (use -allow-overlap if this is intended)", strings.Join(msgs, "; "))
+	}
+	for _, msg := range msgs {
+		fmt.Fprintf(w, "Warning: %s\n", msg)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestFindOutputOverlaps(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs []string
+		want    []string
+	}{
+		{"distinct", []string{"out/claude", "out/kiro"}, nil},
+		{"same", []string{"out/claude", "out/./claude/"}, []string{"targets t0 and t1 share an output directory"}},
+		{"nested", []string{"out", "out/kiro"}, []string{"targets t0 and t1 have nested output directories"}},
+		{"nested reversed", []string{"out/kiro", "out"}, []string{"targets t0 and t1 have nested output directories"}},
+		{"common prefix", []string{"out/claude", "out/claude-code"}, nil},
+		{"several", []string{"a", "b", "a"}, []string{"targets t0 and t2 share an output directory"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var targets []Target
+			for i, output := range tt.outputs {
+				targets = append(targets, Target{Name: "t" + string(rune('0'+i)), Output: output})
+			}
+			var got []string
+			for _, overlap := range findOutputOverlaps(targets, "project") {
+				got = append(got, overlap.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("findOutputOverlaps() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckOutputOverlap(t *testing.T) {
+	targets := []Target{{Name: "claude", Output: "out"}, {Name: "kiro", Output: "out"}}
+
+	var w bytes.Buffer
+	err := checkOutputOverlap(&w, targets, ".", false)
+	if err == nil || !strings.Contains(err.Error(), "claude and kiro") || !strings.Contains(err.Error(), "-allow-overlap") {
+		t.Errorf("checkOutputOverlap() error = %v", err)
+	}
+
+	if err := checkOutputOverlap(&w, targets, ".", true); err != nil {
+		t.Errorf("checkOutputOverlap() with allow error = %v", err)
+	}
+	if !strings.Contains(w.String(), "Warning: targets claude and kiro share an output directory") {
+		t.Errorf("warning = %q", w.String())
+	}
+}
+
+func TestGenerateAgentsOutputNameClash(t *testing.T) {
+	agentList := []*core.Agent{
+		core.NewAgent("reviewer", "Reviews Go").WithNamespace("go").WithInstructions("Review Go."),
+		core.NewAgent("reviewer", "Reviews Python").WithNamespace("python").WithInstructions("Review Python."),
+	}
+
+	// Both would be written to reviewer.md
+	err := generateAgents(io.Discard, io.Discard, agentList, "claude", t.TempDir(), options{WriteConcurrency: 1})
+	var dupErr *core.DuplicateNameError
+	if !errors.As(err, &dupErr) {
+		t.Errorf("generateAgents() error = %v, want DuplicateNameError", err)
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// redacted replaces sensitive values in -print-config output.
+const redacted = "[redacted]"
+
+// sensitiveKey matches config keys whose literal values are redacted.
+var sensitiveKey = regexp.MustCompile(`(?i)(key|token|secret|password|credential)`)
+
+// effectiveConfig is the resolved configuration printed by -print-config.
+type effectiveConfig struct {
+	Spec       string `json:"spec"`
+	SpecSource string `json:"specSource,omitempty"`
+	Format     string `json:"format"`
+	Output     string `json:"output,omitempty"`
+
+	// MergeSpecs are the further -spec directories merged over Spec.
+	MergeSpecs []string `json:"mergeSpecs,omitempty"`
+
+	// Targets are the -targets format:dir pairs.
+	Targets []flagTarget `json:"targets,omitempty"`
+
+	// Project is the -project directory with its deployment targets.
+	Project *effectiveProject `json:"project,omitempty"`
+
+	Filters effectiveFilters `json:"filters"`
+	Options effectiveOptions `json:"options"`
+
+	// Secrets lists every secret:// reference in the deployment targets and
+	// where it would be resolved from. Values are never included.
+	Secrets map[string]secretSource `json:"secrets,omitempty"`
+}
+
+type flagTarget struct {
+	Format string `json:"format"`
+	Output string `json:"output"`
+}
+
+type effectiveProject struct {
+	Dir     string            `json:"dir"`
+	Team    string            `json:"team"`
+	Targets []effectiveTarget `json:"targets"`
+}
+
+type effectiveTarget struct {
+	Target
+
+	// Selected reports whether the target passes the -priority filter.
+	Selected bool `json:"selected"`
+}
+
+type effectiveFilters struct {
+	Priority     string   `json:"priority,omitempty"`
+	NoDeprecated bool     `json:"noDeprecated"`
+	Locale       string   `json:"locale,omitempty"`
+	Only         []string `json:"only,omitempty"`
+}
+
+type effectiveOptions struct {
+	StrictTools          bool   `json:"strictTools"`
+	WarningsAsErrors     bool   `json:"warningsAsErrors"`
+	AllowUnknownCategory bool   `json:"allowUnknownCategory"`
+	Merge                bool   `json:"merge"`
+	SynthCheck           bool   `json:"synthCheck"`
+	Gitattributes        bool   `json:"gitattributes"`
+	Lockfile             string `json:"lockfile,omitempty"`
+	HashAlgorithm        string `json:"hashAlgorithm"`
+	LineEndings          string `json:"lineEndings,omitempty"`
+	DescriptionStyles    string `json:"descriptionStyles,omitempty"`
+	OutputTemplate       string `json:"outputTemplate,omitempty"`
+	LicenseHeader        string `json:"licenseHeader,omitempty"`
+	StampVersion         bool   `json:"stampVersion"`
+	SecretsResolver      string `json:"secretsResolver"`
+	SecretsRegion        string `json:"secretsRegion,omitempty"`
+	WriteConcurrency     int    `json:"writeConcurrency"`
+	Concurrency          int    `json:"concurrency"`
+	AllowOverlap         bool   `json:"allowOverlap"`
+	Recursive            bool   `json:"recursive"`
+	Stream               bool   `json:"stream"`
+	Manifest             string `json:"manifest,omitempty"`
+	DryRun               bool   `json:"dryRun"`
+
+	// Env names the -env variables; their values are not printed.
+	Env            []string `json:"env,omitempty"`
+	EnvFromOS      bool     `json:"envFromOS"`
+	AllowUndefined bool     `json:"allowUndefined"`
+}
+
+type secretSource struct {
+	Resolver string `json:"resolver"`
+
+	// EnvVar and Set describe the variable read by the env resolver.
+	EnvVar string `json:"envVar,omitempty"`
+	Set    *bool  `json:"set,omitempty"`
+}
+
+// runPrintConfig completes cfg from the -project deployment, if any, and
+// prints it as JSON. Secret references stay unresolved and literal values
+// of sensitive-looking config keys are redacted.
+func runPrintConfig(w io.Writer, cfg effectiveConfig, projectDir string) error {
+	if projectDir != "" {
+		deployment, err := readDeployment(projectDir)
+		if err != nil {
+			return err
+		}
+
+		cfg.Project = &effectiveProject{Dir: projectDir, Team: deployment.Team}
+		for _, target := range deployment.Targets {
+			target.Output = filepath.Join(projectDir, target.Output)
+			target.Config = redactConfig(target.Config)
+			collectSecrets(target.Config, cfg.Options.SecretsResolver, &cfg.Secrets)
+			cfg.Project.Targets = append(cfg.Project.Targets, effectiveTarget{
+				Target:   target,
+				Selected: cfg.Filters.Priority == "" || target.Priority == cfg.Filters.Priority,
+			})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(cfg)
+}
+
+// redactConfig returns a copy of config with the literal string values of
+// sensitive-looking keys replaced. secret:// references are kept.
+func redactConfig(config map[string]interface{}) map[string]interface{} {
+	if config == nil {
+		return nil
+	}
+	return redactValue(config, false).(map[string]interface{})
+}
+
+func redactValue(v interface{}, sensitive bool) interface{} {
+	switch v := v.(type) {
+	case string:
+		if _, ok := core.SecretName(v); ok || !sensitive || v == "" {
+			return v
+		}
+		return redacted
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			out[key] = redactValue(elem, sensitive || sensitiveKey.MatchString(key))
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, elem := range v {
+			out[i] = redactValue(elem, sensitive)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// collectSecrets records the source of every secret:// reference in v.
+func collectSecrets(v interface{}, resolver string, secrets *map[string]secretSource) {
+	switch v := v.(type) {
+	case string:
+		name, ok := core.SecretName(v)
+		if !ok {
+			return
+		}
+		if *secrets == nil {
+			*secrets = make(map[string]secretSource)
+		}
+		source := secretSource{Resolver: resolver}
+		if resolver == "env" {
+			source.EnvVar = core.EnvSecretResolver{}.EnvVar(name)
+			_, set := os.LookupEnv(source.EnvVar)
+			source.Set = &set
+		}
+		(*secrets)[name] = source
+	case map[string]interface{}:
+		for _, elem := range v {
+			collectSecrets(elem, resolver, secrets)
+		}
+	case []interface{}:
+		for _, elem := range v {
+			collectSecrets(elem, resolver, secrets)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunPrintConfig(t *testing.T) {
+	dir := t.TempDir()
+	deployment := `{
+		"team": "stats",
+		"targets": [
+			{"name": "local", "platform": "agentkit-local", "priority": "p1", "output": "out/local",
+			 "config": {"apiKey": "sk-live-123", "llm": {"token": "tok-456"}, "toolAnalytics": "json"}},
+			{"name": "aws", "platform": "aws-agentcore", "priority": "p2", "output": "out/aws",
+			 "config": {"apiKey": "secret://anthropic-api-key", "region": "us-west-2"}}
+		]
+	}`
+	if err := os.WriteFile(filepath.Join(dir, "deployment.json"), []byte(deployment), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ANTHROPIC_API_KEY", "sk-from-env")
+
+	cfg := effectiveConfig{
+		Spec:    "specs",
+		Format:  "claude",
+		Filters: effectiveFilters{Priority: "p1"},
+		Options: effectiveOptions{SecretsResolver: "env"},
+	}
+	var buf bytes.Buffer
+	if err := runPrintConfig(&buf, cfg, dir); err != nil {
+		t.Fatalf("runPrintConfig() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, leaked := range []string{"sk-live-123", "tok-456", "sk-from-env"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("output leaks %q:\n%s", leaked, out)
+		}
+	}
+
+	var got effectiveConfig
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	targets := got.Project.Targets
+	if len(targets) != 2 || !targets[0].Selected || targets[1].Selected {
+		t.Fatalf("targets = %+v, want only p1 selected", targets)
+	}
+	if targets[0].Config["apiKey"] != redacted || targets[0].Config["toolAnalytics"] != "json" {
+		t.Errorf("local config = %v", targets[0].Config)
+	}
+	if targets[1].Config["apiKey"] != "secret://anthropic-api-key" {
+		t.Errorf("secret reference should be kept, got %v", targets[1].Config["apiKey"])
+	}
+	source := got.Secrets["anthropic-api-key"]
+	if source.EnvVar != "ANTHROPIC_API_KEY" || source.Set == nil || !*source.Set {
+		t.Errorf("secret source = %+v", source)
+	}
+}
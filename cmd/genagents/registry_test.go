@@ -0,0 +1,154 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// stubAdapter is the claude adapter registered under another name.
+type stubAdapter struct {
+	core.Adapter
+}
+
+func (stubAdapter) Name() string { return "stub" }
+
+func TestGenerateAgentsRegistry(t *testing.T) {
+	claude, ok := core.GetAdapter("claude")
+	if !ok {
+		t.Fatal("claude adapter is not registered")
+	}
+	registry := core.NewRegistry()
+	registry.Register(stubAdapter{claude})
+
+	agentList := []*core.Agent{core.NewAgent("reviewer", "Reviews code")}
+	outputDir := t.TempDir()
+	opts := options{WriteConcurrency: 1, Registry: registry}
+	if err := generateAgents(io.Discard, io.Discard, agentList, "stub", outputDir, opts); err != nil {
+		t.Fatalf("generateAgents() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "reviewer.md")); err != nil {
+		t.Error(err)
+	}
+
+	// The private registry holds only the stub, and the stub stays out of
+	// the default registry
+	err := generateAgents(io.Discard, io.Discard, agentList, "claude", outputDir, opts)
+	if err == nil || !strings.Contains(err.Error(), "available: stub") {
+		t.Errorf("generateAgents(claude) error = %v, want an unknown format error", err)
+	}
+	if _, ok := core.GetAdapter("stub"); ok {
+		t.Error("stub adapter leaked into the default registry")
+	}
+}
+
+func TestCommandsUseRegistry(t *testing.T) {
+	claude, ok := core.GetAdapter("claude")
+	if !ok {
+		t.Fatal("claude adapter is not registered")
+	}
+	registry := core.NewRegistry()
+	registry.Register(stubAdapter{claude})
+	opts := options{Registry: registry}
+
+	var matrix strings.Builder
+	if err := runToolMatrix(&matrix, opts); err != nil {
+		t.Fatal(err)
+	}
+	if header, _, _ := strings.Cut(matrix.String(), "\n"); strings.Fields(header)[1] != "stub" || len(strings.Fields(header)) != 2 {
+		t.Errorf("tool matrix header = %q, want the stub only", header)
+	}
+
+	var selftest strings.Builder
+	if err := runSelfTest(&selftest, true, opts); err != nil || !strings.Contains(selftest.String(), "stub") || strings.Contains(selftest.String(), "claude") {
+		t.Errorf("runSelfTest() = %v, output:\n%s", err, selftest.String())
+	}
+
+	if err := setDescriptionStyles("claude:truncate:80", registry); err == nil {
+		t.Error("setDescriptionStyles(claude) succeeded, want an unknown format error")
+	}
+
+	specDir := t.TempDir()
+	spec := "---\nname: reviewer\ndescription: Reviews code\n---\n\nReview.\n"
+	if err := os.WriteFile(filepath.Join(specDir, "reviewer.md"), []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+	list, err := newPreviewServer(specDir, false, registry).summaries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || !reflect.DeepEqual(list[0].Formats, []string{"stub"}) {
+		t.Errorf("preview summaries = %+v, want the stub format only", list)
+	}
+}
+
+// dirAdapter is the claude adapter writing each agent as a directory of
+// instructions.md and config.json.
+type dirAdapter struct {
+	core.Adapter
+}
+
+func (dirAdapter) Name() string { return "dir" }
+
+func (dirAdapter) WriteDir(agent *core.Agent, dir string) error {
+	if err := core.WriteOutputFile(filepath.Join(dir, "instructions.md"), []byte(agent.Instructions+"\n")); err != nil {
+		return err
+	}
+	return core.WriteOutputFile(filepath.Join(dir, "config.json"), []byte(`{"name": "`+agent.Name+`"}`+"\n"))
+}
+
+func TestGenerateAgentsDirectoryAdapter(t *testing.T) {
+	claude, ok := core.GetAdapter("claude")
+	if !ok {
+		t.Fatal("claude adapter is not registered")
+	}
+	registry := core.NewRegistry()
+	registry.Register(dirAdapter{claude})
+
+	agentList := []*core.Agent{core.NewAgent("reviewer", "Reviews code").WithInstructions("Review.")}
+	outputDir := t.TempDir()
+	opts := options{WriteConcurrency: 1, Registry: registry}
+	if err := generateAgents(io.Discard, io.Discard, agentList, "dir", outputDir, opts); err != nil {
+		t.Fatalf("generateAgents() error = %v", err)
+	}
+	for _, name := range []string{"instructions.md", "config.json"} {
+		if _, err := os.Stat(filepath.Join(outputDir, "reviewer", name)); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "reviewer.md")); err == nil {
+		t.Error("directory adapter also wrote reviewer.md")
+	}
+
+	// Unchanged directories are not rewritten
+	var out strings.Builder
+	if err := generateAgents(&out, io.Discard, agentList, "dir", outputDir, opts); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "(0 written, 1 unchanged)") {
+		t.Errorf("second run = %q, want nothing written", out.String())
+	}
+
+	// A dry run plans each file of the directory
+	agentList[0] = agentList[0].WithInstructions("Review carefully.")
+	opts.DryRun = &dryRunPlan{}
+	if err := generateAgents(io.Discard, io.Discard, agentList, "dir", outputDir, opts); err != nil {
+		t.Fatal(err)
+	}
+	changes := opts.DryRun.changes()
+	if len(changes) != 1 || changes[0].Path != filepath.Join(outputDir, "reviewer", "instructions.md") {
+		t.Errorf("dry run changes = %+v, want instructions.md only", changes)
+	}
+
+	opts.DryRun = nil
+	opts.OutputTemplate = template.Must(template.New("t").Parse("{{.Name}}"))
+	if err := generateAgents(io.Discard, io.Discard, agentList, "dir", outputDir, opts); err == nil {
+		t.Error("generateAgents() with an output template succeeded, want an error")
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// selfTestAgents returns the built-in sample agents used by -selftest.
+// They cover a minimal agent, a fully populated one, and multi-line
+// instructions with characters that need escaping in most formats.
+func selfTestAgents() []*core.Agent {
+	full := core.NewAgent("release-coordinator", "Orchestrates software releases").
+		WithModel(core.ModelOpus).
+		WithTools("Read", "Write", "Bash", "Glob", "Grep").
+		WithInstructions("You are a release coordinator.\n\n## Steps\n\n1. Check the changelog\n2. Tag the release")
+	full.Skills = []string{"version-analysis"}
+
+	quoted := core.NewAgent("quoter", `Handles "quotes", colons: and \backslashes`).
+		WithModel(core.ModelHaiku).
+		WithTools("Read").
+		WithInstructions("Say \"hello\" and use `code`.\nSecond line: done.")
+
+	return []*core.Agent{
+		core.NewAgent("minimal", "A minimal agent"),
+		full,
+		quoted,
+	}
+}
+
+// runSelfTest marshals every sample agent with every adapter in the options'
+// registry and, where the adapter supports parsing, parses the output back
+// and checks that the result is stable. It reports each failure to w and
+// returns an error if any check failed.
+func runSelfTest(w io.Writer, verbose bool, opts options) error {
+	registry := opts.registry()
+	names := registry.AdapterNames()
+	if len(names) == 0 {
+		return fmt.Errorf("selftest: no adapters registered")
+	}
+
+	samples := selfTestAgents()
+	failures := 0
+	for _, name := range names {
+		adapter, ok := registry.GetAdapter(name)
+		if !ok || adapter.Name() != name {
+			fmt.Fprintf(w, "FAIL %s: adapter not registered under its own name\n", name)
+			failures++
+			continue
+		}
+		if adapter.FileExtension() == "" {
+			fmt.Fprintf(w, "FAIL %s: empty file extension\n", name)
+			failures++
+		}
+
+		for _, agent := range samples {
+			mode, err := selfTestRoundTrip(adapter, agent)
+			if err != nil {
+				fmt.Fprintf(w, "FAIL %s/%s: %v\n", name, agent.Name, err)
+				failures++
+				continue
+			}
+			if verbose {
+				fmt.Fprintf(w, "ok   %s/%s (%s)\n", name, agent.Name, mode)
+			}
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("selftest: %d check(s) failed", failures)
+	}
+	fmt.Fprintf(w, "Selftest passed: %d adapters, %d sample agents\n", len(names), len(samples))
+	return nil
+}
+
+// selfTestRoundTrip checks one adapter against one agent. Tool and model
+// mappings may be lossy, so identity fields must survive the first round
+// trip, and the whole agent must be unchanged by a second one.
+func selfTestRoundTrip(adapter core.Adapter, agent *core.Agent) (string, error) {
+	data, err := adapter.Marshal(agent)
+	if err != nil {
+		return "", fmt.Errorf("marshal: %w", err)
+	}
+
+	first, err := adapter.Parse(data)
+	if errors.Is(err, core.ErrNotSupported) {
+		return "marshal only", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("parse: %w", err)
+	}
+
+	if first.Name != agent.Name {
+		return "", fmt.Errorf("name: got %q, want %q", first.Name, agent.Name)
+	}
+	if first.Description != agent.Description {
+		return "", fmt.Errorf("description: got %q, want %q", first.Description, agent.Description)
+	}
+	if strings.TrimSpace(first.Instructions) != strings.TrimSpace(agent.Instructions) {
+		return "", fmt.Errorf("instructions: got %q, want %q", first.Instructions, agent.Instructions)
+	}
+
+	data, err = adapter.Marshal(first)
+	if err != nil {
+		return "", fmt.Errorf("re-marshal: %w", err)
+	}
+	second, err := adapter.Parse(data)
+	if err != nil {
+		return "", fmt.Errorf("re-parse: %w", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		return "", fmt.Errorf("unstable round trip:\n  first:  %+v\n  second: %+v", first.Spec, second.Spec)
+	}
+
+	return "round trip", nil
+}
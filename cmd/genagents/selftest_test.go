@@ -0,0 +1,13 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRunSelfTest(t *testing.T) {
+	var out bytes.Buffer
+	if err := runSelfTest(&out, true, options{}); err != nil {
+		t.Fatalf("runSelfTest() error = %v\n%s", err, out.String())
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/agentplexus/assistantkit/agents"
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// previewServer serves generated agent output over HTTP without writing to disk.
+// Specs are re-read on every request, so edits show up on the next refresh.
+type previewServer struct {
+	specDir  string
+	verbose  bool
+	registry *core.Registry
+}
+
+// agentSummary is the JSON representation of an agent in the listing.
+type agentSummary struct {
+	Name        string   `json:"name"`
+	Namespace   string   `json:"namespace,omitempty"`
+	Description string   `json:"description"`
+	Source      string   `json:"source,omitempty"`
+	Formats     []string `json:"formats"`
+}
+
+// agentPreview is the JSON representation of a single agent's generated output.
+type agentPreview struct {
+	agentSummary
+	Outputs map[string]string `json:"outputs"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
+
+// runServe starts the preview server on addr, previewing the formats of
+// the options' registry.
+func runServe(addr, specDir string, verbose bool, opts options) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           newPreviewServer(specDir, verbose, opts.registry()).handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	fmt.Printf("Serving previews of %s on http://%s\n", specDir, displayAddr(addr))
+	return srv.ListenAndServe()
+}
+
+func newPreviewServer(specDir string, verbose bool, registry *core.Registry) *previewServer {
+	return &previewServer{specDir: specDir, verbose: verbose, registry: registry}
+}
+
+func (s *previewServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /agents/{name}", s.handleAgentPage)
+	mux.HandleFunc("GET /api/agents", s.handleAPIList)
+	mux.HandleFunc("GET /api/agents/{name}", s.handleAPIAgent)
+	return mux
+}
+
+func (s *previewServer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	list, err := s.summaries()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.render(w, indexTemplate, list)
+}
+
+func (s *previewServer) handleAgentPage(w http.ResponseWriter, r *http.Request) {
+	preview, status, err := s.preview(r.PathValue("name"))
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	s.render(w, agentTemplate, preview)
+}
+
+func (s *previewServer) handleAPIList(w http.ResponseWriter, r *http.Request) {
+	list, err := s.summaries()
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *previewServer) handleAPIAgent(w http.ResponseWriter, r *http.Request) {
+	preview, status, err := s.preview(r.PathValue("name"))
+	if err != nil {
+		writeJSONError(w, status, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, preview)
+}
+
+// summaries loads the spec directory and summarizes every agent.
+func (s *previewServer) summaries() ([]agentSummary, error) {
+	agentList, err := agents.ReadCanonicalDir(s.specDir)
+	if err != nil {
+		return nil, err
+	}
+
+	formats := s.registry.AdapterNames()
+	list := make([]agentSummary, 0, len(agentList))
+	for _, agent := range agentList {
+		list = append(list, summarize(agent, formats))
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// preview generates every registered format for the named agent in memory.
+// Per-format failures are reported alongside the successful outputs.
+func (s *previewServer) preview(name string) (*agentPreview, int, error) {
+	agentList, err := agents.ReadCanonicalDir(s.specDir)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	var agent *core.Agent
+	for _, a := range agentList {
+		if a.Name == name {
+			agent = a
+			break
+		}
+	}
+	if agent == nil {
+		return nil, http.StatusNotFound, fmt.Errorf("agent %q not found in %s", name, s.specDir)
+	}
+
+	formats := s.registry.AdapterNames()
+	preview := &agentPreview{
+		agentSummary: summarize(agent, formats),
+		Outputs:      make(map[string]string, len(formats)),
+	}
+	for _, format := range formats {
+		files, err := s.registry.GenerateFiles([]*core.Agent{agent}, format)
+		if err != nil {
+			if preview.Errors == nil {
+				preview.Errors = make(map[string]string)
+			}
+			preview.Errors[format] = err.Error()
+			continue
+		}
+		for _, data := range files {
+			preview.Outputs[format] = string(data)
+		}
+	}
+
+	return preview, http.StatusOK, nil
+}
+
+func (s *previewServer) render(w http.ResponseWriter, tmpl *template.Template, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil && s.verbose {
+		fmt.Printf("preview: render failed: %v\n", err)
+	}
+}
+
+func summarize(agent *core.Agent, formats []string) agentSummary {
+	return agentSummary{
+		Name:        agent.Name,
+		Namespace:   agent.Namespace,
+		Description: agent.Description,
+		Source:      agent.SourcePath,
+		Formats:     formats,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// displayAddr turns a listen address like ":8080" into a clickable host:port.
+func displayAddr(addr string) string {
+	if len(addr) > 0 && addr[0] == ':' {
+		return "localhost" + addr
+	}
+	return addr
+}
+
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>genagents preview</title></head>
+<body>
+<h1>Agents</h1>
+<ul>
+{{- range .}}
+  <li><a href="/agents/{{.Name}}">{{.Name}}</a>{{if .Namespace}} <small>({{.Namespace}})</small>{{end}} &mdash; {{.Description}}</li>
+{{- else}}
+  <li>No agents found.</li>
+{{- end}}
+</ul>
+<p><a href="/api/agents">JSON API</a></p>
+</body>
+</html>
+`))
+
+var agentTemplate = template.Must(template.New("agent").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Name}} - genagents preview</title></head>
+<body>
+<p><a href="/">&larr; All agents</a></p>
+<h1>{{.Name}}</h1>
+<p>{{.Description}}</p>
+{{- if .Source}}<p><small>{{.Source}}</small></p>{{end}}
+{{- range $format, $out := .Outputs}}
+<h2>{{$format}}</h2>
+<pre>{{$out}}</pre>
+{{- end}}
+{{- range $format, $msg := .Errors}}
+<h2>{{$format}}</h2>
+<p><strong>Error:</strong> {{$msg}}</p>
+{{- end}}
+<p><a href="/api/agents/{{.Name}}">JSON</a></p>
+</body>
+</html>
+`))
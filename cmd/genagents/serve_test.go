@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestPreviewServer(t *testing.T) {
+	dir := t.TempDir()
+	spec := "---\nname: reviewer\ndescription: Reviews code\ntools: [Read, Grep]\n---\n\nReview carefully.\n"
+	if err := os.WriteFile(filepath.Join(dir, "reviewer.md"), []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := httptest.NewServer(newPreviewServer(dir, false, core.DefaultRegistry).handler())
+	defer ts.Close()
+
+	t.Run("list", func(t *testing.T) {
+		var list []agentSummary
+		getJSON(t, ts.URL+"/api/agents", http.StatusOK, &list)
+		if len(list) != 1 || list[0].Name != "reviewer" {
+			t.Fatalf("list = %+v, want one agent named reviewer", list)
+		}
+	})
+
+	t.Run("agent", func(t *testing.T) {
+		var preview agentPreview
+		getJSON(t, ts.URL+"/api/agents/reviewer", http.StatusOK, &preview)
+		if !strings.Contains(preview.Outputs["claude"], "Review carefully.") {
+			t.Errorf("claude output missing instructions: %q", preview.Outputs["claude"])
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		var body map[string]string
+		getJSON(t, ts.URL+"/api/agents/missing", http.StatusNotFound, &body)
+		if body["error"] == "" {
+			t.Error("expected error message")
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+			t.Errorf("Content-Type = %q, want text/html", ct)
+		}
+	})
+}
+
+func getJSON(t *testing.T, url string, wantStatus int, v any) {
+	t.Helper()
+	resp, err := http.Get(url) //nolint:gosec // test server URL
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != wantStatus {
+		t.Fatalf("GET %s status = %d, want %d", url, resp.StatusCode, wantStatus)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("decode %s: %v", url, err)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// specList is the repeatable -spec flag. The first -spec given replaces
+// the default.
+type specList struct {
+	dirs []string
+	set  bool
+}
+
+func (s *specList) String() string {
+	return strings.Join(s.dirs, ",")
+}
+
+func (s *specList) Set(value string) error {
+	if !s.set {
+		s.dirs, s.set = nil, true
+	}
+	s.dirs = append(s.dirs, value)
+	return nil
+}
+
+// mergeSpecs reads each of dirs in order and merges its agents over those
+// of the dirs before it (see core.MergeAgents), so later specs replace
+// agents of the same name. Git sources are fetched first. Inheritance is
+// resolved once, after merging, so a spec may extend a base from an
+// earlier dir.
+func mergeSpecs(dirs []string, opts options) ([]*core.Agent, error) {
+	var agentList []*core.Agent
+	for _, dir := range dirs {
+		if core.IsGitSource(dir) {
+			resolved, err := core.ResolveSpecDir(context.Background(), dir)
+			if err != nil {
+				return nil, err
+			}
+			dir = resolved
+		}
+		override, err := core.ReadCanonicalSpecs(dir, opts.Recursive)
+		if err != nil {
+			return nil, fmt.Errorf("reading spec directory %s: %w", dir, err)
+		}
+		if agentList, err = core.MergeAgents(agentList, override); err != nil {
+			return nil, err
+		}
+	}
+	return core.ResolveCanonical(agentList)
+}
+
+// listAgents prints the agents read from dirs for -verbose. When several
+// specs were merged, each agent's source file is printed too, showing
+// which spec it came from.
+func listAgents(w io.Writer, agentList []*core.Agent, dirs []string) {
+	fmt.Fprintf(w, "Found %d agents in %s\n", len(agentList), strings.Join(dirs, ", "))
+	for _, agent := range agentList {
+		if len(dirs) > 1 && agent.SourcePath != "" {
+			fmt.Fprintf(w, "  - %s: %s (%s)\n", agent.Name, agent.Description, agent.SourcePath)
+		} else {
+			fmt.Fprintf(w, "  - %s: %s\n", agent.Name, agent.Description)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestSpecListFlag(t *testing.T) {
+	specs := &specList{dirs: []string{"default"}}
+	fs := flag.NewFlagSet("genagents", flag.ContinueOnError)
+	fs.Var(specs, "spec", "")
+	if err := fs.Parse([]string{"-spec=shared", "-spec=local"}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(specs.dirs, []string{"shared", "local"}) {
+		t.Errorf("dirs = %q, want shared then local without the default", specs.dirs)
+	}
+}
+
+func TestMergeSpecs(t *testing.T) {
+	dir := t.TempDir()
+	for path, spec := range map[string]string{
+		"shared/reviewer.md": "---\nname: reviewer\ndescription: Reviews code\n---\n\nReview the diff.\n",
+		"shared/planner.md":  "---\nname: planner\ndescription: Plans work\n---\n\nPlan the work.\n",
+		"local/planner.md":   "---\nname: planner\ndescription: Plans releases\n---\n\nPlan the release.\n",
+		"local/deployer.md":  "---\nname: deployer\ndescription: Deploys\n---\n\nDeploy.\n",
+	} {
+		if err := core.WriteOutputFile(filepath.Join(dir, path), []byte(spec)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	merged, err := mergeSpecs([]string{filepath.Join(dir, "shared"), filepath.Join(dir, "local")}, options{})
+	if err != nil {
+		t.Fatalf("mergeSpecs() error = %v", err)
+	}
+	got := make(map[string]string)
+	for _, agent := range merged {
+		got[agent.Name] = agent.Description
+	}
+	want := map[string]string{"reviewer": "Reviews code", "planner": "Plans releases", "deployer": "Deploys"}
+	if !reflect.DeepEqual(got, want) || len(merged) != 3 {
+		t.Errorf("merged = %v, want %v", got, want)
+	}
+
+	var out bytes.Buffer
+	listAgents(&out, merged, []string{"shared", "local"})
+	if want := "  - planner: Plans releases (" + filepath.Join(dir, "local", "planner.md") + ")\n"; !strings.Contains(out.String(), want) {
+		t.Errorf("listAgents() output missing %q:\n%s", want, out.String())
+	}
+
+	if _, err := mergeSpecs([]string{filepath.Join(dir, "shared"), filepath.Join(dir, "missing")}, options{}); err == nil {
+		t.Error("mergeSpecs() accepted a missing spec directory")
+	}
+}
+
+func TestMergeSpecsExtendsEarlierSpec(t *testing.T) {
+	dir := t.TempDir()
+	for path, spec := range map[string]string{
+		"shared/base.md":    "---\nname: base\ndescription: Base reviewer\nabstract: true\ntools: [Read, Grep]\nmodel: opus\n---\n\nFollow the style guide.\n",
+		"local/reviewer.md": "---\nname: reviewer\nextends: base\ndescription: Reviews code\n---\n\nReview the diff.\n",
+	} {
+		if err := core.WriteOutputFile(filepath.Join(dir, path), []byte(spec)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	merged, err := mergeSpecs([]string{filepath.Join(dir, "shared"), filepath.Join(dir, "local")}, options{})
+	if err != nil {
+		t.Fatalf("mergeSpecs() error = %v", err)
+	}
+	if len(merged) != 1 || merged[0].Name != "reviewer" {
+		t.Fatalf("merged = %v, want only the concrete reviewer", merged)
+	}
+	if got := merged[0]; got.Model != "opus" || !reflect.DeepEqual(got.Tools, []string{"Read", "Grep"}) {
+		t.Errorf("reviewer = model %q, tools %v; want the base's opus and [Read Grep]", got.Model, got.Tools)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// specCommit returns the git short SHA of the commit checked out in the
+// repository holding path (a spec directory, bundle file or project), for
+// -stamp-version.
+func specCommit(path string) (string, error) {
+	dir := path
+	if info, err := os.Stat(path); err == nil && !info.IsDir() {
+		dir = filepath.Dir(path)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", "rev-parse", "--short", "HEAD")
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("-stamp-version: no git commit for %s: %w: %s", path, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// addHeaders adds the -stamp-version commit comment and the license header
+// to a generated file with extension ext, license first.
+func (o options) addHeaders(ext string, data []byte) []byte {
+	if o.StampCommit != "" {
+		data = core.AddComment(ext, data, "Generated from spec commit "+o.StampCommit)
+	}
+	return core.AddLicenseHeader(ext, data, o.LicenseHeader)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestSpecCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	spec := filepath.Join(repo, "agents", "reviewer.md")
+	if err := os.MkdirAll(filepath.Dir(spec), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(spec, []byte("---\nname: reviewer\n---\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := specCommit(filepath.Dir(spec)); err == nil {
+		t.Error("specCommit() outside a repository succeeded")
+	}
+
+	git("init", "--quiet")
+	git("add", ".")
+	git("commit", "--quiet", "-m", "specs")
+	for _, path := range []string{filepath.Dir(spec), spec} {
+		commit, err := specCommit(path)
+		if err != nil {
+			t.Fatalf("specCommit(%s) error = %v", path, err)
+		}
+		if !regexp.MustCompile(`^[0-9a-f]{4,}$`).MatchString(commit) {
+			t.Errorf("specCommit(%s) = %q, want a short SHA", path, commit)
+		}
+	}
+}
+
+func TestGenerateAgentsStampCommit(t *testing.T) {
+	agent := core.NewAgent("reviewer", "Reviews code")
+	agent.Version = "1.2.0"
+	opts := options{WriteConcurrency: 1, StampCommit: "abc1234"}
+
+	tests := []struct {
+		format string
+		file   string
+		want   []string
+		absent string
+	}{
+		{"claude", "reviewer.md", []string{"---\n# Generated from spec commit abc1234\nname: reviewer\n", "version: 1.2.0\n"}, ""},
+		{"gemini", "reviewer.toml", []string{"# Generated from spec commit abc1234\n", "# Spec version: 1.2.0\n"}, ""},
+		{"kiro", "reviewer.json", nil, "abc1234"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			outputDir := t.TempDir()
+			if err := generateAgents(io.Discard, io.Discard, []*core.Agent{agent}, tt.format, outputDir, opts); err != nil {
+				t.Fatalf("generateAgents() error = %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(outputDir, tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("%s missing %q:\n%s", tt.file, want, data)
+				}
+			}
+			if tt.absent != "" && strings.Contains(string(data), tt.absent) {
+				t.Errorf("%s has a stamp, but its format has no comments:\n%s", tt.file, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/agentplexus/assistantkit/agents"
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// streamAgents generates the agents in specDir to outputDir in format one
+// at a time (-stream), reading them with agents.WalkCanonical so that
+// the specs are never all held in memory. Each agent is checked, prepared
+// and selected by -only as generateAgents would, but agents are written
+// in order rather than concurrently, and an unknown -only name is only
+// reported once every agent is written.
+func streamAgents(w, warn io.Writer, specDir, format, outputDir string, opts options) error {
+	if info, err := os.Stat(specDir); err == nil && !info.IsDir() {
+		return fmt.Errorf("-stream requires a spec directory, not the bundle %s", specDir)
+	}
+
+	g, err := newAgentGenerator(w, warn, format, outputDir, opts)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	var results []agentResult
+	err = agents.WalkCanonical(specDir, func(agent *core.Agent) error {
+		names = append(names, agent.Name)
+		loaded := []*core.Agent{agent}
+		if err := checkAgents(loaded, opts); err != nil {
+			return err
+		}
+		prepared, err := prepareAgents(warn, loaded, opts)
+		if err != nil {
+			return err
+		}
+		prepared = selectOnly(prepared, opts.Only)
+		if err := g.check(prepared); err != nil {
+			return err
+		}
+		for _, agent := range prepared {
+			res, err := g.write(agent)
+			if err != nil {
+				return err
+			}
+			results = append(results, res)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no agents found in %s", specDir)
+	}
+	if err := checkOnly(names, opts.Only); err != nil {
+		return err
+	}
+	return g.finish(results)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents"
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestStreamAgents(t *testing.T) {
+	specDir := t.TempDir()
+	for name, spec := range map[string]string{
+		"base.md":     "---\nname: base\ndescription: Base\nabstract: true\ntools: [Read]\n---\n\nBe careful.\n",
+		"reviewer.md": "---\nname: reviewer\ndescription: Reviews code\nextends: base\n---\n\nReview the diff.\n",
+		"planner.md":  "---\nname: planner\ndescription: Plans work\ndeprecated: true\n---\n\nPlan the work.\n",
+	} {
+		if err := core.WriteOutputFile(filepath.Join(specDir, name), []byte(spec)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	agentList, err := agents.ReadCanonicalDir(specDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := options{WriteConcurrency: 1}
+	if agentList, err = prepareAgents(io.Discard, agentList, opts); err != nil {
+		t.Fatal(err)
+	}
+	sliceDir := t.TempDir()
+	if err := generateAgents(io.Discard, io.Discard, agentList, "claude", sliceDir, opts); err != nil {
+		t.Fatalf("generateAgents() error = %v", err)
+	}
+
+	streamDir := t.TempDir()
+	var out bytes.Buffer
+	if err := streamAgents(&out, io.Discard, specDir, "claude", streamDir, opts); err != nil {
+		t.Fatalf("streamAgents() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Generated 2 claude agents") {
+		t.Errorf("output = %q", out.String())
+	}
+	for _, name := range []string{"reviewer.md", "planner.md"} {
+		want, err := os.ReadFile(filepath.Join(sliceDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join(streamDir, name))
+		if err != nil {
+			t.Fatalf("streamAgents() did not write %s: %v", name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s differs from generateAgents output:\n%s\nwant:\n%s", name, got, want)
+		}
+	}
+
+	opts.Only = []string{"ghost"}
+	err = streamAgents(io.Discard, io.Discard, specDir, "claude", t.TempDir(), opts)
+	if err == nil || !strings.Contains(err.Error(), `unknown agent "ghost" (available: planner, reviewer)`) {
+		t.Errorf("streamAgents() error = %v, want unknown agent ghost", err)
+	}
+
+	err = streamAgents(io.Discard, io.Discard, t.TempDir(), "claude", t.TempDir(), options{WriteConcurrency: 1})
+	if err == nil || !strings.Contains(err.Error(), "no agents found") {
+		t.Errorf("streamAgents(empty) error = %v, want no agents found", err)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// synthCommand returns the command that synthesizes the CDK app in dir:
+// a global cdk binary, else the project's local one via npx. It returns
+// nil with a reason when synthesis cannot run.
+func synthCommand(ctx context.Context, dir string) (*exec.Cmd, string) {
+	if _, err := os.Stat(filepath.Join(dir, "node_modules")); err != nil {
+		return nil, "dependencies are not installed (run npm install in " + dir + ")"
+	}
+	if cdk, err := exec.LookPath("cdk"); err == nil {
+		return exec.CommandContext(ctx, cdk, "synth", "--quiet"), ""
+	}
+	if _, err := os.Stat(filepath.Join(dir, "node_modules", ".bin", "cdk")); err == nil {
+		if npx, err := exec.LookPath("npx"); err == nil {
+			return exec.CommandContext(ctx, npx, "--no-install", "cdk", "synth", "--quiet"), ""
+		}
+	}
+	return nil, "the CDK CLI is not installed"
+}
+
+// runSynthCheck runs `cdk synth` in the generated CDK project dir and fails
+// if synthesis fails. It skips with a note when the CLI or the project's
+// dependencies are unavailable.
+func runSynthCheck(ctx context.Context, w io.Writer, dir string) error {
+	cmd, reason := synthCommand(ctx, dir)
+	if cmd == nil {
+		fmt.Fprintf(w, "Skipping synth check for %s: %s\n", dir, reason)
+		return nil
+	}
+
+	var out bytes.Buffer
+	cmd.Dir = dir
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("cdk synth failed in %s: %w\n%s", dir, err, strings.TrimSpace(out.String()))
+	}
+	fmt.Fprintf(w, "Synth check passed for %s\n", dir)
+	return nil
+}
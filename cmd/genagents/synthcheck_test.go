@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeCDK puts a cdk script on PATH that prints msg and exits with code.
+func fakeCDK(t *testing.T, msg string, code int) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script fake requires a POSIX shell")
+	}
+	bin := t.TempDir()
+	script := fmt.Sprintf("#!/bin/sh\necho '%s'\nexit %d\n", msg, code)
+	if err := os.WriteFile(filepath.Join(bin, "cdk"), []byte(script), 0700); err != nil { //nolint:gosec // executable test script
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+}
+
+func TestRunSynthCheck(t *testing.T) {
+	project := t.TempDir()
+	if err := os.Mkdir(filepath.Join(project, "node_modules"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("passes", func(t *testing.T) {
+		fakeCDK(t, "ok", 0)
+		var out bytes.Buffer
+		if err := runSynthCheck(context.Background(), &out, project); err != nil {
+			t.Fatalf("runSynthCheck() error = %v", err)
+		}
+		if !strings.Contains(out.String(), "passed") {
+			t.Errorf("output = %q", out.String())
+		}
+	})
+
+	t.Run("fails", func(t *testing.T) {
+		fakeCDK(t, "TS2304: Cannot find name", 1)
+		err := runSynthCheck(context.Background(), &bytes.Buffer{}, project)
+		if err == nil || !strings.Contains(err.Error(), "TS2304") {
+			t.Errorf("runSynthCheck() error = %v, want synth output", err)
+		}
+	})
+
+	t.Run("skips without CLI", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		var out bytes.Buffer
+		if err := runSynthCheck(context.Background(), &out, project); err != nil {
+			t.Fatalf("runSynthCheck() error = %v", err)
+		}
+		if !strings.Contains(out.String(), "not installed") {
+			t.Errorf("output = %q", out.String())
+		}
+	})
+
+	t.Run("skips without dependencies", func(t *testing.T) {
+		fakeCDK(t, "ok", 0)
+		var out bytes.Buffer
+		if err := runSynthCheck(context.Background(), &out, t.TempDir()); err != nil {
+			t.Fatalf("runSynthCheck() error = %v", err)
+		}
+		if !strings.Contains(out.String(), "npm install") {
+			t.Errorf("output = %q", out.String())
+		}
+	})
+}
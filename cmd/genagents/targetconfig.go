@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// configString returns the string option key of target, or "" if it is
+// absent. Any other JSON type is an error naming the key.
+func configString(target Target, key string) (string, error) {
+	raw, ok := target.Config[key]
+	if !ok || raw == nil {
+		return "", nil
+	}
+	value, ok := raw.(string)
+	if !ok {
+		return "", configTypeError(target, key, "a string", raw)
+	}
+	return value, nil
+}
+
+// configInt returns the integer option key of target, or 0 if it is absent.
+// JSON numbers with a fractional part and other types are errors.
+func configInt(target Target, key string) (int, error) {
+	raw, ok := target.Config[key]
+	if !ok || raw == nil {
+		return 0, nil
+	}
+	value, ok := raw.(float64)
+	if !ok || value != math.Trunc(value) {
+		return 0, configTypeError(target, key, "an integer", raw)
+	}
+	return int(value), nil
+}
+
+// configBool returns the boolean option key of target, or false if it is
+// absent. Any other JSON type is an error naming the key.
+func configBool(target Target, key string) (bool, error) {
+	raw, ok := target.Config[key]
+	if !ok || raw == nil {
+		return false, nil
+	}
+	value, ok := raw.(bool)
+	if !ok {
+		return false, configTypeError(target, key, "a boolean", raw)
+	}
+	return value, nil
+}
+
+// configStrings returns the string list option key of target, or nil if
+// it is absent. Any other JSON type, or a non-string entry, is an error
+// naming the key.
+func configStrings(target Target, key string) ([]string, error) {
+	raw, ok := target.Config[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, configTypeError(target, key, "a list of strings", raw)
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		value, ok := item.(string)
+		if !ok {
+			return nil, configTypeError(target, key, "a list of strings", raw)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func configTypeError(target Target, key, want string, got interface{}) error {
+	return fmt.Errorf("target %s: config %q must be %s, got %s", target.Name, key, want, jsonTypeName(got))
+}
+
+// jsonTypeName names the JSON type of a value decoded by encoding/json.
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestTargetConfigAccessors(t *testing.T) {
+	var target Target
+	if err := json.Unmarshal([]byte(`{
+		"name": "aws",
+		"config": {"region": 1234, "stack": "Main", "retries": 3, "ratio": 1.5, "debug": true, "verbose": "yes"}
+	}`), &target); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := configString(target, "stack"); err != nil || got != "Main" {
+		t.Errorf("configString(stack) = %q, %v", got, err)
+	}
+	if got, err := configString(target, "missing"); err != nil || got != "" {
+		t.Errorf("configString(missing) = %q, %v", got, err)
+	}
+	if got, err := configInt(target, "retries"); err != nil || got != 3 {
+		t.Errorf("configInt(retries) = %d, %v", got, err)
+	}
+	if got, err := configBool(target, "debug"); err != nil || !got {
+		t.Errorf("configBool(debug) = %v, %v", got, err)
+	}
+
+	for key, get := range map[string]func() error{
+		"region":  func() error { _, err := configString(target, "region"); return err },
+		"ratio":   func() error { _, err := configInt(target, "ratio"); return err },
+		"verbose": func() error { _, err := configBool(target, "verbose"); return err },
+	} {
+		err := get()
+		if err == nil {
+			t.Errorf("%s: expected type error", key)
+			continue
+		}
+		if !strings.Contains(err.Error(), `"`+key+`"`) || !strings.Contains(err.Error(), "target aws") {
+			t.Errorf("%s: error %q should name the target and key", key, err)
+		}
+	}
+}
+
+func TestGenerateForPlatformRejectsWrongConfigType(t *testing.T) {
+	target := Target{Name: "aws", Platform: "aws-agentcore", Config: map[string]interface{}{"region": float64(1234)}}
+	err := generateForPlatform(io.Discard, io.Discard, "team", nil, target, t.TempDir(), options{})
+	if err == nil || !strings.Contains(err.Error(), `"region"`) {
+		t.Errorf("generateForPlatform() error = %v, want region type error", err)
+	}
+}
+
+func TestGenerateForPlatformModelOverrides(t *testing.T) {
+	agentList := []*core.Agent{core.NewAgent("reviewer", "Reviews code")}
+	target := Target{Name: "prod", Platform: "claude-code", Config: map[string]interface{}{
+		"modelOverrides": map[string]interface{}{"reviewer": "opus", "ghost": "haiku"},
+	}}
+	outputDir := t.TempDir()
+
+	var warn bytes.Buffer
+	if err := generateForPlatform(io.Discard, &warn, "team", agentList, target, outputDir, options{WriteConcurrency: 1}); err != nil {
+		t.Fatalf("generateForPlatform() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "reviewer.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "model: opus") {
+		t.Errorf("reviewer.md does not use the overridden model:\n%s", data)
+	}
+	if agentList[0].Model != core.ModelSonnet {
+		t.Errorf("spec model = %s, want unchanged sonnet", agentList[0].Model)
+	}
+	if warn.String() != "Warning: target prod: modelOverrides names unknown agent ghost\n" {
+		t.Errorf("warnings = %q", warn.String())
+	}
+}
+
+func TestGenerateForPlatformToolFilters(t *testing.T) {
+	coder := core.NewAgent("coder", "Writes code").WithTools("Read", "Write", "Bash")
+	reader := core.NewAgent("reader", "Reads code").WithTools("Bash")
+	target := Target{Name: "prod", Platform: "claude-code", Config: map[string]interface{}{
+		"allowTools": []interface{}{"Read", "Write", "Bash"},
+		"denyTools":  []interface{}{"Bash", "Write"},
+	}}
+	outputDir := t.TempDir()
+
+	var warn bytes.Buffer
+	agentList := []*core.Agent{coder, reader}
+	if err := generateForPlatform(io.Discard, &warn, "team", agentList, target, outputDir, options{WriteConcurrency: 1}); err != nil {
+		t.Fatalf("generateForPlatform() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "coder.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "tools: [Read]\n") {
+		t.Errorf("coder.md does not keep only Read:\n%s", data)
+	}
+	if len(coder.Tools) != 3 {
+		t.Errorf("spec tools = %v, want unchanged", coder.Tools)
+	}
+	if warn.String() != "Warning: target prod: agent reader has no tools after allowTools/denyTools\n" {
+		t.Errorf("warnings = %q", warn.String())
+	}
+
+	target.Config = map[string]interface{}{"denyTools": "Bash"}
+	err = generateForPlatform(io.Discard, io.Discard, "team", agentList, target, outputDir, options{WriteConcurrency: 1})
+	if err == nil || !strings.Contains(err.Error(), `"denyTools" must be a list of strings`) {
+		t.Errorf("generateForPlatform() error = %v, want denyTools type error", err)
+	}
+}
+
+func TestGenerateForPlatformRejectsInvalidAgentCoreConfig(t *testing.T) {
+	tests := []struct {
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{map[string]interface{}{"guardrailId": "gr-1"}, "GuardrailVersion is required"},
+		{map[string]interface{}{"memory": "on"}, `"memory"`},
+		{map[string]interface{}{"memory": map[string]interface{}{"enabled": "yes"}}, `"memory.enabled"`},
+		{map[string]interface{}{"memory": map[string]interface{}{"enabled": true, "ttlDays": float64(400)}}, "between 1 and 365"},
+	}
+	for _, tt := range tests {
+		target := Target{Name: "aws", Platform: "aws-agentcore", Config: tt.config}
+		err := generateForPlatform(io.Discard, io.Discard, "team", nil, target, t.TempDir(), options{})
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.Contains(err.Error(), "target aws") {
+			t.Errorf("generateForPlatform(%v) error = %v, want %q", tt.config, err, tt.wantErr)
+		}
+	}
+}
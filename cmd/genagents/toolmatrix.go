@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// runToolMatrix prints a table of the canonical tools by adapter of the
+// options' registry to w. Each cell is "yes" or "no", or "unknown" for adapters that
+// do not declare their supported tools (see core.ToolSupporter).
+func runToolMatrix(w io.Writer, opts options) error {
+	registry := opts.registry()
+	var adapters []core.Adapter
+	for _, name := range registry.AdapterNames() {
+		if adapter, ok := registry.GetAdapter(name); ok {
+			adapters = append(adapters, adapter)
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, "TOOL")
+	for _, adapter := range adapters {
+		fmt.Fprintf(tw, "\t%s", adapter.Name())
+	}
+	fmt.Fprintln(tw)
+	for _, tool := range core.CanonicalTools() {
+		fmt.Fprint(tw, tool)
+		for _, adapter := range adapters {
+			cell := "unknown"
+			if supported, declared := core.SupportsTool(adapter, tool); declared && supported {
+				cell = "yes"
+			} else if declared {
+				cell = "no"
+			}
+			fmt.Fprintf(tw, "\t%s", cell)
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
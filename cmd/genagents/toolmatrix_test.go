@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunToolMatrix(t *testing.T) {
+	var out bytes.Buffer
+	if err := runToolMatrix(&out, options{}); err != nil {
+		t.Fatalf("runToolMatrix() error = %v", err)
+	}
+
+	rows := make(map[string][]string)
+	var header []string
+	for i, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		fields := strings.Fields(line)
+		if i == 0 {
+			header = fields
+			continue
+		}
+		rows[fields[0]] = fields[1:]
+	}
+	cell := func(tool, adapter string) string {
+		for i, name := range header[1:] {
+			if name == adapter {
+				return rows[tool][i]
+			}
+		}
+		t.Fatalf("no %s column in:\n%s", adapter, out.String())
+		return ""
+	}
+
+	if got := cell("Read", "kiro"); got != "yes" {
+		t.Errorf("Read/kiro = %q, want yes", got)
+	}
+	if got := cell("Bash", "claude"); got != "unknown" {
+		t.Errorf("Bash/claude = %q, want unknown", got)
+	}
+	if len(rows) != 9 {
+		t.Errorf("matrix has %d tool rows, want 9:\n%s", len(rows), out.String())
+	}
+}
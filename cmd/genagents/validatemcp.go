@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// runValidateMCP probes every MCP server declared by the agents and reports
+// unreachable ones per agent. Stdio servers are started briefly, so this is
+// only run on request.
+func runValidateMCP(w io.Writer, agentList []*core.Agent, timeout time.Duration, verbose bool) error {
+	checked, failures := 0, 0
+	for _, agent := range agentList {
+		names := make([]string, 0, len(agent.MCPServers))
+		for name := range agent.MCPServers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			server := agent.MCPServers[name]
+			if !server.IsEnabled() {
+				if verbose {
+					fmt.Fprintf(w, "skip %s/%s (disabled)\n", agent.Name, name)
+				}
+				continue
+			}
+
+			checked++
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			err := server.Probe(ctx)
+			cancel()
+
+			if err != nil {
+				fmt.Fprintf(w, "FAIL %s/%s: %v\n", agent.Name, name, err)
+				failures++
+			} else if verbose {
+				fmt.Fprintf(w, "ok   %s/%s\n", agent.Name, name)
+			}
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d MCP server(s) unreachable", failures, checked)
+	}
+	fmt.Fprintf(w, "All %d MCP server(s) reachable\n", checked)
+	return nil
+}
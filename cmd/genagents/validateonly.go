@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// runValidateOnly parses, validates, and lints the given spec files, as a
+// git pre-commit hook would pass them. Nothing is generated or written.
+// Each problem is printed as one "path: message" line; lint findings are
+// prefixed "warning:" and do not count as errors. It returns the number of
+// errors found, so callers can exit non-zero when it is positive.
+func runValidateOnly(w io.Writer, paths []string, opts options) int {
+	var agentList []*core.Agent
+	errs := 0
+
+	for _, path := range paths {
+		switch filepath.Ext(path) {
+		case ".md", ".json":
+		default:
+			continue // Hooks may pass unrelated staged files
+		}
+
+		agent, err := core.ReadCanonicalFile(path)
+		if err != nil {
+			printIssue(w, path, err)
+			errs++
+			continue
+		}
+		if err := checkSpecs([]*core.Agent{agent}, opts); err != nil {
+			printIssue(w, path, err)
+			errs++
+		}
+		agentList = append(agentList, agent)
+	}
+
+	if err := core.CheckUniqueNames(agentList); err != nil {
+		printIssue(w, "", err)
+		errs++
+	}
+
+	for _, warning := range core.Lint(agentList) {
+		printIssue(w, warning.Source, fmt.Errorf("warning: %s", warning.Message))
+	}
+
+	return errs
+}
+
+// runValidate checks the specs in specDir without generating anything: it
+// reads them (resolving inheritance and running Agent.Validate), then checks
+// each agent's fields, duplicate names and, when validating a -project,
+// every target's platform. Each problem is printed as one line, followed by
+// a summary. Agent warnings (see core.Agent.Warnings), such as unknown
+// tools, are printed too but are not problems. It returns the number of
+// problems found.
+func runValidate(w io.Writer, specDir string, targets []Target, opts options) int {
+	agentList, problems := validateSpecs(w, specDir, targets, nil, opts)
+	if problems > 0 {
+		fmt.Fprintf(w, "Validated %s: %d problem(s)\n", specDir, problems)
+	} else {
+		fmt.Fprintf(w, "Validated %d agents in %s: no problems\n", len(agentList), specDir)
+	}
+	return problems
+}
+
+// validateSpecs runs the checks of runValidate, printing each problem and
+// warning but no summary, and returns the agents read with the number of
+// problems. With -strict-tools, the agents' tools are also checked against
+// each of formats. The -ci validate step uses it too, so both check the
+// same things.
+func validateSpecs(w io.Writer, specDir string, targets []Target, formats []string, opts options) ([]*core.Agent, int) {
+	problems := 0
+	report := func(path string, err error) {
+		printIssue(w, path, err)
+		problems++
+	}
+
+	for _, target := range targets {
+		if err := checkPlatforms([]Target{target}); err != nil {
+			report("deployment.json", err)
+		}
+	}
+
+	agentList, err := readSpecs(specDir, opts)
+	if err != nil {
+		// Validation failures are joined; report each field on its own line
+		for _, err := range splitErrors(err) {
+			var invalid *core.ValidationError
+			if !errors.As(err, &invalid) {
+				report("", err)
+				continue
+			}
+			for _, problem := range invalid.Problems {
+				report(invalid.Path, fmt.Errorf("agent %s: %w", invalid.Agent, problem))
+			}
+		}
+	}
+
+	for _, agent := range agentList {
+		if err := checkSpecs([]*core.Agent{agent}, opts); err != nil {
+			report(agent.SourcePath, err)
+		}
+		for _, msg := range agent.Warnings() {
+			printIssue(w, agent.SourcePath, fmt.Errorf("warning: agent %s: %s", agent.Name, msg))
+		}
+	}
+	if err := core.CheckUniqueNames(agentList); err != nil {
+		report("", err)
+	}
+	for _, format := range formats {
+		if err := checkStrictTools(agentList, format, opts); err != nil {
+			report("", fmt.Errorf("format %s: %w", format, err))
+		}
+	}
+	return agentList, problems
+}
+
+// splitErrors returns the errors joined in err, or err alone. A
+// *core.ValidationError is kept whole, although it unwraps to its fields.
+func splitErrors(err error) []error {
+	if _, ok := err.(*core.ValidationError); ok {
+		return []error{err}
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return []error{err}
+}
+
+// printIssue writes err as a single line, prefixed with path when known.
+// Multi-line messages (e.g. YAML errors) are joined so hook output stays
+// one issue per line.
+func printIssue(w io.Writer, path string, err error) {
+	msg := strings.Join(strings.Fields(strings.ReplaceAll(err.Error(), "\n", " ")), " ")
+	if path != "" {
+		msg = path + ": " + msg
+	}
+	fmt.Fprintln(w, msg)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunValidateOnly(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	good := write("reviewer.md", "---\nname: reviewer\ndescription: Reviews code\ntools: [Read]\n---\n\nUse the `Bash` tool to run tests.\n")
+	dup := write("dup.md", "---\nname: reviewer\ndescription: Also reviews code\n---\n\nReview.\n")
+	badFallback := write("planner.md", "---\nname: planner\ndescription: Plans work\nmodelFallback: [opus, gpt-4]\n---\n\nPlan.\n")
+	broken := write("broken.json", "{not json")
+	other := write("notes.txt", "ignored")
+
+	tests := []struct {
+		name       string
+		paths      []string
+		wantErrs   int
+		wantOutput []string
+	}{
+		{"no files", nil, 0, nil},
+		{"valid with lint warning", []string{good, other}, 0, []string{good + ": warning: "}},
+		{"parse error", []string{broken}, 1, []string{broken + ": "}},
+		{"bad fallback", []string{badFallback}, 1, []string{badFallback + ": "}},
+		{"duplicate names", []string{good, dup}, 1, []string{"reviewer"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if got := runValidateOnly(&out, tt.paths, options{}); got != tt.wantErrs {
+				t.Errorf("runValidateOnly() = %d, want %d\n%s", got, tt.wantErrs, out.String())
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunValidate(t *testing.T) {
+	write := func(dir, name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	clean := t.TempDir()
+	write(clean, "reviewer.md", "---\nname: reviewer\ndescription: Reviews code\ntools: [Read]\n---\n\nReview.\n")
+
+	invalid := t.TempDir()
+	write(invalid, "reviewer.md", "---\nname: reviewer\ndescription: Reviews code\ntools: [Read, bash, mcp__github__create_issue]\ntriggers:\n  - event: on_save\n    action: lint\n---\n")
+	write(invalid, "planner.md", "---\nname: planner\ndescription: Plans work\n---\n\nPlan.\n")
+
+	warned := t.TempDir()
+	write(warned, "reviewer.md", "---\nname: reviewer\ndescription: Reviews code\ntools: [Read, bash]\n---\n\nReview.\n")
+
+	tests := []struct {
+		name       string
+		dir        string
+		targets    []Target
+		want       int
+		wantOutput []string
+	}{
+		{"clean", clean, nil, 0, []string{"Validated 1 agents in " + clean + ": no problems"}},
+		{"one line per problem", invalid, nil, 2, []string{
+			"agent reviewer: instructions is required",
+			`agent reviewer: triggers[0].event "on_save" is not a known trigger event`,
+			": 2 problem(s)",
+		}},
+		{"warnings are not problems", warned, nil, 0, []string{
+			`warning: agent reviewer: tools[1] "bash" is not a known tool (did you mean "Bash"?)`,
+			"Validated 1 agents in " + warned + ": no problems",
+		}},
+		{"unknown platform", clean, []Target{{Name: "ide", Platform: "vscode"}, {Name: "cli", Platform: "claude-code"}}, 1, []string{
+			`deployment.json: target ide: unknown platform "vscode"`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if got := runValidate(&out, tt.dir, tt.targets, options{}); got != tt.want {
+				t.Errorf("runValidate() = %d, want %d\n%s", got, tt.want, out.String())
+			}
+			for _, want := range tt.wantOutput {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, out.String())
+				}
+			}
+		})
+	}
+}
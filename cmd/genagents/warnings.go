@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+// reportWarnings prints the conversion warnings of a format's agents to w,
+// grouped by agent in name order. With asErrors, any warning makes it
+// return an error.
+func reportWarnings(w io.Writer, format string, warnings [][]core.Warning, asErrors bool) error {
+	byAgent := make(map[string][]core.Warning)
+	count := 0
+	for _, agentWarnings := range warnings {
+		for _, warning := range agentWarnings {
+			byAgent[warning.Agent] = append(byAgent[warning.Agent], warning)
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(byAgent))
+	for name := range byAgent {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "Warning: %s conversion of %s:\n", format, name)
+		for _, warning := range byAgent[name] {
+			fmt.Fprintf(w, "  %s: %s\n", warning.Field, warning.Message)
+		}
+	}
+	if asErrors {
+		return fmt.Errorf("%s: %d conversion warnings (-warnings-as-errors)", format, count)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents/core"
+)
+
+func TestGenerateAgentsReportsWarnings(t *testing.T) {
+	agentList := []*core.Agent{
+		core.NewAgent("scout", "Explores").WithModel("gpt-9").WithTools("Read", "Teleport"),
+		core.NewAgent("reviewer", "Reviews code").WithTools("Read"),
+	}
+
+	var warn bytes.Buffer
+	outputDir := t.TempDir()
+	if err := generateAgents(io.Discard, &warn, agentList, "agentkit", outputDir, options{WriteConcurrency: 2}); err != nil {
+		t.Fatalf("generateAgents() error = %v", err)
+	}
+	want := `Warning: agentkit conversion of scout:
+  tools: no agentkit tool for Teleport; passed through unchanged
+  model: unknown model "gpt-9"; passed through unchanged
+`
+	if warn.String() != want {
+		t.Errorf("warnings =\n%s\nwant\n%s", warn.String(), want)
+	}
+
+	opts := options{WriteConcurrency: 2, WarningsAsErrors: true}
+	err := generateAgents(io.Discard, io.Discard, agentList, "agentkit", filepath.Join(outputDir, "strict"), opts)
+	if err == nil || !strings.Contains(err.Error(), "2 conversion warnings") {
+		t.Errorf("generateAgents() error = %v, want warnings-as-errors failure", err)
+	}
+	if err := generateAgents(io.Discard, io.Discard, agentList[1:], "agentkit", filepath.Join(outputDir, "clean"), opts); err != nil {
+		t.Errorf("generateAgents() error = %v for agents without warnings", err)
+	}
+}
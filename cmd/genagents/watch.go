@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce is how long -watch waits after the last spec change before
+// regenerating, so an editor's burst of writes triggers one run.
+const watchDebounce = 200 * time.Millisecond
+
+// runWatch runs generate, then runs it again after every burst of changes
+// to spec files (.md or .json) under specDir, or to specDir itself if it is
+// a bundle file, until ctx is done. Each run is reported with a timestamp;
+// a failed run is reported to w and watching continues.
+func runWatch(ctx context.Context, w io.Writer, specDir string, generate func() error) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("starting watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// Spec directories are read recursively, so watch every subdirectory. A
+	// bundle is watched through its directory, since editors often replace
+	// the file rather than write to it.
+	bundle := ""
+	if info, err := os.Stat(specDir); err == nil && !info.IsDir() {
+		bundle = filepath.Clean(specDir)
+		if err := watcher.Add(filepath.Dir(bundle)); err != nil {
+			return fmt.Errorf("watching %s: %w", bundle, err)
+		}
+	} else if err := watchTree(watcher, specDir); err != nil {
+		return err
+	}
+
+	regenerate := func() {
+		start := time.Now()
+		if err := generate(); err != nil {
+			fmt.Fprintf(w, "[%s] Error: %v\n", start.Format(time.TimeOnly), err)
+			return
+		}
+		fmt.Fprintf(w, "[%s] Regenerated in %s\n", start.Format(time.TimeOnly), time.Since(start).Round(time.Millisecond))
+	}
+	regenerate()
+	fmt.Fprintf(w, "Watching %s for changes (Ctrl-C to stop)\n", specDir)
+
+	// The timer only runs while changes are pending
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if bundle != "" {
+				if filepath.Clean(event.Name) == bundle {
+					timer.Reset(watchDebounce)
+				}
+				continue
+			}
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := watchTree(watcher, event.Name); err != nil {
+						fmt.Fprintf(w, "Warning: %v\n", err)
+					}
+					continue
+				}
+			}
+			if ext := filepath.Ext(event.Name); ext != ".md" && ext != ".json" {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) != 0 {
+				timer.Reset(watchDebounce)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Fprintf(w, "Warning: %v\n", err)
+
+		case <-timer.C:
+			regenerate()
+		}
+	}
+}
+
+// watchTree adds dir and every directory below it to watcher.
+func watchTree(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if err := watcher.Add(path); err != nil {
+			return fmt.Errorf("watching %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// generateFlagTargets reads the specs in specDir and generates every
+// -targets (or -format/-output) target from them, as a normal run does.
+func generateFlagTargets(w, warn io.Writer, specDir string, targets []flagTarget, opts options) error {
+	agentList, err := readSpecs(specDir, opts)
+	if err != nil {
+		return err
+	}
+	if len(agentList) == 0 {
+		return fmt.Errorf("no agents found in %s", specDir)
+	}
+	if err := checkSpecs(agentList, opts); err != nil {
+		return err
+	}
+	agentList, err = prepareAgents(warn, agentList, opts)
+	if err != nil {
+		return err
+	}
+
+	var dirs []string
+	for _, target := range targets {
+		if err := generateAgents(w, warn, agentList, target.Format, target.Output, opts); err != nil {
+			return fmt.Errorf("generating %s agents: %w", target.Format, err)
+		}
+		dirs = append(dirs, target.Output)
+	}
+	return finishOutputs(w, dirs, opts)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunWatch(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var runs atomic.Int32
+	ran := make(chan struct{}, 10)
+	generate := func() error {
+		defer func() { ran <- struct{}{} }()
+		if runs.Add(1) == 2 {
+			return errors.New("bad spec")
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- runWatch(ctx, io.Discard, dir, generate) }()
+
+	wait := func(what string) {
+		t.Helper()
+		select {
+		case <-ran:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+	wait("the initial run")
+
+	// A burst of writes, in a subdirectory too, regenerates once; the
+	// failing run does not stop the watch
+	for i := 0; i < 5; i++ {
+		if err := os.WriteFile(filepath.Join(dir, "data", "loader.md"), []byte("---\nname: loader\n---\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wait("the failing run")
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "reviewer.md"), []byte("---\nname: reviewer\n---\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	wait("the run after the fix")
+
+	time.Sleep(2 * watchDebounce)
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("runWatch() error = %v", err)
+	}
+	if n := runs.Load(); n != 3 {
+		t.Errorf("generate ran %d times, want 3", n)
+	}
+}
+
+func TestGenerateFlagTargetsBundle(t *testing.T) {
+	dir := t.TempDir()
+	bundle := filepath.Join(dir, "agents.yaml")
+	spec := "- name: lead\n  description: Leads\n  instructions: Lead.\n- name: reviewer\n  description: Reviews\n  tools: [Read]\n  instructions: Review.\n"
+	if err := os.WriteFile(bundle, []byte(spec), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "out")
+	targets := []flagTarget{{Format: "claude", Output: out}}
+	if err := generateFlagTargets(io.Discard, io.Discard, bundle, targets, options{}); err != nil {
+		t.Fatalf("generateFlagTargets() error = %v", err)
+	}
+	for _, name := range []string{"lead.md", "reviewer.md"} {
+		if _, err := os.Stat(filepath.Join(out, name)); err != nil {
+			t.Errorf("%s not generated: %v", name, err)
+		}
+	}
+}
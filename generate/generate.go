@@ -95,6 +95,11 @@ func Plugins(specDir, outputDir string, platforms []string) (*Result, error) {
 	}
 	result.AgentCount = len(agts)
 
+	// Tag the plugin with its agents' category unless the spec sets one
+	if plugin.Category == "" {
+		plugin.Category = sharedCategory(agts)
+	}
+
 	// Generate each platform
 	for _, platform := range platforms {
 		platformDir := filepath.Join(outputDir, platform)
@@ -122,6 +127,22 @@ func Plugins(specDir, outputDir string, platforms []string) (*Result, error) {
 	return result, nil
 }
 
+// sharedCategory returns the category every categorized agent has in
+// common, or "" if there is none or they disagree.
+func sharedCategory(agts []*agents.Agent) string {
+	category := ""
+	for _, agt := range agts {
+		switch {
+		case agt.Category == "":
+		case category == "":
+			category = agt.Category
+		case category != agt.Category:
+			return ""
+		}
+	}
+	return category
+}
+
 func loadPlugin(path string) (*PluginSpec, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -0,0 +1,124 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/agentplexus/assistantkit/agents"
+)
+
+// TagResult contains the results of tag-grouped plugin generation.
+type TagResult struct {
+	// AgentCount is the number of agents loaded.
+	AgentCount int
+
+	// PluginDirs maps each tag to its plugin's output directory.
+	PluginDirs map[string]string
+}
+
+// pluginTag matches tags usable in a plugin name.
+var pluginTag = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// ClaudePluginsByTag generates one Claude plugin per tag from a canonical
+// spec directory, bundling the agents carrying that tag.
+//
+// Each plugin is written to outputDir/claude/<plugin>-<tag>/ with its own
+// manifest, derived from the spec's plugin.json, and agents/ directory. An
+// agent with several of the tags is included in each of their plugins.
+// Commands and skills are not bundled. Every selected tag must have at
+// least one agent, and every agent must have at least one selected tag.
+func ClaudePluginsByTag(specDir, outputDir string, tags []string) (*TagResult, error) {
+	plugin, err := loadPlugin(filepath.Join(specDir, "plugin.json"))
+	if err != nil {
+		return nil, fmt.Errorf("loading plugin spec: %w", err)
+	}
+
+	agts, err := loadAgents(filepath.Join(specDir, "agents"))
+	if err != nil {
+		return nil, fmt.Errorf("loading agents: %w", err)
+	}
+
+	groups, err := groupAgentsByTag(agts, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TagResult{
+		AgentCount: len(agts),
+		PluginDirs: make(map[string]string, len(tags)),
+	}
+	for _, tag := range tags {
+		members := groups[tag]
+
+		groupPlugin := *plugin
+		groupPlugin.Name = plugin.Name + "-" + tag
+		groupPlugin.Description = fmt.Sprintf("%s (%s agents)", plugin.Description, tag)
+		groupPlugin.Commands = ""
+		groupPlugin.Skills = ""
+		groupPlugin.Hooks = ""
+		if groupPlugin.Category == "" {
+			groupPlugin.Category = sharedCategory(members)
+		}
+
+		dir := filepath.Join(outputDir, "claude", groupPlugin.Name)
+		if err := generateClaude(dir, &groupPlugin, nil, nil, members); err != nil {
+			return nil, fmt.Errorf("generating claude plugin for tag %s: %w", tag, err)
+		}
+		result.PluginDirs[tag] = dir
+	}
+
+	return result, nil
+}
+
+// groupAgentsByTag returns the agents carrying each tag, in load order. It
+// reports every invalid or duplicate tag, tag without agents, and agent
+// without a selected tag.
+func groupAgentsByTag(agts []*agents.Agent, tags []string) (map[string][]*agents.Agent, error) {
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("no tags selected")
+	}
+
+	var errs []error
+	groups := make(map[string][]*agents.Agent, len(tags))
+	for _, tag := range tags {
+		switch _, dup := groups[tag]; {
+		case !pluginTag.MatchString(tag):
+			errs = append(errs, fmt.Errorf("tag %q must be lowercase letters, digits and hyphens", tag))
+		case dup:
+			errs = append(errs, fmt.Errorf("tag %q selected more than once", tag))
+		}
+		groups[tag] = nil
+	}
+
+	var ungrouped []string
+	for _, agt := range agts {
+		grouped := false
+		for _, tag := range agt.Tags {
+			if _, ok := groups[tag]; ok && !slices.Contains(groups[tag], agt) {
+				groups[tag] = append(groups[tag], agt)
+				grouped = true
+			}
+		}
+		if !grouped {
+			ungrouped = append(ungrouped, agt.Name)
+		}
+	}
+
+	for _, tag := range tags {
+		if len(groups[tag]) == 0 && pluginTag.MatchString(tag) {
+			errs = append(errs, fmt.Errorf("no agents have tag %q", tag))
+		}
+	}
+	if len(ungrouped) > 0 {
+		errs = append(errs, fmt.Errorf("agents without a selected tag: %s", strings.Join(ungrouped, ", ")))
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return groups, nil
+}
@@ -0,0 +1,113 @@
+package generate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/agents"
+)
+
+func writeJSON(t *testing.T, path string, v interface{}) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func taggedAgent(name string, tags ...string) *agents.Agent {
+	agt := agents.NewAgent(name, "Agent "+name)
+	agt.Instructions = "Do " + name + "."
+	agt.Tags = tags
+	return agt
+}
+
+func TestClaudePluginsByTag(t *testing.T) {
+	specDir := t.TempDir()
+	writeJSON(t, filepath.Join(specDir, "plugin.json"), map[string]string{"name": "toolkit", "version": "1.0.0", "description": "Team toolkit"})
+	for _, agt := range []*agents.Agent{
+		taggedAgent("loader", "data"),
+		taggedAgent("reporter", "data", "ops"),
+		taggedAgent("pager", "ops"),
+	} {
+		writeJSON(t, filepath.Join(specDir, "agents", agt.Name+".json"), agt)
+	}
+
+	outputDir := t.TempDir()
+	result, err := ClaudePluginsByTag(specDir, outputDir, []string{"data", "ops"})
+	if err != nil {
+		t.Fatalf("ClaudePluginsByTag() error = %v", err)
+	}
+
+	wantAgents := map[string][]string{
+		"data": {"loader", "reporter"},
+		"ops":  {"pager", "reporter"},
+	}
+	for tag, names := range wantAgents {
+		dir := result.PluginDirs[tag]
+		if dir != filepath.Join(outputDir, "claude", "toolkit-"+tag) {
+			t.Errorf("PluginDirs[%s] = %s", tag, dir)
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, ".claude-plugin", "plugin.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var manifest struct{ Name string }
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			t.Fatal(err)
+		}
+		if manifest.Name != "toolkit-"+tag {
+			t.Errorf("manifest name = %q, want toolkit-%s", manifest.Name, tag)
+		}
+
+		entries, err := os.ReadDir(filepath.Join(dir, "agents"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, entry := range entries {
+			got = append(got, strings.TrimSuffix(entry.Name(), ".md"))
+		}
+		if strings.Join(got, ",") != strings.Join(names, ",") {
+			t.Errorf("%s plugin agents = %v, want %v", tag, got, names)
+		}
+	}
+}
+
+func TestGroupAgentsByTagErrors(t *testing.T) {
+	agts := []*agents.Agent{taggedAgent("loader", "data"), taggedAgent("misc")}
+
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"no tags", nil, []string{"no tags selected"}},
+		{"ungrouped agent", []string{"data"}, []string{"agents without a selected tag: misc"}},
+		{"empty and invalid tags", []string{"data", "ops", "Data"}, []string{`no agents have tag "ops"`, `tag "Data" must be lowercase`}},
+		{"duplicate tag", []string{"data", "data"}, []string{`tag "data" selected more than once`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := groupAgentsByTag(agts, tt.tags)
+			if err == nil {
+				t.Fatal("groupAgentsByTag() should fail")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q missing %q", err, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+)
+
+// ProbeProtocolVersion is the MCP protocol version sent by Probe.
+const ProbeProtocolVersion = "2025-03-26"
+
+// Probe checks that the server is reachable. Stdio servers are started and
+// must answer an MCP initialize request; the process is killed afterwards.
+// HTTP servers must accept an initialize POST, and SSE servers must open an
+// event stream. The context bounds the whole check.
+//
+// Probe has side effects (it runs the server command), so callers should
+// only use it when explicitly requested.
+func (s *Server) Probe(ctx context.Context) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	switch s.InferTransport() {
+	case TransportStdio:
+		return s.probeStdio(ctx)
+	case TransportHTTP:
+		return s.probeHTTP(ctx)
+	case TransportSSE:
+		return s.probeSSE(ctx)
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidTransport, s.Transport)
+	}
+}
+
+// initializeRequest returns a JSON-RPC initialize request.
+func initializeRequest() []byte {
+	req := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "initialize",
+		"params": map[string]interface{}{
+			"protocolVersion": ProbeProtocolVersion,
+			"capabilities":    map[string]interface{}{},
+			"clientInfo": map[string]interface{}{
+				"name":    "assistantkit",
+				"version": "1.0.0",
+			},
+		},
+	}
+	data, _ := json.Marshal(req)
+	return data
+}
+
+// rpcResponse is the subset of a JSON-RPC response that Probe inspects.
+type rpcResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (s *Server) probeStdio(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, s.Command, s.Args...) //nolint:gosec // G204: running the configured server is the point of the probe
+	cmd.Dir = s.Cwd
+	cmd.Env = os.Environ()
+	for k, v := range s.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start %s: %w", s.Command, err)
+	}
+	defer func() {
+		cancel()
+		_ = cmd.Wait()
+	}()
+
+	if _, err := stdin.Write(append(initializeRequest(), '\n')); err != nil {
+		return fmt.Errorf("write initialize: %w", err)
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var resp rpcResponse
+			if json.Unmarshal(scanner.Bytes(), &resp) != nil || string(resp.ID) != "1" {
+				continue // Skip notifications and non-JSON log lines
+			}
+			result <- checkInitializeResponse(&resp)
+			return
+		}
+		result <- fmt.Errorf("server exited without answering initialize")
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("no initialize response: %w", ctx.Err())
+	}
+}
+
+func (s *Server) probeHTTP(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(initializeRequest()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	s.setHeaders(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("initialize returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *Server) probeSSE(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	s.setHeaders(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	// Don't drain: the event stream stays open until closed
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("event stream returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *Server) setHeaders(req *http.Request) {
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+	if s.BearerTokenEnvVar != "" {
+		if token := os.Getenv(s.BearerTokenEnvVar); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+}
+
+func checkInitializeResponse(resp *rpcResponse) error {
+	if resp.Error != nil {
+		return fmt.Errorf("initialize failed: %s (code %d)", resp.Error.Message, resp.Error.Code)
+	}
+	if len(resp.Result) == 0 {
+		return fmt.Errorf("initialize response has no result")
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestProbeHelperProcess is not a real test; it acts as a stdio MCP server
+// when re-executed by TestProbeStdio.
+func TestProbeHelperProcess(t *testing.T) {
+	if os.Getenv("PROBE_HELPER") == "" {
+		return
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	if scanner.Scan() {
+		fmt.Println("starting up") // Non-JSON output must be skipped
+		switch os.Getenv("PROBE_HELPER") {
+		case "ok":
+			fmt.Println(`{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-03-26"}}`)
+		case "error":
+			fmt.Println(`{"jsonrpc":"2.0","id":1,"error":{"code":-32600,"message":"bad request"}}`)
+		}
+	}
+	os.Exit(0)
+}
+
+func TestProbeStdio(t *testing.T) {
+	tests := []struct {
+		mode    string
+		wantErr bool
+	}{
+		{"ok", false},
+		{"error", true},
+		{"silent", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			s := &Server{
+				Command: os.Args[0],
+				Args:    []string{"-test.run=TestProbeHelperProcess"},
+				Env:     map[string]string{"PROBE_HELPER": tt.mode},
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			err := s.Probe(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Probe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProbeRemote(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method == http.MethodGet {
+			w.Header().Set("Content-Type", "text/event-stream")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	headers := map[string]string{"Authorization": "Bearer token"}
+	tests := []struct {
+		name    string
+		server  Server
+		wantErr bool
+	}{
+		{"http", Server{URL: ts.URL, Headers: headers}, false},
+		{"sse", Server{Transport: TransportSSE, URL: ts.URL, Headers: headers}, false},
+		{"unauthorized", Server{URL: ts.URL}, true},
+		{"unreachable", Server{URL: "http://127.0.0.1:1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			err := tt.server.Probe(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Probe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ type ClaudePlugin struct {
 	License    string `json:"license,omitempty"`
 	Repository string `json:"repository,omitempty"`
 	Homepage   string `json:"homepage,omitempty"`
+	Category   string `json:"category,omitempty"`
 
 	// MCP Servers - embedded directly in plugin.json for consolidated config
 	MCPServers map[string]MCPServerConfig `json:"mcpServers,omitempty"`
@@ -71,6 +72,7 @@ func (cp *ClaudePlugin) ToCanonical() *core.Plugin {
 		License:     cp.License,
 		Repository:  cp.Repository,
 		Homepage:    cp.Homepage,
+		Category:    cp.Category,
 		Commands:    cp.Commands,
 		Skills:      cp.Skills,
 		Agents:      cp.Agents,
@@ -102,6 +104,7 @@ func FromCanonical(p *core.Plugin) *ClaudePlugin {
 		License:     p.License,
 		Repository:  p.Repository,
 		Homepage:    p.Homepage,
+		Category:    p.Category,
 	}
 
 	// Set default paths if components are specified
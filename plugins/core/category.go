@@ -0,0 +1,42 @@
+package core
+
+import (
+	"slices"
+	"strings"
+)
+
+// MarketplaceCategories lists the categories accepted by the Claude plugin
+// marketplace.
+var MarketplaceCategories = []string{
+	"automation",
+	"data",
+	"database",
+	"deployment",
+	"design",
+	"development",
+	"documentation",
+	"learning",
+	"monitoring",
+	"productivity",
+	"security",
+	"testing",
+}
+
+// IsMarketplaceCategory reports whether category is a known marketplace
+// category.
+func IsMarketplaceCategory(category string) bool {
+	return slices.Contains(MarketplaceCategories, category)
+}
+
+// ValidateCategory returns an UnknownCategoryError if category is set and
+// not a known marketplace category. name identifies the plugin or agent.
+func ValidateCategory(name, category string) error {
+	if category == "" || IsMarketplaceCategory(category) {
+		return nil
+	}
+	return &UnknownCategoryError{Name: name, Category: category}
+}
+
+func categoryList() string {
+	return strings.Join(MarketplaceCategories, ", ")
+}
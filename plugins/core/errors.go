@@ -71,3 +71,14 @@ type ValidationError struct {
 func (e *ValidationError) Error() string {
 	return fmt.Sprintf("validation error: %s: %s", e.Field, e.Message)
 }
+
+// UnknownCategoryError occurs when a plugin or agent declares a category
+// that is not in MarketplaceCategories.
+type UnknownCategoryError struct {
+	Name     string
+	Category string
+}
+
+func (e *UnknownCategoryError) Error() string {
+	return fmt.Sprintf("%s: unknown marketplace category %q (known: %s)", e.Name, e.Category, categoryList())
+}
@@ -12,6 +12,7 @@ type Plugin struct {
 	License     string `json:"license,omitempty"`
 	Repository  string `json:"repository,omitempty"`
 	Homepage    string `json:"homepage,omitempty"`
+	Category    string `json:"category,omitempty"` // Marketplace category
 
 	// Components - paths to spec files
 	Commands string `json:"commands,omitempty"` // Directory containing command specs
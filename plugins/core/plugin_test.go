@@ -69,3 +69,23 @@ func TestPluginAddMCPServer(t *testing.T) {
 		t.Errorf("expected Command 'npx', got '%s'", server.Command)
 	}
 }
+
+func TestValidateCategory(t *testing.T) {
+	tests := []struct {
+		category string
+		wantErr  bool
+	}{
+		{"", false},
+		{"development", false},
+		{"security", false},
+		{"Development", true},
+		{"games", true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateCategory("my-plugin", tt.category)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateCategory(%q) error = %v, wantErr %v", tt.category, err, tt.wantErr)
+		}
+	}
+}
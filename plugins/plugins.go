@@ -54,8 +54,12 @@ var (
 	Convert            = core.Convert
 	ReadCanonicalFile  = core.ReadCanonicalFile
 	WriteCanonicalFile = core.WriteCanonicalFile
+	ValidateCategory   = core.ValidateCategory
 )
 
+// MarketplaceCategories lists the known marketplace categories.
+var MarketplaceCategories = core.MarketplaceCategories
+
 // Re-export error types
 type (
 	ParseError      = core.ParseError
@@ -63,4 +67,6 @@ type (
 	ReadError       = core.ReadError
 	WriteError      = core.WriteError
 	ValidationError = core.ValidationError
+
+	UnknownCategoryError = core.UnknownCategoryError
 )
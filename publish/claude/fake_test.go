@@ -0,0 +1,184 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	gogithub "github.com/google/go-github/v81/github"
+
+	"github.com/agentplexus/assistantkit/publish/github"
+)
+
+// fakeGitHub is an in-memory githubClient. It serves the marketplace's
+// index and base branch files, records the forks, branches, commits and
+// PRs it is asked for, and fails the methods named in fail. It is safe
+// for concurrent use.
+type fakeGitHub struct {
+	mu       sync.Mutex
+	user     string
+	index    string           // marketplace index; empty lists no plugins
+	upstream []string         // files on the marketplace's base branch
+	fail     map[string]error // errors to return, by method name
+
+	calls    []string          // methods called, in order
+	forks    []string          // owner/repo of each fork ensured
+	branches map[string]string // owner/repo:branch to its base SHA
+	commits  []string          // commit messages
+	deleted  []string          // files deleted by commits
+	prs      []string          // PR titles
+	labels   []string          // labels added to the last PR
+	dryRuns  map[string]bool   // PR title to the dry run mode it was created in
+	retries  int               // MaxRetries of the last call
+}
+
+// call records a call to method and returns its injected error, if any.
+func (f *fakeGitHub) call(method string) error {
+	f.calls = append(f.calls, method)
+	return f.fail[method]
+}
+
+func (f *fakeGitHub) WithOptions(dryRun bool, maxRetries int, retryBaseDelay time.Duration) githubClient {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.retries = maxRetries
+	return fakeCall{fakeGitHub: f, dryRun: dryRun}
+}
+
+// fakeCall is the client of one call to a fakeGitHub, recording the dry
+// run mode each PR is created in.
+type fakeCall struct {
+	*fakeGitHub
+	dryRun bool
+}
+
+func (c fakeCall) CreatePR(ctx context.Context, upstreamOwner, upstreamRepo, forkOwner, branch, baseBranch, title, body string) (*gogithub.PullRequest, error) {
+	pr, err := c.fakeGitHub.CreatePR(ctx, upstreamOwner, upstreamRepo, forkOwner, branch, baseBranch, title, body)
+	if err == nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.dryRuns == nil {
+			c.dryRuns = make(map[string]bool)
+		}
+		c.dryRuns[title] = c.dryRun
+	}
+	return pr, err
+}
+
+func (f *fakeGitHub) GetAuthenticatedUser(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("GetAuthenticatedUser"); err != nil {
+		return "", err
+	}
+	if f.user == "" {
+		return "octocat", nil
+	}
+	return f.user, nil
+}
+
+func (f *fakeGitHub) EnsureFork(ctx context.Context, upstreamOwner, upstreamRepo, forkOwner string) (string, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("EnsureFork"); err != nil {
+		return "", "", err
+	}
+	f.forks = append(f.forks, forkOwner+"/"+upstreamRepo)
+	return forkOwner, upstreamRepo, nil
+}
+
+func (f *fakeGitHub) GetBranchSHA(ctx context.Context, owner, repoName, branch string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("GetBranchSHA"); err != nil {
+		return "", err
+	}
+	return "base-sha", nil
+}
+
+func (f *fakeGitHub) CreateBranch(ctx context.Context, owner, repoName, branch, baseSHA string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("CreateBranch"); err != nil {
+		return err
+	}
+	if f.branches == nil {
+		f.branches = make(map[string]string)
+	}
+	f.branches[owner+"/"+repoName+":"+branch] = baseSHA
+	return nil
+}
+
+func (f *fakeGitHub) CreateCommit(ctx context.Context, owner, repoName, branch, message string, files []github.FileContent) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("CreateCommit"); err != nil {
+		return "", err
+	}
+	f.commits = append(f.commits, message)
+	return fmt.Sprintf("commit-%d", len(f.commits)), nil
+}
+
+func (f *fakeGitHub) ListFiles(ctx context.Context, owner, repoName, ref, dir string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("ListFiles"); err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, file := range f.upstream {
+		if strings.HasPrefix(file, dir+"/") {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+func (f *fakeGitHub) GetFileContent(ctx context.Context, owner, repoName, ref, filePath string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("GetFileContent"); err != nil {
+		return nil, err
+	}
+	if f.index == "" {
+		return []byte(`{"plugins": []}`), nil
+	}
+	return []byte(f.index), nil
+}
+
+func (f *fakeGitHub) DeleteFiles(ctx context.Context, owner, repoName, branch, message string, paths []string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("DeleteFiles"); err != nil {
+		return "", err
+	}
+	f.commits = append(f.commits, message)
+	f.deleted = append(f.deleted, paths...)
+	return fmt.Sprintf("commit-%d", len(f.commits)), nil
+}
+
+func (f *fakeGitHub) CreatePR(ctx context.Context, upstreamOwner, upstreamRepo, forkOwner, branch, baseBranch, title, body string) (*gogithub.PullRequest, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("CreatePR"); err != nil {
+		return nil, err
+	}
+	f.prs = append(f.prs, title)
+	number := len(f.prs)
+	return &gogithub.PullRequest{
+		Number:  gogithub.Ptr(number),
+		HTMLURL: gogithub.Ptr(fmt.Sprintf("https://github.com/%s/%s/pull/%d", upstreamOwner, upstreamRepo, number)),
+	}, nil
+}
+
+func (f *fakeGitHub) AddLabels(ctx context.Context, owner, repoName string, number int, labels []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call("AddLabels"); err != nil {
+		return err
+	}
+	f.labels = labels
+	return nil
+}
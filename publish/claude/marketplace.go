@@ -3,10 +3,18 @@ package claude
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
+	gogithub "github.com/google/go-github/v81/github"
+
+	pluginscore "github.com/agentplexus/assistantkit/plugins/core"
 	"github.com/agentplexus/assistantkit/publish/core"
 	"github.com/agentplexus/assistantkit/publish/github"
 )
@@ -23,24 +31,77 @@ const (
 
 	// DefaultBaseBranch is the default branch to target.
 	DefaultBaseBranch = "main"
+
+	// MarketplaceIndexFile is the marketplace's list of plugins within the
+	// marketplace repo.
+	MarketplaceIndexFile = ".claude-plugin/marketplace.json"
+)
+
+// PR labels marking a first submission or an update of a listed plugin.
+const (
+	LabelNewPlugin = "new-plugin"
+	LabelUpdate    = "update"
 )
 
+// ManifestFile is the plugin manifest path within a plugin directory.
+const ManifestFile = core.PluginManifestFile
+
 // RequiredFiles lists files that must exist in a plugin.
 var RequiredFiles = []string{
-	".claude-plugin/plugin.json",
+	ManifestFile,
 	"README.md",
 }
 
-// Publisher submits plugins to the Claude Code official marketplace.
+// Publisher submits plugins to the Claude Code official marketplace. It
+// is safe for concurrent use: each call configures its own client.
 type Publisher struct {
-	client *github.Client
+	client githubClient
 	config core.MarketplaceConfig
 }
 
+// githubClient is the part of *github.Client the publisher uses: forking,
+// reading and creating branches, committing files and opening PRs.
+type githubClient interface {
+	// WithOptions returns a client for one call with dry run mode and
+	// retries set, leaving the receiver unchanged.
+	WithOptions(dryRun bool, maxRetries int, retryBaseDelay time.Duration) githubClient
+
+	GetAuthenticatedUser(ctx context.Context) (string, error)
+	EnsureFork(ctx context.Context, upstreamOwner, upstreamRepo, forkOwner string) (string, string, error)
+	GetBranchSHA(ctx context.Context, owner, repoName, branch string) (string, error)
+	CreateBranch(ctx context.Context, owner, repoName, branch, baseSHA string) error
+	CreateCommit(ctx context.Context, owner, repoName, branch, message string, files []github.FileContent) (string, error)
+	ListFiles(ctx context.Context, owner, repoName, ref, dir string) ([]string, error)
+	GetFileContent(ctx context.Context, owner, repoName, ref, filePath string) ([]byte, error)
+	DeleteFiles(ctx context.Context, owner, repoName, branch, message string, paths []string) (string, error)
+	CreatePR(ctx context.Context, upstreamOwner, upstreamRepo, forkOwner, branch, baseBranch, title, body string) (*gogithub.PullRequest, error)
+	AddLabels(ctx context.Context, owner, repoName string, number int, labels []string) error
+}
+
+// apiClient adapts *github.Client to githubClient.
+type apiClient struct {
+	*github.Client
+}
+
+// WithOptions returns a configured copy of the client.
+func (c apiClient) WithOptions(dryRun bool, maxRetries int, retryBaseDelay time.Duration) githubClient {
+	return apiClient{c.Client.WithOptions(dryRun, maxRetries, retryBaseDelay)}
+}
+
+// Option configures a Publisher created by NewPublisher.
+type Option func(*Publisher)
+
+// WithClient makes the publisher call GitHub through client instead of a
+// *github.Client for the token, e.g., an in-memory fake in tests.
+func WithClient(client githubClient) Option {
+	return func(p *Publisher) {
+		p.client = client
+	}
+}
+
 // NewPublisher creates a new Claude marketplace publisher.
-func NewPublisher(token string) *Publisher {
-	return &Publisher{
-		client: github.NewClient(token),
+func NewPublisher(token string, opts ...Option) *Publisher {
+	p := &Publisher{
 		config: core.MarketplaceConfig{
 			Owner:         MarketplaceOwner,
 			Repo:          MarketplaceRepo,
@@ -49,6 +110,13 @@ func NewPublisher(token string) *Publisher {
 			RequiredFiles: RequiredFiles,
 		},
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.client == nil {
+		p.client = apiClient{github.NewClient(token)}
+	}
+	return p
 }
 
 // Name returns the marketplace identifier.
@@ -77,38 +145,76 @@ func (p *Publisher) Validate(pluginDir string) error {
 	return nil
 }
 
-// Publish submits the plugin to the Claude Code marketplace.
+// Exists reports whether the marketplace index on the base branch lists
+// the plugin name and, if so, the version it records.
+func (p *Publisher) Exists(ctx context.Context, name string) (bool, string, error) {
+	return p.exists(ctx, p.client, name)
+}
+
+// exists implements Exists with client.
+func (p *Publisher) exists(ctx context.Context, client githubClient, name string) (bool, string, error) {
+	data, err := client.GetFileContent(ctx, p.config.Owner, p.config.Repo, p.config.BaseBranch, MarketplaceIndexFile)
+	if err != nil {
+		return false, "", err
+	}
+	return findIndexEntry(data, name)
+}
+
+// findIndexEntry looks name up among the plugins of a marketplace index.
+func findIndexEntry(index []byte, name string) (bool, string, error) {
+	var parsed struct {
+		Plugins []struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"plugins"`
+	}
+	if err := json.Unmarshal(index, &parsed); err != nil {
+		return false, "", fmt.Errorf("parse %s: %w", MarketplaceIndexFile, err)
+	}
+	for _, plugin := range parsed.Plugins {
+		if plugin.Name == name {
+			return true, plugin.Version, nil
+		}
+	}
+	return false, "", nil
+}
+
+// Publish submits the plugin to the Claude Code marketplace. A plugin the
+// marketplace index already lists is submitted as an update: its files
+// are replaced, files it no longer has are deleted, the default branch,
+// commit message, title and body say "update", and the PR is labeled
+// LabelUpdate instead of LabelNewPlugin. An index that cannot be read
+// fails the publish before anything is pushed, since it leaves new and
+// updated plugins indistinguishable. Labels that cannot be applied
+// (e.g., without triage access to the marketplace) are noted in the
+// result's Status rather than failing the publish.
 func (p *Publisher) Publish(ctx context.Context, opts core.PublishOptions) (*core.PublishResult, error) {
 	// Validate first
 	if err := p.Validate(opts.PluginDir); err != nil {
 		return nil, err
 	}
+	if err := core.ValidatePlugin(opts.PluginDir, opts.PluginName); err != nil {
+		return nil, err
+	}
+	if err := checkCategory(opts); err != nil {
+		return nil, err
+	}
 
-	p.client.SetDryRun(opts.DryRun)
+	client := p.client.WithOptions(opts.DryRun, opts.MaxRetries, opts.RetryBaseDelay)
 
-	// Get authenticated user if fork owner not specified
-	forkOwner := opts.ForkOwner
-	if forkOwner == "" {
-		user, err := p.client.GetAuthenticatedUser(ctx)
-		if err != nil {
-			return nil, err
-		}
-		forkOwner = user
+	update, version, err := p.exists(ctx, client, opts.PluginName)
+	if err != nil {
+		return nil, fmt.Errorf("check marketplace index: %w", err)
 	}
-
-	// Ensure fork exists
-	if opts.Verbose {
-		fmt.Printf("Ensuring fork of %s/%s exists for %s...\n",
-			p.config.Owner, p.config.Repo, forkOwner)
+	if update && opts.Verbose {
+		fmt.Printf("Marketplace lists %s %s; submitting an update\n", opts.PluginName, version)
 	}
-	forkOwner, forkRepo, err := p.client.EnsureFork(ctx, p.config.Owner, p.config.Repo, forkOwner)
-	if err != nil {
-		return nil, err
+	labels := []string{LabelNewPlugin}
+	if update {
+		labels = []string{LabelUpdate}
 	}
 
-	// Get base branch SHA
-	baseBranch := p.config.BaseBranch
-	baseSHA, err := p.client.GetBranchSHA(ctx, p.config.Owner, p.config.Repo, baseBranch)
+	commitMsg, title, body, err := renderPublishText(opts, update)
 	if err != nil {
 		return nil, err
 	}
@@ -117,15 +223,16 @@ func (p *Publisher) Publish(ctx context.Context, opts core.PublishOptions) (*cor
 	branch := opts.Branch
 	if branch == "" {
 		branch = fmt.Sprintf("add-%s", opts.PluginName)
+		if update {
+			branch = fmt.Sprintf("update-%s", opts.PluginName)
+		}
 	}
 
-	// Create branch in fork
-	if opts.Verbose {
-		fmt.Printf("Creating branch %s...\n", branch)
-	}
-	if err := p.client.CreateBranch(ctx, forkOwner, forkRepo, branch, baseSHA); err != nil {
+	forkOwner, forkRepo, err := p.prepareBranch(ctx, client, opts.ForkOwner, branch, opts.Verbose)
+	if err != nil {
 		return nil, err
 	}
+	baseBranch := p.config.BaseBranch
 
 	// Read local plugin files
 	destPath := filepath.Join(p.config.PluginPath, opts.PluginName)
@@ -133,6 +240,11 @@ func (p *Publisher) Publish(ctx context.Context, opts core.PublishOptions) (*cor
 	if err != nil {
 		return nil, err
 	}
+	if opts.Category != "" {
+		if err := setFileCategory(files, filepath.ToSlash(filepath.Join(destPath, ManifestFile)), opts.Category); err != nil {
+			return nil, err
+		}
+	}
 
 	if opts.Verbose {
 		fmt.Printf("Adding %d files to %s...\n", len(files), destPath)
@@ -142,34 +254,40 @@ func (p *Publisher) Publish(ctx context.Context, opts core.PublishOptions) (*cor
 	}
 
 	// Create commit
-	commitMsg := fmt.Sprintf("Add %s plugin", opts.PluginName)
 	if opts.Verbose {
 		fmt.Printf("Creating commit: %s\n", commitMsg)
 	}
-	_, err = p.client.CreateCommit(ctx, forkOwner, forkRepo, branch, commitMsg, files)
+	_, err = client.CreateCommit(ctx, forkOwner, forkRepo, branch, commitMsg, files)
 	if err != nil {
-		return nil, err
+		return nil, &core.CommitError{Message: commitMsg, Err: err}
 	}
 
-	// Create PR title and body
-	title := opts.Title
-	if title == "" {
-		title = fmt.Sprintf("Add %s plugin", opts.PluginName)
+	// Delete the files an update no longer has
+	var removed []string
+	if update {
+		if removed, err = p.staleFiles(ctx, client, destPath, files); err != nil {
+			return nil, err
+		}
 	}
-
-	body := opts.Body
-	if body == "" {
-		body = generatePRBody(opts.PluginName, opts.PluginDir)
+	if len(removed) > 0 {
+		if opts.Verbose {
+			fmt.Printf("Removing %d files no longer in the plugin...\n", len(removed))
+		}
+		msg := fmt.Sprintf("Remove files no longer in %s plugin", opts.PluginName)
+		if _, err := client.DeleteFiles(ctx, forkOwner, forkRepo, branch, msg, removed); err != nil {
+			return nil, &core.CommitError{Message: msg, Err: err}
+		}
 	}
 
 	// Create PR
 	if opts.Verbose {
 		fmt.Printf("Creating PR: %s\n", title)
 	}
-	pr, err := p.client.CreatePR(ctx, p.config.Owner, p.config.Repo, forkOwner, branch, baseBranch, title, body)
+	pr, err := client.CreatePR(ctx, p.config.Owner, p.config.Repo, forkOwner, branch, baseBranch, title, body)
 	if err != nil {
-		return nil, err
+		return nil, &core.PRError{Title: title, Err: err}
 	}
+	labelErr := client.AddLabels(ctx, p.config.Owner, p.config.Repo, pr.GetNumber(), labels)
 
 	// Build file list
 	var fileNames []string
@@ -179,21 +297,228 @@ func (p *Publisher) Publish(ctx context.Context, opts core.PublishOptions) (*cor
 
 	status := "PR created successfully"
 	if opts.DryRun {
-		status = "Dry run completed - no PR created"
+		status = fmt.Sprintf("Dry run completed - no PR created; would open %q from %s:%s into %s/%s:%s adding %d files",
+			title, forkOwner, branch, p.config.Owner, p.config.Repo, baseBranch, len(files))
+	}
+	if labelErr != nil {
+		status += fmt.Sprintf("; labels %s not applied: %v", strings.Join(labels, ", "), labelErr)
 	}
 
 	return &core.PublishResult{
-		PRURL:      pr.GetHTMLURL(),
-		PRNumber:   pr.GetNumber(),
-		Branch:     branch,
-		ForkURL:    fmt.Sprintf("https://github.com/%s/%s", forkOwner, forkRepo),
-		Status:     status,
-		FilesAdded: fileNames,
+		PRURL:        pr.GetHTMLURL(),
+		PRNumber:     pr.GetNumber(),
+		Branch:       branch,
+		ForkURL:      fmt.Sprintf("https://github.com/%s/%s", forkOwner, forkRepo),
+		Status:       status,
+		DryRun:       opts.DryRun,
+		Labels:       labels,
+		FilesAdded:   fileNames,
+		FilesRemoved: removed,
 	}, nil
 }
 
-// generatePRBody creates a default PR description.
-func generatePRBody(pluginName, pluginDir string) string {
+// staleFiles returns the files under destPath on the marketplace's base
+// branch that are not among files.
+func (p *Publisher) staleFiles(ctx context.Context, client githubClient, destPath string, files []github.FileContent) ([]string, error) {
+	upstream, err := client.ListFiles(ctx, p.config.Owner, p.config.Repo, p.config.BaseBranch, filepath.ToSlash(destPath))
+	if err != nil {
+		return nil, err
+	}
+	var stale []string
+	for _, file := range upstream {
+		if !slices.ContainsFunc(files, func(f github.FileContent) bool { return filepath.ToSlash(f.Path) == file }) {
+			stale = append(stale, file)
+		}
+	}
+	return stale, nil
+}
+
+// Unpublish opens a PR deleting the plugin's directory from the Claude Code
+// marketplace. The plugin must exist on the marketplace's base branch.
+func (p *Publisher) Unpublish(ctx context.Context, opts core.UnpublishOptions) (*core.PublishResult, error) {
+	if name := opts.PluginName; name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return nil, &core.ValidationError{PluginDir: opts.PluginName, Message: "a plugin name without path separators is required"}
+	}
+
+	client := p.client.WithOptions(opts.DryRun, opts.MaxRetries, opts.RetryBaseDelay)
+
+	// Check the plugin exists upstream before proposing its deletion
+	pluginPath := path.Join(p.config.PluginPath, opts.PluginName)
+	files, err := client.ListFiles(ctx, p.config.Owner, p.config.Repo, p.config.BaseBranch, pluginPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, &core.PluginNotFoundError{Owner: p.config.Owner, Repo: p.config.Repo, Path: pluginPath}
+	}
+
+	branch := opts.Branch
+	if branch == "" {
+		branch = fmt.Sprintf("remove-%s", opts.PluginName)
+	}
+
+	forkOwner, forkRepo, err := p.prepareBranch(ctx, client, opts.ForkOwner, branch, opts.Verbose)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Verbose {
+		fmt.Printf("Removing %d files from %s...\n", len(files), pluginPath)
+		for _, f := range files {
+			fmt.Printf("  %s\n", f)
+		}
+	}
+
+	commitMsg := fmt.Sprintf("Remove %s plugin", opts.PluginName)
+	if _, err := client.DeleteFiles(ctx, forkOwner, forkRepo, branch, commitMsg, files); err != nil {
+		return nil, &core.CommitError{Message: commitMsg, Err: err}
+	}
+
+	title := opts.Title
+	if title == "" {
+		title = fmt.Sprintf("Remove %s plugin", opts.PluginName)
+	}
+	body := opts.Body
+	if body == "" {
+		body = generateRemovalPRBody(opts.PluginName, opts.Reason)
+	}
+
+	if opts.Verbose {
+		fmt.Printf("Creating PR: %s\n", title)
+	}
+	pr, err := client.CreatePR(ctx, p.config.Owner, p.config.Repo, forkOwner, branch, p.config.BaseBranch, title, body)
+	if err != nil {
+		return nil, &core.PRError{Title: title, Err: err}
+	}
+
+	status := "PR created successfully"
+	if opts.DryRun {
+		status = fmt.Sprintf("Dry run completed - no PR created; would open %q from %s:%s into %s/%s:%s removing %d files",
+			title, forkOwner, branch, p.config.Owner, p.config.Repo, p.config.BaseBranch, len(files))
+	}
+
+	return &core.PublishResult{
+		PRURL:        pr.GetHTMLURL(),
+		PRNumber:     pr.GetNumber(),
+		Branch:       branch,
+		ForkURL:      fmt.Sprintf("https://github.com/%s/%s", forkOwner, forkRepo),
+		Status:       status,
+		DryRun:       opts.DryRun,
+		FilesRemoved: files,
+	}, nil
+}
+
+// prepareBranch ensures forkOwner (default: the authenticated user) has a
+// fork of the marketplace and creates branch in it from the base branch.
+// It returns the fork owner and repository.
+func (p *Publisher) prepareBranch(ctx context.Context, client githubClient, forkOwner, branch string, verbose bool) (string, string, error) {
+	// Get authenticated user if fork owner not specified
+	if forkOwner == "" {
+		user, err := client.GetAuthenticatedUser(ctx)
+		if err != nil {
+			return "", "", err
+		}
+		forkOwner = user
+	}
+
+	// Ensure fork exists
+	if verbose {
+		fmt.Printf("Ensuring fork of %s/%s exists for %s...\n",
+			p.config.Owner, p.config.Repo, forkOwner)
+	}
+	forkOwner, forkRepo, err := client.EnsureFork(ctx, p.config.Owner, p.config.Repo, forkOwner)
+	if err != nil {
+		return "", "", &core.ForkError{Owner: p.config.Owner, Repo: p.config.Repo, Err: err}
+	}
+
+	// Get base branch SHA
+	baseSHA, err := client.GetBranchSHA(ctx, p.config.Owner, p.config.Repo, p.config.BaseBranch)
+	if err != nil {
+		return "", "", &core.BranchError{Branch: p.config.BaseBranch, Err: err}
+	}
+
+	// Create branch in fork
+	if verbose {
+		fmt.Printf("Creating branch %s...\n", branch)
+	}
+	if err := client.CreateBranch(ctx, forkOwner, forkRepo, branch, baseSHA); err != nil {
+		return "", "", &core.BranchError{Branch: branch, Err: err}
+	}
+	return forkOwner, forkRepo, nil
+}
+
+// checkCategory rejects an unknown marketplace category, taken from
+// opts.Category or else the plugin manifest, unless opts allow it.
+func checkCategory(opts core.PublishOptions) error {
+	category := opts.Category
+	if category == "" {
+		data, err := os.ReadFile(filepath.Join(opts.PluginDir, ManifestFile))
+		if err != nil {
+			return err
+		}
+		var manifest struct {
+			Category string `json:"category"`
+		}
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return &core.ValidationError{PluginDir: opts.PluginDir, Message: fmt.Sprintf("invalid %s: %v", ManifestFile, err)}
+		}
+		category = manifest.Category
+	}
+
+	if opts.AllowUnknownCategory {
+		return nil
+	}
+	if err := pluginscore.ValidateCategory(opts.PluginName, category); err != nil {
+		return &core.ValidationError{PluginDir: opts.PluginDir, Message: err.Error()}
+	}
+	return nil
+}
+
+// setFileCategory sets the category in the manifest among files.
+func setFileCategory(files []github.FileContent, manifestPath, category string) error {
+	for i := range files {
+		if filepath.ToSlash(files[i].Path) != manifestPath {
+			continue
+		}
+		var manifest map[string]interface{}
+		if err := json.Unmarshal(files[i].Content, &manifest); err != nil {
+			return fmt.Errorf("parse %s: %w", manifestPath, err)
+		}
+		manifest["category"] = category
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return err
+		}
+		files[i].Content = append(data, '\n')
+		return nil
+	}
+	return fmt.Errorf("%s not found in plugin files", manifestPath)
+}
+
+// renderPublishText renders the commit message, PR title and PR body
+// templates of opts, defaulting those not set. With update set, the
+// default commit message and title update the plugin instead of adding it.
+func renderPublishText(opts core.PublishOptions, update bool) (commitMsg, title, body string, err error) {
+	data := core.NewTemplateData(opts.PluginName, filepath.Join(opts.PluginDir, ManifestFile))
+	defaultTitle := fmt.Sprintf("Add %s plugin", opts.PluginName)
+	if update {
+		defaultTitle = fmt.Sprintf("Update %s plugin", opts.PluginName)
+	}
+	if commitMsg, err = core.RenderTemplate("commit message", opts.CommitMessage, defaultTitle, data); err != nil {
+		return "", "", "", err
+	}
+	if title, err = core.RenderTemplate("title", opts.Title, defaultTitle, data); err != nil {
+		return "", "", "", err
+	}
+	if body, err = core.RenderTemplate("body", opts.Body, generatePRBody(opts.PluginName, opts.PluginDir, update), data); err != nil {
+		return "", "", "", err
+	}
+	return commitMsg, title, body, nil
+}
+
+// generatePRBody creates a default PR description, for an update of a
+// listed plugin if update is set.
+func generatePRBody(pluginName, pluginDir string, update bool) string {
 	// Try to read README for description
 	readmePath := filepath.Join(pluginDir, "README.md")
 	readme, err := os.ReadFile(readmePath)
@@ -204,11 +529,11 @@ func generatePRBody(pluginName, pluginDir string) string {
 		description = extractDescription(string(readme))
 	}
 
-	body := fmt.Sprintf(`## Summary
-
-Adding the **%s** plugin to the Claude Code marketplace.
-
-`, pluginName)
+	summary := "Adding the **%s** plugin to the Claude Code marketplace."
+	if update {
+		summary = "Updating the **%s** plugin in the Claude Code marketplace."
+	}
+	body := "## Summary\n\n" + fmt.Sprintf(summary, pluginName) + "\n\n"
 
 	if description != "" {
 		body += fmt.Sprintf("### Description\n\n%s\n\n", description)
@@ -226,6 +551,24 @@ Adding the **%s** plugin to the Claude Code marketplace.
 	return body
 }
 
+// generateRemovalPRBody creates a default PR description for a removal.
+func generateRemovalPRBody(pluginName, reason string) string {
+	body := fmt.Sprintf(`## Summary
+
+Removing the **%s** plugin from the Claude Code marketplace.
+
+`, pluginName)
+
+	if reason != "" {
+		body += fmt.Sprintf("### Reason\n\n%s\n\n", reason)
+	}
+
+	body += "---\n\n"
+	body += "*Submitted via [aiassistkit](https://github.com/agentplexus/assistantkit) publish tool*\n"
+
+	return body
+}
+
 // extractDescription extracts the first paragraph after a markdown title.
 func extractDescription(readme string) string {
 	lines := splitLines(readme)
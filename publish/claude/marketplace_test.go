@@ -1,9 +1,17 @@
 package claude
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	gogithub "github.com/google/go-github/v81/github"
+
+	"github.com/agentplexus/assistantkit/publish/core"
+	"github.com/agentplexus/assistantkit/publish/github"
 )
 
 func TestPublisher_Name(t *testing.T) {
@@ -101,3 +109,171 @@ func TestMarketplaceConfig(t *testing.T) {
 		t.Errorf("ExternalPluginsPath = %q, want %q", ExternalPluginsPath, "external_plugins")
 	}
 }
+
+func TestCheckCategory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".claude-plugin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `{"name": "test-plugin", "version": "1.0.0", "category": "games"}`
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(manifest), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    core.PublishOptions
+		wantErr bool
+	}{
+		{"unknown manifest category", core.PublishOptions{PluginDir: dir}, true},
+		{"override allowed", core.PublishOptions{PluginDir: dir, AllowUnknownCategory: true}, false},
+		{"known category option", core.PublishOptions{PluginDir: dir, Category: "testing"}, false},
+		{"unknown category option", core.PublishOptions{PluginDir: dir, Category: "toys"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkCategory(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkCategory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetFileCategory(t *testing.T) {
+	files := []github.FileContent{
+		{Path: "external_plugins/test-plugin/README.md", Content: []byte("# Test")},
+		{Path: "external_plugins/test-plugin/.claude-plugin/plugin.json", Content: []byte(`{"name": "test-plugin"}`)},
+	}
+
+	if err := setFileCategory(files, "external_plugins/test-plugin/.claude-plugin/plugin.json", "testing"); err != nil {
+		t.Fatalf("setFileCategory() error = %v", err)
+	}
+	if got := string(files[1].Content); !strings.Contains(got, `"category": "testing"`) {
+		t.Errorf("manifest missing category: %s", got)
+	}
+
+	if err := setFileCategory(files[:1], "external_plugins/test-plugin/.claude-plugin/plugin.json", "testing"); err == nil {
+		t.Error("expected error when manifest is missing")
+	}
+}
+
+func TestUnpublishRejectsInvalidName(t *testing.T) {
+	p := NewPublisher("test-token")
+	for _, name := range []string{"", ".", "..", "a/b", `a\b`} {
+		_, err := p.Unpublish(context.Background(), core.UnpublishOptions{PluginName: name, DryRun: true})
+		var valErr *core.ValidationError
+		if !errors.As(err, &valErr) {
+			t.Errorf("Unpublish(%q) error = %v, want ValidationError", name, err)
+		}
+	}
+}
+
+func TestPublishDryRunValidates(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewPublisher("test-token")
+	_, err := p.Publish(context.Background(), core.PublishOptions{PluginDir: dir, PluginName: "test-plugin", DryRun: true})
+	var valErr *core.ValidationError
+	if !errors.As(err, &valErr) || len(valErr.Missing) != 1 || valErr.Missing[0] != ManifestFile {
+		t.Errorf("Publish() error = %v, want ValidationError missing %s", err, ManifestFile)
+	}
+}
+
+func TestFilesUnder(t *testing.T) {
+	entries := []*gogithub.TreeEntry{
+		{Path: gogithub.Ptr("external_plugins/foo"), Type: gogithub.Ptr("tree")},
+		{Path: gogithub.Ptr("external_plugins/foo/README.md"), Type: gogithub.Ptr("blob")},
+		{Path: gogithub.Ptr("external_plugins/foo/.claude-plugin/plugin.json"), Type: gogithub.Ptr("blob")},
+		{Path: gogithub.Ptr("external_plugins/foobar/README.md"), Type: gogithub.Ptr("blob")},
+	}
+
+	got := github.FilesUnder(entries, "external_plugins/foo")
+	want := []string{"external_plugins/foo/README.md", "external_plugins/foo/.claude-plugin/plugin.json"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("FilesUnder() = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateRemovalPRBody(t *testing.T) {
+	body := generateRemovalPRBody("old-plugin", "Superseded by new-plugin")
+	for _, want := range []string{"Removing the **old-plugin** plugin", "### Reason", "Superseded by new-plugin"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(generateRemovalPRBody("old-plugin", ""), "### Reason") {
+		t.Error("reason section should be omitted without a reason")
+	}
+}
+
+func TestRenderPublishText(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".claude-plugin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(`{"name": "test-plugin", "version": "1.2.0"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	commitMsg, title, body, err := renderPublishText(core.PublishOptions{
+		PluginDir:     dir,
+		PluginName:    "test-plugin",
+		CommitMessage: "Release {{.Name}} v{{.Version}}",
+		Body:          "Changes in {{.Version}}. Refs PLAT-42.",
+	}, false)
+	if err != nil {
+		t.Fatalf("renderPublishText() error = %v", err)
+	}
+	if commitMsg != "Release test-plugin v1.2.0" {
+		t.Errorf("commit message = %q", commitMsg)
+	}
+	if title != "Add test-plugin plugin" {
+		t.Errorf("title = %q, want the default", title)
+	}
+	if body != "Changes in 1.2.0. Refs PLAT-42." {
+		t.Errorf("body = %q", body)
+	}
+
+	_, title, _, err = renderPublishText(core.PublishOptions{PluginDir: dir, PluginName: "test-plugin"}, true)
+	if err != nil || title != "Update test-plugin plugin" {
+		t.Errorf("renderPublishText(update) title = %q, error = %v", title, err)
+	}
+
+	_, _, _, err = renderPublishText(core.PublishOptions{PluginDir: dir, PluginName: "test-plugin", Title: "{{.Ticket}}"}, false)
+	var valErr *core.ValidationError
+	if !errors.As(err, &valErr) || !strings.Contains(err.Error(), "invalid title template") {
+		t.Errorf("renderPublishText() error = %v, want invalid title template", err)
+	}
+}
+
+func TestFindIndexEntry(t *testing.T) {
+	index := []byte(`{"name": "claude-plugins-official", "plugins": [
+		{"name": "linter", "version": "1.4.0", "source": "./external_plugins/linter"},
+		{"name": "formatter", "source": "./external_plugins/formatter"}
+	]}`)
+
+	tests := []struct {
+		name        string
+		wantExists  bool
+		wantVersion string
+	}{
+		{"linter", true, "1.4.0"},
+		{"formatter", true, ""},
+		{"missing", false, ""},
+	}
+	for _, tt := range tests {
+		exists, version, err := findIndexEntry(index, tt.name)
+		if err != nil || exists != tt.wantExists || version != tt.wantVersion {
+			t.Errorf("findIndexEntry(%q) = %v, %q, %v; want %v, %q", tt.name, exists, version, err, tt.wantExists, tt.wantVersion)
+		}
+	}
+
+	if _, _, err := findIndexEntry([]byte("not json"), "linter"); err == nil {
+		t.Error("findIndexEntry() accepted an invalid index")
+	}
+}
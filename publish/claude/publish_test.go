@@ -0,0 +1,214 @@
+package claude
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/publish/core"
+)
+
+func writeTestPlugin(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".claude-plugin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestFile), []byte(`{"name": "test-plugin", "version": "1.2.0", "category": "productivity"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Test Plugin\n\nA test plugin.\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestPublishFlow(t *testing.T) {
+	client := &fakeGitHub{user: "alice"}
+	p := NewPublisher("test-token", WithClient(client))
+
+	result, err := p.Publish(context.Background(), core.PublishOptions{PluginDir: writeTestPlugin(t), PluginName: "test-plugin"})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	wantCalls := []string{"GetFileContent", "GetAuthenticatedUser", "EnsureFork", "GetBranchSHA", "CreateBranch", "CreateCommit", "CreatePR", "AddLabels"}
+	if !reflect.DeepEqual(client.calls, wantCalls) {
+		t.Errorf("calls = %q, want %q", client.calls, wantCalls)
+	}
+	if !reflect.DeepEqual(client.forks, []string{"alice/" + MarketplaceRepo}) {
+		t.Errorf("forks = %q", client.forks)
+	}
+	if client.branches["alice/"+MarketplaceRepo+":add-test-plugin"] != "base-sha" {
+		t.Errorf("branches = %v", client.branches)
+	}
+	if result.PRNumber != 1 || result.ForkURL != "https://github.com/alice/"+MarketplaceRepo {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestPublishNewAndUpdate(t *testing.T) {
+	tests := []struct {
+		name        string
+		client      *fakeGitHub
+		wantBranch  string
+		wantCommits []string
+		wantLabels  []string
+		wantRemoved []string
+	}{
+		{
+			name:        "new plugin",
+			client:      &fakeGitHub{index: `{"plugins": [{"name": "other"}]}`},
+			wantBranch:  "add-test-plugin",
+			wantCommits: []string{"Add test-plugin plugin"},
+			wantLabels:  []string{LabelNewPlugin},
+		},
+		{
+			name: "update",
+			client: &fakeGitHub{
+				index:    `{"plugins": [{"name": "test-plugin", "version": "1.1.0"}]}`,
+				upstream: []string{"external_plugins/test-plugin/README.md", "external_plugins/test-plugin/commands/old.md"},
+			},
+			wantBranch:  "update-test-plugin",
+			wantCommits: []string{"Update test-plugin plugin", "Remove files no longer in test-plugin plugin"},
+			wantLabels:  []string{LabelUpdate},
+			wantRemoved: []string{"external_plugins/test-plugin/commands/old.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPublisher("test-token", WithClient(tt.client))
+
+			result, err := p.Publish(context.Background(), core.PublishOptions{PluginDir: writeTestPlugin(t), PluginName: "test-plugin"})
+			if err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+			if result.Branch != tt.wantBranch {
+				t.Errorf("Branch = %q, want %q", result.Branch, tt.wantBranch)
+			}
+			if !reflect.DeepEqual(tt.client.commits, tt.wantCommits) {
+				t.Errorf("commits = %q, want %q", tt.client.commits, tt.wantCommits)
+			}
+			if !reflect.DeepEqual(tt.client.prs, tt.wantCommits[:1]) {
+				t.Errorf("PR titles = %q, want %q", tt.client.prs, tt.wantCommits[:1])
+			}
+			if !reflect.DeepEqual(result.Labels, tt.wantLabels) || !reflect.DeepEqual(tt.client.labels, tt.wantLabels) {
+				t.Errorf("Labels = %q, applied %q, want %q", result.Labels, tt.client.labels, tt.wantLabels)
+			}
+			if !reflect.DeepEqual(result.FilesRemoved, tt.wantRemoved) || !reflect.DeepEqual(tt.client.deleted, tt.wantRemoved) {
+				t.Errorf("FilesRemoved = %q, deleted %q, want %q", result.FilesRemoved, tt.client.deleted, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestPublishErrors(t *testing.T) {
+	boom := errors.New("boom")
+	tests := []struct {
+		method string
+		want   any
+	}{
+		{"EnsureFork", new(*core.ForkError)},
+		{"GetBranchSHA", new(*core.BranchError)},
+		{"CreateBranch", new(*core.BranchError)},
+		{"CreateCommit", new(*core.CommitError)},
+		{"CreatePR", new(*core.PRError)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			client := &fakeGitHub{fail: map[string]error{tt.method: boom}}
+			p := NewPublisher("test-token", WithClient(client))
+
+			_, err := p.Publish(context.Background(), core.PublishOptions{PluginDir: writeTestPlugin(t), PluginName: "test-plugin"})
+			if !errors.As(err, tt.want) {
+				t.Errorf("Publish() error = %T %v, want %T", err, err, tt.want)
+			}
+			if !errors.Is(err, boom) {
+				t.Errorf("Publish() error = %v, does not wrap the client error", err)
+			}
+		})
+	}
+}
+
+func TestPublishUnreadableIndex(t *testing.T) {
+	boom := errors.New("502 Bad Gateway")
+	client := &fakeGitHub{fail: map[string]error{"GetFileContent": boom}}
+	p := NewPublisher("test-token", WithClient(client))
+
+	_, err := p.Publish(context.Background(), core.PublishOptions{PluginDir: writeTestPlugin(t), PluginName: "test-plugin"})
+	if !errors.Is(err, boom) {
+		t.Fatalf("Publish() error = %v, want the index error", err)
+	}
+	if !reflect.DeepEqual(client.calls, []string{"GetFileContent"}) {
+		t.Errorf("calls = %q, want nothing after the index check", client.calls)
+	}
+}
+
+func TestPublishLabelFailure(t *testing.T) {
+	client := &fakeGitHub{fail: map[string]error{"AddLabels": errors.New("403 Forbidden")}}
+	p := NewPublisher("test-token", WithClient(client))
+
+	result, err := p.Publish(context.Background(), core.PublishOptions{PluginDir: writeTestPlugin(t), PluginName: "test-plugin"})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if result.PRNumber != 1 || !strings.Contains(result.Status, "labels new-plugin not applied: 403 Forbidden") {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestUnpublishFlow(t *testing.T) {
+	client := &fakeGitHub{upstream: []string{"external_plugins/test-plugin/README.md", "external_plugins/other/README.md"}}
+	p := NewPublisher("test-token", WithClient(client))
+
+	result, err := p.Unpublish(context.Background(), core.UnpublishOptions{PluginName: "test-plugin", MaxRetries: 2})
+	if err != nil {
+		t.Fatalf("Unpublish() error = %v", err)
+	}
+	if client.retries != 2 {
+		t.Errorf("client configured with %d retries, want 2", client.retries)
+	}
+	if !reflect.DeepEqual(client.deleted, []string{"external_plugins/test-plugin/README.md"}) || result.Branch != "remove-test-plugin" {
+		t.Errorf("deleted = %q, result = %+v", client.deleted, result)
+	}
+
+	_, err = p.Unpublish(context.Background(), core.UnpublishOptions{PluginName: "missing"})
+	var notFound *core.PluginNotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("Unpublish(missing) error = %v, want *core.PluginNotFoundError", err)
+	}
+}
+
+func TestPublishAllMixedDryRun(t *testing.T) {
+	client := &fakeGitHub{}
+	p := NewPublisher("test-token", WithClient(client))
+	dir := writeTestPlugin(t)
+
+	var jobs []core.PublishJob
+	for i := range 8 {
+		jobs = append(jobs, core.PublishJob{Publisher: p, Options: core.PublishOptions{
+			PluginDir:  dir,
+			PluginName: "test-plugin",
+			Branch:     fmt.Sprintf("add-test-plugin-%d", i),
+			Title:      fmt.Sprintf("Add test-plugin %d", i),
+			DryRun:     i%2 == 0,
+		}})
+	}
+
+	for i, result := range core.PublishAll(context.Background(), jobs) {
+		opts := jobs[i].Options
+		if result.Err != nil {
+			t.Fatalf("%s: %v", opts.Title, result.Err)
+		}
+		if dryRun, ok := client.dryRuns[opts.Title]; !ok || dryRun != opts.DryRun {
+			t.Errorf("%s: created with dry run %v, want %v", opts.Title, dryRun, opts.DryRun)
+		}
+	}
+}
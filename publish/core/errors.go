@@ -1,21 +1,39 @@
 package core
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ValidationError indicates the plugin failed validation.
 type ValidationError struct {
 	PluginDir string
 	Message   string
 	Missing   []string // Missing required files
+	Problems  []string // Manifest problems found by ValidatePlugin
 }
 
 func (e *ValidationError) Error() string {
 	if len(e.Missing) > 0 {
 		return fmt.Sprintf("validation failed for %s: missing files: %v", e.PluginDir, e.Missing)
 	}
+	if len(e.Problems) > 0 {
+		return fmt.Sprintf("validation failed for %s: %s", e.PluginDir, strings.Join(e.Problems, "; "))
+	}
 	return fmt.Sprintf("validation failed for %s: %s", e.PluginDir, e.Message)
 }
 
+// PluginNotFoundError indicates the plugin is not in the marketplace repo.
+type PluginNotFoundError struct {
+	Owner string
+	Repo  string
+	Path  string
+}
+
+func (e *PluginNotFoundError) Error() string {
+	return fmt.Sprintf("plugin not found: %s/%s has no %s", e.Owner, e.Repo, e.Path)
+}
+
 // ForkError indicates a failure to fork the repository.
 type ForkError struct {
 	Owner string
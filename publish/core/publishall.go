@@ -0,0 +1,55 @@
+package core
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultPublishConcurrency is the maximum number of jobs PublishAll runs
+// at once.
+const DefaultPublishConcurrency = 4
+
+// PublishJob pairs a publisher with the options for one submission.
+type PublishJob struct {
+	Publisher Publisher
+	Options   PublishOptions
+}
+
+// PublishAll runs jobs concurrently, at most DefaultPublishConcurrency at a
+// time, and returns one result per job in job order. A failed job's result
+// has Err set; other jobs still run. Jobs not yet started when ctx is
+// cancelled fail with the context error. Jobs may share a Publisher, so
+// publishers must apply each call's options to that call only.
+func PublishAll(ctx context.Context, jobs []PublishJob) []PublishResult {
+	results := make([]PublishResult, len(jobs))
+	sem := make(chan struct{}, DefaultPublishConcurrency)
+
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i] = PublishResult{Err: ctx.Err()}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, job PublishJob) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := ctx.Err(); err != nil {
+				results[i] = PublishResult{Err: err}
+				return
+			}
+			result, err := job.Publisher.Publish(ctx, job.Options)
+			if result != nil {
+				results[i] = *result
+			}
+			results[i].Err = err
+		}(i, job)
+	}
+	wg.Wait()
+
+	return results
+}
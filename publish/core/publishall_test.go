@@ -0,0 +1,78 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakePublisher struct {
+	BasePublisher
+
+	active, peak atomic.Int32
+	fail         string
+}
+
+func (p *fakePublisher) Name() string              { return "fake" }
+func (p *fakePublisher) Validate(dir string) error { return nil }
+
+func (p *fakePublisher) Publish(ctx context.Context, opts PublishOptions) (*PublishResult, error) {
+	n := p.active.Add(1)
+	defer p.active.Add(-1)
+	for {
+		peak := p.peak.Load()
+		if n <= peak || p.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	if opts.PluginName == p.fail {
+		return nil, errors.New("boom")
+	}
+	return &PublishResult{Branch: "add-" + opts.PluginName}, nil
+}
+
+func (p *fakePublisher) Unpublish(ctx context.Context, opts UnpublishOptions) (*PublishResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestPublishAll(t *testing.T) {
+	pub := &fakePublisher{fail: "p3"}
+	var jobs []PublishJob
+	for _, name := range []string{"p0", "p1", "p2", "p3", "p4", "p5", "p6", "p7"} {
+		jobs = append(jobs, PublishJob{Publisher: pub, Options: PublishOptions{PluginName: name}})
+	}
+
+	results := PublishAll(context.Background(), jobs)
+	if len(results) != len(jobs) {
+		t.Fatalf("got %d results, want %d", len(results), len(jobs))
+	}
+	for i, result := range results {
+		name := jobs[i].Options.PluginName
+		if name == "p3" {
+			if result.Err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+			continue
+		}
+		if result.Err != nil || result.Branch != "add-"+name {
+			t.Errorf("%s: result = %+v", name, result)
+		}
+	}
+	if peak := pub.peak.Load(); peak > DefaultPublishConcurrency {
+		t.Errorf("peak concurrency = %d, want <= %d", peak, DefaultPublishConcurrency)
+	}
+}
+
+func TestPublishAllCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := PublishAll(ctx, []PublishJob{{Publisher: &fakePublisher{}}})
+	if !errors.Is(results[0].Err, context.Canceled) {
+		t.Errorf("Err = %v, want context.Canceled", results[0].Err)
+	}
+}
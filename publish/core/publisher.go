@@ -1,7 +1,10 @@
 // Package core provides the Publisher interface for marketplace submissions.
 package core
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Publisher defines the interface for publishing plugins to marketplaces.
 type Publisher interface {
@@ -14,6 +17,24 @@ type Publisher interface {
 	// Publish submits the plugin to the marketplace.
 	// Returns the PR URL on success.
 	Publish(ctx context.Context, opts PublishOptions) (*PublishResult, error)
+
+	// Unpublish proposes removing a published plugin from the marketplace.
+	// Returns the PR URL on success.
+	Unpublish(ctx context.Context, opts UnpublishOptions) (*PublishResult, error)
+
+	// Exists reports whether the marketplace already lists the plugin and,
+	// if so, at what version (empty if the marketplace does not say).
+	Exists(ctx context.Context, name string) (exists bool, version string, err error)
+}
+
+// BasePublisher provides defaults for Publisher methods that a
+// marketplace may not support. Embed it in publishers to inherit them.
+type BasePublisher struct{}
+
+// Exists reports that the plugin is not listed, so callers fall back to
+// submitting it as new.
+func (BasePublisher) Exists(ctx context.Context, name string) (bool, string, error) {
+	return false, "", nil
 }
 
 // PublishOptions configures the publish operation.
@@ -28,23 +49,104 @@ type PublishOptions struct {
 	// Required scopes: repo, workflow
 	GitHubToken string
 
+	// GitLabToken is the GitLab access token used by the gitlab publisher.
+	// Required scope: api
+	GitLabToken string
+
+	// ForkOwner is the GitHub username/org that owns the fork.
+	// If empty, uses the authenticated user.
+	ForkOwner string
+
+	// Branch is the name of the branch to create for the PR.
+	// If empty, defaults to "add-<plugin-name>", or to
+	// "update-<plugin-name>" when the plugin is already listed in the
+	// marketplace. (Publishers that do not check the marketplace index,
+	// such as GitLab, always use the "add" defaults.)
+	Branch string
+
+	// CommitMessage is the message of the commit adding or updating the
+	// plugin. If empty, defaults to "Add <plugin-name> plugin", or to
+	// "Update <plugin-name> plugin" when the plugin is already listed.
+	CommitMessage string
+
+	// Title is the PR title.
+	// If empty, defaults to "Add <plugin-name> plugin", or to
+	// "Update <plugin-name> plugin" when the plugin is already listed.
+	Title string
+
+	// Body is the PR description, e.g., with a changelog or ticket
+	// reference. If empty, a default description is generated, describing
+	// an update when the plugin is already listed.
+	//
+	// CommitMessage, Title and Body are text/template strings executed
+	// with a TemplateData holding the plugin name and version (e.g.,
+	// "Release {{.Name}} v{{.Version}}").
+	Body string
+
+	// Category sets the marketplace category in the submitted plugin
+	// manifest, replacing any category it already declares.
+	Category string
+
+	// AllowUnknownCategory permits categories outside the marketplace's
+	// known list. By default they are rejected before anything is pushed.
+	AllowUnknownCategory bool
+
+	// MaxRetries is how many times a failed GitHub call (fork, branch,
+	// listing, commit or PR) is retried when the failure is transient: a rate
+	// limit, a 5xx response or a network error. Commits and PRs are only
+	// retried after checking that the failed attempt did not land. Zero
+	// disables retries.
+	MaxRetries int
+
+	// RetryBaseDelay is the wait before the first retry, doubled with
+	// jitter for each further one. Rate-limited calls wait as long as
+	// GitHub asks instead, up to a minute. If zero, defaults to 1s.
+	RetryBaseDelay time.Duration
+
+	// DryRun if true, validates the plugin and runs the read-only checks
+	// (authentication, upstream project and base branch) but forks,
+	// commits and opens nothing. The result has DryRun set and a Status
+	// describing the PR that would have been opened.
+	DryRun bool
+
+	// Verbose enables detailed logging.
+	Verbose bool
+}
+
+// UnpublishOptions configures the unpublish operation.
+type UnpublishOptions struct {
+	// PluginName is the name of the plugin directory in the marketplace.
+	PluginName string
+
+	// GitLabToken is the GitLab access token used by the gitlab publisher.
+	GitLabToken string
+
 	// ForkOwner is the GitHub username/org that owns the fork.
 	// If empty, uses the authenticated user.
 	ForkOwner string
 
 	// Branch is the name of the branch to create for the PR.
-	// If empty, defaults to "add-<plugin-name>".
+	// If empty, defaults to "remove-<plugin-name>".
 	Branch string
 
 	// Title is the PR title.
-	// If empty, defaults to "Add <plugin-name> plugin".
+	// If empty, defaults to "Remove <plugin-name> plugin".
 	Title string
 
 	// Body is the PR description.
 	// If empty, a default description is generated.
 	Body string
 
-	// DryRun if true, validates and prepares but doesn't create the PR.
+	// Reason is included in the default PR description, if set.
+	Reason string
+
+	// MaxRetries and RetryBaseDelay retry transient GitHub failures, as
+	// for PublishOptions.
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+
+	// DryRun if true, checks the plugin exists but doesn't create the PR.
+	// The result has DryRun set, as for PublishOptions.DryRun.
 	DryRun bool
 
 	// Verbose enables detailed logging.
@@ -65,11 +167,24 @@ type PublishResult struct {
 	// ForkURL is the URL of the fork repository.
 	ForkURL string
 
-	// Status is a human-readable status message.
+	// Status is a human-readable status message. For a dry run, it
+	// describes the PR that would have been opened.
 	Status string
 
+	// DryRun reports that nothing was pushed and no PR was opened.
+	DryRun bool
+
+	// Labels lists the labels for the PR (e.g., "new-plugin" or "update").
+	Labels []string
+
 	// FilesAdded lists the files that were added/updated.
 	FilesAdded []string
+
+	// FilesRemoved lists the files that were deleted by Unpublish.
+	FilesRemoved []string
+
+	// Err is the error of a failed job run by PublishAll.
+	Err error
 }
 
 // MarketplaceConfig defines the target repository for a marketplace.
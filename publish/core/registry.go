@@ -0,0 +1,64 @@
+package core
+
+import (
+	"sort"
+	"sync"
+)
+
+// Registry manages publisher registration and lookup.
+type Registry struct {
+	mu         sync.RWMutex
+	publishers map[string]Publisher
+}
+
+// NewRegistry creates a new publisher registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		publishers: make(map[string]Publisher),
+	}
+}
+
+// Register adds a publisher to the registry.
+func (r *Registry) Register(publisher Publisher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.publishers[publisher.Name()] = publisher
+}
+
+// GetPublisher returns a publisher by name.
+func (r *Registry) GetPublisher(name string) (Publisher, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	publisher, ok := r.publishers[name]
+	return publisher, ok
+}
+
+// PublisherNames returns all registered publisher names sorted alphabetically.
+func (r *Registry) PublisherNames() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.publishers))
+	for name := range r.publishers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DefaultRegistry is the global publisher registry.
+var DefaultRegistry = NewRegistry()
+
+// Register adds a publisher to the default registry.
+func Register(publisher Publisher) {
+	DefaultRegistry.Register(publisher)
+}
+
+// GetPublisher returns a publisher from the default registry.
+func GetPublisher(name string) (Publisher, bool) {
+	return DefaultRegistry.GetPublisher(name)
+}
+
+// PublisherNames returns publisher names from the default registry.
+func PublisherNames() []string {
+	return DefaultRegistry.PublisherNames()
+}
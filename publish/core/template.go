@@ -0,0 +1,52 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// TemplateData is the data the CommitMessage, Title and Body templates of
+// PublishOptions receive (e.g., "Release {{.Name}} v{{.Version}}").
+type TemplateData struct {
+	// Name is the plugin name.
+	Name string
+
+	// Version is the plugin version from its manifest, or empty.
+	Version string
+}
+
+// NewTemplateData returns the template data for the plugin named name,
+// taking the version from the JSON manifest at manifestPath (e.g.,
+// .claude-plugin/plugin.json). An unreadable manifest leaves Version empty.
+func NewTemplateData(name, manifestPath string) TemplateData {
+	data := TemplateData{Name: name}
+	if content, err := os.ReadFile(manifestPath); err == nil {
+		var manifest struct {
+			Version string `json:"version"`
+		}
+		if json.Unmarshal(content, &manifest) == nil {
+			data.Version = manifest.Version
+		}
+	}
+	return data
+}
+
+// RenderTemplate renders text, one of the field templates of
+// PublishOptions, with data. Empty text renders fallback instead.
+func RenderTemplate(field, text, fallback string, data TemplateData) (string, error) {
+	if text == "" {
+		return fallback, nil
+	}
+	tmpl, err := template.New(field).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", &ValidationError{PluginDir: data.Name, Message: fmt.Sprintf("invalid %s template: %v", field, err)}
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", &ValidationError{PluginDir: data.Name, Message: fmt.Sprintf("invalid %s template: %v", field, err)}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// PluginManifestFile is the plugin manifest path within a plugin directory.
+const PluginManifestFile = ".claude-plugin/plugin.json"
+
+// semverPattern matches a Semantic Versioning 2.0.0 version, without a
+// leading "v".
+var semverPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// componentFields are the manifest fields naming component files or
+// directories, each a path or a list of paths relative to the plugin root.
+var componentFields = []string{"commands", "agents", "skills", "hooks"}
+
+// ValidatePlugin checks the plugin in dir before it is published: its
+// manifest must exist and parse, be named name (unless name is empty),
+// have a semantic version, and every command, agent, skill and hooks path
+// it references must exist. It returns a *ValidationError listing every
+// problem found.
+func ValidatePlugin(dir, name string) error {
+	data, err := os.ReadFile(filepath.Join(dir, PluginManifestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return &ValidationError{PluginDir: dir, Missing: []string{PluginManifestFile}}
+	}
+	if err != nil {
+		return err
+	}
+
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return &ValidationError{PluginDir: dir, Message: fmt.Sprintf("invalid %s: %v", PluginManifestFile, err)}
+	}
+
+	var problems []string
+	var manifestName, version string
+	if raw, ok := manifest["name"]; ok && json.Unmarshal(raw, &manifestName) != nil {
+		problems = append(problems, "name must be a string")
+	}
+	switch {
+	case manifestName == "":
+		problems = append(problems, "name is required")
+	case name != "" && manifestName != name:
+		problems = append(problems, fmt.Sprintf("name %q does not match plugin name %q", manifestName, name))
+	}
+	if raw, ok := manifest["version"]; ok && json.Unmarshal(raw, &version) != nil {
+		problems = append(problems, "version must be a string")
+	}
+	switch {
+	case version == "":
+		problems = append(problems, "version is required")
+	case !semverPattern.MatchString(version):
+		problems = append(problems, fmt.Sprintf("version %q is not a semantic version (e.g., 1.2.0)", version))
+	}
+
+	for _, field := range componentFields {
+		raw, ok := manifest[field]
+		if !ok {
+			continue
+		}
+		paths, ok := componentPaths(raw)
+		if !ok {
+			// Hooks may also be given inline
+			if field != "hooks" {
+				problems = append(problems, fmt.Sprintf("%s must be a path or a list of paths", field))
+			}
+			continue
+		}
+		for _, path := range paths {
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(path))); err != nil {
+				problems = append(problems, fmt.Sprintf("%s path %s does not exist", field, path))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{PluginDir: dir, Problems: problems}
+	}
+	return nil
+}
+
+// componentPaths decodes a manifest component field holding a path or a
+// list of paths.
+func componentPaths(raw json.RawMessage) ([]string, bool) {
+	var path string
+	if json.Unmarshal(raw, &path) == nil {
+		return []string{path}, true
+	}
+	var paths []string
+	if json.Unmarshal(raw, &paths) == nil {
+		return paths, true
+	}
+	return nil, false
+}
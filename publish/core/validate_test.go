@@ -0,0 +1,81 @@
+package core
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestValidatePlugin(t *testing.T) {
+	tests := []struct {
+		name         string
+		dir          string
+		pluginName   string
+		wantMissing  []string
+		wantProblems []string
+		wantMessage  bool
+	}{
+		{name: "valid", dir: "valid", pluginName: "valid"},
+		{name: "valid without name check", dir: "valid"},
+		{name: "name mismatch", dir: "valid", pluginName: "other",
+			wantProblems: []string{`name "valid" does not match plugin name "other"`}},
+		{name: "no manifest", dir: "no-manifest", pluginName: "no-manifest",
+			wantMissing: []string{PluginManifestFile}},
+		{name: "malformed manifest", dir: "bad-json", pluginName: "bad-json", wantMessage: true},
+		{name: "bad version", dir: "bad-version", pluginName: "bad-version",
+			wantProblems: []string{`version "v1.0" is not a semantic version (e.g., 1.2.0)`}},
+		{name: "missing components", dir: "missing-components", pluginName: "missing-components",
+			wantProblems: []string{
+				"commands path ./commands/deploy.md does not exist",
+				"agents path ./agents/ does not exist",
+				"hooks path ./hooks/hooks.json does not exist",
+			}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePlugin(filepath.Join("testdata", "plugins", tt.dir), tt.pluginName)
+			wantErr := tt.wantMissing != nil || tt.wantProblems != nil || tt.wantMessage
+			if !wantErr {
+				if err != nil {
+					t.Errorf("ValidatePlugin() error = %v", err)
+				}
+				return
+			}
+
+			var valErr *ValidationError
+			if !errors.As(err, &valErr) {
+				t.Fatalf("ValidatePlugin() error = %v, want ValidationError", err)
+			}
+			if !reflect.DeepEqual(valErr.Missing, tt.wantMissing) {
+				t.Errorf("Missing = %q, want %q", valErr.Missing, tt.wantMissing)
+			}
+			if !reflect.DeepEqual(valErr.Problems, tt.wantProblems) {
+				t.Errorf("Problems = %q, want %q", valErr.Problems, tt.wantProblems)
+			}
+			if (valErr.Message != "") != tt.wantMessage {
+				t.Errorf("Message = %q, want message %v", valErr.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestSemverPattern(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"0.1.0", true},
+		{"1.2.3-rc.1+build.5", true},
+		{"1.0", false},
+		{"v1.0.0", false},
+		{"01.0.0", false},
+		{"1.0.0-", false},
+	}
+	for _, tt := range tests {
+		if got := semverPattern.MatchString(tt.version); got != tt.want {
+			t.Errorf("semverPattern.MatchString(%q) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
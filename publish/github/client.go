@@ -4,6 +4,10 @@ package github
 
 import (
 	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
 
 	"github.com/google/go-github/v81/github"
 	"github.com/grokify/gogithub/auth"
@@ -15,22 +19,42 @@ import (
 type Client struct {
 	gh     *github.Client
 	dryRun bool
+
+	// maxRetries and retryBaseDelay configure retry (see SetRetry)
+	maxRetries     int
+	retryBaseDelay time.Duration
 }
 
 // NewClient creates a new GitHub client with the given token.
 func NewClient(token string) *Client {
 	gh := auth.NewGitHubClient(context.Background(), token)
-	return &Client{gh: gh}
+	return &Client{gh: gh, retryBaseDelay: DefaultRetryBaseDelay}
 }
 
-// SetDryRun enables or disables dry run mode.
+// SetDryRun enables or disables dry run mode. It modifies c, so clients
+// shared by concurrent operations should use WithOptions instead.
 func (c *Client) SetDryRun(dryRun bool) {
 	c.dryRun = dryRun
 }
 
+// WithOptions returns a copy of c, sharing its API connection, with dry
+// run mode and retries (see SetRetry) set. c itself is not modified, so
+// concurrent operations can each configure their own copy.
+func (c *Client) WithOptions(dryRun bool, maxRetries int, retryBaseDelay time.Duration) *Client {
+	configured := *c
+	configured.SetDryRun(dryRun)
+	configured.SetRetry(maxRetries, retryBaseDelay)
+	return &configured
+}
+
 // GetAuthenticatedUser returns the authenticated user's login.
 func (c *Client) GetAuthenticatedUser(ctx context.Context) (string, error) {
-	return auth.GetAuthenticatedUser(ctx, c.gh)
+	var user string
+	err := c.retry(ctx, func() (err error) {
+		user, err = auth.GetAuthenticatedUser(ctx, c.gh)
+		return err
+	})
+	return user, err
 }
 
 // EnsureFork ensures a fork exists for the given repository.
@@ -39,7 +63,13 @@ func (c *Client) EnsureFork(ctx context.Context, upstreamOwner, upstreamRepo, fo
 	if c.dryRun {
 		return forkOwner, upstreamRepo, nil
 	}
-	return repo.EnsureFork(ctx, c.gh, upstreamOwner, upstreamRepo, forkOwner)
+	var owner, name string
+	// Safe to retry: an existing fork is looked up rather than created again
+	err := c.retry(ctx, func() (err error) {
+		owner, name, err = repo.EnsureFork(ctx, c.gh, upstreamOwner, upstreamRepo, forkOwner)
+		return err
+	})
+	return owner, name, err
 }
 
 // GetDefaultBranch returns the default branch of a repository.
@@ -49,7 +79,12 @@ func (c *Client) GetDefaultBranch(ctx context.Context, owner, repoName string) (
 
 // GetBranchSHA returns the SHA of the given branch.
 func (c *Client) GetBranchSHA(ctx context.Context, owner, repoName, branch string) (string, error) {
-	return repo.GetBranchSHA(ctx, c.gh, owner, repoName, branch)
+	var sha string
+	err := c.retry(ctx, func() (err error) {
+		sha, err = repo.GetBranchSHA(ctx, c.gh, owner, repoName, branch)
+		return err
+	})
+	return sha, err
 }
 
 // CreateBranch creates a new branch from the given base SHA.
@@ -57,21 +92,126 @@ func (c *Client) CreateBranch(ctx context.Context, owner, repoName, branch, base
 	if c.dryRun {
 		return nil
 	}
-	return repo.CreateBranch(ctx, c.gh, owner, repoName, branch, baseSHA)
+	return c.retryWrite(ctx, func() error {
+		return repo.CreateBranch(ctx, c.gh, owner, repoName, branch, baseSHA)
+	}, func() (bool, error) {
+		sha, err := repo.GetBranchSHA(ctx, c.gh, owner, repoName, branch)
+		return err == nil && sha == baseSHA, nil
+	})
 }
 
 // FileContent represents a file to be committed.
 type FileContent = repo.FileContent
 
-// CreateCommit creates a commit with the given files.
+// CreateCommit creates a commit with the given files. A failed attempt is
+// only retried while the branch head has not moved, so a commit whose
+// response was lost is not created twice.
 func (c *Client) CreateCommit(ctx context.Context, owner, repoName, branch, message string, files []FileContent) (string, error) {
 	if c.dryRun {
 		return "dry-run-sha", nil
 	}
-	return repo.CreateCommit(ctx, c.gh, owner, repoName, branch, message, files)
+	head, err := c.GetBranchSHA(ctx, owner, repoName, branch)
+	if err != nil {
+		return "", err
+	}
+	var sha string
+	err = c.retryWrite(ctx, func() (err error) {
+		sha, err = repo.CreateCommit(ctx, c.gh, owner, repoName, branch, message, files)
+		return err
+	}, c.headMoved(ctx, owner, repoName, branch, head, &sha))
+	return sha, err
+}
+
+// headMoved returns a retryWrite check reporting whether branch has moved
+// from head, storing its new head in sha if so.
+func (c *Client) headMoved(ctx context.Context, owner, repoName, branch, head string, sha *string) func() (bool, error) {
+	return func() (bool, error) {
+		current, err := repo.GetBranchSHA(ctx, c.gh, owner, repoName, branch)
+		if err != nil {
+			return false, err
+		}
+		if current == head {
+			return false, nil
+		}
+		*sha = current
+		return true, nil
+	}
+}
+
+// ListFiles returns the paths of all files under dir in the repository at
+// ref (a branch or commit SHA), using a recursive tree listing.
+func (c *Client) ListFiles(ctx context.Context, owner, repoName, ref, dir string) ([]string, error) {
+	var tree *github.Tree
+	err := c.retry(ctx, func() (err error) {
+		tree, _, err = c.gh.Git.GetTree(ctx, owner, repoName, ref, true)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return FilesUnder(tree.Entries, dir), nil
+}
+
+// GetFileContent returns the content of the file at filePath in the
+// repository at ref (a branch or commit SHA).
+func (c *Client) GetFileContent(ctx context.Context, owner, repoName, ref, filePath string) ([]byte, error) {
+	var content string
+	err := c.retry(ctx, func() error {
+		file, _, _, err := c.gh.Repositories.GetContents(ctx, owner, repoName, filePath, &github.RepositoryContentGetOptions{Ref: ref})
+		if err != nil {
+			return err
+		}
+		if file == nil {
+			return fmt.Errorf("%s is a directory", filePath)
+		}
+		content, err = file.GetContent()
+		return err
+	})
+	return []byte(content), err
+}
+
+// FilesUnder returns the paths of the blob entries below dir, in order.
+func FilesUnder(entries []*github.TreeEntry, dir string) []string {
+	prefix := strings.TrimSuffix(path.Clean(dir), "/") + "/"
+	var files []string
+	for _, entry := range entries {
+		if entry.GetType() == "blob" && strings.HasPrefix(entry.GetPath(), prefix) {
+			files = append(files, entry.GetPath())
+		}
+	}
+	return files
+}
+
+// DeleteFiles creates a commit deleting the given files. Like
+// CreateCommit, it is only retried while the branch head has not moved.
+func (c *Client) DeleteFiles(ctx context.Context, owner, repoName, branch, message string, paths []string) (string, error) {
+	if c.dryRun {
+		return "dry-run-sha", nil
+	}
+	head, err := c.GetBranchSHA(ctx, owner, repoName, branch)
+	if err != nil {
+		return "", err
+	}
+	var sha string
+	err = c.retryWrite(ctx, func() error {
+		batch, err := repo.NewBatch(ctx, c.gh, owner, repoName, branch, message)
+		if err != nil {
+			return err
+		}
+		for _, p := range paths {
+			if err := batch.Delete(p); err != nil {
+				return err
+			}
+		}
+		sha, err = batch.Commit(ctx)
+		return err
+	}, c.headMoved(ctx, owner, repoName, branch, head, &sha))
+	return sha, err
 }
 
-// CreatePR creates a pull request.
+// CreatePR creates a pull request. Before retrying a failed attempt, it
+// looks for an open pull request from the branch, so one whose response
+// was lost is returned instead of being opened twice.
 func (c *Client) CreatePR(ctx context.Context, upstreamOwner, upstreamRepo, forkOwner, branch, baseBranch, title, body string) (*github.PullRequest, error) {
 	if c.dryRun {
 		return &github.PullRequest{
@@ -80,7 +220,35 @@ func (c *Client) CreatePR(ctx context.Context, upstreamOwner, upstreamRepo, fork
 			State:   github.Ptr("dry-run"),
 		}, nil
 	}
-	return pr.CreatePR(ctx, c.gh, upstreamOwner, upstreamRepo, forkOwner, branch, baseBranch, title, body)
+	var created *github.PullRequest
+	err := c.retryWrite(ctx, func() (err error) {
+		created, err = pr.CreatePR(ctx, c.gh, upstreamOwner, upstreamRepo, forkOwner, branch, baseBranch, title, body)
+		return err
+	}, func() (bool, error) {
+		open, err := pr.ListPRs(ctx, c.gh, upstreamOwner, upstreamRepo, &github.PullRequestListOptions{
+			State: "open",
+			Head:  forkOwner + ":" + branch,
+			Base:  baseBranch,
+		})
+		if err != nil || len(open) == 0 {
+			return false, err
+		}
+		created = open[0]
+		return true, nil
+	})
+	return created, err
+}
+
+// AddLabels adds labels to the pull request or issue number.
+func (c *Client) AddLabels(ctx context.Context, owner, repoName string, number int, labels []string) error {
+	if c.dryRun {
+		return nil
+	}
+	// Safe to retry: adding a label the issue already has is a no-op
+	return c.retry(ctx, func() error {
+		_, _, err := c.gh.Issues.AddLabelsToIssue(ctx, owner, repoName, number, labels)
+		return err
+	})
 }
 
 // ReadLocalFiles reads all files from a local directory recursively.
@@ -0,0 +1,171 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v81/github"
+
+	"github.com/agentplexus/assistantkit/publish/core"
+)
+
+// DefaultRetryBaseDelay is the delay before the first retry when none is
+// set. Each further retry doubles it.
+const DefaultRetryBaseDelay = time.Second
+
+// MaxRetryWait caps the wait before a retry. A rate limit that resets
+// later than this is waited out only this long before trying again, so a
+// publish never blocks for up to an hour on an exhausted quota.
+const MaxRetryWait = time.Minute
+
+// SetRetry makes each GitHub operation retry transient failures up to
+// maxRetries times, waiting baseDelay (default DefaultRetryBaseDelay)
+// before the first retry and doubling it, with jitter, before each
+// further one. Zero maxRetries disables retries. Like SetDryRun, it
+// modifies c.
+func (c *Client) SetRetry(maxRetries int, baseDelay time.Duration) {
+	if baseDelay <= 0 {
+		baseDelay = DefaultRetryBaseDelay
+	}
+	c.maxRetries = maxRetries
+	c.retryBaseDelay = baseDelay
+}
+
+// retry calls op until it succeeds, fails permanently or runs out of
+// retries. Rate-limited calls (403/429) wait as long as GitHub asks, up to
+// MaxRetryWait; other transient failures (5xx, network errors) back off
+// exponentially. Rejected credentials are returned as *core.AuthError and
+// never retried. ctx aborts the wait between attempts.
+//
+// op must be idempotent: reads, or writes that treat existing state as
+// success. Other writes use retryWrite.
+func (c *Client) retry(ctx context.Context, op func() error) error {
+	return c.retryWrite(ctx, op, nil)
+}
+
+// retryWrite is retry for writes that are not idempotent, such as creating
+// a commit or pull request: when a response is lost, GitHub may have
+// applied the write anyway, and repeating it would apply it twice. Before
+// each retry, landed checks GitHub's current state and reports whether the
+// failed attempt took effect; if so, retryWrite returns nil without
+// repeating op. If the check itself fails, op's error is returned.
+func (c *Client) retryWrite(ctx context.Context, op func() error, landed func() (bool, error)) error {
+	for attempt := 0; ; attempt++ {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		wait, retryable := retryDelay(err)
+		if !retryable {
+			if isAuthFailure(err) {
+				return &core.AuthError{Message: err.Error()}
+			}
+			return err
+		}
+		if attempt >= c.maxRetries {
+			return err
+		}
+
+		timer := time.NewTimer(c.retryWait(wait, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		if landed != nil {
+			done, checkErr := landed()
+			if checkErr != nil {
+				return err
+			}
+			if done {
+				return nil
+			}
+		}
+	}
+}
+
+// retryWait returns the wait before retry attempt+1: the wait GitHub asked
+// for, capped at MaxRetryWait, or exponential backoff if it did not ask.
+func (c *Client) retryWait(asked time.Duration, attempt int) time.Duration {
+	if asked == 0 {
+		return backoff(c.retryBaseDelay, attempt)
+	}
+	return min(asked, MaxRetryWait)
+}
+
+// retryDelay reports whether err is transient and, for rate limits, how
+// long GitHub asked to wait (zero if it did not say).
+func retryDelay(err error) (time.Duration, bool) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		return abuseErr.GetRetryAfter(), true
+	}
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		if wait := retryAfter(rateErr.Response); wait > 0 {
+			return wait, true
+		}
+		return max(time.Until(rateErr.Rate.Reset.Time), 0), true
+	}
+
+	resp := errorResponse(err)
+	if resp == nil {
+		// No response: the request failed in transit
+		return 0, true
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return retryAfter(resp), true
+	case resp.StatusCode == http.StatusForbidden && resp.Header.Get("Retry-After") != "":
+		return retryAfter(resp), true
+	case resp.StatusCode >= 500:
+		return 0, true
+	}
+	return 0, false
+}
+
+// isAuthFailure reports whether err is GitHub rejecting the credentials
+// (401) or their permissions (403 without a rate limit).
+func isAuthFailure(err error) bool {
+	resp := errorResponse(err)
+	return resp != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden)
+}
+
+// errorResponse returns the HTTP response of a GitHub API error, or nil.
+func errorResponse(err error) *http.Response {
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) {
+		return errResp.Response
+	}
+	return nil
+}
+
+// retryAfter returns the wait a Retry-After header (in seconds) asks for,
+// or zero.
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// backoff returns the wait before retry attempt+1: base doubled attempt
+// times, jittered to between half and all of that.
+func backoff(base time.Duration, attempt int) time.Duration {
+	delay := base << min(attempt, 16)
+	return delay/2 + rand.N(delay/2+1)
+}
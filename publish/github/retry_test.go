@@ -0,0 +1,229 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v81/github"
+
+	"github.com/agentplexus/assistantkit/publish/core"
+)
+
+// newTestClient returns a client for a fake GitHub API answering each
+// request with the next of statuses (then 201), and a count of requests.
+func newTestClient(t *testing.T, header http.Header, statuses ...int) (*Client, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1)) - 1
+		if n < len(statuses) {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(statuses[n])
+			_, _ = w.Write([]byte(`{"message": "failed"}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"ref": "refs/heads/add-plugin"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	gh := github.NewClient(nil)
+	gh.BaseURL, _ = url.Parse(srv.URL + "/")
+	return &Client{gh: gh, retryBaseDelay: DefaultRetryBaseDelay}, &calls
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		header     http.Header
+		statuses   []int
+		wantCalls  int32
+		wantErr    bool
+		wantAuth   bool
+	}{
+		{"success", 3, nil, nil, 1, false, false},
+		{"server errors", 3, nil, []int{502, 503}, 3, false, false},
+		{"retries exhausted", 1, nil, []int{502, 502, 502}, 2, true, false},
+		{"retries disabled", 0, nil, []int{502}, 1, true, false},
+		{"too many requests", 3, http.Header{"Retry-After": {"0"}}, []int{429}, 2, false, false},
+		{"secondary rate limit", 3, http.Header{"Retry-After": {"0"}}, []int{403}, 2, false, false},
+		{"bad credentials", 3, nil, []int{401}, 1, true, true},
+		{"forbidden", 3, nil, []int{403}, 1, true, true},
+		{"unprocessable", 3, nil, []int{422}, 1, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, calls := newTestClient(t, tt.header, tt.statuses...)
+			c.SetRetry(tt.maxRetries, time.Millisecond)
+
+			_, err := c.GetBranchSHA(context.Background(), "user", "repo", "add-plugin")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetBranchSHA() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var authErr *core.AuthError
+			if errors.As(err, &authErr) != tt.wantAuth {
+				t.Errorf("GetBranchSHA() error = %T %v, want AuthError %v", err, err, tt.wantAuth)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("requests = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryListFiles(t *testing.T) {
+	c, calls := newTestClient(t, nil, 502)
+	c.SetRetry(1, time.Millisecond)
+
+	if _, err := c.ListFiles(context.Background(), "owner", "repo", "main", "plugins/p"); err != nil {
+		t.Fatalf("ListFiles() error = %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2", got)
+	}
+}
+
+func TestRetryContextCanceled(t *testing.T) {
+	c, calls := newTestClient(t, nil, 502, 502, 502)
+	c.SetRetry(5, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	if err := c.CreateBranch(ctx, "user", "repo", "add-plugin", "abc123"); err == nil {
+		t.Fatal("CreateBranch() succeeded, want the last error")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1 (no retry after cancel)", got)
+	}
+}
+
+// newRoutedTestClient returns a client for a fake GitHub API answering
+// "METHOD /path" requests with routes, and a count of requests to each.
+func newRoutedTestClient(t *testing.T, routes map[string]http.HandlerFunc) (*Client, map[string]*atomic.Int32) {
+	t.Helper()
+	calls := make(map[string]*atomic.Int32, len(routes))
+	for route := range routes {
+		calls[route] = &atomic.Int32{}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.Method + " " + r.URL.Path
+		handler, ok := routes[route]
+		if !ok {
+			t.Errorf("unexpected request %s", route)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		calls[route].Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	gh := github.NewClient(nil)
+	gh.BaseURL, _ = url.Parse(srv.URL + "/")
+	return &Client{gh: gh, retryBaseDelay: DefaultRetryBaseDelay}, calls
+}
+
+func TestRetryWriteLanded(t *testing.T) {
+	lost := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusBadGateway) }
+
+	t.Run("pull request", func(t *testing.T) {
+		c, calls := newRoutedTestClient(t, map[string]http.HandlerFunc{
+			"POST /repos/owner/repo/pulls": lost,
+			"GET /repos/owner/repo/pulls": func(w http.ResponseWriter, r *http.Request) {
+				if head := r.URL.Query().Get("head"); head != "user:add-plugin" {
+					t.Errorf("listed pull requests from %q", head)
+				}
+				_, _ = w.Write([]byte(`[{"number": 7}]`))
+			},
+		})
+		c.SetRetry(3, time.Millisecond)
+
+		created, err := c.CreatePR(context.Background(), "owner", "repo", "user", "add-plugin", "main", "Add plugin", "")
+		if err != nil || created.GetNumber() != 7 {
+			t.Fatalf("CreatePR() = %v, %v; want the existing pull request", created, err)
+		}
+		if got := calls["POST /repos/owner/repo/pulls"].Load(); got != 1 {
+			t.Errorf("create requests = %d, want 1", got)
+		}
+	})
+
+	t.Run("branch", func(t *testing.T) {
+		c, calls := newRoutedTestClient(t, map[string]http.HandlerFunc{
+			"POST /repos/user/repo/git/refs": lost,
+			"GET /repos/user/repo/git/ref/heads/add-plugin": func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"ref": "refs/heads/add-plugin", "object": {"sha": "abc123"}}`))
+			},
+		})
+		c.SetRetry(3, time.Millisecond)
+
+		if err := c.CreateBranch(context.Background(), "user", "repo", "add-plugin", "abc123"); err != nil {
+			t.Fatalf("CreateBranch() error = %v", err)
+		}
+		if got := calls["POST /repos/user/repo/git/refs"].Load(); got != 1 {
+			t.Errorf("create requests = %d, want 1", got)
+		}
+	})
+
+	t.Run("not landed", func(t *testing.T) {
+		c, calls := newRoutedTestClient(t, map[string]http.HandlerFunc{
+			"POST /repos/owner/repo/pulls": lost,
+			"GET /repos/owner/repo/pulls": func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`[]`))
+			},
+		})
+		c.SetRetry(2, time.Millisecond)
+
+		if _, err := c.CreatePR(context.Background(), "owner", "repo", "user", "add-plugin", "main", "Add plugin", ""); err == nil {
+			t.Fatal("CreatePR() succeeded, want the last error")
+		}
+		if got := calls["POST /repos/owner/repo/pulls"].Load(); got != 3 {
+			t.Errorf("create requests = %d, want 3", got)
+		}
+	})
+}
+
+func TestRetryWait(t *testing.T) {
+	c := &Client{retryBaseDelay: 100 * time.Millisecond}
+	if got := c.retryWait(2*time.Hour, 0); got != MaxRetryWait {
+		t.Errorf("retryWait(2h) = %v, want cap %v", got, MaxRetryWait)
+	}
+	if got := c.retryWait(5*time.Second, 0); got != 5*time.Second {
+		t.Errorf("retryWait(5s) = %v, want 5s", got)
+	}
+	if got := c.retryWait(0, 0); got < 50*time.Millisecond || got > 100*time.Millisecond {
+		t.Errorf("retryWait(0) = %v, want backoff", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt, want := range []time.Duration{100, 200, 400, 800} {
+		want *= time.Millisecond
+		for range 20 {
+			got := backoff(100*time.Millisecond, attempt)
+			if got < want/2 || got > want {
+				t.Errorf("backoff(100ms, %d) = %v, want within [%v, %v]", attempt, got, want/2, want)
+			}
+		}
+	}
+}
+
+func TestWithOptions(t *testing.T) {
+	c := NewClient("token")
+	configured := c.WithOptions(true, 2, time.Second)
+	if !configured.dryRun || configured.maxRetries != 2 || configured.retryBaseDelay != time.Second || configured.gh != c.gh {
+		t.Errorf("WithOptions() = %+v", configured)
+	}
+	if c.dryRun || c.maxRetries != 0 {
+		t.Errorf("WithOptions() modified the client: %+v", c)
+	}
+}
@@ -0,0 +1,319 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agentplexus/assistantkit/publish/core"
+)
+
+// APIError is a GitLab API response with an unexpected status.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("GitLab API %s %s: %d %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// Project is a GitLab project.
+type Project struct {
+	ID                int    `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	WebURL            string `json:"web_url"`
+	ImportStatus      string `json:"import_status"`
+}
+
+// MergeRequest is a GitLab merge request.
+type MergeRequest struct {
+	IID    int    `json:"iid"`
+	WebURL string `json:"web_url"`
+}
+
+// FileContent represents a file to be committed.
+type FileContent struct {
+	Path    string
+	Content []byte
+}
+
+// commitAction is one file change in a commit.
+type commitAction struct {
+	Action   string `json:"action"` // create, update or delete
+	FilePath string `json:"file_path"`
+	Content  string `json:"content,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Client calls the GitLab REST API (v4) for marketplace operations.
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+	dryRun  bool
+}
+
+// forkPollInterval is how often EnsureFork checks whether a new fork is
+// ready.
+var forkPollInterval = time.Second
+
+// NewClient creates a client for the GitLab instance at baseURL (e.g.,
+// "https://gitlab.com") authenticating with token.
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// SetDryRun enables or disables dry run mode, in which nothing is created.
+func (c *Client) SetDryRun(dryRun bool) {
+	c.dryRun = dryRun
+}
+
+// do sends a request to the API and decodes the JSON response into out, if
+// set. It returns the response headers.
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) (http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+"/api/v4"+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return nil, &core.AuthError{Message: "GitLab rejected the token"}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr struct {
+			Message interface{} `json:"message"`
+			Error   string      `json:"error"`
+		}
+		data, _ := io.ReadAll(resp.Body)
+		msg := strings.TrimSpace(string(data))
+		if json.Unmarshal(data, &apiErr) == nil {
+			if apiErr.Message != nil {
+				msg = fmt.Sprint(apiErr.Message)
+			} else if apiErr.Error != "" {
+				msg = apiErr.Error
+			}
+		}
+		return nil, &APIError{Method: method, Path: path, StatusCode: resp.StatusCode, Message: msg}
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return nil, fmt.Errorf("decoding GitLab API %s %s: %w", method, path, err)
+		}
+	}
+	return resp.Header, nil
+}
+
+// projectPath returns the API path of the project with the given path
+// (e.g., "group/project") or numeric ID.
+func projectPath(project string) string {
+	return "/projects/" + url.PathEscape(project)
+}
+
+// isNotFound reports whether err is a 404 API response.
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// GetAuthenticatedUser returns the authenticated user's username.
+func (c *Client) GetAuthenticatedUser(ctx context.Context) (string, error) {
+	var user struct {
+		Username string `json:"username"`
+	}
+	if _, err := c.do(ctx, http.MethodGet, "/user", nil, &user); err != nil {
+		return "", err
+	}
+	return user.Username, nil
+}
+
+// GetProject returns the project with the given path or ID.
+func (c *Client) GetProject(ctx context.Context, project string) (*Project, error) {
+	var p Project
+	if _, err := c.do(ctx, http.MethodGet, projectPath(project), nil, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// EnsureFork returns the fork of upstream in namespace, forking it first if
+// namespace has no project of that name. If namespace owns upstream, the
+// upstream project itself is returned. A new fork is returned once GitLab
+// has finished copying the repository.
+func (c *Client) EnsureFork(ctx context.Context, upstream *Project, namespace string) (*Project, error) {
+	name := upstream.PathWithNamespace[strings.LastIndex(upstream.PathWithNamespace, "/")+1:]
+	if namespace+"/"+name == upstream.PathWithNamespace {
+		return upstream, nil
+	}
+
+	fork, err := c.GetProject(ctx, namespace+"/"+name)
+	if err == nil || !isNotFound(err) {
+		return fork, err
+	}
+	if c.dryRun {
+		return &Project{PathWithNamespace: namespace + "/" + name, WebURL: c.baseURL + "/" + namespace + "/" + name}, nil
+	}
+
+	fork = &Project{}
+	body := map[string]string{"namespace_path": namespace}
+	if _, err := c.do(ctx, http.MethodPost, projectPath(strconv.Itoa(upstream.ID))+"/fork", body, fork); err != nil {
+		return nil, err
+	}
+	for fork.ImportStatus != "" && fork.ImportStatus != "none" && fork.ImportStatus != "finished" {
+		if fork.ImportStatus == "failed" {
+			return nil, fmt.Errorf("GitLab failed to copy the repository into %s", fork.PathWithNamespace)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(forkPollInterval):
+		}
+		if fork, err = c.GetProject(ctx, strconv.Itoa(fork.ID)); err != nil {
+			return nil, err
+		}
+	}
+	return fork, nil
+}
+
+// CreateBranch creates branch in the project from ref.
+func (c *Client) CreateBranch(ctx context.Context, project *Project, branch, ref string) error {
+	if c.dryRun {
+		return nil
+	}
+	body := map[string]string{"branch": branch, "ref": ref}
+	_, err := c.do(ctx, http.MethodPost, projectPath(strconv.Itoa(project.ID))+"/repository/branches", body, nil)
+	return err
+}
+
+// ListFiles returns the paths of all files under dir in the project at ref.
+// A missing dir has no files.
+func (c *Client) ListFiles(ctx context.Context, project *Project, ref, dir string) ([]string, error) {
+	var files []string
+	for page := "1"; page != ""; {
+		query := url.Values{
+			"path":      {dir},
+			"ref":       {ref},
+			"recursive": {"true"},
+			"per_page":  {"100"},
+			"page":      {page},
+		}
+		var entries []struct {
+			Path string `json:"path"`
+			Type string `json:"type"`
+		}
+		header, err := c.do(ctx, http.MethodGet, projectPath(strconv.Itoa(project.ID))+"/repository/tree?"+query.Encode(), nil, &entries)
+		if isNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.Type == "blob" {
+				files = append(files, entry.Path)
+			}
+		}
+		page = header.Get("X-Next-Page")
+	}
+	return files, nil
+}
+
+// CreateCommit commits files to branch, creating those not in existing and
+// updating the rest.
+func (c *Client) CreateCommit(ctx context.Context, project *Project, branch, message string, files []FileContent, existing []string) error {
+	present := make(map[string]bool, len(existing))
+	for _, path := range existing {
+		present[path] = true
+	}
+	actions := make([]commitAction, len(files))
+	for i, f := range files {
+		action := "create"
+		if present[f.Path] {
+			action = "update"
+		}
+		actions[i] = commitAction{
+			Action:   action,
+			FilePath: f.Path,
+			Content:  base64.StdEncoding.EncodeToString(f.Content),
+			Encoding: "base64",
+		}
+	}
+	return c.commit(ctx, project, branch, message, actions)
+}
+
+// DeleteFiles commits the deletion of paths to branch.
+func (c *Client) DeleteFiles(ctx context.Context, project *Project, branch, message string, paths []string) error {
+	actions := make([]commitAction, len(paths))
+	for i, path := range paths {
+		actions[i] = commitAction{Action: "delete", FilePath: path}
+	}
+	return c.commit(ctx, project, branch, message, actions)
+}
+
+func (c *Client) commit(ctx context.Context, project *Project, branch, message string, actions []commitAction) error {
+	if c.dryRun {
+		return nil
+	}
+	body := map[string]interface{}{
+		"branch":         branch,
+		"commit_message": message,
+		"actions":        actions,
+	}
+	_, err := c.do(ctx, http.MethodPost, projectPath(strconv.Itoa(project.ID))+"/repository/commits", body, nil)
+	return err
+}
+
+// CreateMergeRequest opens a merge request from branch in source into
+// targetBranch of target.
+func (c *Client) CreateMergeRequest(ctx context.Context, source, target *Project, branch, targetBranch, title, description string) (*MergeRequest, error) {
+	if c.dryRun {
+		return &MergeRequest{WebURL: target.WebURL + "/-/merge_requests/0"}, nil
+	}
+	body := map[string]interface{}{
+		"source_branch":     branch,
+		"target_branch":     targetBranch,
+		"target_project_id": target.ID,
+		"title":             title,
+		"description":       description,
+	}
+	var mr MergeRequest
+	if _, err := c.do(ctx, http.MethodPost, projectPath(strconv.Itoa(source.ID))+"/merge_requests", body, &mr); err != nil {
+		return nil, err
+	}
+	return &mr, nil
+}
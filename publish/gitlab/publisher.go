@@ -0,0 +1,364 @@
+// Package gitlab provides a publisher for Claude Code plugin marketplaces
+// hosted on GitLab, such as a company's internal plugin registry.
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/agentplexus/assistantkit/publish/claude"
+	"github.com/agentplexus/assistantkit/publish/core"
+)
+
+const (
+	// DefaultBaseURL is the GitLab instance used when none is configured.
+	DefaultBaseURL = "https://gitlab.com"
+
+	// DefaultBaseBranch is the default branch to target.
+	DefaultBaseBranch = "main"
+
+	// DefaultPluginPath is the default directory for plugins in the
+	// marketplace project.
+	DefaultPluginPath = "plugins"
+
+	// EnvBaseURL names the environment variable the registered publisher
+	// reads its GitLab instance from.
+	EnvBaseURL = "GITLAB_URL"
+
+	// EnvProject names the environment variable the registered publisher
+	// reads its marketplace project (e.g., "platform/claude-plugins") from.
+	EnvProject = "GITLAB_MARKETPLACE_PROJECT"
+)
+
+func init() {
+	core.Register(&Publisher{})
+}
+
+// Publisher submits plugins to a GitLab-hosted marketplace project by
+// merge request. The token is read from each call's options.
+//
+// The zero Publisher, registered as "gitlab", reads its instance from
+// GITLAB_URL (default https://gitlab.com) and its marketplace project from
+// GITLAB_MARKETPLACE_PROJECT when it publishes.
+type Publisher struct {
+	core.BasePublisher
+
+	baseURL string
+	config  core.MarketplaceConfig
+}
+
+// NewPublisher creates a publisher for the marketplace project
+// config.Owner/config.Repo on the GitLab instance at baseURL. Owner may be
+// a nested group (e.g., "corp/platform"). Empty BaseBranch, PluginPath and
+// RequiredFiles take the package defaults.
+func NewPublisher(baseURL string, config core.MarketplaceConfig) *Publisher {
+	return &Publisher{baseURL: baseURL, config: config}
+}
+
+// Name returns the marketplace identifier.
+func (p *Publisher) Name() string {
+	return "gitlab"
+}
+
+// target returns the GitLab instance and marketplace project, with
+// defaults and environment settings applied.
+func (p *Publisher) target() (string, core.MarketplaceConfig, error) {
+	baseURL, config := p.baseURL, p.config
+	if baseURL == "" {
+		baseURL = os.Getenv(EnvBaseURL)
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if config.Owner == "" && config.Repo == "" {
+		project := strings.Trim(os.Getenv(EnvProject), "/")
+		if i := strings.LastIndex(project, "/"); i > 0 {
+			config.Owner, config.Repo = project[:i], project[i+1:]
+		}
+	}
+	if config.Owner == "" || config.Repo == "" {
+		return "", config, fmt.Errorf("no GitLab marketplace project configured (set %s, e.g., platform/claude-plugins)", EnvProject)
+	}
+	if config.BaseBranch == "" {
+		config.BaseBranch = DefaultBaseBranch
+	}
+	if config.PluginPath == "" {
+		config.PluginPath = DefaultPluginPath
+	}
+	if config.RequiredFiles == nil {
+		config.RequiredFiles = claude.RequiredFiles
+	}
+	return baseURL, config, nil
+}
+
+// Validate checks if the plugin directory has all required files.
+func (p *Publisher) Validate(pluginDir string) error {
+	required := p.config.RequiredFiles
+	if required == nil {
+		required = claude.RequiredFiles
+	}
+
+	var missing []string
+	for _, file := range required {
+		if _, err := os.Stat(filepath.Join(pluginDir, file)); os.IsNotExist(err) {
+			missing = append(missing, file)
+		}
+	}
+	if len(missing) > 0 {
+		return &core.ValidationError{PluginDir: pluginDir, Missing: missing}
+	}
+	return nil
+}
+
+// Publish opens a merge request adding the plugin to the marketplace.
+func (p *Publisher) Publish(ctx context.Context, opts core.PublishOptions) (*core.PublishResult, error) {
+	if err := p.Validate(opts.PluginDir); err != nil {
+		return nil, err
+	}
+	if err := core.ValidatePlugin(opts.PluginDir, opts.PluginName); err != nil {
+		return nil, err
+	}
+	baseURL, config, err := p.target()
+	if err != nil {
+		return nil, err
+	}
+	if opts.GitLabToken == "" {
+		return nil, &core.AuthError{Message: "a GitLab token is required"}
+	}
+	client := NewClient(baseURL, opts.GitLabToken)
+	client.SetDryRun(opts.DryRun)
+
+	branch := opts.Branch
+	if branch == "" {
+		branch = fmt.Sprintf("add-%s", opts.PluginName)
+	}
+
+	data := core.NewTemplateData(opts.PluginName, filepath.Join(opts.PluginDir, claude.ManifestFile))
+	commitMsg, err := core.RenderTemplate("commit message", opts.CommitMessage, fmt.Sprintf("Add %s plugin", opts.PluginName), data)
+	if err != nil {
+		return nil, err
+	}
+	title, err := core.RenderTemplate("title", opts.Title, fmt.Sprintf("Add %s plugin", opts.PluginName), data)
+	if err != nil {
+		return nil, err
+	}
+	defaultBody := fmt.Sprintf("Adding the **%s** plugin to the marketplace.\n\n---\n\n*Submitted via [aiassistkit](https://github.com/agentplexus/assistantkit) publish tool*\n", opts.PluginName)
+	body, err := core.RenderTemplate("body", opts.Body, defaultBody, data)
+	if err != nil {
+		return nil, err
+	}
+
+	upstream, err := client.GetProject(ctx, config.Owner+"/"+config.Repo)
+	if err != nil {
+		return nil, err
+	}
+	fork, err := prepareBranch(ctx, client, upstream, config, opts.ForkOwner, branch, opts.Verbose)
+	if err != nil {
+		return nil, err
+	}
+
+	destPath := path.Join(config.PluginPath, opts.PluginName)
+	files, err := readLocalFiles(opts.PluginDir, destPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// The branch starts from the fork's base branch; a fork only planned
+	// by a dry run would start from the marketplace's
+	base := fork
+	if fork.ID == 0 {
+		base = upstream
+	}
+	existing, err := client.ListFiles(ctx, base, config.BaseBranch, destPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Verbose {
+		fmt.Printf("Adding %d files to %s...\n", len(files), destPath)
+		for _, f := range files {
+			fmt.Printf("  %s\n", f.Path)
+		}
+	}
+
+	if err := client.CreateCommit(ctx, fork, branch, commitMsg, files, existing); err != nil {
+		return nil, &core.CommitError{Message: commitMsg, Err: err}
+	}
+
+	if opts.Verbose {
+		fmt.Printf("Creating merge request: %s\n", title)
+	}
+	mr, err := client.CreateMergeRequest(ctx, fork, upstream, branch, config.BaseBranch, title, body)
+	if err != nil {
+		return nil, &core.PRError{Title: title, Err: err}
+	}
+
+	fileNames := make([]string, len(files))
+	for i, f := range files {
+		fileNames[i] = f.Path
+	}
+
+	status := "Merge request created successfully"
+	if opts.DryRun {
+		status = fmt.Sprintf("Dry run completed - no merge request created; would open %q from %s into %s:%s adding %d files",
+			title, branch, upstream.PathWithNamespace, config.BaseBranch, len(files))
+	}
+
+	return &core.PublishResult{
+		PRURL:      mr.WebURL,
+		PRNumber:   mr.IID,
+		Branch:     branch,
+		ForkURL:    fork.WebURL,
+		Status:     status,
+		DryRun:     opts.DryRun,
+		FilesAdded: fileNames,
+	}, nil
+}
+
+// Unpublish opens a merge request deleting the plugin's directory from the
+// marketplace. The plugin must exist on the marketplace's base branch.
+func (p *Publisher) Unpublish(ctx context.Context, opts core.UnpublishOptions) (*core.PublishResult, error) {
+	if name := opts.PluginName; name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return nil, &core.ValidationError{PluginDir: opts.PluginName, Message: "a plugin name without path separators is required"}
+	}
+	baseURL, config, err := p.target()
+	if err != nil {
+		return nil, err
+	}
+	if opts.GitLabToken == "" {
+		return nil, &core.AuthError{Message: "a GitLab token is required"}
+	}
+	client := NewClient(baseURL, opts.GitLabToken)
+	client.SetDryRun(opts.DryRun)
+
+	upstream, err := client.GetProject(ctx, config.Owner+"/"+config.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the plugin exists upstream before proposing its deletion
+	pluginPath := path.Join(config.PluginPath, opts.PluginName)
+	files, err := client.ListFiles(ctx, upstream, config.BaseBranch, pluginPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, &core.PluginNotFoundError{Owner: config.Owner, Repo: config.Repo, Path: pluginPath}
+	}
+
+	branch := opts.Branch
+	if branch == "" {
+		branch = fmt.Sprintf("remove-%s", opts.PluginName)
+	}
+
+	fork, err := prepareBranch(ctx, client, upstream, config, opts.ForkOwner, branch, opts.Verbose)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Verbose {
+		fmt.Printf("Removing %d files from %s...\n", len(files), pluginPath)
+		for _, f := range files {
+			fmt.Printf("  %s\n", f)
+		}
+	}
+
+	commitMsg := fmt.Sprintf("Remove %s plugin", opts.PluginName)
+	if err := client.DeleteFiles(ctx, fork, branch, commitMsg, files); err != nil {
+		return nil, &core.CommitError{Message: commitMsg, Err: err}
+	}
+
+	title := opts.Title
+	if title == "" {
+		title = fmt.Sprintf("Remove %s plugin", opts.PluginName)
+	}
+	body := opts.Body
+	if body == "" {
+		body = fmt.Sprintf("Removing the **%s** plugin from the marketplace.\n\n", opts.PluginName)
+		if opts.Reason != "" {
+			body += fmt.Sprintf("### Reason\n\n%s\n\n", opts.Reason)
+		}
+	}
+
+	if opts.Verbose {
+		fmt.Printf("Creating merge request: %s\n", title)
+	}
+	mr, err := client.CreateMergeRequest(ctx, fork, upstream, branch, config.BaseBranch, title, body)
+	if err != nil {
+		return nil, &core.PRError{Title: title, Err: err}
+	}
+
+	status := "Merge request created successfully"
+	if opts.DryRun {
+		status = fmt.Sprintf("Dry run completed - no merge request created; would open %q from %s into %s:%s removing %d files",
+			title, branch, upstream.PathWithNamespace, config.BaseBranch, len(files))
+	}
+
+	return &core.PublishResult{
+		PRURL:        mr.WebURL,
+		PRNumber:     mr.IID,
+		Branch:       branch,
+		ForkURL:      fork.WebURL,
+		Status:       status,
+		DryRun:       opts.DryRun,
+		FilesRemoved: files,
+	}, nil
+}
+
+// prepareBranch ensures forkOwner (default: the authenticated user) has a
+// fork of the marketplace project, unless it owns the project, and creates
+// branch in it from the base branch. It returns the project holding the
+// branch.
+func prepareBranch(ctx context.Context, client *Client, upstream *Project, config core.MarketplaceConfig, forkOwner, branch string, verbose bool) (*Project, error) {
+	// Get authenticated user if fork owner not specified
+	if forkOwner == "" {
+		user, err := client.GetAuthenticatedUser(ctx)
+		if err != nil {
+			return nil, err
+		}
+		forkOwner = user
+	}
+
+	if verbose {
+		fmt.Printf("Ensuring fork of %s exists for %s...\n", upstream.PathWithNamespace, forkOwner)
+	}
+	fork, err := client.EnsureFork(ctx, upstream, forkOwner)
+	if err != nil {
+		return nil, &core.ForkError{Owner: config.Owner, Repo: config.Repo, Err: err}
+	}
+
+	if verbose {
+		fmt.Printf("Creating branch %s...\n", branch)
+	}
+	if err := client.CreateBranch(ctx, fork, branch, config.BaseBranch); err != nil {
+		return nil, &core.BranchError{Branch: branch, Err: err}
+	}
+	return fork, nil
+}
+
+// readLocalFiles reads all files under dir, naming each by its
+// slash-separated path below prefix.
+func readLocalFiles(dir, prefix string) ([]FileContent, error) {
+	var files []FileContent
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files = append(files, FileContent{Path: path.Join(prefix, filepath.ToSlash(rel)), Content: data})
+		return nil
+	})
+	return files, err
+}
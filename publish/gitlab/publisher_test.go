@@ -0,0 +1,288 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/agentplexus/assistantkit/publish/core"
+)
+
+// fakeGitLab serves the parts of the GitLab API the publisher uses. The
+// marketplace is corp/platform/plugins; forks are created in the requested
+// namespace and report their import as started until fetched again.
+type fakeGitLab struct {
+	mu       sync.Mutex
+	projects []*Project
+	tree     []string // Files every tree listing returns
+	fail     string   // Request path suffix answered with a 500
+	posts    []string // Paths of POST requests
+	commits  []map[string]interface{}
+	mrs      []map[string]interface{}
+}
+
+func newFakeGitLab(t *testing.T) (*fakeGitLab, *httptest.Server) {
+	f := &fakeGitLab{projects: []*Project{{ID: 1, PathWithNamespace: "corp/platform/plugins"}}}
+	ts := httptest.NewServer(f)
+	t.Cleanup(ts.Close)
+	for _, p := range f.projects {
+		p.WebURL = ts.URL + "/" + p.PathWithNamespace
+	}
+	return f, ts
+}
+
+func (f *fakeGitLab) project(id string) *Project {
+	for _, p := range f.projects {
+		if p.PathWithNamespace == id || strconv.Itoa(p.ID) == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (f *fakeGitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("PRIVATE-TOKEN") != "token" {
+		http.Error(w, `{"message": "401 Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4")
+	if f.fail != "" && strings.HasSuffix(path, f.fail) {
+		http.Error(w, `{"message": "boom"}`, http.StatusInternalServerError)
+		return
+	}
+	if path == "/user" {
+		json.NewEncoder(w).Encode(map[string]string{"username": "dev"})
+		return
+	}
+
+	rest := strings.TrimPrefix(path, "/projects/")
+	id, sub, _ := strings.Cut(rest, "/")
+	id, _ = url.PathUnescape(id)
+	project := f.project(id)
+	if project == nil {
+		http.Error(w, `{"message": "404 Project Not Found"}`, http.StatusNotFound)
+		return
+	}
+
+	var body map[string]interface{}
+	if r.Method == http.MethodPost {
+		f.posts = append(f.posts, path)
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+
+	switch {
+	case r.Method == http.MethodGet && sub == "":
+		reply := *project
+		project.ImportStatus = "finished"
+		json.NewEncoder(w).Encode(reply)
+	case sub == "fork":
+		namespace := body["namespace_path"].(string)
+		fork := &Project{ID: len(f.projects) + 1, PathWithNamespace: namespace + "/plugins", ImportStatus: "started"}
+		fork.WebURL = "http://" + r.Host + "/" + fork.PathWithNamespace
+		f.projects = append(f.projects, fork)
+		json.NewEncoder(w).Encode(fork)
+	case sub == "repository/tree":
+		json.NewEncoder(w).Encode(treeEntries(f.tree, r.URL.Query().Get("path")))
+	case sub == "repository/branches":
+		w.WriteHeader(http.StatusCreated)
+	case sub == "repository/commits":
+		f.commits = append(f.commits, body)
+		w.WriteHeader(http.StatusCreated)
+	case sub == "merge_requests":
+		f.mrs = append(f.mrs, body)
+		json.NewEncoder(w).Encode(MergeRequest{IID: 7, WebURL: "http://" + r.Host + "/corp/platform/plugins/-/merge_requests/7"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func treeEntries(files []string, dir string) []map[string]string {
+	entries := []map[string]string{}
+	for _, file := range files {
+		if strings.HasPrefix(file, dir+"/") {
+			entries = append(entries, map[string]string{"path": file, "type": "blob"})
+		}
+	}
+	return entries
+}
+
+func writePlugin(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		".claude-plugin/plugin.json": `{"name": "my-plugin", "version": "0.3.0"}`,
+		"README.md":                  "# My Plugin\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func init() {
+	forkPollInterval = 0
+}
+
+func TestPublish(t *testing.T) {
+	f, ts := newFakeGitLab(t)
+	f.tree = []string{"plugins/my-plugin/README.md"}
+	publisher := NewPublisher(ts.URL, core.MarketplaceConfig{Owner: "corp/platform", Repo: "plugins"})
+
+	result, err := publisher.Publish(context.Background(), core.PublishOptions{
+		PluginDir:     writePlugin(t),
+		PluginName:    "my-plugin",
+		GitLabToken:   "token",
+		CommitMessage: "Release {{.Name}} {{.Version}}",
+	})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if result.PRNumber != 7 || !strings.HasSuffix(result.PRURL, "/merge_requests/7") {
+		t.Errorf("result = %+v, want merge request 7", result)
+	}
+	if result.Branch != "add-my-plugin" || result.ForkURL != ts.URL+"/dev/plugins" {
+		t.Errorf("branch = %s, fork = %s", result.Branch, result.ForkURL)
+	}
+
+	if len(f.commits) != 1 {
+		t.Fatalf("got %d commits, want 1", len(f.commits))
+	}
+	if msg := f.commits[0]["commit_message"]; msg != "Release my-plugin 0.3.0" {
+		t.Errorf("commit message = %v", msg)
+	}
+	actions := make(map[string]string)
+	for _, a := range f.commits[0]["actions"].([]interface{}) {
+		action := a.(map[string]interface{})
+		actions[action["file_path"].(string)] = action["action"].(string)
+	}
+	if actions["plugins/my-plugin/README.md"] != "update" || actions["plugins/my-plugin/.claude-plugin/plugin.json"] != "create" {
+		t.Errorf("commit actions = %v", actions)
+	}
+
+	mr := f.mrs[0]
+	if mr["target_project_id"].(float64) != 1 || mr["source_branch"] != "add-my-plugin" || mr["target_branch"] != "main" {
+		t.Errorf("merge request = %v", mr)
+	}
+}
+
+func TestPublishDryRun(t *testing.T) {
+	f, ts := newFakeGitLab(t)
+	publisher := NewPublisher(ts.URL, core.MarketplaceConfig{Owner: "corp/platform", Repo: "plugins"})
+
+	result, err := publisher.Publish(context.Background(), core.PublishOptions{
+		PluginDir:   writePlugin(t),
+		PluginName:  "my-plugin",
+		GitLabToken: "token",
+		DryRun:      true,
+	})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(f.posts) != 0 {
+		t.Errorf("dry run made POST requests: %v", f.posts)
+	}
+	if len(result.FilesAdded) != 2 || !result.DryRun || !strings.HasPrefix(result.Status, "Dry run") {
+		t.Errorf("result = %+v", result)
+	}
+	if want := `would open "Add my-plugin plugin"`; !strings.Contains(result.Status, want) {
+		t.Errorf("Status = %q, want it to contain %s", result.Status, want)
+	}
+}
+
+func TestPublishErrors(t *testing.T) {
+	var forkErr *core.ForkError
+	var branchErr *core.BranchError
+	var commitErr *core.CommitError
+	var prErr *core.PRError
+	var authErr *core.AuthError
+
+	tests := []struct {
+		name  string
+		fail  string
+		token string
+		want  interface{}
+	}{
+		{"fork", "/fork", "token", &forkErr},
+		{"branch", "/repository/branches", "token", &branchErr},
+		{"commit", "/repository/commits", "token", &commitErr},
+		{"merge request", "/merge_requests", "token", &prErr},
+		{"rejected token", "", "wrong", &authErr},
+		{"missing token", "", "", &authErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, ts := newFakeGitLab(t)
+			f.fail = tt.fail
+			publisher := NewPublisher(ts.URL, core.MarketplaceConfig{Owner: "corp/platform", Repo: "plugins"})
+
+			_, err := publisher.Publish(context.Background(), core.PublishOptions{
+				PluginDir:   writePlugin(t),
+				PluginName:  "my-plugin",
+				GitLabToken: tt.token,
+			})
+			if !errors.As(err, tt.want) {
+				t.Errorf("Publish() error = %v (%T), want %T", err, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnpublish(t *testing.T) {
+	f, ts := newFakeGitLab(t)
+	publisher := NewPublisher(ts.URL, core.MarketplaceConfig{Owner: "corp/platform", Repo: "plugins"})
+	opts := core.UnpublishOptions{PluginName: "my-plugin", GitLabToken: "token", ForkOwner: "corp/platform"}
+
+	var notFound *core.PluginNotFoundError
+	if _, err := publisher.Unpublish(context.Background(), opts); !errors.As(err, &notFound) {
+		t.Fatalf("Unpublish() error = %v, want *core.PluginNotFoundError", err)
+	}
+
+	// Branching in the marketplace's own namespace needs no fork
+	f.tree = []string{"plugins/my-plugin/README.md", "plugins/other/README.md"}
+	result, err := publisher.Unpublish(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Unpublish() error = %v", err)
+	}
+	if len(result.FilesRemoved) != 1 || result.FilesRemoved[0] != "plugins/my-plugin/README.md" {
+		t.Errorf("FilesRemoved = %v", result.FilesRemoved)
+	}
+	for _, post := range f.posts {
+		if strings.HasSuffix(post, "/fork") {
+			t.Errorf("unexpected fork request %s", post)
+		}
+	}
+	if f.mrs[0]["target_project_id"].(float64) != 1 || !strings.HasPrefix(f.posts[len(f.posts)-1], "/projects/1/") {
+		t.Errorf("merge request should be opened within the marketplace project: %v", f.posts)
+	}
+}
+
+func TestRegistered(t *testing.T) {
+	publisher, ok := core.GetPublisher("gitlab")
+	if !ok {
+		t.Fatal("gitlab publisher is not registered")
+	}
+
+	t.Setenv(EnvProject, "")
+	_, err := publisher.Publish(context.Background(), core.PublishOptions{PluginDir: writePlugin(t), PluginName: "my-plugin", GitLabToken: "token"})
+	if err == nil || !strings.Contains(err.Error(), EnvProject) {
+		t.Errorf("Publish() error = %v, want a missing project error", err)
+	}
+}
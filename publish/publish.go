@@ -2,6 +2,7 @@
 //
 // Supported marketplaces:
 //   - Claude Code: anthropics/claude-plugins-official
+//   - GitLab: a Claude Code plugin marketplace project on any GitLab instance
 //
 // Example usage:
 //
@@ -37,22 +38,39 @@ import (
 
 	// Import publishers for side-effect registration
 	_ "github.com/agentplexus/assistantkit/publish/claude"
+	_ "github.com/agentplexus/assistantkit/publish/gitlab"
 )
 
 // Re-export core types for convenience.
 type (
 	Publisher         = core.Publisher
+	BasePublisher     = core.BasePublisher
 	PublishOptions    = core.PublishOptions
+	UnpublishOptions  = core.UnpublishOptions
 	PublishResult     = core.PublishResult
 	MarketplaceConfig = core.MarketplaceConfig
+	PublishJob        = core.PublishJob
+)
+
+// PublishAll runs publish jobs concurrently and returns a result per job.
+var PublishAll = core.PublishAll
+
+// ValidatePlugin checks a plugin's manifest and the files it references.
+var ValidatePlugin = core.ValidatePlugin
+
+// Re-export registry functions.
+var (
+	GetPublisher   = core.GetPublisher
+	PublisherNames = core.PublisherNames
 )
 
 // Re-export error types.
 type (
-	ValidationError = core.ValidationError
-	ForkError       = core.ForkError
-	BranchError     = core.BranchError
-	CommitError     = core.CommitError
-	PRError         = core.PRError
-	AuthError       = core.AuthError
+	ValidationError     = core.ValidationError
+	PluginNotFoundError = core.PluginNotFoundError
+	ForkError           = core.ForkError
+	BranchError         = core.BranchError
+	CommitError         = core.CommitError
+	PRError             = core.PRError
+	AuthError           = core.AuthError
 )